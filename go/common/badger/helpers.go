@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dgraph-io/badger/v4"
@@ -16,6 +17,10 @@ import (
 const (
 	gcInterval     = 5 * time.Minute
 	gcDiscardRatio = 0.5
+
+	// compactDiscardRatio is the value log discard ratio used for explicit compactions. It is
+	// lower than the periodic one so that more space is reclaimed at the cost of more rewriting.
+	compactDiscardRatio = 0.1
 )
 
 // NewLogAdapter returns a badger.Logger backed by an oasis-node logger.
@@ -51,6 +56,9 @@ type GCWorker struct {
 
 	db *badger.DB
 
+	// gcLock serializes value log GC runs as BadgerDB rejects concurrent ones.
+	gcLock sync.Mutex
+
 	startOne cmSync.One
 }
 
@@ -68,14 +76,6 @@ func (gc *GCWorker) run(ctx context.Context) {
 	ticker := time.NewTicker(gcInterval)
 	defer ticker.Stop()
 
-	doGC := func() error {
-		for {
-			if err := gc.db.RunValueLogGC(gcDiscardRatio); err != nil {
-				return err
-			}
-		}
-	}
-
 	for {
 		select {
 		case <-ctx.Done():
@@ -84,9 +84,9 @@ func (gc *GCWorker) run(ctx context.Context) {
 		}
 
 		// Run the value log GC.
-		err := doGC()
+		err := gc.runValueLogGC(ctx, gcDiscardRatio)
 		switch err {
-		case nil, badger.ErrNoRewrite:
+		case nil, badger.ErrNoRewrite, context.Canceled:
 		default:
 			gc.logger.Error("failed to GC value log",
 				"err", err,
@@ -95,6 +95,33 @@ func (gc *GCWorker) run(ctx context.Context) {
 	}
 }
 
+// Compact garbage collects the value log until no more space can be reclaimed.
+//
+// The LSM tree is not flattened as that is unsafe while the database is in use, discardable key
+// versions are dropped by the regular background compactions instead.
+func (gc *GCWorker) Compact(ctx context.Context) error {
+	switch err := gc.runValueLogGC(ctx, compactDiscardRatio); err {
+	case nil, badger.ErrNoRewrite, badger.ErrGCInMemoryMode:
+		return nil
+	default:
+		return fmt.Errorf("failed to GC value log: %w", err)
+	}
+}
+
+func (gc *GCWorker) runValueLogGC(ctx context.Context, discardRatio float64) error {
+	gc.gcLock.Lock()
+	defer gc.gcLock.Unlock()
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := gc.db.RunValueLogGC(discardRatio); err != nil {
+			return err
+		}
+	}
+}
+
 // NewGCWorker creates a new BadgerDB value log GC worker for the provided
 // db, logging to the specified logger.
 func NewGCWorker(logger *logging.Logger, db *badger.DB) *GCWorker {
@@ -132,6 +132,9 @@ type Backend interface {
 	// MinGasPrice returns the minimum gas price.
 	MinGasPrice(ctx context.Context) (*quantity.Quantity, error)
 
+	// EstimateGasPrice returns gas price suggestions based on recent consensus blocks.
+	EstimateGasPrice(ctx context.Context) (*GasPriceEstimate, error)
+
 	// GetBlock returns a consensus block at a specific height.
 	GetBlock(ctx context.Context, height int64) (*Block, error)
 
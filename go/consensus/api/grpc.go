@@ -37,6 +37,8 @@ var (
 	methodEstimateGas = serviceName.NewMethod("EstimateGas", &EstimateGasRequest{})
 	// methodMinGasPrice is the MinGasPrice method.
 	methodMinGasPrice = serviceName.NewMethod("MinGasPrice", nil)
+	// methodEstimateGasPrice is the EstimateGasPrice method.
+	methodEstimateGasPrice = serviceName.NewMethod("EstimateGasPrice", nil)
 	// methodGetSignerNonce is a GetSignerNonce method.
 	methodGetSignerNonce = serviceName.NewMethod("GetSignerNonce", &GetSignerNonceRequest{})
 	// methodGetBlock is the GetBlock method.
@@ -65,6 +67,10 @@ var (
 	methodStateSyncGetPrefixes = serviceName.NewMethod("StateSyncGetPrefixes", syncer.GetPrefixesRequest{})
 	// methodStateSyncIterate is the StateSyncIterate method.
 	methodStateSyncIterate = serviceName.NewMethod("StateSyncIterate", syncer.IterateRequest{})
+	// methodStateSyncGetMulti is the StateSyncGetMulti method.
+	methodStateSyncGetMulti = serviceName.NewMethod("StateSyncGetMulti", syncer.GetMultiRequest{})
+	// methodStateSyncGetRange is the StateSyncGetRange method.
+	methodStateSyncGetRange = serviceName.NewMethod("StateSyncGetRange", syncer.GetRangeRequest{})
 	// methodGetChainContext is the GetChainContext method.
 	methodGetChainContext = serviceName.NewMethod("GetChainContext", nil)
 	// methodGetStatus is the GetStatus method.
@@ -108,6 +114,10 @@ var (
 				MethodName: methodMinGasPrice.ShortName(),
 				Handler:    handlerMinGasPrice,
 			},
+			{
+				MethodName: methodEstimateGasPrice.ShortName(),
+				Handler:    handlerEstimateGasPrice,
+			},
 			{
 				MethodName: methodGetSignerNonce.ShortName(),
 				Handler:    handlerGetSignerNonce,
@@ -160,6 +170,14 @@ var (
 				MethodName: methodStateSyncIterate.ShortName(),
 				Handler:    handlerStateSyncIterate,
 			},
+			{
+				MethodName: methodStateSyncGetMulti.ShortName(),
+				Handler:    handlerStateSyncGetMulti,
+			},
+			{
+				MethodName: methodStateSyncGetRange.ShortName(),
+				Handler:    handlerStateSyncGetRange,
+			},
 			{
 				MethodName: methodGetGenesisDocument.ShortName(),
 				Handler:    handlerGetGenesisDocument,
@@ -329,6 +347,25 @@ func handlerMinGasPrice(
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerEstimateGasPrice(
+	srv any,
+	ctx context.Context,
+	_ func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	if interceptor == nil {
+		return srv.(Services).Core().EstimateGasPrice(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodEstimateGasPrice.FullName(),
+	}
+	handler := func(ctx context.Context, _ any) (any, error) {
+		return srv.(Services).Core().EstimateGasPrice(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
 func handlerGetSignerNonce(
 	srv any,
 	ctx context.Context,
@@ -616,6 +653,55 @@ func handlerStateSyncIterate(
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerStateSyncGetMulti(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	rq := new(syncer.GetMultiRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if err := rq.Validate(); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Services).Core().State().SyncGetMulti(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodStateSyncGetMulti.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Services).Core().State().SyncGetMulti(ctx, req.(*syncer.GetMultiRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
+func handlerStateSyncGetRange(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	rq := new(syncer.GetRangeRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Services).Core().State().SyncGetRange(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodStateSyncGetRange.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Services).Core().State().SyncGetRange(ctx, req.(*syncer.GetRangeRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 func handlerGetGenesisDocument(
 	srv any,
 	ctx context.Context,
@@ -823,6 +909,14 @@ func (c *Client) MinGasPrice(ctx context.Context) (*quantity.Quantity, error) {
 	return &rsp, nil
 }
 
+func (c *Client) EstimateGasPrice(ctx context.Context) (*GasPriceEstimate, error) {
+	var rsp GasPriceEstimate
+	if err := c.conn.Invoke(ctx, methodEstimateGasPrice.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) GetSignerNonce(ctx context.Context, req *GetSignerNonceRequest) (uint64, error) {
 	var nonce uint64
 	if err := c.conn.Invoke(ctx, methodGetSignerNonce.FullName(), req, &nonce); err != nil {
@@ -934,6 +1028,24 @@ func (rs *stateReadSync) SyncIterate(ctx context.Context, request *syncer.Iterat
 	return &rsp, nil
 }
 
+// Implements syncer.ReadSyncer.
+func (rs *stateReadSync) SyncGetMulti(ctx context.Context, request *syncer.GetMultiRequest) (*syncer.ProofResponse, error) {
+	var rsp syncer.ProofResponse
+	if err := rs.c.conn.Invoke(ctx, methodStateSyncGetMulti.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+// Implements syncer.ReadSyncer.
+func (rs *stateReadSync) SyncGetRange(ctx context.Context, request *syncer.GetRangeRequest) (*syncer.ProofResponse, error) {
+	var rsp syncer.ProofResponse
+	if err := rs.c.conn.Invoke(ctx, methodStateSyncGetRange.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) State() syncer.ReadSyncer {
 	return &stateReadSync{c}
 }
@@ -26,6 +26,27 @@ const (
 type PriceDiscovery interface {
 	// GasPrice returns the current consensus gas price.
 	GasPrice() (*quantity.Quantity, error)
+
+	// GasPriceEstimate returns gas price suggestions based on recent consensus blocks.
+	GasPriceEstimate() (*GasPriceEstimate, error)
+}
+
+// GasPriceEstimate are gas price suggestions based on recent consensus blocks.
+type GasPriceEstimate struct {
+	// Low is the gas price that should be enough for a transaction to be included when blocks
+	// are not congested.
+	Low quantity.Quantity `json:"low"`
+	// Median is the median gas price paid by transactions in recent blocks.
+	Median quantity.Quantity `json:"median"`
+	// High is the gas price that should be enough for a transaction to be included quickly even
+	// when blocks are congested.
+	High quantity.Quantity `json:"high"`
+
+	// BaseFee is the base gas price, which is adjusted based on the fullness of recent blocks
+	// (similar to EIP-1559) and is never lower than the minimum gas price.
+	BaseFee quantity.Quantity `json:"base_fee"`
+	// BlockFullness is the average fullness of recent blocks (in percent).
+	BlockFullness uint8 `json:"block_fullness"`
 }
 
 // SubmissionManager is a transaction submission manager interface.
@@ -278,6 +299,10 @@ func (pd *noOpPriceDiscovery) GasPrice() (*quantity.Quantity, error) {
 	return nil, transaction.ErrMethodNotSupported
 }
 
+func (pd *noOpPriceDiscovery) GasPriceEstimate() (*GasPriceEstimate, error) {
+	return nil, transaction.ErrMethodNotSupported
+}
+
 // NoOpSubmissionManager implements a submission manager that doesn't support submitting transactions.
 type NoOpSubmissionManager struct{}
 
@@ -1,6 +1,8 @@
 package abci
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/config"
 	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 )
@@ -27,6 +30,10 @@ const (
 
 	// PruneKeepN retains the last N latest versions.
 	PruneKeepN
+
+	// PruneKeepEpochBoundaries retains the last N latest versions and
+	// the first version of every (Nth) epoch.
+	PruneKeepEpochBoundaries
 )
 
 func (s PruneStrategy) String() string {
@@ -35,6 +42,8 @@ func (s PruneStrategy) String() string {
 		return config.PruneStrategyNone
 	case PruneKeepN:
 		return config.PruneStrategyKeepN
+	case PruneKeepEpochBoundaries:
+		return config.PruneStrategyKeepEpochBoundaries
 	default:
 		return "[unknown]"
 	}
@@ -46,6 +55,8 @@ func (s *PruneStrategy) FromString(str string) error {
 		*s = PruneNone
 	case config.PruneStrategyKeepN:
 		*s = PruneKeepN
+	case config.PruneStrategyKeepEpochBoundaries:
+		*s = PruneKeepEpochBoundaries
 	default:
 		return fmt.Errorf("abci/pruner: unknown pruning strategy: '%v'", str)
 	}
@@ -63,6 +74,14 @@ type PruneConfig struct {
 
 	// PruneInterval configures the pruning interval.
 	PruneInterval time.Duration
+
+	// EpochInterval is the interval (in epochs) at which epoch boundaries
+	// are retained when applicable. Zero means every epoch.
+	EpochInterval uint64
+
+	// BlockTime returns the time of the block at the given height. It is
+	// required by strategies retaining epoch boundaries.
+	BlockTime BlockTimeFunc
 }
 
 // StatePruner is a concrete ABCI mux state pruner implementation.
@@ -81,6 +100,16 @@ type StatePruner interface {
 	//
 	// This method can be called concurrently with Prune.
 	GetLastRetainedVersion() uint64
+
+	// GetStateSnapshot returns the state snapshot retained at the given
+	// height after the version has been pruned, together with the node
+	// database holding it.
+	//
+	// This method can be called concurrently with Prune.
+	GetStateSnapshot(height int64) (*api.StateSnapshot, nodedb.NodeDB, error)
+
+	// Close releases any resources held by the pruner.
+	Close()
 }
 
 type nonePruner struct{}
@@ -97,6 +126,13 @@ func (p *nonePruner) GetLastRetainedVersion() uint64 {
 	return 0
 }
 
+func (p *nonePruner) GetStateSnapshot(int64) (*api.StateSnapshot, nodedb.NodeDB, error) {
+	return nil, nil, consensus.ErrVersionNotFound
+}
+
+func (p *nonePruner) Close() {
+}
+
 type genericPruner struct {
 	sync.Mutex
 
@@ -107,6 +143,9 @@ type genericPruner struct {
 	keepN               uint64
 	lastRetainedVersion uint64
 
+	// snapshots is the optional store of retained epoch boundary snapshots.
+	snapshots *epochSnapshots
+
 	handlers []consensus.StatePruneHandler
 }
 
@@ -143,6 +182,27 @@ PruneLoop:
 			break
 		}
 
+		// Retain a copy of the state at epoch boundaries.
+		if p.snapshots != nil {
+			err := p.snapshots.process(i)
+			switch {
+			case err == nil:
+			case errors.Is(err, errSnapshotPending):
+				// The state is still being copied, retry on the next pass.
+				p.earliestVersion = i
+				break PruneLoop
+			default:
+				// Never prune state that may need to be retained, retry on the next pass.
+				p.logger.Error("failed to retain state snapshot, holding pruning",
+					"err", err,
+					"latest_version", latestVersion,
+					"version", i,
+				)
+				p.earliestVersion = i
+				break PruneLoop
+			}
+		}
+
 		// Before pruning anything, run all prune handlers. If any of them
 		// fails we abort the prune.
 		for _, ph := range p.handlers {
@@ -195,6 +255,19 @@ PruneLoop:
 	return nil
 }
 
+func (p *genericPruner) GetStateSnapshot(height int64) (*api.StateSnapshot, nodedb.NodeDB, error) {
+	if p.snapshots == nil {
+		return nil, nil, consensus.ErrVersionNotFound
+	}
+	return p.snapshots.get(height)
+}
+
+func (p *genericPruner) Close() {
+	if p.snapshots != nil {
+		p.snapshots.close()
+	}
+}
+
 func (p *genericPruner) RegisterHandler(handler consensus.StatePruneHandler) {
 	p.Lock()
 	defer p.Unlock()
@@ -202,7 +275,14 @@ func (p *genericPruner) RegisterHandler(handler consensus.StatePruneHandler) {
 	p.handlers = append(p.handlers, handler)
 }
 
-func newStatePruner(cfg *PruneConfig, ndb nodedb.NodeDB) (StatePruner, error) {
+func newStatePruner(
+	ctx context.Context,
+	cfg *PruneConfig,
+	ndb nodedb.NodeDB,
+	initialHeight uint64,
+	snapshotDir string,
+	getEpoch EpochFunc,
+) (StatePruner, error) {
 	// The roothash checkCommittees call requires at least 1 previous block
 	// for timekeeping purposes.
 	const minKept = 1
@@ -223,6 +303,22 @@ func newStatePruner(cfg *PruneConfig, ndb nodedb.NodeDB) (StatePruner, error) {
 			ndb:    ndb,
 			keepN:  cfg.NumKept,
 		}
+	case PruneKeepEpochBoundaries:
+		if cfg.NumKept < minKept {
+			return nil, fmt.Errorf("abci/pruner: invalid number of versions retained: %v", cfg.NumKept)
+		}
+
+		snapshots, err := newEpochSnapshots(ctx, snapshotDir, ndb, initialHeight, cfg.EpochInterval, getEpoch, cfg.BlockTime)
+		if err != nil {
+			return nil, err
+		}
+
+		statePruner = &genericPruner{
+			logger:    logger,
+			ndb:       ndb,
+			keepN:     cfg.NumKept,
+			snapshots: snapshots,
+		}
 	default:
 		return nil, fmt.Errorf("abci/pruner: unsupported pruning strategy: %v", cfg.Strategy)
 	}
@@ -230,6 +326,7 @@ func newStatePruner(cfg *PruneConfig, ndb nodedb.NodeDB) (StatePruner, error) {
 	logger.Debug("ABCI state pruner created",
 		"strategy", cfg.Strategy,
 		"num_kept", cfg.NumKept,
+		"epoch_interval", cfg.EpochInterval,
 	)
 
 	return statePruner, nil
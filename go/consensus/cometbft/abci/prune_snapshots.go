@@ -0,0 +1,361 @@
+package abci
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	nodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	badgerNodedb "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+const (
+	// snapshotsNodeDBDir is the subdirectory which contains the node database holding retained
+	// state snapshots.
+	snapshotsNodeDBDir = "nodedb"
+	// snapshotsCheckpointDir is the subdirectory used for temporary checkpoints while copying
+	// state into the snapshot node database.
+	snapshotsCheckpointDir = "checkpoints"
+	// snapshotsMetadataFile is the name of the file holding retained state snapshot metadata.
+	snapshotsMetadataFile = "snapshots.cbor"
+
+	// snapshotChunkSize is the chunk size used when copying state into the snapshot node database.
+	snapshotChunkSize = 8 * 1024 * 1024
+)
+
+// errSnapshotPending is the error returned when the state of a version is still being copied into
+// the snapshot store and the version must not be pruned yet.
+var errSnapshotPending = errors.New("state snapshot in progress")
+
+// EpochFunc returns the epoch at the given block height.
+type EpochFunc func(ctx context.Context, height int64) (beacon.EpochTime, error)
+
+// BlockTimeFunc returns the time of the block at the given height.
+type BlockTimeFunc func(ctx context.Context, height int64) (time.Time, error)
+
+// epochSnapshotsMetadata is the persisted state of the epoch boundary snapshot store.
+type epochSnapshotsMetadata struct {
+	// LastEpoch is the epoch of the last successfully processed version.
+	LastEpoch beacon.EpochTime `json:"last_epoch"`
+	// Snapshots are the retained state snapshots in ascending height order.
+	Snapshots []*api.StateSnapshot `json:"snapshots"`
+}
+
+// snapshotCopy is a state snapshot being copied into the snapshot store in the background.
+type snapshotCopy struct {
+	snapshot *api.StateSnapshot
+
+	doneCh chan struct{}
+	err    error
+}
+
+// epochSnapshots retains copies of the state at epoch boundaries before the pruner removes them
+// from the main node database.
+//
+// The node database can only prune its earliest version so retained versions are copied into a
+// separate node database which is never pruned.
+type epochSnapshots struct {
+	sync.RWMutex
+
+	ctx       context.Context
+	cancelCtx context.CancelFunc
+	logger    *logging.Logger
+
+	dir  string
+	ndb  nodedb.NodeDB
+	sndb nodedb.NodeDB
+
+	initialHeight uint64
+	epochInterval uint64
+	getEpoch      EpochFunc
+	getBlockTime  BlockTimeFunc
+
+	meta     epochSnapshotsMetadata
+	byHeight map[int64]*api.StateSnapshot
+
+	pending *snapshotCopy
+	copyWg  sync.WaitGroup
+
+	// unpersisted is true iff the metadata has been updated but could not be persisted.
+	unpersisted bool
+}
+
+// process must be called for each version, in ascending order, before it is pruned. In case the
+// version is the first version of a retained epoch, its state is copied into the snapshot store in
+// the background and errSnapshotPending is returned until the copy completes.
+//
+// Any other error means that the version has not been processed and must not be pruned. Calling
+// process again for the same version retries processing.
+func (es *epochSnapshots) process(version uint64) error {
+	if es.unpersisted {
+		if err := es.persist(); err != nil {
+			return err
+		}
+	}
+
+	if es.pending == nil {
+		height := int64(version)
+		epoch, err := es.getEpoch(es.ctx, height)
+		if err != nil {
+			return fmt.Errorf("failed to query epoch: %w", err)
+		}
+		if epoch == es.meta.LastEpoch {
+			return nil
+		}
+		if !es.isRetainedBoundary(version, epoch) {
+			es.meta.LastEpoch = epoch
+			return es.persist()
+		}
+		if err = es.startCopy(height, epoch); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case <-es.pending.doneCh:
+	default:
+		return errSnapshotPending
+	}
+	cp := es.pending
+	es.pending = nil
+
+	if cp.err != nil {
+		return fmt.Errorf("failed to copy state at height %d: %w", cp.snapshot.Height, cp.err)
+	}
+
+	es.Lock()
+	es.meta.LastEpoch = cp.snapshot.Epoch
+	es.meta.Snapshots = append(es.meta.Snapshots, cp.snapshot)
+	es.byHeight[cp.snapshot.Height] = cp.snapshot
+	es.Unlock()
+
+	es.logger.Info("retained state snapshot",
+		"height", cp.snapshot.Height,
+		"epoch", cp.snapshot.Epoch,
+	)
+
+	return es.persist()
+}
+
+// isRetainedBoundary returns true iff the given version, whose epoch differs from the epoch of
+// the last successfully processed version, is the first version of an epoch that should be
+// retained.
+func (es *epochSnapshots) isRetainedBoundary(version uint64, epoch beacon.EpochTime) bool {
+	if epoch%beacon.EpochTime(es.epochInterval) != 0 {
+		return false
+	}
+	// Epoch transitions can only be observed between processed versions, so the very first
+	// version processed is only known to start an epoch in case it is the initial version.
+	return es.meta.LastEpoch != beacon.EpochInvalid || version == es.initialHeight
+}
+
+// startCopy starts copying the state at the given height into the snapshot store.
+func (es *epochSnapshots) startCopy(height int64, epoch beacon.EpochTime) error {
+	roots, err := es.ndb.GetRootsForVersion(uint64(height))
+	if err != nil {
+		return fmt.Errorf("failed to get roots: %w", err)
+	}
+	if len(roots) != 1 {
+		return fmt.Errorf("incorrect number of roots (%d) at height %d", len(roots), height)
+	}
+	root := roots[0]
+
+	blockTime, err := es.getBlockTime(es.ctx, height)
+	if err != nil {
+		return fmt.Errorf("failed to query block time: %w", err)
+	}
+
+	cp := &snapshotCopy{
+		snapshot: &api.StateSnapshot{
+			Height: height,
+			Epoch:  epoch,
+			Time:   blockTime,
+			Root:   root,
+		},
+		doneCh: make(chan struct{}),
+	}
+	es.pending = cp
+
+	es.copyWg.Add(1)
+	go func() {
+		defer es.copyWg.Done()
+		defer close(cp.doneCh)
+
+		// The state may have already been copied in case we failed to persist metadata.
+		existing, err := es.sndb.GetRootsForVersion(root.Version)
+		if err != nil {
+			cp.err = fmt.Errorf("failed to get snapshot roots: %w", err)
+			return
+		}
+		if len(existing) == 0 {
+			cp.err = es.copyState(root)
+		}
+	}()
+
+	return nil
+}
+
+// copyState copies the given root from the main node database into the snapshot node database.
+func (es *epochSnapshots) copyState(root node.Root) (err error) {
+	creator, err := checkpoint.NewFileCreator(filepath.Join(es.dir, snapshotsCheckpointDir), es.ndb)
+	if err != nil {
+		return err
+	}
+	cp, err := creator.CreateCheckpoint(es.ctx, checkpoint.VersionZstd, root, snapshotChunkSize)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = creator.DeleteCheckpoint(es.ctx, cp.Version, root)
+	}()
+
+	restorer, err := checkpoint.NewRestorer(es.sndb)
+	if err != nil {
+		return err
+	}
+	if err = es.sndb.StartMultipartInsert(root.Version); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = restorer.AbortRestore(es.ctx)
+		}
+		// On success this only clears the insertion log.
+		if abortErr := es.sndb.AbortMultipartInsert(); abortErr != nil && err == nil {
+			err = abortErr
+		}
+	}()
+
+	if err = restorer.StartRestore(es.ctx, cp); err != nil {
+		return err
+	}
+	for idx := range cp.Chunks {
+		var cm *checkpoint.ChunkMetadata
+		if cm, err = cp.GetChunkMetadata(uint64(idx)); err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err = creator.GetCheckpointChunk(es.ctx, cm, &buf); err != nil {
+			return err
+		}
+		if _, err = restorer.RestoreChunk(es.ctx, uint64(idx), &buf); err != nil {
+			return err
+		}
+	}
+
+	return es.sndb.Finalize([]node.Root{root})
+}
+
+func (es *epochSnapshots) persist() error {
+	es.RLock()
+	raw := cbor.Marshal(es.meta)
+	es.RUnlock()
+
+	// Make sure the metadata is persisted before any further versions are processed, as the
+	// retained state would otherwise be forgotten on restart.
+	es.unpersisted = true
+
+	fn := filepath.Join(es.dir, snapshotsMetadataFile)
+	tmpFn := fn + ".tmp"
+	if err := os.WriteFile(tmpFn, raw, 0o600); err != nil {
+		return fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+	if err := os.Rename(tmpFn, fn); err != nil {
+		return fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+	es.unpersisted = false
+	return nil
+}
+
+// get returns the state snapshot retained at the given height.
+func (es *epochSnapshots) get(height int64) (*api.StateSnapshot, nodedb.NodeDB, error) {
+	es.RLock()
+	defer es.RUnlock()
+
+	snapshot, ok := es.byHeight[height]
+	if !ok {
+		return nil, nil, consensus.ErrVersionNotFound
+	}
+	return snapshot, es.sndb, nil
+}
+
+func (es *epochSnapshots) close() {
+	es.cancelCtx()
+	es.copyWg.Wait()
+	es.sndb.Close()
+}
+
+func newEpochSnapshots(
+	ctx context.Context,
+	dir string,
+	ndb nodedb.NodeDB,
+	initialHeight uint64,
+	epochInterval uint64,
+	getEpoch EpochFunc,
+	getBlockTime BlockTimeFunc,
+) (*epochSnapshots, error) {
+	if epochInterval == 0 {
+		epochInterval = 1
+	}
+	if getEpoch == nil || getBlockTime == nil {
+		return nil, fmt.Errorf("abci/pruner: epoch and block time sources are required")
+	}
+
+	if err := common.Mkdir(dir); err != nil {
+		return nil, fmt.Errorf("abci/pruner: failed to create snapshot directory: %w", err)
+	}
+
+	es := &epochSnapshots{
+		logger:        logging.GetLogger("abci-mux/pruner/snapshots"),
+		dir:           dir,
+		ndb:           ndb,
+		initialHeight: initialHeight,
+		epochInterval: epochInterval,
+		getEpoch:      getEpoch,
+		getBlockTime:  getBlockTime,
+		meta: epochSnapshotsMetadata{
+			LastEpoch: beacon.EpochInvalid,
+		},
+		byHeight: make(map[int64]*api.StateSnapshot),
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, snapshotsMetadataFile))
+	switch {
+	case err == nil:
+		if err = cbor.Unmarshal(raw, &es.meta); err != nil {
+			return nil, fmt.Errorf("abci/pruner: malformed snapshot metadata: %w", err)
+		}
+		for _, snapshot := range es.meta.Snapshots {
+			es.byHeight[snapshot.Height] = snapshot
+		}
+	case os.IsNotExist(err):
+	default:
+		return nil, fmt.Errorf("abci/pruner: failed to read snapshot metadata: %w", err)
+	}
+
+	es.sndb, err = badgerNodedb.New(&nodedb.Config{
+		DB:               filepath.Join(dir, snapshotsNodeDBDir),
+		MaxCacheSize:     16 * 1024 * 1024,
+		DiscardWriteLogs: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("abci/pruner: failed to open snapshot node database: %w", err)
+	}
+	es.ctx, es.cancelCtx = context.WithCancel(ctx)
+
+	return es, nil
+}
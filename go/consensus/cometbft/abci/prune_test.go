@@ -2,27 +2,32 @@ package abci
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 	mkvsDB "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 	mkvsBadgerDB "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
 	mkvsNode "github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 )
 
-func TestPruneKeepN(t *testing.T) {
+func newTestPruneNodeDB(t *testing.T, numVersions uint64) mkvsDB.NodeDB {
 	require := require.New(t)
 
 	// Create a new random temporary directory under /tmp.
 	dir, err := os.MkdirTemp("", "abci-prune.test.badger")
 	require.NoError(err, "TempDir")
-	defer os.RemoveAll(dir)
+	t.Cleanup(func() { os.RemoveAll(dir) })
 
 	// Create a Badger-backed Node DB.
 	ndb, err := mkvsBadgerDB.New(&mkvsDB.Config{
@@ -31,10 +36,11 @@ func TestPruneKeepN(t *testing.T) {
 		MaxCacheSize: 16 * 1024 * 1024,
 	})
 	require.NoError(err, "New")
+	t.Cleanup(ndb.Close)
 	tree := mkvs.New(nil, ndb, mkvsNode.RootTypeState)
 
 	ctx := context.Background()
-	for i := uint64(1); i <= 11; i++ {
+	for i := uint64(1); i <= numVersions; i++ {
 		err = tree.Insert(ctx, []byte(fmt.Sprintf("key:%d", i)), []byte(fmt.Sprintf("value:%d", i)))
 		require.NoError(err, "Insert")
 
@@ -44,11 +50,18 @@ func TestPruneKeepN(t *testing.T) {
 		err = ndb.Finalize([]mkvsNode.Root{{Namespace: common.Namespace{}, Version: i, Type: mkvsNode.RootTypeState, Hash: rootHash}})
 		require.NoError(err, "Finalize")
 	}
+	return ndb
+}
+
+func TestPruneKeepN(t *testing.T) {
+	require := require.New(t)
 
-	pruner, err := newStatePruner(&PruneConfig{
+	ndb := newTestPruneNodeDB(t, 11)
+
+	pruner, err := newStatePruner(context.Background(), &PruneConfig{
 		Strategy: PruneKeepN,
 		NumKept:  2,
-	}, ndb)
+	}, ndb, 1, "", nil)
 	require.NoError(err, "newStatePruner failed")
 
 	earliestVersion := ndb.GetEarliestVersion()
@@ -72,3 +85,159 @@ func TestPruneKeepN(t *testing.T) {
 	lastRetainedVersion = pruner.GetLastRetainedVersion()
 	require.EqualValues(9, lastRetainedVersion, "last retained version should be correct")
 }
+
+func TestPruneKeepEpochBoundaries(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	ndb := newTestPruneNodeDB(t, 11)
+
+	dir, err := os.MkdirTemp("", "abci-prune.test.snapshots")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	// Epochs start at heights 3, 6 and 9.
+	getEpoch := func(_ context.Context, height int64) (beacon.EpochTime, error) {
+		return beacon.EpochTime(height / 3), nil
+	}
+	blockTime := func(_ context.Context, height int64) (time.Time, error) {
+		return time.Unix(height, 0), nil
+	}
+	newPruner := func() StatePruner {
+		pruner, err := newStatePruner(ctx, &PruneConfig{
+			Strategy:      PruneKeepEpochBoundaries,
+			NumKept:       2,
+			EpochInterval: 2,
+			BlockTime:     blockTime,
+		}, ndb, 1, dir, getEpoch)
+		require.NoError(err, "newStatePruner failed")
+		return pruner
+	}
+	// State is copied in the background so pruning may need multiple passes.
+	pruneUntil := func(pruner StatePruner, latestVersion, earliestVersion uint64) {
+		require.Eventually(func() bool {
+			if err := pruner.Prune(latestVersion); err != nil {
+				return false
+			}
+			return ndb.GetEarliestVersion() == earliestVersion
+		}, 10*time.Second, 10*time.Millisecond, "earliest version should be correct")
+	}
+
+	pruner := newPruner()
+	pruneUntil(pruner, 7, 5)
+	require.EqualValues(5, pruner.GetLastRetainedVersion(), "last retained version should be correct")
+	pruner.Close()
+
+	// Snapshots should be retained across restarts.
+	pruner = newPruner()
+	defer pruner.Close()
+	pruneUntil(pruner, 11, 9)
+
+	// The earliest version and the first version of every second epoch should be retained.
+	for height := int64(1); height < 9; height++ {
+		snapshot, sndb, err := pruner.GetStateSnapshot(height)
+		if height != 1 && height != 6 {
+			require.ErrorIs(err, consensus.ErrVersionNotFound, "snapshot at height %d should not exist", height)
+			continue
+		}
+		require.NoError(err, "GetStateSnapshot(%d)", height)
+		require.EqualValues(height, snapshot.Height)
+		require.EqualValues(height/3, snapshot.Epoch)
+		require.Equal(time.Unix(height, 0), snapshot.Time)
+		require.EqualValues(height, snapshot.Root.Version)
+
+		tree := mkvs.NewWithRoot(nil, sndb, snapshot.Root)
+		for i := int64(1); i <= 11; i++ {
+			value, err := tree.Get(ctx, []byte(fmt.Sprintf("key:%d", i)))
+			require.NoError(err, "Get")
+			if i > height {
+				require.Nil(value, "snapshot should not contain later state")
+				continue
+			}
+			require.Equal([]byte(fmt.Sprintf("value:%d", i)), value, "snapshot should contain state")
+		}
+		tree.Close()
+	}
+
+	// An epoch seen first should not be retained unless it starts at the initial height.
+	otherDir, err := os.MkdirTemp("", "abci-prune.test.snapshots")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(otherDir)
+
+	otherPruner, err := newStatePruner(ctx, &PruneConfig{
+		Strategy:      PruneKeepEpochBoundaries,
+		NumKept:       1,
+		EpochInterval: 2,
+		BlockTime:     blockTime,
+	}, ndb, 1, otherDir, func(_ context.Context, height int64) (beacon.EpochTime, error) {
+		return beacon.EpochTime(height/3 + 1), nil
+	})
+	require.NoError(err, "newStatePruner failed")
+	defer otherPruner.Close()
+	pruneUntil(otherPruner, 11, 10)
+	_, _, err = otherPruner.GetStateSnapshot(9)
+	require.ErrorIs(err, consensus.ErrVersionNotFound, "snapshot of the first epoch seen should not exist")
+}
+
+func TestPruneKeepEpochBoundariesErrors(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	ndb := newTestPruneNodeDB(t, 11)
+
+	dir, err := os.MkdirTemp("", "abci-prune.test.snapshots")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	// Epochs start at heights 3, 6 and 9.
+	var failEpoch, failBlockTime atomic.Bool
+	getEpoch := func(_ context.Context, height int64) (beacon.EpochTime, error) {
+		if height == 5 && failEpoch.Load() {
+			return 0, errors.New("epoch not available")
+		}
+		return beacon.EpochTime(height / 3), nil
+	}
+	blockTime := func(_ context.Context, height int64) (time.Time, error) {
+		if height == 6 && failBlockTime.Load() {
+			return time.Time{}, errors.New("block time not available")
+		}
+		return time.Unix(height, 0), nil
+	}
+	pruner, err := newStatePruner(ctx, &PruneConfig{
+		Strategy:      PruneKeepEpochBoundaries,
+		NumKept:       1,
+		EpochInterval: 2,
+		BlockTime:     blockTime,
+	}, ndb, 1, dir, getEpoch)
+	require.NoError(err, "newStatePruner failed")
+	defer pruner.Close()
+
+	pruneUntil := func(latestVersion, earliestVersion uint64) {
+		require.Eventually(func() bool {
+			if err := pruner.Prune(latestVersion); err != nil {
+				return false
+			}
+			return ndb.GetEarliestVersion() == earliestVersion
+		}, 10*time.Second, 10*time.Millisecond, "earliest version should be correct")
+	}
+
+	// Pruning should be held at versions that could not be processed.
+	failEpoch.Store(true)
+	pruneUntil(11, 5)
+	require.NoError(pruner.Prune(11), "Prune")
+	require.EqualValues(5, ndb.GetEarliestVersion(), "pruning should be held while the epoch is unknown")
+	failEpoch.Store(false)
+
+	failBlockTime.Store(true)
+	pruneUntil(11, 6)
+	require.NoError(pruner.Prune(11), "Prune")
+	require.EqualValues(6, ndb.GetEarliestVersion(), "pruning should be held while the state is not retained")
+	failBlockTime.Store(false)
+
+	// Once the errors are resolved, all boundaries should be retained.
+	pruneUntil(11, 10)
+	for _, height := range []int64{1, 6} {
+		_, _, err = pruner.GetStateSnapshot(height)
+		require.NoError(err, "GetStateSnapshot(%d)", height)
+	}
+}
@@ -39,6 +39,10 @@ var _ api.ApplicationState = (*applicationState)(nil)
 // appStateDir is the subdirectory which contains ABCI state.
 const appStateDir = "state"
 
+// appStateSnapshotsDir is the subdirectory of the ABCI state directory which contains state
+// snapshots retained by the pruner.
+const appStateSnapshotsDir = "snapshots"
+
 type proposalState struct {
 	// header is the partial proposal header (only set when we are the proposer).
 	header *cmtproto.Header
@@ -225,6 +229,10 @@ func (s *applicationState) LastRetainedVersion() (int64, error) {
 	return int64(s.statePruner.GetLastRetainedVersion()), nil
 }
 
+func (s *applicationState) GetStateSnapshot(height int64) (*api.StateSnapshot, storage.NodeDB, error) {
+	return s.statePruner.GetStateSnapshot(height)
+}
+
 func (s *applicationState) Storage() storage.LocalBackend {
 	return s.storage
 }
@@ -501,6 +509,7 @@ func (s *applicationState) doCleanup() {
 		<-s.prunerClosedCh
 		<-s.metricsClosedCh
 
+		s.statePruner.Close()
 		s.storage.Cleanup()
 		s.storage = nil
 	}
@@ -718,12 +727,6 @@ func newApplicationState(ctx context.Context, upgrader upgrade.Backend, cfg *App
 	canonicalState := mkvs.NewWithRoot(nil, ndb, *stateRoot, mkvs.WithoutWriteLog())
 	checkState := mkvs.NewWithRoot(nil, ndb, *stateRoot, mkvs.WithoutWriteLog())
 
-	// Initialize the state pruner.
-	statePruner, err := newStatePruner(&cfg.Pruning, ndb)
-	if err != nil {
-		return nil, fmt.Errorf("state: failed to create pruner: %w", err)
-	}
-
 	var minGasPrice quantity.Quantity
 	if err = minGasPrice.FromInt64(int64(cfg.MinGasPrice)); err != nil {
 		return nil, fmt.Errorf("state: invalid minimum gas price: %w", err)
@@ -740,7 +743,6 @@ func newApplicationState(ctx context.Context, upgrader upgrade.Backend, cfg *App
 		checkState:         checkState,
 		stateRoot:          *stateRoot,
 		storage:            ldb,
-		statePruner:        statePruner,
 		prunerClosedCh:     make(chan struct{}),
 		prunerNotifyCh:     channels.NewRingChannel(1),
 		pruneInterval:      cfg.Pruning.PruneInterval,
@@ -755,6 +757,14 @@ func newApplicationState(ctx context.Context, upgrader upgrade.Backend, cfg *App
 		metricsClosedCh:    make(chan struct{}),
 	}
 
+	// Initialize the state pruner.
+	snapshotDir := filepath.Join(cfg.DataDir, appStateDir, appStateSnapshotsDir)
+	s.statePruner, err = newStatePruner(ctx, &cfg.Pruning, ndb, cfg.InitialHeight, snapshotDir, s.GetEpoch)
+	if err != nil {
+		cancelCtx()
+		return nil, fmt.Errorf("state: failed to create pruner: %w", err)
+	}
+
 	// Refresh consensus parameters when loading state if we are past genesis.
 	if latestVersion >= s.initialHeight {
 		if err = s.doCommitOrInitChainLocked(); err != nil {
@@ -92,6 +92,26 @@ type ApplicationQueryState interface {
 	// LastRetainedVersion returns the earliest retained version the ABCI
 	// state.
 	LastRetainedVersion() (int64, error)
+
+	// GetStateSnapshot returns the state snapshot retained by the pruner at
+	// the given height together with the node database holding it.
+	//
+	// In case no snapshot is retained at the given height, ErrVersionNotFound
+	// is returned.
+	GetStateSnapshot(height int64) (*StateSnapshot, storage.NodeDB, error)
+}
+
+// StateSnapshot is a copy of the state at an epoch boundary retained by the
+// state pruner after the version itself has been pruned.
+type StateSnapshot struct {
+	// Height is the block height of the snapshot.
+	Height int64 `json:"height"`
+	// Epoch is the epoch at the snapshot height.
+	Epoch beacon.EpochTime `json:"epoch"`
+	// Time is the time of the block at the snapshot height.
+	Time time.Time `json:"time"`
+	// Root is the state root of the snapshot.
+	Root storage.Root `json:"root"`
 }
 
 // MockApplicationState is the mock application state interface.
@@ -179,6 +199,10 @@ func (ms *mockApplicationState) LastRetainedVersion() (int64, error) {
 	return ms.cfg.Genesis.Height, nil
 }
 
+func (ms *mockApplicationState) GetStateSnapshot(int64) (*StateSnapshot, storage.NodeDB, error) {
+	return nil, nil, consensus.ErrVersionNotFound
+}
+
 func (ms *mockApplicationState) GetCurrentEpoch(context.Context) (beacon.EpochTime, error) {
 	return ms.cfg.CurrentEpoch, nil
 }
@@ -290,8 +314,13 @@ func NewImmutableStateAt(ctx context.Context, state ApplicationQueryState, versi
 	}
 	switch len(roots) {
 	case 0:
-		// No roots for that state -- it may have been pruned.
-		return nil, consensus.ErrVersionNotFound
+		// No roots for that state -- it may have been pruned, but a snapshot may be retained.
+		snapshot, sndb, err := state.GetStateSnapshot(version)
+		if err != nil {
+			return nil, consensus.ErrVersionNotFound
+		}
+		tree := mkvs.NewWithRoot(nil, sndb, snapshot.Root, mkvs.WithoutWriteLog())
+		return &ImmutableState{tree}, nil
 	case 1:
 		// A single root.
 	default:
@@ -317,6 +317,21 @@ func (app *Application) executeProposal(ctx *api.Context, state *governanceState
 			ctx.Logger().Debug("governance: no module applied change parameters proposal")
 			return governance.ErrInvalidArgument
 		}
+	case proposal.Content.Text != nil:
+		// To not violate the consensus, text proposals should be ignored when disabled.
+		params, err := state.ConsensusParameters(ctx)
+		if err != nil {
+			ctx.Logger().Error("failed to query consensus parameters",
+				"err", err,
+			)
+			return governance.ErrInvalidArgument
+		}
+		if !params.EnableTextProposal {
+			ctx.Logger().Debug("text proposals are disabled")
+			return governance.ErrInvalidArgument
+		}
+
+		// Text proposals are non-binding, passing them has no effect.
 	default:
 		return governance.ErrInvalidArgument
 	}
@@ -393,94 +408,21 @@ func (app *Application) closeProposal(
 		"validator_entities_pool", validatorEntitiesPool,
 		"votes", votes,
 	)
-	validatorVotes := make(map[stakingAPI.Address]*governance.Vote)
-	validatorVoteShares := make(map[stakingAPI.Address]map[governance.Vote]quantity.Quantity)
-	for validator := range validatorEntitiesPool {
-		validatorVoteShares[validator] = make(map[governance.Vote]quantity.Quantity)
-	}
-
-	// Tally the validator votes.
-	for _, vote := range votes {
-		escrow, ok := validatorEntitiesPool[vote.Voter]
-		if !ok {
-			// Skip non-validator votes.
-			continue
-		}
-		validatorVotes[vote.Voter] = &vote.Vote //nolint:gosec
-		if err = addShares(validatorVoteShares[vote.Voter], vote.Vote, escrow.TotalShares); err != nil {
-			return fmt.Errorf("failed to add shares: %w", err)
-		}
-	}
-
-	// Tally delegator votes.
-	for _, vote := range votes {
-		// Fetch outgoing delegations.
-		delegations, err := stakingState.DelegationsFor(ctx, vote.Voter)
-		if err != nil {
-			ctx.Logger().Error("failed to fetch delegations for",
-				"delegator", vote.Voter,
-				"err", err,
-			)
-			return fmt.Errorf("failed to fetch delegations: %w", err)
-		}
-		var delegationToValidator bool
-		for to, delegation := range delegations {
-			if _, ok := validatorEntitiesPool[to]; !ok {
-				continue
-			}
-			delegationToValidator = true
-			validatorVote := validatorVotes[to]
-
-			// Skip if vote matches the delegated validator vote.
-			if validatorVote != nil && *validatorVote == vote.Vote {
-				continue
-			}
-
-			// Deduct shares from the validators shares.
-			if validatorVote != nil {
-				if err := subShares(validatorVoteShares[to], *validatorVote, delegation.Shares); err != nil {
-					return fmt.Errorf("failed to sub votes: %w", err)
-				}
-			}
-
-			// Add shares to the voters vote.
-			if err := addShares(validatorVoteShares[to], vote.Vote, delegation.Shares); err != nil {
-				return fmt.Errorf("failed to add votes: %w", err)
-			}
-		}
-		if !delegationToValidator {
-			proposal.InvalidVotes++
-		}
+	tally, err := tallyVotes(ctx, stakingState, validatorEntitiesPool, votes)
+	if err != nil {
+		ctx.Logger().Error("failed to tally votes",
+			"err", err,
+		)
+		return err
 	}
+	proposal.InvalidVotes += tally.invalidVotes
 
 	// Finalize the voting results - convert votes in shares into results in stake.
-	proposal.Results = make(map[governance.Vote]quantity.Quantity)
-	for validator, votes := range validatorVoteShares {
-		validatorPool, ok := validatorEntitiesPool[validator]
-		if !ok {
-			// This should NEVER happen.
-			panic("governance: missing validator pool")
-		}
-		for vote, shares := range votes {
-			// Compute stake from shares.
-			escrow, err := validatorPool.StakeForShares(shares.Clone())
-			if err != nil {
-				ctx.Logger().Error("failed to compute stake from shares for",
-					"share_pool", validatorPool,
-					"validator", validator,
-					"err", err,
-				)
-				return fmt.Errorf("failed to compute stake from shares: %w", err)
-
-			}
-
-			// Add stake to vote.
-			currentVotes := proposal.Results[vote]
-			if err := currentVotes.Add(escrow); err != nil {
-				return fmt.Errorf("failed to add votes: %w", err)
-			}
-			proposal.Results[vote] = currentVotes
-		}
+	if proposal.Results, err = tally.results(validatorEntitiesPool); err != nil {
+		ctx.Logger().Error("failed to compute voting results",
+			"err", err,
+		)
+		return err
 	}
 
 	ctx.Logger().Debug("close proposal",
@@ -492,26 +434,6 @@ func (app *Application) closeProposal(
 	return proposal.CloseProposal(totalVotingStake, params.StakeThreshold)
 }
 
-func addShares(validatorVoteShares map[governance.Vote]quantity.Quantity, vote governance.Vote, amount quantity.Quantity) error {
-	amt := amount.Clone()
-	currShares := validatorVoteShares[vote]
-	if err := amt.Add(&currShares); err != nil {
-		return fmt.Errorf("failed to add votes: %w", err)
-	}
-	validatorVoteShares[vote] = *amt
-	return nil
-}
-
-func subShares(validatorVoteShares map[governance.Vote]quantity.Quantity, vote governance.Vote, amount quantity.Quantity) error {
-	amt := amount.Clone()
-	currShares := validatorVoteShares[vote]
-	if err := currShares.Sub(amt); err != nil {
-		return fmt.Errorf("failed to sub votes: %w", err)
-	}
-	validatorVoteShares[vote] = currShares
-	return nil
-}
-
 // EndBlock implements api.Application.
 func (app *Application) EndBlock(ctx *api.Context) (types.ResponseEndBlock, error) {
 	// Check if epoch has changed.
@@ -10,6 +10,7 @@ import (
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
@@ -464,6 +465,11 @@ func TestExecuteProposal(t *testing.T) {
 			Epoch:     20,
 		},
 	}
+	textProposal := &governance.TextProposal{
+		Title:       "Signaling proposal",
+		ContentHash: hash.NewFromBytes([]byte("off-chain content")),
+		URI:         "https://example.com/proposal.md",
+	}
 	defaultAtEpoch := func(epoch beacon.EpochTime) *governance.UpgradeProposal {
 		proposal := defaultUpgradeProposal
 		proposal.Descriptor.Epoch = epoch
@@ -600,6 +606,14 @@ func TestExecuteProposal(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			"executing text proposal should fail when text proposals are disabled",
+			&governance.Proposal{
+				ID:      13,
+				Content: governance.ProposalContent{Text: textProposal},
+			},
+			governance.ErrInvalidArgument,
+		},
 	} {
 		err = app.executeProposal(ctx, state, tc.proposal)
 		if tc.err != nil {
@@ -618,6 +632,21 @@ func TestExecuteProposal(t *testing.T) {
 		err = state.SetProposal(ctx, tc.proposal)
 		require.NoError(err, "SetProposal")
 	}
+
+	// Enable text proposals.
+	params, err := state.ConsensusParameters(ctx)
+	require.NoError(err, "ConsensusParameters")
+	params.EnableTextProposal = true
+	err = state.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	proposal := &governance.Proposal{
+		ID:      14,
+		Content: governance.ProposalContent{Text: textProposal},
+	}
+	err = app.executeProposal(ctx, state, proposal)
+	require.NoError(err, "executing text proposal should work when text proposals are enabled")
+	require.Equal(governance.StatePassed, proposal.State)
 }
 
 func TestBeginBlock(t *testing.T) {
@@ -5,7 +5,10 @@ import (
 
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	governanceState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/governance/state"
+	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/scheduler/state"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
@@ -15,6 +18,8 @@ type Query interface {
 	Proposals(context.Context) ([]*governance.Proposal, error)
 	Proposal(context.Context, uint64) (*governance.Proposal, error)
 	Votes(context.Context, uint64) ([]*governance.VoteEntry, error)
+	VoteBreakdown(context.Context, uint64) (*governance.VoteBreakdown, error)
+	DelegatorVotes(context.Context, uint64, staking.Address) ([]*governance.DelegatorVote, error)
 	PendingUpgrades(context.Context) ([]*upgrade.Descriptor, error)
 	Genesis(context.Context) (*governance.Genesis, error)
 	ConsensusParameters(context.Context) (*governance.ConsensusParameters, error)
@@ -32,12 +37,16 @@ func (f *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error)
 		return nil, err
 	}
 	return &governanceQuerier{
-		state: governanceState.NewImmutableState(state),
+		state:          governanceState.NewImmutableState(state),
+		stakingState:   stakingState.NewImmutableState(state),
+		schedulerState: schedulerState.NewImmutableState(state),
 	}, nil
 }
 
 type governanceQuerier struct {
-	state *governanceState.ImmutableState
+	state          *governanceState.ImmutableState
+	stakingState   *stakingState.ImmutableState
+	schedulerState *schedulerState.ImmutableState
 }
 
 func (q *governanceQuerier) ActiveProposals(ctx context.Context) ([]*governance.Proposal, error) {
@@ -56,6 +65,55 @@ func (q *governanceQuerier) Votes(ctx context.Context, id uint64) ([]*governance
 	return q.state.Votes(ctx, id)
 }
 
+func (q *governanceQuerier) VoteBreakdown(ctx context.Context, id uint64) (*governance.VoteBreakdown, error) {
+	// Make sure the proposal exists.
+	proposal, err := q.state.Proposal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := q.state.Votes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	totalVotingStake, validatorEntitiesEscrow, err := validatorsEscrow(ctx, q.stakingState, q.schedulerState)
+	if err != nil {
+		return nil, err
+	}
+	tally, err := tallyVotes(ctx, q.stakingState, validatorEntitiesEscrow, votes)
+	if err != nil {
+		return nil, err
+	}
+	vb, err := tally.breakdown(ctx, q.stakingState, totalVotingStake, validatorEntitiesEscrow, votes)
+	if err != nil {
+		return nil, err
+	}
+
+	// The validator set and escrow may have changed since a proposal has been closed, so use the
+	// final results recorded at close time.
+	if proposal.State != governance.StateActive {
+		vb.Closed = true
+		vb.Results = proposal.Results
+		vb.InvalidVotes = proposal.InvalidVotes
+	}
+	return vb, nil
+}
+
+func (q *governanceQuerier) DelegatorVotes(ctx context.Context, id uint64, delegator staking.Address) ([]*governance.DelegatorVote, error) {
+	// Make sure the proposal exists.
+	if _, err := q.state.Proposal(ctx, id); err != nil {
+		return nil, err
+	}
+	votes, err := q.state.Votes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, validatorEntitiesEscrow, err := validatorsEscrow(ctx, q.stakingState, q.schedulerState)
+	if err != nil {
+		return nil, err
+	}
+	return delegatorVotes(ctx, q.stakingState, validatorEntitiesEscrow, votes, delegator)
+}
+
 func (q *governanceQuerier) PendingUpgrades(ctx context.Context) ([]*upgrade.Descriptor, error) {
 	return q.state.PendingUpgrades(ctx)
 }
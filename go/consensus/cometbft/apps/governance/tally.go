@@ -0,0 +1,294 @@
+package governance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	stakingAPI "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// voteTally is the result of tallying the votes of a proposal.
+type voteTally struct {
+	// validatorVotes are the votes of validator entities.
+	validatorVotes map[stakingAPI.Address]*governance.Vote
+	// validatorVoteShares are the escrow shares of each validator entity that count towards each
+	// vote.
+	validatorVoteShares map[stakingAPI.Address]map[governance.Vote]quantity.Quantity
+	// invalidVotes is the number of votes cast by accounts that are neither validators nor
+	// delegate to any validator.
+	invalidVotes uint64
+}
+
+// tallyVotes tallies the given votes. Validator entities vote with all of their escrow shares,
+// unless a delegator has voted itself, in which case its shares count towards its own vote.
+func tallyVotes(
+	ctx context.Context,
+	stakingState *stakingState.ImmutableState,
+	validatorEntitiesPool map[stakingAPI.Address]*stakingAPI.SharePool,
+	votes []*governance.VoteEntry,
+) (*voteTally, error) {
+	t := &voteTally{
+		validatorVotes:      make(map[stakingAPI.Address]*governance.Vote),
+		validatorVoteShares: make(map[stakingAPI.Address]map[governance.Vote]quantity.Quantity),
+	}
+	for validator := range validatorEntitiesPool {
+		t.validatorVoteShares[validator] = make(map[governance.Vote]quantity.Quantity)
+	}
+
+	// Tally the validator votes.
+	for _, vote := range votes {
+		escrow, ok := validatorEntitiesPool[vote.Voter]
+		if !ok {
+			// Skip non-validator votes.
+			continue
+		}
+		t.validatorVotes[vote.Voter] = &vote.Vote //nolint:gosec
+		if err := addShares(t.validatorVoteShares[vote.Voter], vote.Vote, escrow.TotalShares); err != nil {
+			return nil, fmt.Errorf("failed to add shares: %w", err)
+		}
+	}
+
+	// Tally delegator votes.
+	for _, vote := range votes {
+		// Fetch outgoing delegations.
+		delegations, err := stakingState.DelegationsFor(ctx, vote.Voter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch delegations: %w", err)
+		}
+		var delegationToValidator bool
+		for to, delegation := range delegations {
+			if _, ok := validatorEntitiesPool[to]; !ok {
+				continue
+			}
+			delegationToValidator = true
+
+			validatorVote := t.validatorVotes[to]
+
+			// Skip if vote matches the delegated validator vote.
+			if validatorVote != nil && *validatorVote == vote.Vote {
+				continue
+			}
+
+			// Deduct shares from the validators shares.
+			if validatorVote != nil {
+				if err := subShares(t.validatorVoteShares[to], *validatorVote, delegation.Shares); err != nil {
+					return nil, fmt.Errorf("failed to sub votes: %w", err)
+				}
+			}
+
+			// Add shares to the voters vote.
+			if err := addShares(t.validatorVoteShares[to], vote.Vote, delegation.Shares); err != nil {
+				return nil, fmt.Errorf("failed to add votes: %w", err)
+			}
+		}
+		if !delegationToValidator {
+			t.invalidVotes++
+		}
+	}
+
+	return t, nil
+}
+
+// validatorResults converts the escrow shares of the given validator entity into stake.
+func (t *voteTally) validatorResults(
+	validator stakingAPI.Address,
+	validatorPool *stakingAPI.SharePool,
+) (map[governance.Vote]quantity.Quantity, error) {
+	results := make(map[governance.Vote]quantity.Quantity)
+	for vote, shares := range t.validatorVoteShares[validator] {
+		// Compute stake from shares.
+		escrow, err := validatorPool.StakeForShares(shares.Clone())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute stake from shares: %w", err)
+		}
+		results[vote] = *escrow
+	}
+	return results, nil
+}
+
+// results converts the tallied escrow shares into stake.
+func (t *voteTally) results(
+	validatorEntitiesPool map[stakingAPI.Address]*stakingAPI.SharePool,
+) (map[governance.Vote]quantity.Quantity, error) {
+	results := make(map[governance.Vote]quantity.Quantity)
+	for validator := range t.validatorVoteShares {
+		validatorPool, ok := validatorEntitiesPool[validator]
+		if !ok {
+			// This should NEVER happen.
+			panic("governance: missing validator pool")
+		}
+		validatorResults, err := t.validatorResults(validator, validatorPool)
+		if err != nil {
+			return nil, err
+		}
+		for vote, escrow := range validatorResults {
+			// Add stake to vote.
+			currentVotes := results[vote]
+			if err := currentVotes.Add(&escrow); err != nil {
+				return nil, fmt.Errorf("failed to add votes: %w", err)
+			}
+			results[vote] = currentVotes
+		}
+	}
+	return results, nil
+}
+
+// breakdown returns the per-validator breakdown of the tallied votes.
+//
+// As computing the breakdown is expensive, it should only be used when querying.
+func (t *voteTally) breakdown(
+	ctx context.Context,
+	stakingState *stakingState.ImmutableState,
+	totalVotingStake *quantity.Quantity,
+	validatorEntitiesPool map[stakingAPI.Address]*stakingAPI.SharePool,
+	votes []*governance.VoteEntry,
+) (*governance.VoteBreakdown, error) {
+	results, err := t.results(validatorEntitiesPool)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := delegatorOverrides(ctx, stakingState, validatorEntitiesPool, votes)
+	if err != nil {
+		return nil, err
+	}
+
+	vb := &governance.VoteBreakdown{
+		TotalVotingStake: *totalVotingStake.Clone(),
+		Results:          results,
+		InvalidVotes:     t.invalidVotes,
+	}
+	for validator, validatorPool := range validatorEntitiesPool {
+		validatorResults, err := t.validatorResults(validator, validatorPool)
+		if err != nil {
+			return nil, err
+		}
+		vb.Validators = append(vb.Validators, &governance.ValidatorVoteBreakdown{
+			Validator: validator,
+			Vote:      t.validatorVotes[validator],
+			Stake:     validatorPool.Balance,
+			Results:   validatorResults,
+			Overrides: overrides[validator],
+		})
+	}
+	slices.SortFunc(vb.Validators, func(a, b *governance.ValidatorVoteBreakdown) int {
+		return bytes.Compare(a.Validator[:], b.Validator[:])
+	})
+
+	return vb, nil
+}
+
+// delegatorOverrides returns the votes of delegators that override the votes of the validator
+// entities they delegate to, grouped by validator entity.
+func delegatorOverrides(
+	ctx context.Context,
+	stakingState *stakingState.ImmutableState,
+	validatorEntitiesPool map[stakingAPI.Address]*stakingAPI.SharePool,
+	votes []*governance.VoteEntry,
+) (map[stakingAPI.Address][]*governance.DelegatorVote, error) {
+	overrides := make(map[stakingAPI.Address][]*governance.DelegatorVote)
+	for _, vote := range votes {
+		delegations, err := stakingState.DelegationsFor(ctx, vote.Voter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch delegations: %w", err)
+		}
+		for to, delegation := range delegations {
+			pool, ok := validatorEntitiesPool[to]
+			if !ok || vote.Voter.Equal(to) {
+				// Skip non-validators and validator self-delegations.
+				continue
+			}
+			stake, err := pool.StakeForShares(delegation.Shares.Clone())
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute stake from shares: %w", err)
+			}
+			overrides[to] = append(overrides[to], &governance.DelegatorVote{
+				Delegator: vote.Voter,
+				Validator: to,
+				Vote:      &vote.Vote,
+				Override:  true,
+				Shares:    delegation.Shares,
+				Stake:     *stake,
+			})
+		}
+	}
+	return overrides, nil
+}
+
+// delegatorVotes computes the effective votes of the given delegator, one for each validator entity
+// that it delegates to.
+func delegatorVotes(
+	ctx context.Context,
+	stakingState *stakingState.ImmutableState,
+	validatorEntitiesPool map[stakingAPI.Address]*stakingAPI.SharePool,
+	votes []*governance.VoteEntry,
+	delegator stakingAPI.Address,
+) ([]*governance.DelegatorVote, error) {
+	votesByVoter := make(map[stakingAPI.Address]*governance.Vote, len(votes))
+	for _, vote := range votes {
+		votesByVoter[vote.Voter] = &vote.Vote
+	}
+
+	delegations, err := stakingState.DelegationsFor(ctx, delegator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch delegations: %w", err)
+	}
+
+	var dvs []*governance.DelegatorVote
+	for to, delegation := range delegations {
+		pool, ok := validatorEntitiesPool[to]
+		if !ok {
+			continue
+		}
+		stake, err := pool.StakeForShares(delegation.Shares.Clone())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute stake from shares: %w", err)
+		}
+
+		dv := &governance.DelegatorVote{
+			Delegator: delegator,
+			Validator: to,
+			Shares:    delegation.Shares,
+			Stake:     *stake,
+		}
+		switch vote, ok := votesByVoter[delegator]; ok {
+		case true:
+			// Delegator has voted, overriding the validator vote.
+			dv.Vote = vote
+			dv.Override = true
+		case false:
+			// Delegator inherits the validator vote (if any).
+			dv.Vote = votesByVoter[to]
+		}
+		dvs = append(dvs, dv)
+	}
+	slices.SortFunc(dvs, func(a, b *governance.DelegatorVote) int {
+		return bytes.Compare(a.Validator[:], b.Validator[:])
+	})
+
+	return dvs, nil
+}
+
+func addShares(validatorVoteShares map[governance.Vote]quantity.Quantity, vote governance.Vote, amount quantity.Quantity) error {
+	amt := amount.Clone()
+	currShares := validatorVoteShares[vote]
+	if err := amt.Add(&currShares); err != nil {
+		return fmt.Errorf("failed to add votes: %w", err)
+	}
+	validatorVoteShares[vote] = *amt
+	return nil
+}
+
+func subShares(validatorVoteShares map[governance.Vote]quantity.Quantity, vote governance.Vote, amount quantity.Quantity) error {
+	amt := amount.Clone()
+	currShares := validatorVoteShares[vote]
+	if err := currShares.Sub(amt); err != nil {
+		return fmt.Errorf("failed to sub votes: %w", err)
+	}
+	validatorVoteShares[vote] = currShares
+	return nil
+}
@@ -0,0 +1,139 @@
+package governance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestVoteBreakdown(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	validator1 := staking.NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	validator2 := staking.NewAddress(signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	delegator1 := staking.NewAddress(signature.NewPublicKey("cccfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	delegator2 := staking.NewAddress(signature.NewPublicKey("dddfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	delegator3 := staking.NewAddress(signature.NewPublicKey("eeefffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	nonDelegator := staking.NewAddress(signature.NewPublicKey("fffaffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+
+	validatorEntitiesPool := map[staking.Address]*staking.SharePool{
+		validator1: {
+			Balance:     *quantity.NewFromUint64(400),
+			TotalShares: *quantity.NewFromUint64(400),
+		},
+		validator2: {
+			Balance:     *quantity.NewFromUint64(200),
+			TotalShares: *quantity.NewFromUint64(100),
+		},
+	}
+	totalVotingStake := quantity.NewFromUint64(600)
+
+	for _, d := range []struct {
+		from   staking.Address
+		to     staking.Address
+		shares uint64
+	}{
+		{validator1, validator1, 100},
+		{delegator1, validator1, 100},
+		{delegator2, validator1, 100},
+		{delegator3, validator1, 100},
+		{validator2, validator2, 50},
+		{delegator2, validator2, 50},
+	} {
+		err := stakeState.SetDelegation(ctx, d.from, d.to, &staking.Delegation{
+			Shares: *quantity.NewFromUint64(d.shares),
+		})
+		require.NoError(err, "SetDelegation")
+	}
+
+	votes := []*governance.VoteEntry{
+		{Voter: validator1, Vote: governance.VoteYes},
+		{Voter: delegator1, Vote: governance.VoteNo},
+		{Voter: delegator2, Vote: governance.VoteAbstain},
+		{Voter: nonDelegator, Vote: governance.VoteYes},
+	}
+
+	tally, err := tallyVotes(ctx, stakeState.ImmutableState, validatorEntitiesPool, votes)
+	require.NoError(err, "tallyVotes")
+	require.EqualValues(1, tally.invalidVotes, "votes without delegations should be invalid")
+
+	results, err := tally.results(validatorEntitiesPool)
+	require.NoError(err, "results")
+	require.EqualValues(map[governance.Vote]quantity.Quantity{
+		governance.VoteYes:     *quantity.NewFromUint64(200),
+		governance.VoteNo:      *quantity.NewFromUint64(100),
+		governance.VoteAbstain: *quantity.NewFromUint64(200),
+	}, results)
+
+	vb, err := tally.breakdown(ctx, stakeState.ImmutableState, totalVotingStake, validatorEntitiesPool, votes)
+	require.NoError(err, "breakdown")
+	require.EqualValues(*totalVotingStake, vb.TotalVotingStake)
+	require.EqualValues(results, vb.Results)
+	require.EqualValues(1, vb.InvalidVotes)
+	require.Len(vb.Validators, 2)
+
+	byValidator := make(map[staking.Address]*governance.ValidatorVoteBreakdown)
+	for _, v := range vb.Validators {
+		byValidator[v.Validator] = v
+	}
+
+	v1 := byValidator[validator1]
+	require.NotNil(v1)
+	require.EqualValues(governance.VoteYes, *v1.Vote)
+	require.EqualValues(map[governance.Vote]quantity.Quantity{
+		governance.VoteYes:     *quantity.NewFromUint64(200),
+		governance.VoteNo:      *quantity.NewFromUint64(100),
+		governance.VoteAbstain: *quantity.NewFromUint64(100),
+	}, v1.Results)
+	require.Len(v1.Overrides, 2, "validator self-delegation should not be an override")
+	require.EqualValues(delegator1, v1.Overrides[0].Delegator)
+	require.EqualValues(governance.VoteNo, *v1.Overrides[0].Vote)
+	require.EqualValues(*quantity.NewFromUint64(100), v1.Overrides[0].Stake)
+
+	v2 := byValidator[validator2]
+	require.NotNil(v2)
+	require.Nil(v2.Vote, "validator without a vote")
+	require.EqualValues(map[governance.Vote]quantity.Quantity{
+		governance.VoteAbstain: *quantity.NewFromUint64(100),
+	}, v2.Results)
+	require.Len(v2.Overrides, 1)
+	require.EqualValues(delegator2, v2.Overrides[0].Delegator)
+	require.EqualValues(*quantity.NewFromUint64(50), v2.Overrides[0].Shares)
+	require.EqualValues(*quantity.NewFromUint64(100), v2.Overrides[0].Stake)
+
+	// Delegator that has voted.
+	dvs, err := delegatorVotes(ctx, stakeState.ImmutableState, validatorEntitiesPool, votes, delegator2)
+	require.NoError(err, "delegatorVotes")
+	require.Len(dvs, 2)
+	for _, dv := range dvs {
+		require.True(dv.Override)
+		require.EqualValues(governance.VoteAbstain, *dv.Vote)
+	}
+
+	// Delegator that has not voted inherits the validator vote.
+	dvs, err = delegatorVotes(ctx, stakeState.ImmutableState, validatorEntitiesPool, votes, delegator3)
+	require.NoError(err, "delegatorVotes")
+	require.Len(dvs, 1)
+	require.False(dvs[0].Override)
+	require.EqualValues(validator1, dvs[0].Validator)
+	require.EqualValues(governance.VoteYes, *dvs[0].Vote)
+	require.EqualValues(*quantity.NewFromUint64(100), dvs[0].Stake)
+
+	// Account without delegations.
+	dvs, err = delegatorVotes(ctx, stakeState.ImmutableState, validatorEntitiesPool, votes, nonDelegator)
+	require.NoError(err, "delegatorVotes")
+	require.Empty(dvs)
+}
@@ -49,6 +49,11 @@ func (app *Application) submitProposal(
 		return nil, governance.ErrInvalidArgument
 	}
 
+	// To not violate the consensus, text proposals should be ignored when disabled.
+	if proposalContent.Text != nil && !params.EnableTextProposal {
+		return nil, governance.ErrInvalidArgument
+	}
+
 	// Charge gas for this transaction.
 	if err = ctx.Gas().UseGas(1, governance.GasOpSubmitProposal, params.GasCosts); err != nil {
 		return nil, err
@@ -156,6 +161,8 @@ func (app *Application) submitProposal(
 			ctx.Logger().Debug("governance: no module interested in change parameters proposal")
 			return nil, governance.ErrInvalidArgument
 		}
+	case proposalContent.Text != nil:
+		// Text proposals are non-binding so there is nothing else to validate.
 	default:
 		return nil, governance.ErrInvalidArgument
 	}
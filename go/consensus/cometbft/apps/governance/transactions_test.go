@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
@@ -61,6 +62,15 @@ func TestSubmitProposal(t *testing.T) {
 	allowPropMetaParams := *baseConsParams
 	allowPropMetaParams.AllowProposalMetadata = true
 
+	enableTextPropParams := *baseConsParams
+	enableTextPropParams.EnableTextProposal = true
+
+	textProposal := &governance.TextProposal{
+		Title:       "Signaling proposal",
+		ContentHash: hash.NewFromBytes([]byte("off-chain content")),
+		URI:         "https://example.com/proposal.md",
+	}
+
 	for _, tc := range []struct {
 		msg             string
 		params          *governance.ConsensusParameters
@@ -256,6 +266,30 @@ func TestSubmitProposal(t *testing.T) {
 			},
 			governance.ErrUpgradeAlreadyPending,
 		},
+		{
+			"should fail text proposal when text proposals are disabled",
+			baseConsParams,
+			pk1,
+			&governance.ProposalContent{Text: textProposal},
+			func() {},
+			governance.ErrInvalidArgument,
+		},
+		{
+			"should fail with invalid text proposal",
+			&enableTextPropParams,
+			pk1,
+			&governance.ProposalContent{Text: &governance.TextProposal{Title: "Signaling proposal"}},
+			func() {},
+			governance.ErrInvalidArgument,
+		},
+		{
+			"should work with valid text proposal when text proposals are enabled",
+			&enableTextPropParams,
+			pk1,
+			&governance.ProposalContent{Text: textProposal},
+			func() {},
+			nil,
+		},
 	} {
 		err = state.SetConsensusParameters(ctx, tc.params)
 		require.NoError(err, "setting governance consensus parameters should not error")
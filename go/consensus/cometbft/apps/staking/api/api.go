@@ -12,7 +12,8 @@ type messageKind uint8
 // implements the AccountHookInvocation interface.
 //
 // Only the module that can handle the given destination should respond. The response value
-// depends on the hook.
+// depends on the hook. Unless noted otherwise, a non-nil response signals that the operation is
+// allowed and an error signals that the operation should be rejected.
 var MessageAccountHook = messageKind(0)
 
 // AccountHookInvocation is the message body for dispatching account hooks.
@@ -45,3 +46,91 @@ func (wh *WithdrawHookInvocation) Kind() staking.HookKind {
 func (wh *WithdrawHookInvocation) DestinationMatches(dst staking.HookDestination) bool {
 	return wh.Destination == dst
 }
+
+// IncomingTransferHookInvocation is the message body for HookKindIncomingTransfer hook dispatch.
+type IncomingTransferHookInvocation struct {
+	// Destination is the configured hook destination.
+	Destination staking.HookDestination
+	// From is the address of the account that is attempting the transfer.
+	From staking.Address
+	// To is the address of the destination account.
+	To staking.Address
+	// Amount is the amount that is being transferred.
+	Amount *quantity.Quantity
+}
+
+// Kind is the invoked hook kind.
+func (ih *IncomingTransferHookInvocation) Kind() staking.HookKind {
+	return staking.HookKindIncomingTransfer
+}
+
+// DestinationMatches checks whether the destination matches the given destination.
+func (ih *IncomingTransferHookInvocation) DestinationMatches(dst staking.HookDestination) bool {
+	return ih.Destination == dst
+}
+
+// AddEscrowHookInvocation is the message body for HookKindAddEscrow hook dispatch.
+type AddEscrowHookInvocation struct {
+	// Destination is the configured hook destination.
+	Destination staking.HookDestination
+	// Owner is the address of the account that is attempting to delegate.
+	Owner staking.Address
+	// Escrow is the address of the escrow account.
+	Escrow staking.Address
+	// Amount is the amount that is being delegated.
+	Amount *quantity.Quantity
+}
+
+// Kind is the invoked hook kind.
+func (ah *AddEscrowHookInvocation) Kind() staking.HookKind {
+	return staking.HookKindAddEscrow
+}
+
+// DestinationMatches checks whether the destination matches the given destination.
+func (ah *AddEscrowHookInvocation) DestinationMatches(dst staking.HookDestination) bool {
+	return ah.Destination == dst
+}
+
+// ReclaimEscrowHookInvocation is the message body for HookKindReclaimEscrow hook dispatch.
+type ReclaimEscrowHookInvocation struct {
+	// Destination is the configured hook destination.
+	Destination staking.HookDestination
+	// Owner is the address of the account that is attempting to reclaim.
+	Owner staking.Address
+	// Escrow is the address of the escrow account.
+	Escrow staking.Address
+	// Shares is the number of active shares that are being reclaimed.
+	Shares *quantity.Quantity
+}
+
+// Kind is the invoked hook kind.
+func (rh *ReclaimEscrowHookInvocation) Kind() staking.HookKind {
+	return staking.HookKindReclaimEscrow
+}
+
+// DestinationMatches checks whether the destination matches the given destination.
+func (rh *ReclaimEscrowHookInvocation) DestinationMatches(dst staking.HookDestination) bool {
+	return rh.Destination == dst
+}
+
+// AllowanceChangeHookInvocation is the message body for HookKindAllowanceChange hook dispatch.
+type AllowanceChangeHookInvocation struct {
+	// Destination is the configured hook destination.
+	Destination staking.HookDestination
+	// Owner is the address of the account that is changing the allowance.
+	Owner staking.Address
+	// Beneficiary is the address of the beneficiary account.
+	Beneficiary staking.Address
+	// Allowance is the new allowance.
+	Allowance *quantity.Quantity
+}
+
+// Kind is the invoked hook kind.
+func (ah *AllowanceChangeHookInvocation) Kind() staking.HookKind {
+	return staking.HookKindAllowanceChange
+}
+
+// DestinationMatches checks whether the destination matches the given destination.
+func (ah *AllowanceChangeHookInvocation) DestinationMatches(dst staking.HookDestination) bool {
+	return ah.Destination == dst
+}
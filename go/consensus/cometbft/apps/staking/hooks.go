@@ -0,0 +1,39 @@
+package staking
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	stakingApi "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// accountHook returns the destination of the hook of the given kind in case one is configured for
+// the given account and hooks of that kind are enabled.
+func accountHook(
+	params *staking.ConsensusParameters,
+	acct *staking.Account,
+	kind staking.HookKind,
+) (staking.HookDestination, bool) {
+	if !params.IsHookKindEnabled(kind) {
+		return staking.HookDestination{}, false
+	}
+	dst, ok := acct.General.Hooks[kind]
+	return dst, ok
+}
+
+// invokeAccountHook dispatches the given account hook invocation and returns an error in case the
+// hook destination rejected the operation or failed to handle it.
+//
+// NOTE: Since hooks may modify state, the caller should invoke this in a transaction context. Hooks
+// must not modify the staking accounts involved in the operation.
+func (app *Application) invokeAccountHook(ctx *api.Context, ahi stakingApi.AccountHookInvocation) error {
+	res, err := app.md.Publish(ctx, stakingApi.MessageAccountHook, ahi)
+	if err != nil {
+		return fmt.Errorf("%w: %s hook invocation failed: %w", staking.ErrForbidden, ahi.Kind(), err)
+	}
+	if res == nil {
+		return fmt.Errorf("%w: %s hook not handled", staking.ErrForbidden, ahi.Kind())
+	}
+	return nil
+}
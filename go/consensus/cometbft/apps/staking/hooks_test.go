@@ -0,0 +1,217 @@
+package staking
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	stakingApi "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/api"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+type testHookDispatcher struct {
+	invocations []stakingApi.AccountHookInvocation
+	reject      bool
+}
+
+func (d *testHookDispatcher) Subscribe(any, abciAPI.MessageSubscriber) {
+}
+
+func (d *testHookDispatcher) Publish(_ *abciAPI.Context, kind, msg any) (any, error) {
+	if kind != stakingApi.MessageAccountHook {
+		return nil, abciAPI.ErrNoSubscribers
+	}
+	d.invocations = append(d.invocations, msg.(stakingApi.AccountHookInvocation))
+	if d.reject {
+		return nil, fmt.Errorf("rejected")
+	}
+	return struct{}{}, nil
+}
+
+func TestAccountHooks(t *testing.T) {
+	require := require.New(t)
+	var err error
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	md := &testHookDispatcher{}
+	app := &Application{
+		state: appState,
+		md:    md,
+	}
+
+	pk1 := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr1 := staking.NewAddress(pk1)
+	pk2 := signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr2 := staking.NewAddress(pk2)
+
+	require.NoError(stakeState.SetTotalSupply(ctx, quantity.NewFromUint64(200_000)), "SetTotalSupply")
+
+	err = stakeState.SetAccount(ctx, addr1, &staking.Account{
+		General: staking.GeneralAccount{
+			Balance: *quantity.NewFromUint64(100_000),
+		},
+	})
+	require.NoError(err, "SetAccount1")
+
+	err = stakeState.SetAccount(ctx, addr2, &staking.Account{
+		General: staking.GeneralAccount{
+			Balance: *quantity.NewFromUint64(100_000),
+		},
+	})
+	require.NoError(err, "SetAccount2")
+
+	dst := staking.HookDestination{Module: "test"}
+	for _, kind := range staking.HookKinds {
+		err = stakeState.SetAccountHook(ctx, addr2, kind, &dst)
+		require.NoError(err, "SetAccountHook")
+	}
+
+	params := &staking.ConsensusParameters{
+		MaxAllowances: 16,
+	}
+	err = stakeState.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	newTxCtx := func(signer signature.PublicKey) *abciAPI.Context {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+		txCtx.SetTxSigner(signer)
+		return txCtx
+	}
+
+	// Hooks should not be dispatched when they are not enabled.
+	txCtx := newTxCtx(pk1)
+	defer txCtx.Close()
+	_, err = app.transfer(txCtx, stakeState, &staking.Transfer{
+		To:     addr2,
+		Amount: *quantity.NewFromUint64(1_000),
+	})
+	require.NoError(err, "Transfer")
+	require.Empty(md.invocations, "disabled hooks should not be dispatched")
+
+	// Enable hooks.
+	params.EnabledHookKinds = []staking.HookKind{
+		staking.HookKindIncomingTransfer,
+		staking.HookKindAddEscrow,
+		staking.HookKindReclaimEscrow,
+		staking.HookKindAllowanceChange,
+	}
+	err = stakeState.SetConsensusParameters(ctx, params)
+	require.NoError(err, "SetConsensusParameters")
+
+	// Incoming transfer hook.
+	_, err = app.transfer(txCtx, stakeState, &staking.Transfer{
+		To:     addr2,
+		Amount: *quantity.NewFromUint64(1_000),
+	})
+	require.NoError(err, "Transfer")
+	require.Len(md.invocations, 1)
+	require.Equal(&stakingApi.IncomingTransferHookInvocation{
+		Destination: dst,
+		From:        addr1,
+		To:          addr2,
+		Amount:      quantity.NewFromUint64(1_000),
+	}, md.invocations[0])
+
+	// Self-transfers should not dispatch the hook.
+	txCtx2 := newTxCtx(pk2)
+	defer txCtx2.Close()
+	_, err = app.transfer(txCtx2, stakeState, &staking.Transfer{
+		To:     addr2,
+		Amount: *quantity.NewFromUint64(1_000),
+	})
+	require.NoError(err, "Transfer")
+	require.Len(md.invocations, 1, "self-transfers should not dispatch the hook")
+
+	// Rejected transfers should fail and leave balances unchanged.
+	md.reject = true
+	_, err = app.transfer(txCtx, stakeState, &staking.Transfer{
+		To:     addr2,
+		Amount: *quantity.NewFromUint64(1_000),
+	})
+	require.ErrorIs(err, staking.ErrForbidden, "Transfer")
+	require.Len(md.invocations, 2)
+	acct2, err := stakeState.Account(ctx, addr2)
+	require.NoError(err, "Account")
+	require.EqualValues(*quantity.NewFromUint64(102_000), acct2.General.Balance, "rejected transfer should not change balance")
+
+	// Add escrow hook.
+	_, err = app.addEscrow(txCtx, stakeState, &staking.Escrow{
+		Account: addr2,
+		Amount:  *quantity.NewFromUint64(10_000),
+	})
+	require.ErrorIs(err, staking.ErrForbidden, "AddEscrow")
+	require.Len(md.invocations, 3)
+
+	md.reject = false
+	_, err = app.addEscrow(txCtx, stakeState, &staking.Escrow{
+		Account: addr2,
+		Amount:  *quantity.NewFromUint64(10_000),
+	})
+	require.NoError(err, "AddEscrow")
+	require.Len(md.invocations, 4)
+	require.Equal(&stakingApi.AddEscrowHookInvocation{
+		Destination: dst,
+		Owner:       addr1,
+		Escrow:      addr2,
+		Amount:      quantity.NewFromUint64(10_000),
+	}, md.invocations[3])
+
+	// Reclaim escrow hook.
+	md.reject = true
+	_, err = app.reclaimEscrow(txCtx, stakeState, &staking.ReclaimEscrow{
+		Account: addr2,
+		Shares:  *quantity.NewFromUint64(5_000),
+	})
+	require.ErrorIs(err, staking.ErrForbidden, "ReclaimEscrow")
+	require.Len(md.invocations, 5)
+
+	md.reject = false
+	_, err = app.reclaimEscrow(txCtx, stakeState, &staking.ReclaimEscrow{
+		Account: addr2,
+		Shares:  *quantity.NewFromUint64(5_000),
+	})
+	require.NoError(err, "ReclaimEscrow")
+	require.Len(md.invocations, 6)
+	require.Equal(&stakingApi.ReclaimEscrowHookInvocation{
+		Destination: dst,
+		Owner:       addr1,
+		Escrow:      addr2,
+		Shares:      quantity.NewFromUint64(5_000),
+	}, md.invocations[5])
+
+	// Allowance change hook.
+	md.reject = true
+	err = app.allow(txCtx, stakeState, &staking.Allow{
+		Beneficiary:  addr2,
+		AmountChange: *quantity.NewFromUint64(100),
+	})
+	require.ErrorIs(err, staking.ErrForbidden, "Allow")
+	require.Len(md.invocations, 7)
+	acct1, err := stakeState.Account(ctx, addr1)
+	require.NoError(err, "Account")
+	require.Empty(acct1.General.Allowances, "rejected allowance change should not be applied")
+
+	md.reject = false
+	err = app.allow(txCtx, stakeState, &staking.Allow{
+		Beneficiary:  addr2,
+		AmountChange: *quantity.NewFromUint64(100),
+	})
+	require.NoError(err, "Allow")
+	require.Len(md.invocations, 8)
+	require.Equal(&stakingApi.AllowanceChangeHookInvocation{
+		Destination: dst,
+		Owner:       addr1,
+		Beneficiary: addr2,
+		Allowance:   quantity.NewFromUint64(100),
+	}, md.invocations[7])
+}
@@ -637,6 +637,24 @@ func (s *MutableState) SetAccountHook(ctx context.Context, addr staking.Address,
 	return s.SetAccount(ctx, addr, acct)
 }
 
+// RemoveAccountHook removes the account hook of the given kind, if any.
+func (s *MutableState) RemoveAccountHook(ctx context.Context, addr staking.Address, kind staking.HookKind) error {
+	acct, err := s.Account(ctx, addr)
+	if err != nil {
+		return err
+	}
+	if _, ok := acct.General.Hooks[kind]; !ok {
+		return nil
+	}
+
+	delete(acct.General.Hooks, kind)
+	if len(acct.General.Hooks) == 0 {
+		acct.General.Hooks = nil
+	}
+
+	return s.SetAccount(ctx, addr, acct)
+}
+
 func (s *MutableState) SetTotalSupply(ctx context.Context, q *quantity.Quantity) error {
 	err := s.ms.Insert(ctx, totalSupplyKeyFmt.Encode(), cbor.Marshal(q))
 	return abciAPI.UnavailableStateError(err)
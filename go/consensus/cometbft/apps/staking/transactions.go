@@ -100,6 +100,10 @@ func (app *Application) transferImpl(
 		panic("BUG: transferImpl - destination address is burn address")
 	}
 
+	// Start a new transaction and rollback in case we fail.
+	ctx = ctx.NewTransaction()
+	defer ctx.Close()
+
 	if fromAddr.Equal(xfer.To) {
 		// Handle transfer to self as just a balance check.
 		if from.General.Balance.Cmp(&xfer.Amount) < 0 {
@@ -120,6 +124,20 @@ func (app *Application) transferImpl(
 		if err != nil {
 			return fmt.Errorf("failed to fetch account: %w", err)
 		}
+
+		// Check if an incoming transfer hook is configured for the destination account.
+		if dst, ok := accountHook(params, to, staking.HookKindIncomingTransfer); ok {
+			ih := &stakingApi.IncomingTransferHookInvocation{
+				Destination: dst,
+				From:        fromAddr,
+				To:          xfer.To,
+				Amount:      xfer.Amount.Clone(),
+			}
+			if err = app.invokeAccountHook(ctx, ih); err != nil {
+				return err
+			}
+		}
+
 		if err = quantity.Move(&to.General.Balance, &from.General.Balance, &xfer.Amount); err != nil {
 			ctx.Logger().Debug("Transfer: failed to move balance",
 				"err", err,
@@ -157,6 +175,8 @@ func (app *Application) transferImpl(
 		return fmt.Errorf("failed to fetch account: %w", err)
 	}
 
+	ctx.Commit()
+
 	return nil
 }
 
@@ -289,6 +309,10 @@ func (app *Application) addEscrow(ctx *api.Context, state *stakingState.MutableS
 		return nil, staking.ErrForbidden
 	}
 
+	// Start a new transaction and rollback in case we fail.
+	ctx = ctx.NewTransaction()
+	defer ctx.Close()
+
 	from, err := state.Account(ctx, fromAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch account: %w", err)
@@ -309,6 +333,19 @@ func (app *Application) addEscrow(ctx *api.Context, state *stakingState.MutableS
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch account: %w", err)
 		}
+
+		// Check if an add escrow hook is configured for the escrow account.
+		if dst, ok := accountHook(params, to, staking.HookKindAddEscrow); ok {
+			ah := &stakingApi.AddEscrowHookInvocation{
+				Destination: dst,
+				Owner:       fromAddr,
+				Escrow:      escrow.Account,
+				Amount:      escrow.Amount.Clone(),
+			}
+			if err = app.invokeAccountHook(ctx, ah); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// Fetch delegation.
@@ -366,6 +403,8 @@ func (app *Application) addEscrow(ctx *api.Context, state *stakingState.MutableS
 		NewShares: *obtainedShares,
 	}))
 
+	ctx.Commit()
+
 	return &staking.AddEscrowResult{
 		Owner:     fromAddr,
 		Escrow:    escrow.Account,
@@ -408,6 +447,10 @@ func (app *Application) reclaimEscrow(ctx *api.Context, state *stakingState.Muta
 		return nil, staking.ErrForbidden
 	}
 
+	// Start a new transaction and rollback in case we fail.
+	ctx = ctx.NewTransaction()
+	defer ctx.Close()
+
 	to, err := state.Account(ctx, toAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch account: %w", err)
@@ -428,6 +471,19 @@ func (app *Application) reclaimEscrow(ctx *api.Context, state *stakingState.Muta
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch account: %w", err)
 		}
+
+		// Check if a reclaim escrow hook is configured for the escrow account.
+		if dst, ok := accountHook(params, from, staking.HookKindReclaimEscrow); ok {
+			rh := &stakingApi.ReclaimEscrowHookInvocation{
+				Destination: dst,
+				Owner:       toAddr,
+				Escrow:      reclaim.Account,
+				Shares:      reclaim.Shares.Clone(),
+			}
+			if err = app.invokeAccountHook(ctx, rh); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// Fetch delegation.
@@ -521,6 +577,8 @@ func (app *Application) reclaimEscrow(ctx *api.Context, state *stakingState.Muta
 		DebondEndTime:   deb.DebondEndTime,
 	}))
 
+	ctx.Commit()
+
 	return &staking.ReclaimEscrowResult{
 		Owner:           toAddr,
 		Escrow:          reclaim.Account,
@@ -684,6 +742,30 @@ func (app *Application) allow(
 		return staking.ErrTooManyAllowances
 	}
 
+	// Start a new transaction and rollback in case we fail.
+	ctx = ctx.NewTransaction()
+	defer ctx.Close()
+
+	// Check if an allowance change hook is configured for the beneficiary account.
+	if params.IsHookKindEnabled(staking.HookKindAllowanceChange) {
+		var beneficiary *staking.Account
+		beneficiary, err = state.Account(ctx, allow.Beneficiary)
+		if err != nil {
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+		if dst, ok := accountHook(params, beneficiary, staking.HookKindAllowanceChange); ok {
+			ah := &stakingApi.AllowanceChangeHookInvocation{
+				Destination: dst,
+				Owner:       addr,
+				Beneficiary: allow.Beneficiary,
+				Allowance:   allowance.Clone(),
+			}
+			if err = app.invokeAccountHook(ctx, ah); err != nil {
+				return err
+			}
+		}
+	}
+
 	if err = state.SetAccount(ctx, addr, acct); err != nil {
 		return fmt.Errorf("failed to set account: %w", err)
 	}
@@ -696,6 +778,8 @@ func (app *Application) allow(
 		AmountChange: *amountChange,
 	}))
 
+	ctx.Commit()
+
 	return nil
 }
 
@@ -1,8 +1,14 @@
 package vault
 
 import (
+	"fmt"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
 	vaultState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/vault/state"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	vault "github.com/oasisprotocol/oasis-core/go/vault/api"
 )
 
@@ -74,16 +80,213 @@ func (app *Application) executeAction(ctx *api.Context, vlt *vault.Vault, action
 		ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&vault.AuthorityUpdatedEvent{
 			Vault: vlt.Address(),
 		}))
+	case action.UpdateSpendingPolicy != nil:
+		// Update the vault spending policy.
+		action.UpdateSpendingPolicy.Apply(vlt)
+		ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&vault.SpendingPolicyUpdatedEvent{
+			Vault: vlt.Address(),
+		}))
+	case action.UpdateAccountHooks != nil:
+		// Update the hooks of the vault account.
+		stakeState := stakingState.NewMutableState(ctx.State())
+		for _, kind := range action.UpdateAccountHooks.Enable {
+			err := stakeState.SetAccountHook(ctx, vlt.Address(), kind, &staking.HookDestination{
+				Module: vault.ModuleName,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for _, kind := range action.UpdateAccountHooks.Disable {
+			if err := stakeState.RemoveAccountHook(ctx, vlt.Address(), kind); err != nil {
+				return err
+			}
+		}
+
+		ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&vault.AccountHooksUpdatedEvent{
+			Vault: vlt.Address(),
+		}))
 	case action.ExecuteMessage != nil:
+		// Spending state updates should only be applied in case the message succeeds.
+		txCtx := ctx.NewTransaction()
+		defer txCtx.Close()
+
+		// Ensure the message complies with the vault spending policy.
+		violation, err := app.authorizeSpending(txCtx, vlt, action.ExecuteMessage)
+		if err != nil {
+			return err
+		}
+		if violation != nil {
+			ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(violation))
+			return fmt.Errorf("%w: %s", vault.ErrSpendingPolicyViolated, violation.Violation)
+		}
+
 		// Execute a message with vault as the caller.
-		_, err := app.md.Publish(ctx, api.MessageExecuteSubcall, &api.SubcallInfo{
+		_, err = app.md.Publish(txCtx, api.MessageExecuteSubcall, &api.SubcallInfo{
 			Caller: vlt.Address(),
 			Method: action.ExecuteMessage.Method,
 			Body:   action.ExecuteMessage.Body,
 		})
-		return err
+		if err != nil {
+			return err
+		}
+
+		txCtx.Commit()
 	default:
 		return vault.ErrUnsupportedAction
 	}
 	return nil
 }
+
+// authorizeSpending checks whether a message executed on behalf of the vault complies with the
+// vault spending policy. In case it does, the vault spending state is updated accordingly,
+// otherwise the violation is returned.
+func (app *Application) authorizeSpending(ctx *api.Context, vlt *vault.Vault, msg *vault.ActionExecuteMessage) (*vault.SpendingPolicyViolatedEvent, error) {
+	if vlt.SpendingPolicy == nil {
+		return nil, nil
+	}
+
+	outflow, err := msg.Outflow()
+	if err != nil {
+		return nil, vault.ErrInvalidArgument
+	}
+	if outflow == nil {
+		return nil, nil
+	}
+
+	epoch, err := app.state.GetCurrentEpoch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch current epoch: %w", err)
+	}
+
+	state := vaultState.NewMutableState(ctx.State())
+	spendingState, err := state.SpendingState(ctx, vlt.Address())
+	switch err {
+	case nil:
+	case vault.ErrNoSuchState:
+		spendingState = &vault.SpendingState{}
+	default:
+		return nil, err
+	}
+
+	if violation := spendingState.AuthorizeOutflow(vlt.SpendingPolicy, epoch, outflow); violation != vault.SpendingViolationNone {
+		return &vault.SpendingPolicyViolatedEvent{
+			Vault:     vlt.Address(),
+			To:        outflow.To,
+			Amount:    outflow.Amount,
+			Violation: violation,
+		}, nil
+	}
+
+	if err = state.SetSpendingState(ctx, vlt.Address(), spendingState); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// executePendingAction executes a sufficiently authorized pending action, removes it and, unless
+// the action has been scheduled before, advances the vault nonce. Action execution failures are
+// recorded in the emitted event.
+func (app *Application) executePendingAction(ctx *api.Context, vlt *vault.Vault, pendingAction *vault.PendingAction) error {
+	evExec := &vault.ActionExecutedEvent{
+		Vault: vlt.Address(),
+		Nonce: pendingAction.Nonce,
+	}
+	err := app.executeAction(ctx, vlt, &pendingAction.Action)
+	switch {
+	case api.IsUnavailableStateError(err):
+		// Propagate state unavailability errors.
+		return err
+	default:
+		// Record other errors (or success) in the execution event.
+		evExec.Result.Module, evExec.Result.Code = errors.Code(err)
+		ctx.Logger().Debug("vault executed action",
+			"err", err,
+			"vault", vlt.Address(),
+			"nonce", pendingAction.Nonce,
+			"action", pendingAction.Action,
+		)
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(evExec))
+
+	// Remove pending action as it has been executed.
+	state := vaultState.NewMutableState(ctx.State())
+	if err = state.RemovePendingAction(ctx, vlt.Address(), pendingAction.Nonce); err != nil {
+		return err
+	}
+
+	if pendingAction.Nonce == vlt.Nonce {
+		vlt.Nonce++
+	}
+	return state.SetVault(ctx, vlt)
+}
+
+// processPendingActions executes time-locked actions whose execution delay has passed and removes
+// actions that have expired.
+func (app *Application) processPendingActions(ctx *api.Context, epoch beacon.EpochTime) error {
+	state := vaultState.NewMutableState(ctx.State())
+	entries, err := state.DuePendingActions(ctx, epoch)
+	if err != nil {
+		return fmt.Errorf("failed to fetch due pending actions: %w", err)
+	}
+
+	for _, entry := range entries {
+		// Actions may have multiple queue entries, make sure the action still exists.
+		pendingAction, err := state.PendingAction(ctx, entry.Vault, entry.Action.Nonce)
+		switch err {
+		case nil:
+		case vault.ErrNoSuchAction:
+			continue
+		default:
+			return err
+		}
+
+		vlt, err := state.Vault(ctx, entry.Vault)
+		if err != nil {
+			return fmt.Errorf("failed to fetch vault: %w", err)
+		}
+
+		switch {
+		case pendingAction.IsExpired(epoch):
+			// Garbage collect expired actions.
+			if err = app.expirePendingAction(ctx, vlt, pendingAction); err != nil {
+				return err
+			}
+		case pendingAction.IsExecutable(epoch):
+			// Execute time-locked actions.
+			txCtx := ctx.NewTransaction()
+			if err = app.executePendingAction(txCtx, vlt, pendingAction); err != nil {
+				txCtx.Close()
+				return err
+			}
+			txCtx.Commit()
+			txCtx.Close()
+		}
+	}
+	return nil
+}
+
+// expirePendingAction removes an expired pending action. In case the action is the next action
+// of the vault, the vault nonce is advanced.
+func (app *Application) expirePendingAction(ctx *api.Context, vlt *vault.Vault, pendingAction *vault.PendingAction) error {
+	ctx.Logger().Debug("vault action expired",
+		"vault", vlt.Address(),
+		"nonce", pendingAction.Nonce,
+	)
+
+	state := vaultState.NewMutableState(ctx.State())
+	if err := state.RemovePendingAction(ctx, vlt.Address(), pendingAction.Nonce); err != nil {
+		return err
+	}
+
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&vault.ActionExpiredEvent{
+		Vault: vlt.Address(),
+		Nonce: pendingAction.Nonce,
+	}))
+
+	if pendingAction.Nonce != vlt.Nonce {
+		return nil
+	}
+	vlt.Nonce++
+	return state.SetVault(ctx, vlt)
+}
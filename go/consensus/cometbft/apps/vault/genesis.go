@@ -49,6 +49,13 @@ func (app *Application) InitChain(ctx *abciAPI.Context, _ types.RequestInitChain
 		}
 	}
 
+	// Insert spending states.
+	for vaultAddr, ss := range st.SpendingStates {
+		if err := state.SetSpendingState(ctx, vaultAddr, ss); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -65,9 +72,10 @@ func (q *vaultQuerier) Genesis(ctx context.Context) (*vault.Genesis, error) {
 		return nil, err
 	}
 
-	// Account states and pending actions.
+	// Account states, pending actions and spending states.
 	pendingActions := make(map[staking.Address][]*vault.PendingAction)
 	states := make(map[staking.Address]map[staking.Address]*vault.AddressState)
+	spendingStates := make(map[staking.Address]*vault.SpendingState)
 	for _, vlt := range vaults {
 		var actions []*vault.PendingAction
 		actions, err = q.state.PendingActions(ctx, vlt.Address())
@@ -87,6 +95,15 @@ func (q *vaultQuerier) Genesis(ctx context.Context) (*vault.Genesis, error) {
 		if len(vaultStates) > 0 {
 			states[vlt.Address()] = vaultStates
 		}
+
+		spendingState, err := q.state.SpendingState(ctx, vlt.Address())
+		switch err {
+		case nil:
+			spendingStates[vlt.Address()] = spendingState
+		case vault.ErrNoSuchState:
+		default:
+			return nil, err
+		}
 	}
 
 	return &vault.Genesis{
@@ -94,5 +111,6 @@ func (q *vaultQuerier) Genesis(ctx context.Context) (*vault.Genesis, error) {
 		Vaults:         vaults,
 		PendingActions: pendingActions,
 		States:         states,
+		SpendingStates: spendingStates,
 	}, nil
 }
@@ -41,6 +41,9 @@ func createTestGenesis() *vault.Genesis {
 					},
 					Threshold: 1,
 				},
+				SpendingPolicy: &vault.SpendingPolicy{
+					TransferLimit: *quantity.NewFromUint64(100),
+				},
 			},
 			{
 				Creator: testAddrA,
@@ -70,6 +73,13 @@ func createTestGenesis() *vault.Genesis {
 				},
 			},
 		},
+		SpendingStates: map[staking.Address]*vault.SpendingState{
+			vault.NewVaultAddress(testAddrA, 42): {
+				Epochs: []vault.EpochSpending{
+					{Epoch: 5, Amount: *quantity.NewFromUint64(50)},
+				},
+			},
+		},
 		PendingActions: map[staking.Address][]*vault.PendingAction{
 			vault.NewVaultAddress(testAddrA, 45): {
 				{
@@ -155,6 +165,12 @@ func TestInitChain(t *testing.T) {
 				require.Len(pendingActions, 1, "pending actions should be correctly initialized")
 				require.EqualValues(0, pendingActions[0].Nonce)
 				require.EqualValues([]staking.Address{testAddrB}, pendingActions[0].AuthorizedBy)
+
+				var spendingState *vault.SpendingState
+				spendingState, err = state.SpendingState(ctx, vaults[1].Address())
+				require.NoError(err, "SpendingState")
+				require.Len(spendingState.Epochs, 1, "spending states should be correctly initialized")
+				require.EqualValues(5, spendingState.Epochs[0].Epoch)
 			},
 		},
 	} {
@@ -187,6 +203,7 @@ func TestGenesis(t *testing.T) {
 				Vaults:         nil,
 				States:         make(map[staking.Address]map[staking.Address]*vault.AddressState),
 				PendingActions: make(map[staking.Address][]*vault.PendingAction),
+				SpendingStates: make(map[staking.Address]*vault.SpendingState),
 			},
 		},
 		{
@@ -206,6 +223,10 @@ func TestGenesis(t *testing.T) {
 						require.NoError(err, "SetAddressState")
 					}
 				}
+				for vltAddr, spendingState := range testGenesis.SpendingStates {
+					err = state.SetSpendingState(ctx, vltAddr, spendingState)
+					require.NoError(err, "SetSpendingState")
+				}
 				for vltAddr, pendingActions := range testGenesis.PendingActions {
 					for _, pa := range pendingActions {
 						err = state.SetPendingAction(ctx, vltAddr, pa)
@@ -240,6 +261,7 @@ func TestGenesis(t *testing.T) {
 			}
 		}
 
+		require.Equal(tc.expectedGenesis.SpendingStates, g.SpendingStates, tc.msg)
 		require.Len(g.PendingActions, len(tc.expectedGenesis.PendingActions))
 		for vltAddr, pendingActions := range g.PendingActions {
 			require.ElementsMatch(tc.expectedGenesis.PendingActions[vltAddr], pendingActions)
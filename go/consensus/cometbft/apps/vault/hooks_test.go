@@ -0,0 +1,182 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	stakingApp "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking"
+	stakingApi "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/api"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	vaultState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/vault/state"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	vault "github.com/oasisprotocol/oasis-core/go/vault/api"
+)
+
+// testHookDispatcher dispatches account hooks to the vault application.
+type testHookDispatcher struct {
+	app *Application
+}
+
+// Implements MessageDispatcher.
+func (d *testHookDispatcher) Subscribe(any, abciAPI.MessageSubscriber) {
+}
+
+// Implements MessageDispatcher.
+func (d *testHookDispatcher) Publish(ctx *abciAPI.Context, kind, msg any) (any, error) {
+	if kind != stakingApi.MessageAccountHook {
+		return nil, abciAPI.ErrNoSubscribers
+	}
+	return d.app.ExecuteMessage(ctx, kind, msg)
+}
+
+func TestAccountHooks(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	md := &testHookDispatcher{}
+	app := &Application{
+		state: appState,
+		md:    md,
+	}
+	md.app = app
+	stakeApp := stakingApp.New(appState, md)
+
+	state := vaultState.NewMutableState(ctx.State())
+	err := state.SetConsensusParameters(ctx, &vault.ConsensusParameters{
+		MaxAuthorityAddresses: 32,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+	err = stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		MaxAllowances: 16,
+		EnabledHookKinds: []staking.HookKind{
+			staking.HookKindIncomingTransfer,
+			staking.HookKindAddEscrow,
+			staking.HookKindReclaimEscrow,
+			staking.HookKindAllowanceChange,
+		},
+	})
+	require.NoError(err, "SetConsensusParameters")
+	err = stakeState.SetTotalSupply(ctx, quantity.NewFromUint64(100_000))
+	require.NoError(err, "SetTotalSupply")
+	err = stakeState.SetAccount(ctx, testAddrD, &staking.Account{
+		General: staking.GeneralAccount{
+			Balance: *quantity.NewFromUint64(100_000),
+		},
+	})
+	require.NoError(err, "SetAccount")
+
+	ctx = appState.NewContext(abciAPI.ContextDeliverTx)
+	defer ctx.Close()
+
+	err = app.create(ctx, &vault.Create{
+		AdminAuthority: vault.Authority{
+			Addresses: []staking.Address{
+				testAddrA,
+			},
+			Threshold: 1,
+		},
+		SuspendAuthority: vault.Authority{
+			Addresses: []staking.Address{
+				testAddrA,
+			},
+			Threshold: 1,
+		},
+	})
+	require.NoError(err, "create")
+	vaultAddr := vault.NewVaultAddress(ctx.CallerAddress(), 0)
+
+	var nonce uint64
+	authorize := func(action vault.Action) {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx).WithCallerAddress(testAddrA)
+		defer txCtx.Close()
+		err = app.authorizeAction(txCtx, &vault.AuthorizeAction{
+			Vault:  vaultAddr,
+			Nonce:  nonce,
+			Action: action,
+		})
+		require.NoError(err, "authorizeAction")
+		nonce++
+	}
+	execute := func(method transaction.MethodName, body any) error {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx).WithCallerAddress(testAddrD)
+		defer txCtx.Close()
+		return stakeApp.ExecuteTx(txCtx, transaction.NewTransaction(0, nil, method, body))
+	}
+	transfer := func() error {
+		return execute(staking.MethodTransfer, &staking.Transfer{
+			To:     vaultAddr,
+			Amount: *quantity.NewFromUint64(1_000),
+		})
+	}
+	addEscrow := func() error {
+		return execute(staking.MethodAddEscrow, &staking.Escrow{
+			Account: vaultAddr,
+			Amount:  *quantity.NewFromUint64(10_000),
+		})
+	}
+	reclaimEscrow := func() error {
+		return execute(staking.MethodReclaimEscrow, &staking.ReclaimEscrow{
+			Account: vaultAddr,
+			Shares:  *quantity.NewFromUint64(1_000),
+		})
+	}
+	allow := func() error {
+		return execute(staking.MethodAllow, &staking.Allow{
+			Beneficiary:  vaultAddr,
+			AmountChange: *quantity.NewFromUint64(100),
+		})
+	}
+
+	// Enable account hooks.
+	authorize(vault.Action{UpdateAccountHooks: &vault.ActionUpdateAccountHooks{
+		Enable: []staking.HookKind{
+			staking.HookKindIncomingTransfer,
+			staking.HookKindAddEscrow,
+			staking.HookKindReclaimEscrow,
+			staking.HookKindAllowanceChange,
+		},
+	}})
+	acct, err := stakeState.Account(ctx, vaultAddr)
+	require.NoError(err, "Account")
+	require.Len(acct.General.Hooks, 5, "account hooks should be enabled")
+
+	// Operations should be allowed while the vault is active.
+	require.NoError(transfer(), "transfer into an active vault should be allowed")
+	require.NoError(addEscrow(), "escrow to an active vault should be allowed")
+	require.NoError(allow(), "allowance to an active vault should be allowed")
+
+	acct, err = stakeState.Account(ctx, vaultAddr)
+	require.NoError(err, "Account")
+	require.EqualValues(*quantity.NewFromUint64(1_000), acct.General.Balance)
+
+	// Operations other than reclaiming escrow should be rejected while the vault is suspended.
+	authorize(vault.Action{Suspend: &vault.ActionSuspend{}})
+	require.ErrorIs(transfer(), staking.ErrForbidden, "transfer into a suspended vault should be rejected")
+	require.ErrorIs(addEscrow(), staking.ErrForbidden, "escrow to a suspended vault should be rejected")
+	require.ErrorIs(allow(), staking.ErrForbidden, "allowance to a suspended vault should be rejected")
+	require.NoError(reclaimEscrow(), "reclaiming escrow from a suspended vault should be allowed")
+
+	acct, err = stakeState.Account(ctx, vaultAddr)
+	require.NoError(err, "Account")
+	require.EqualValues(*quantity.NewFromUint64(1_000), acct.General.Balance, "rejected transfer should not change balance")
+
+	// Disabled hooks should no longer be invoked.
+	authorize(vault.Action{UpdateAccountHooks: &vault.ActionUpdateAccountHooks{
+		Disable: []staking.HookKind{
+			staking.HookKindIncomingTransfer,
+		},
+	}})
+	acct, err = stakeState.Account(ctx, vaultAddr)
+	require.NoError(err, "Account")
+	require.Len(acct.General.Hooks, 4, "account hook should be disabled")
+	require.NoError(transfer(), "transfer into a vault without the hook should be allowed")
+}
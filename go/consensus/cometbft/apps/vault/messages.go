@@ -93,7 +93,34 @@ func (app *Application) invokeAccountHook(ctx *api.Context, msg any) (any, error
 
 		// Withdrawal is allowed.
 		return struct{}{}, nil
+	case *stakingApi.IncomingTransferHookInvocation:
+		// Suspended vaults do not accept incoming transfers.
+		return checkVaultActive(ctx, state, hi.To)
+	case *stakingApi.AddEscrowHookInvocation:
+		// Suspended vaults do not accept new delegations.
+		return checkVaultActive(ctx, state, hi.Escrow)
+	case *stakingApi.AllowanceChangeHookInvocation:
+		// Suspended vaults do not accept allowance changes.
+		return checkVaultActive(ctx, state, hi.Beneficiary)
+	case *stakingApi.ReclaimEscrowHookInvocation:
+		// Delegators can always reclaim their stake so that their funds are never locked.
+		if _, err := state.Vault(ctx, hi.Escrow); err != nil {
+			return nil, err
+		}
+		return struct{}{}, nil
 	default:
 		return nil, nil
 	}
 }
+
+// checkVaultActive allows the hooked operation in case the given vault is active.
+func checkVaultActive(ctx *api.Context, state *vaultState.MutableState, addr staking.Address) (any, error) {
+	vlt, err := state.Vault(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if !vlt.IsActive() {
+		return nil, vault.ErrForbidden
+	}
+	return struct{}{}, nil
+}
@@ -14,6 +14,7 @@ type Query interface {
 	Vaults(context.Context) ([]*vault.Vault, error)
 	Vault(context.Context, staking.Address) (*vault.Vault, error)
 	AddressState(context.Context, staking.Address, staking.Address) (*vault.AddressState, error)
+	SpendingState(context.Context, staking.Address) (*vault.SpendingState, error)
 	PendingActions(context.Context, staking.Address) ([]*vault.PendingAction, error)
 	Genesis(context.Context) (*vault.Genesis, error)
 	ConsensusParameters(context.Context) (*vault.ConsensusParameters, error)
@@ -51,6 +52,10 @@ func (q *vaultQuerier) AddressState(ctx context.Context, vault staking.Address,
 	return q.state.AddressState(ctx, vault, address)
 }
 
+func (q *vaultQuerier) SpendingState(ctx context.Context, address staking.Address) (*vault.SpendingState, error) {
+	return q.state.SpendingState(ctx, address)
+}
+
 func (q *vaultQuerier) PendingActions(ctx context.Context, address staking.Address) ([]*vault.PendingAction, error) {
 	return q.state.PendingActions(ctx, address)
 }
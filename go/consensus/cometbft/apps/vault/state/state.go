@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
@@ -32,8 +33,27 @@ var (
 	//
 	// Value is CBOR-serialized vault.ConsensusParameters.
 	parametersKeyFmt = consensus.KeyFormat.New(0x33)
+
+	// spendingStateKeyFmt is the key format used for storing per-vault spending state.
+	//
+	// Value is CBOR-serialized SpendingState.
+	spendingStateKeyFmt = consensus.KeyFormat.New(0x34, &staking.Address{})
+
+	// pendingActionQueueKeyFmt is the key format used for indexing pending actions by the epochs
+	// at which they need to be processed (epoch, vault address, nonce).
+	//
+	// Value is empty.
+	pendingActionQueueKeyFmt = consensus.KeyFormat.New(0x35, uint64(0), &staking.Address{}, uint64(0))
 )
 
+// PendingActionQueueEntry is a pending action that needs to be processed at a given epoch, either
+// because its execution delay has passed or because it has expired.
+type PendingActionQueueEntry struct {
+	Epoch  beacon.EpochTime
+	Vault  staking.Address
+	Action *vault.PendingAction
+}
+
 // ImmutableState is an immutable vault state wrapper.
 type ImmutableState struct {
 	state *api.ImmutableState
@@ -128,6 +148,23 @@ func (s *ImmutableState) AddressStates(ctx context.Context, vaultAddr staking.Ad
 	return states, nil
 }
 
+// SpendingState looks up the spending state of the given vault.
+func (s *ImmutableState) SpendingState(ctx context.Context, vaultAddr staking.Address) (*vault.SpendingState, error) {
+	raw, err := s.state.Get(ctx, spendingStateKeyFmt.Encode(&vaultAddr))
+	if err != nil {
+		return nil, api.UnavailableStateError(err)
+	}
+	if raw == nil {
+		return nil, vault.ErrNoSuchState
+	}
+
+	var state vault.SpendingState
+	if err := cbor.Unmarshal(raw, &state); err != nil {
+		return nil, api.UnavailableStateError(err)
+	}
+	return &state, nil
+}
+
 func (s *ImmutableState) PendingAction(ctx context.Context, vaultAddr staking.Address, nonce uint64) (*vault.PendingAction, error) {
 	raw, err := s.state.Get(ctx, pendingActionsKeyFmt.Encode(vaultAddr, nonce))
 	if err != nil {
@@ -167,6 +204,39 @@ func (s *ImmutableState) PendingActions(ctx context.Context, vaultAddr staking.A
 	return actions, nil
 }
 
+// DuePendingActions returns the pending actions that need to be processed at or before the given
+// epoch.
+func (s *ImmutableState) DuePendingActions(ctx context.Context, epoch beacon.EpochTime) ([]*PendingActionQueueEntry, error) {
+	it := s.state.NewIterator(ctx)
+	defer it.Close()
+
+	var entries []*PendingActionQueueEntry
+	for it.Seek(pendingActionQueueKeyFmt.Encode()); it.Valid(); it.Next() {
+		var (
+			decEpoch  uint64
+			vaultAddr staking.Address
+			nonce     uint64
+		)
+		if !pendingActionQueueKeyFmt.Decode(it.Key(), &decEpoch, &vaultAddr, &nonce) || decEpoch > uint64(epoch) {
+			break
+		}
+
+		pa, err := s.PendingAction(ctx, vaultAddr, nonce)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &PendingActionQueueEntry{
+			Epoch:  beacon.EpochTime(decEpoch),
+			Vault:  vaultAddr,
+			Action: pa,
+		})
+	}
+	if it.Err() != nil {
+		return nil, api.UnavailableStateError(it.Err())
+	}
+	return entries, nil
+}
+
 // ConsensusParameters returns the vault consensus parameters.
 func (s *ImmutableState) ConsensusParameters(ctx context.Context) (*vault.ConsensusParameters, error) {
 	raw, err := s.state.Get(ctx, parametersKeyFmt.Encode())
@@ -238,18 +308,76 @@ func (s *MutableState) SetAddressState(ctx context.Context, vaultAddr staking.Ad
 	return api.UnavailableStateError(err)
 }
 
+// SetSpendingState sets the spending state of the given vault.
+func (s *MutableState) SetSpendingState(ctx context.Context, vaultAddr staking.Address, state *vault.SpendingState) error {
+	err := s.ms.Insert(ctx, spendingStateKeyFmt.Encode(&vaultAddr), cbor.Marshal(state))
+	return api.UnavailableStateError(err)
+}
+
 // SetPendingAction updates the pending action.
+//
+// NOTE: This operation performs multiple actions so it should be wrapped in a transaction.
 func (s *MutableState) SetPendingAction(ctx context.Context, vaultAddr staking.Address, action *vault.PendingAction) error {
-	err := s.ms.Insert(ctx, pendingActionsKeyFmt.Encode(vaultAddr, action.Nonce), cbor.Marshal(action))
-	return api.UnavailableStateError(err)
+	// Remove any queue entries of the previous version of the action as its epochs may change.
+	if err := s.removePendingActionQueueEntries(ctx, vaultAddr, action.Nonce); err != nil {
+		return err
+	}
+
+	if err := s.ms.Insert(ctx, pendingActionsKeyFmt.Encode(vaultAddr, action.Nonce), cbor.Marshal(action)); err != nil {
+		return api.UnavailableStateError(err)
+	}
+
+	for _, epoch := range pendingActionQueueEpochs(action) {
+		err := s.ms.Insert(ctx, pendingActionQueueKeyFmt.Encode(uint64(epoch), &vaultAddr, action.Nonce), []byte{})
+		if err != nil {
+			return api.UnavailableStateError(err)
+		}
+	}
+	return nil
 }
 
 // RemovePendingAction removes the pending action with the given nonce.
+//
+// NOTE: This operation performs multiple actions so it should be wrapped in a transaction.
 func (s *MutableState) RemovePendingAction(ctx context.Context, vaultAddr staking.Address, nonce uint64) error {
+	if err := s.removePendingActionQueueEntries(ctx, vaultAddr, nonce); err != nil {
+		return err
+	}
 	err := s.ms.Remove(ctx, pendingActionsKeyFmt.Encode(vaultAddr, nonce))
 	return api.UnavailableStateError(err)
 }
 
+func (s *MutableState) removePendingActionQueueEntries(ctx context.Context, vaultAddr staking.Address, nonce uint64) error {
+	action, err := s.PendingAction(ctx, vaultAddr, nonce)
+	switch err {
+	case nil:
+	case vault.ErrNoSuchAction:
+		return nil
+	default:
+		return err
+	}
+
+	for _, epoch := range pendingActionQueueEpochs(action) {
+		if err = s.ms.Remove(ctx, pendingActionQueueKeyFmt.Encode(uint64(epoch), &vaultAddr, nonce)); err != nil {
+			return api.UnavailableStateError(err)
+		}
+	}
+	return nil
+}
+
+// pendingActionQueueEpochs returns the epochs at which the given pending action needs to be
+// processed.
+func pendingActionQueueEpochs(action *vault.PendingAction) []beacon.EpochTime {
+	var epochs []beacon.EpochTime
+	if action.IsScheduled() {
+		epochs = append(epochs, action.ExecuteAt)
+	}
+	if action.Expiry != 0 && action.Expiry != action.ExecuteAt {
+		epochs = append(epochs, action.Expiry)
+	}
+	return epochs
+}
+
 // SetConsensusParameters sets vault consensus parameters.
 //
 // NOTE: This method must only be called from InitChain/EndBlock contexts.
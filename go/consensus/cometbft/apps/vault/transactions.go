@@ -3,7 +3,6 @@ package vault
 import (
 	"fmt"
 
-	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
 	vaultState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/vault/state"
@@ -81,9 +80,9 @@ func (app *Application) authorizeAction(ctx *api.Context, authAction *vault.Auth
 		return err
 	}
 
-	// Validate action nonce. Currently queuing multiple future actions is not allowed.
-	if authAction.Nonce != vlt.Nonce {
-		return vault.ErrInvalidNonce
+	// Validate action nonce.
+	if err = checkActionNonce(ctx, state, vlt, authAction.Nonce); err != nil {
+		return err
 	}
 
 	// Validate whether the caller is authorized to submit an action.
@@ -91,6 +90,15 @@ func (app *Application) authorizeAction(ctx *api.Context, authAction *vault.Auth
 		return vault.ErrForbidden
 	}
 
+	// Validate action expiry.
+	epoch, err := app.state.GetCurrentEpoch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch current epoch: %w", err)
+	}
+	if authAction.Expiry != 0 && authAction.Expiry <= epoch {
+		return vault.ErrActionExpired
+	}
+
 	if ctx.IsCheckOnly() {
 		return nil
 	}
@@ -111,7 +119,7 @@ func (app *Application) authorizeAction(ctx *api.Context, authAction *vault.Auth
 	case nil:
 		// Ensure that the action is the same so that the authorizer really signed the correct
 		// action.
-		if !pendingAction.Action.Equal(&authAction.Action) {
+		if !pendingAction.Action.Equal(&authAction.Action) || pendingAction.Expiry != authAction.Expiry {
 			return vault.ErrInvalidArgument
 		}
 	case vault.ErrNoSuchAction:
@@ -119,6 +127,7 @@ func (app *Application) authorizeAction(ctx *api.Context, authAction *vault.Auth
 		pendingAction = &vault.PendingAction{
 			Nonce:  authAction.Nonce,
 			Action: authAction.Action,
+			Expiry: authAction.Expiry,
 		}
 	default:
 		return err
@@ -139,49 +148,47 @@ func (app *Application) authorizeAction(ctx *api.Context, authAction *vault.Auth
 	}
 
 	// Check if action has become executable.
-	var canExecute bool
-	for _, auth := range pendingAction.Action.Authorities(vlt) {
-		if auth.Verify(pendingAction.AuthorizedBy) {
-			canExecute = true
-			break
-		}
-	}
-	if !canExecute {
+	delay, ok := pendingAction.Action.ExecutionDelay(vlt, pendingAction.AuthorizedBy)
+	if !ok {
 		ctx.Commit()
 		return nil
 	}
 
-	// Execute action.
-	evExec := &vault.ActionExecutedEvent{
-		Vault: authAction.Vault,
-		Nonce: authAction.Nonce,
-	}
-	err = app.executeAction(ctx, vlt, &pendingAction.Action)
-	switch {
-	case api.IsUnavailableStateError(err):
-		// Propagate state unavailability errors.
-		return err
-	default:
-		// Record other errors (or success) in the execution event.
-		evExec.Result.Module, evExec.Result.Code = errors.Code(err)
+	// Time-locked actions are only scheduled for execution once the execution delay passes.
+	if delay > 0 {
+		executeAt := epoch + delay
+		if pendingAction.IsScheduled() && pendingAction.ExecuteAt <= executeAt {
+			// Action is already scheduled for execution at an earlier epoch.
+			ctx.Commit()
+			return nil
+		}
 
-		ctx.Logger().Debug("vault executed action",
-			"err", err,
-			"vault", authAction.Vault,
-			"nonce", authAction.Nonce,
-			"action", pendingAction.Action,
-		)
-	}
+		pendingAction.ExecuteAt = executeAt
+		if err = state.SetPendingAction(ctx, authAction.Vault, pendingAction); err != nil {
+			return err
+		}
 
-	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(evExec))
+		// Scheduled actions no longer block the vault so that further actions (e.g. suspending
+		// the vault) can be authorized while waiting for the execution delay to pass.
+		if pendingAction.Nonce == vlt.Nonce {
+			vlt.Nonce++
+			if err = state.SetVault(ctx, vlt); err != nil {
+				return err
+			}
+		}
 
-	// Remove pending action as it has been executed.
-	if err = state.RemovePendingAction(ctx, authAction.Vault, authAction.Nonce); err != nil {
-		return err
+		ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&vault.ActionScheduledEvent{
+			Vault:     authAction.Vault,
+			Nonce:     authAction.Nonce,
+			ExecuteAt: executeAt,
+		}))
+
+		ctx.Commit()
+		return nil
 	}
 
-	vlt.Nonce++
-	if err = state.SetVault(ctx, vlt); err != nil {
+	// Execute action.
+	if err = app.executePendingAction(ctx, vlt, pendingAction); err != nil {
 		return err
 	}
 
@@ -203,9 +210,9 @@ func (app *Application) cancelAction(ctx *api.Context, cancelAction *vault.Cance
 		return err
 	}
 
-	// Validate action nonce. Currently queuing multiple future actions is not allowed.
-	if cancelAction.Nonce != vlt.Nonce {
-		return vault.ErrInvalidNonce
+	// Validate action nonce.
+	if err = checkActionNonce(ctx, state, vlt, cancelAction.Nonce); err != nil {
+		return err
 	}
 
 	// Before we know what the canceled action is, we can only check that the caller is part of at
@@ -242,8 +249,9 @@ func (app *Application) cancelAction(ctx *api.Context, cancelAction *vault.Cance
 		return err
 	}
 
-	// Perform action-specific authority check now that we know what the action is.
-	if !pendingAction.Action.IsAuthorized(vlt, ctx.CallerAddress()) {
+	// Perform action-specific authority check now that we know what the action is. The suspend
+	// authority can cancel any action so that it can act in an emergency.
+	if !pendingAction.Action.IsAuthorized(vlt, ctx.CallerAddress()) && !vlt.SuspendAuthority.Contains(ctx.CallerAddress()) {
 		return vault.ErrForbidden
 	}
 
@@ -256,12 +264,37 @@ func (app *Application) cancelAction(ctx *api.Context, cancelAction *vault.Cance
 		return err
 	}
 
-	vlt.Nonce++
-	if err = state.SetVault(ctx, vlt); err != nil {
-		return err
+	// Scheduled actions have already released the nonce.
+	if pendingAction.Nonce == vlt.Nonce {
+		vlt.Nonce++
+		if err = state.SetVault(ctx, vlt); err != nil {
+			return err
+		}
 	}
 
 	ctx.Commit()
 
 	return nil
 }
+
+// checkActionNonce ensures that the given nonce refers to either the next action of the vault or
+// to an action that has already been scheduled for execution. Queuing multiple future actions is
+// currently not allowed.
+func checkActionNonce(ctx *api.Context, state *vaultState.MutableState, vlt *vault.Vault, nonce uint64) error {
+	if nonce == vlt.Nonce {
+		return nil
+	}
+
+	pendingAction, err := state.PendingAction(ctx, vlt.Address(), nonce)
+	switch err {
+	case nil:
+	case vault.ErrNoSuchAction:
+		return vault.ErrInvalidNonce
+	default:
+		return err
+	}
+	if !pendingAction.IsScheduled() {
+		return vault.ErrInvalidNonce
+	}
+	return nil
+}
@@ -6,7 +6,10 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
 	vaultState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/vault/state"
@@ -690,3 +693,509 @@ func TestAuthorizeCancelAction(t *testing.T) {
 		}
 	}
 }
+
+func TestTimeLockedActions(t *testing.T) {
+	require := require.New(t)
+
+	cfg := &abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 10,
+	}
+	appState := abciAPI.NewMockApplicationState(cfg)
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	md := &testMsgDispatcher{}
+	app := &Application{
+		state: appState,
+		md:    md,
+	}
+
+	state := vaultState.NewMutableState(ctx.State())
+	err := state.SetConsensusParameters(ctx, &vault.ConsensusParameters{
+		MaxAuthorityAddresses: 32,
+		MaxExecutionDelay:     10,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	ctx = appState.NewContext(abciAPI.ContextDeliverTx)
+	defer ctx.Close()
+
+	// Create a vault with a time-locked admin authority.
+	err = app.create(ctx, &vault.Create{
+		AdminAuthority: vault.Authority{
+			Addresses: []staking.Address{
+				testAddrA,
+				testAddrB,
+			},
+			Threshold:      2,
+			ExecutionDelay: 2,
+		},
+		SuspendAuthority: vault.Authority{
+			Addresses: []staking.Address{
+				testAddrC,
+			},
+			Threshold: 1,
+		},
+	})
+	require.NoError(err, "create")
+	vaultAddr := vault.NewVaultAddress(ctx.CallerAddress(), 0)
+
+	authorize := func(caller staking.Address, action *vault.AuthorizeAction) error {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx).WithCallerAddress(caller)
+		defer txCtx.Close()
+		return app.authorizeAction(txCtx, action)
+	}
+	advanceEpoch := func(epoch beacon.EpochTime) {
+		cfg.CurrentEpoch = epoch
+		cfg.EpochChanged = true
+		appState.UpdateMockApplicationStateConfig(cfg)
+
+		blkCtx := appState.NewContext(abciAPI.ContextEndBlock)
+		defer blkCtx.Close()
+		_, err = app.EndBlock(blkCtx)
+		require.NoError(err, "EndBlock")
+	}
+	execMsg := &vault.AuthorizeAction{
+		Vault: vaultAddr,
+		Nonce: 0,
+		Action: vault.Action{ExecuteMessage: &vault.ActionExecuteMessage{
+			Method: "foo.Bar",
+		}},
+	}
+
+	// Sufficiently authorized time-locked action should be scheduled.
+	err = authorize(testAddrA, execMsg)
+	require.NoError(err, "authorizeAction")
+	err = authorize(testAddrB, execMsg)
+	require.NoError(err, "authorizeAction")
+	require.Empty(md.delivered, "time-locked action should not be executed immediately")
+
+	pa, err := state.PendingAction(ctx, vaultAddr, 0)
+	require.NoError(err, "PendingAction")
+	require.EqualValues(12, pa.ExecuteAt)
+
+	// Action should only be executed after the execution delay.
+	advanceEpoch(11)
+	require.Empty(md.delivered, "time-locked action should not be executed before the delay")
+	advanceEpoch(12)
+	require.Len(md.delivered, 1, "time-locked action should be executed after the delay")
+	require.EqualValues("foo.Bar", md.delivered[0].Method)
+
+	_, err = state.PendingAction(ctx, vaultAddr, 0)
+	require.ErrorIs(err, vault.ErrNoSuchAction, "executed action should be removed")
+	vlt, err := state.Vault(ctx, vaultAddr)
+	require.NoError(err, "Vault")
+	require.EqualValues(1, vlt.Nonce, "nonce should advance")
+
+	// Authorities without an execution delay should execute actions immediately.
+	err = authorize(testAddrC, &vault.AuthorizeAction{
+		Vault:  vaultAddr,
+		Nonce:  1,
+		Action: vault.Action{Suspend: &vault.ActionSuspend{}},
+	})
+	require.NoError(err, "authorizeAction")
+	vlt, err = state.Vault(ctx, vaultAddr)
+	require.NoError(err, "Vault")
+	require.False(vlt.IsActive(), "vault should be suspended")
+	require.EqualValues(2, vlt.Nonce, "nonce should advance")
+
+	// Actions that have already expired should be rejected.
+	err = authorize(testAddrA, &vault.AuthorizeAction{
+		Vault:  vaultAddr,
+		Nonce:  2,
+		Action: vault.Action{Resume: &vault.ActionResume{}},
+		Expiry: 12,
+	})
+	require.ErrorIs(err, vault.ErrActionExpired)
+
+	// Authorizations must agree on the expiry.
+	resume := &vault.AuthorizeAction{
+		Vault:  vaultAddr,
+		Nonce:  2,
+		Action: vault.Action{Resume: &vault.ActionResume{}},
+		Expiry: 14,
+	}
+	err = authorize(testAddrA, resume)
+	require.NoError(err, "authorizeAction")
+	err = authorize(testAddrB, &vault.AuthorizeAction{
+		Vault:  vaultAddr,
+		Nonce:  2,
+		Action: vault.Action{Resume: &vault.ActionResume{}},
+		Expiry: 15,
+	})
+	require.ErrorIs(err, vault.ErrInvalidArgument)
+
+	// Expired actions should be garbage collected.
+	advanceEpoch(13)
+	_, err = state.PendingAction(ctx, vaultAddr, 2)
+	require.NoError(err, "action should not be removed before expiry")
+	advanceEpoch(14)
+	_, err = state.PendingAction(ctx, vaultAddr, 2)
+	require.ErrorIs(err, vault.ErrNoSuchAction, "expired action should be removed")
+	vlt, err = state.Vault(ctx, vaultAddr)
+	require.NoError(err, "Vault")
+	require.EqualValues(3, vlt.Nonce, "nonce should advance")
+	require.False(vlt.IsActive(), "vault should remain suspended")
+
+	// Scheduled actions should expire if not executed before the expiry.
+	resume.Nonce = 3
+	resume.Expiry = 16
+	err = authorize(testAddrA, resume)
+	require.NoError(err, "authorizeAction")
+	err = authorize(testAddrB, resume)
+	require.NoError(err, "authorizeAction")
+	pa, err = state.PendingAction(ctx, vaultAddr, 3)
+	require.NoError(err, "PendingAction")
+	require.EqualValues(16, pa.ExecuteAt)
+
+	advanceEpoch(16)
+	_, err = state.PendingAction(ctx, vaultAddr, 3)
+	require.ErrorIs(err, vault.ErrNoSuchAction, "expired action should be removed")
+	vlt, err = state.Vault(ctx, vaultAddr)
+	require.NoError(err, "Vault")
+	require.False(vlt.IsActive(), "expired action should not be executed")
+	require.EqualValues(4, vlt.Nonce, "nonce should advance")
+}
+
+func TestSpendingPolicy(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 1,
+	})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	md := &testMsgDispatcher{}
+	app := &Application{
+		state: appState,
+		md:    md,
+	}
+
+	state := vaultState.NewMutableState(ctx.State())
+	err := state.SetConsensusParameters(ctx, &vault.ConsensusParameters{
+		MaxAuthorityAddresses: 32,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	ctx = appState.NewContext(abciAPI.ContextDeliverTx)
+	defer ctx.Close()
+
+	err = app.create(ctx, &vault.Create{
+		AdminAuthority: vault.Authority{
+			Addresses: []staking.Address{
+				testAddrA,
+			},
+			Threshold: 1,
+		},
+		SuspendAuthority: vault.Authority{
+			Addresses: []staking.Address{
+				testAddrA,
+			},
+			Threshold: 1,
+		},
+	})
+	require.NoError(err, "create")
+	vaultAddr := vault.NewVaultAddress(ctx.CallerAddress(), 0)
+
+	var nonce uint64
+	authorize := func(action vault.Action) *abciAPI.Context {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx).WithCallerAddress(testAddrA)
+		err = app.authorizeAction(txCtx, &vault.AuthorizeAction{
+			Vault:  vaultAddr,
+			Nonce:  nonce,
+			Action: action,
+		})
+		require.NoError(err, "authorizeAction")
+		nonce++
+		return txCtx
+	}
+	transfer := func(to staking.Address, amount uint64) (*abciAPI.Context, bool) {
+		delivered := len(md.delivered)
+		txCtx := authorize(vault.Action{ExecuteMessage: &vault.ActionExecuteMessage{
+			Method: staking.MethodTransfer,
+			Body: cbor.Marshal(&staking.Transfer{
+				To:     to,
+				Amount: *quantity.NewFromUint64(amount),
+			}),
+		}})
+		return txCtx, len(md.delivered) > delivered
+	}
+
+	// Transfers should not be restricted without a spending policy.
+	txCtx, ok := transfer(testAddrC, 1000)
+	require.True(ok, "transfer without a spending policy should be executed")
+	txCtx.Close()
+
+	// Configure a spending policy.
+	txCtx = authorize(vault.Action{UpdateSpendingPolicy: &vault.ActionUpdateSpendingPolicy{
+		Policy: &vault.SpendingPolicy{
+			Allowlist:     []staking.Address{testAddrB},
+			TransferLimit: *quantity.NewFromUint64(50),
+			WindowLimit:   *quantity.NewFromUint64(80),
+			WindowEpochs:  2,
+		},
+	}})
+	require.True(txCtx.HasEvent(AppName, &vault.SpendingPolicyUpdatedEvent{}))
+	txCtx.Close()
+
+	vlt, err := state.Vault(ctx, vaultAddr)
+	require.NoError(err, "Vault")
+	require.NotNil(vlt.SpendingPolicy, "spending policy should be configured")
+
+	for _, tc := range []struct {
+		msg    string
+		to     staking.Address
+		amount uint64
+		ok     bool
+	}{
+		{"transfer to a destination that is not allowed should be rejected", testAddrC, 10, false},
+		{"transfer above the transfer limit should be rejected", testAddrB, 60, false},
+		{"transfer within limits should be executed", testAddrB, 50, true},
+		{"transfer above the window limit should be rejected", testAddrB, 40, false},
+		{"transfer within the window limit should be executed", testAddrB, 30, true},
+	} {
+		txCtx, ok = transfer(tc.to, tc.amount)
+		require.Equal(tc.ok, ok, tc.msg)
+		require.Equal(!tc.ok, txCtx.HasEvent(AppName, &vault.SpendingPolicyViolatedEvent{}), tc.msg)
+		txCtx.Close()
+	}
+
+	ss, err := state.SpendingState(ctx, vaultAddr)
+	require.NoError(err, "SpendingState")
+	require.Len(ss.Epochs, 1)
+	require.EqualValues(*quantity.NewFromUint64(80), ss.Epochs[0].Amount)
+
+	// Other messages moving tokens out of the vault should also be restricted.
+	for _, tc := range []struct {
+		msg    string
+		method transaction.MethodName
+		body   any
+		ok     bool
+	}{
+		{
+			"escrow to a destination that is not allowed should be rejected",
+			staking.MethodAddEscrow,
+			&staking.Escrow{Account: testAddrC, Amount: *quantity.NewFromUint64(10)},
+			false,
+		},
+		{
+			"escrow above the window limit should be rejected",
+			staking.MethodAddEscrow,
+			&staking.Escrow{Account: testAddrB, Amount: *quantity.NewFromUint64(10)},
+			false,
+		},
+		{
+			"allowance above the window limit should be rejected",
+			staking.MethodAllow,
+			&staking.Allow{Beneficiary: testAddrB, AmountChange: *quantity.NewFromUint64(10)},
+			false,
+		},
+		{
+			"allowance decrease should be executed",
+			staking.MethodAllow,
+			&staking.Allow{Beneficiary: testAddrC, Negative: true, AmountChange: *quantity.NewFromUint64(10)},
+			true,
+		},
+		{
+			"burn with an allowlist should be rejected",
+			staking.MethodBurn,
+			&staking.Burn{Amount: *quantity.NewFromUint64(1)},
+			false,
+		},
+		{
+			"escrow reclamation should be executed",
+			staking.MethodReclaimEscrow,
+			&staking.ReclaimEscrow{Account: testAddrC, Shares: *quantity.NewFromUint64(10)},
+			true,
+		},
+	} {
+		delivered := len(md.delivered)
+		txCtx = authorize(vault.Action{ExecuteMessage: &vault.ActionExecuteMessage{
+			Method: tc.method,
+			Body:   cbor.Marshal(tc.body),
+		}})
+		require.Equal(tc.ok, len(md.delivered) > delivered, tc.msg)
+		require.Equal(!tc.ok, txCtx.HasEvent(AppName, &vault.SpendingPolicyViolatedEvent{}), tc.msg)
+		txCtx.Close()
+	}
+
+	// Allowance grants should count towards the window limit once the window moves on.
+	appState.UpdateMockApplicationStateConfig(&abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 3,
+	})
+	txCtx = authorize(vault.Action{ExecuteMessage: &vault.ActionExecuteMessage{
+		Method: staking.MethodAllow,
+		Body:   cbor.Marshal(&staking.Allow{Beneficiary: testAddrB, AmountChange: *quantity.NewFromUint64(50)}),
+	}})
+	require.False(txCtx.HasEvent(AppName, &vault.SpendingPolicyViolatedEvent{}))
+	txCtx.Close()
+	txCtx, ok = transfer(testAddrB, 40)
+	require.False(ok, "transfer above the window limit should be rejected")
+	txCtx.Close()
+
+	// Removing the spending policy should lift the restrictions.
+	txCtx = authorize(vault.Action{UpdateSpendingPolicy: &vault.ActionUpdateSpendingPolicy{}})
+	txCtx.Close()
+	txCtx, ok = transfer(testAddrC, 1000)
+	require.True(ok, "transfer without a spending policy should be executed")
+	txCtx.Close()
+}
+
+func TestScheduledActions(t *testing.T) {
+	require := require.New(t)
+
+	cfg := &abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 10,
+	}
+	appState := abciAPI.NewMockApplicationState(cfg)
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	md := &testMsgDispatcher{}
+	app := &Application{
+		state: appState,
+		md:    md,
+	}
+
+	state := vaultState.NewMutableState(ctx.State())
+	err := state.SetConsensusParameters(ctx, &vault.ConsensusParameters{
+		MaxAuthorityAddresses: 32,
+		MaxExecutionDelay:     10,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	ctx = appState.NewContext(abciAPI.ContextDeliverTx)
+	defer ctx.Close()
+
+	// Create a vault with a time-locked admin authority.
+	err = app.create(ctx, &vault.Create{
+		AdminAuthority: vault.Authority{
+			Addresses: []staking.Address{
+				testAddrA,
+				testAddrB,
+			},
+			Threshold:      2,
+			ExecutionDelay: 2,
+		},
+		SuspendAuthority: vault.Authority{
+			Addresses: []staking.Address{
+				testAddrC,
+			},
+			Threshold: 1,
+		},
+	})
+	require.NoError(err, "create")
+	vaultAddr := vault.NewVaultAddress(ctx.CallerAddress(), 0)
+
+	authorize := func(caller staking.Address, action *vault.AuthorizeAction) error {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx).WithCallerAddress(caller)
+		defer txCtx.Close()
+		return app.authorizeAction(txCtx, action)
+	}
+	cancel := func(caller staking.Address, nonce uint64) error {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx).WithCallerAddress(caller)
+		defer txCtx.Close()
+		return app.cancelAction(txCtx, &vault.CancelAction{
+			Vault: vaultAddr,
+			Nonce: nonce,
+		})
+	}
+	advanceEpoch := func(epoch beacon.EpochTime) {
+		cfg.CurrentEpoch = epoch
+		cfg.EpochChanged = true
+		appState.UpdateMockApplicationStateConfig(cfg)
+
+		blkCtx := appState.NewContext(abciAPI.ContextEndBlock)
+		defer blkCtx.Close()
+		_, err = app.EndBlock(blkCtx)
+		require.NoError(err, "EndBlock")
+	}
+	execMsg := func(nonce uint64) *vault.AuthorizeAction {
+		return &vault.AuthorizeAction{
+			Vault: vaultAddr,
+			Nonce: nonce,
+			Action: vault.Action{ExecuteMessage: &vault.ActionExecuteMessage{
+				Method: "foo.Bar",
+			}},
+		}
+	}
+
+	// Scheduling an action should release the nonce.
+	err = authorize(testAddrA, execMsg(0))
+	require.NoError(err, "authorizeAction")
+	err = authorize(testAddrB, execMsg(0))
+	require.NoError(err, "authorizeAction")
+	vlt, err := state.Vault(ctx, vaultAddr)
+	require.NoError(err, "Vault")
+	require.EqualValues(1, vlt.Nonce, "scheduling an action should advance the nonce")
+
+	// Authorizations for the scheduled action should still be accepted, but only for the same
+	// action.
+	err = authorize(testAddrB, execMsg(0))
+	require.NoError(err, "authorizeAction")
+	err = authorize(testAddrA, &vault.AuthorizeAction{
+		Vault:  vaultAddr,
+		Nonce:  0,
+		Action: vault.Action{Resume: &vault.ActionResume{}},
+	})
+	require.ErrorIs(err, vault.ErrInvalidArgument)
+
+	// The suspend authority should be able to suspend the vault while an action is scheduled.
+	err = authorize(testAddrC, &vault.AuthorizeAction{
+		Vault:  vaultAddr,
+		Nonce:  1,
+		Action: vault.Action{Suspend: &vault.ActionSuspend{}},
+	})
+	require.NoError(err, "authorizeAction")
+	vlt, err = state.Vault(ctx, vaultAddr)
+	require.NoError(err, "Vault")
+	require.False(vlt.IsActive(), "vault should be suspended")
+	require.EqualValues(2, vlt.Nonce, "nonce should advance")
+
+	// Non-authorities should not be able to cancel scheduled actions.
+	err = cancel(testAddrD, 0)
+	require.ErrorIs(err, vault.ErrForbidden)
+
+	// The suspend authority should be able to cancel scheduled admin actions.
+	err = cancel(testAddrC, 0)
+	require.NoError(err, "cancelAction")
+	_, err = state.PendingAction(ctx, vaultAddr, 0)
+	require.ErrorIs(err, vault.ErrNoSuchAction, "canceled action should be removed")
+	vlt, err = state.Vault(ctx, vaultAddr)
+	require.NoError(err, "Vault")
+	require.EqualValues(2, vlt.Nonce, "canceling a scheduled action should not advance the nonce")
+
+	entries, err := state.DuePendingActions(ctx, 100)
+	require.NoError(err, "DuePendingActions")
+	require.Empty(entries, "canceled action should be removed from the queue")
+
+	advanceEpoch(12)
+	require.Empty(md.delivered, "canceled action should not be executed")
+
+	// Scheduled actions should execute without affecting the nonce of later actions.
+	err = authorize(testAddrA, execMsg(2))
+	require.NoError(err, "authorizeAction")
+	err = authorize(testAddrB, execMsg(2))
+	require.NoError(err, "authorizeAction")
+	err = authorize(testAddrA, &vault.AuthorizeAction{
+		Vault:  vaultAddr,
+		Nonce:  3,
+		Action: vault.Action{Resume: &vault.ActionResume{}},
+	})
+	require.NoError(err, "authorizeAction")
+
+	entries, err = state.DuePendingActions(ctx, 14)
+	require.NoError(err, "DuePendingActions")
+	require.Len(entries, 1)
+	require.EqualValues(14, entries[0].Epoch)
+	require.EqualValues(2, entries[0].Action.Nonce)
+
+	advanceEpoch(14)
+	require.Len(md.delivered, 1, "scheduled action should be executed after the delay")
+	vlt, err = state.Vault(ctx, vaultAddr)
+	require.NoError(err, "Vault")
+	require.EqualValues(3, vlt.Nonce, "executing a scheduled action should not advance the nonce")
+	_, err = state.PendingAction(ctx, vaultAddr, 3)
+	require.NoError(err, "pending action should remain")
+}
@@ -1,6 +1,8 @@
 package vault
 
 import (
+	"fmt"
+
 	"github.com/cometbft/cometbft/abci/types"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
@@ -126,6 +128,11 @@ func (app *Application) BeginBlock(_ *api.Context) error {
 }
 
 // EndBlock implements api.Application.
-func (app *Application) EndBlock(_ *api.Context) (types.ResponseEndBlock, error) {
+func (app *Application) EndBlock(ctx *api.Context) (types.ResponseEndBlock, error) {
+	if changed, epoch := app.state.EpochChanged(ctx); changed {
+		if err := app.processPendingActions(ctx, epoch); err != nil {
+			return types.ResponseEndBlock{}, fmt.Errorf("cometbft/vault: failed to process pending actions: %w", err)
+		}
+	}
 	return types.ResponseEndBlock{}, nil
 }
@@ -85,7 +85,13 @@ type P2PConfig struct {
 // SubmissionConfig is the transaction submission configuration.
 type SubmissionConfig struct {
 	// Gas price used when submitting consensus transactions.
+	//
+	// This is the fallback gas price, the actual gas price is never lower than the minimum gas
+	// price, the median gas price in recent full blocks and, unless IgnoreBaseFee is set, the
+	// current base fee.
 	GasPrice uint64 `yaml:"gas_price"`
+	// Do not raise the gas price used when submitting consensus transactions to the base fee.
+	IgnoreBaseFee bool `yaml:"ignore_base_fee,omitempty"`
 	// Max transaction fee when submitting consensus transactions.
 	MaxFee uint64 `yaml:"max_fee"`
 }
@@ -95,6 +101,9 @@ const (
 	PruneStrategyNone = "none"
 	// PruneStrategyKeepN is the identifier of the strategy that keeps the last N versions.
 	PruneStrategyKeepN = "keep_n"
+	// PruneStrategyKeepEpochBoundaries is the identifier of the strategy that keeps the last N
+	// versions and the state at epoch boundaries.
+	PruneStrategyKeepEpochBoundaries = "keep_epoch_boundaries"
 )
 
 // PruneConfig is the CometBFT ABCI state pruning configuration structure.
@@ -105,6 +114,8 @@ type PruneConfig struct {
 	NumKept uint64 `yaml:"num_kept"`
 	// ABCI state pruning interval.
 	Interval time.Duration `yaml:"interval"`
+	// Retain the state at every Nth epoch boundary (when applicable). Zero means every epoch.
+	EpochInterval uint64 `yaml:"epoch_interval,omitempty"`
 	// Light blocks kept in trusted store.
 	NumLightBlocksKept uint16 `yaml:"num_light_blocks_kept"`
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	dbm "github.com/cometbft/cometbft-db"
 	cmtmerkle "github.com/cometbft/cometbft/crypto/merkle"
@@ -348,14 +349,23 @@ func (n *commonNode) Checkpointer() checkpoint.Checkpointer {
 
 // Implements consensusAPI.Backend.
 func (n *commonNode) StateToGenesis(ctx context.Context, height int64) (*genesisAPI.Document, error) {
+	var genesisTime time.Time
 	blk, err := n.GetCometBFTBlock(ctx, height)
-	if err != nil {
-		return nil, err
-	}
-	if blk == nil {
-		return nil, consensusAPI.ErrNoCommittedBlocks
+	switch err {
+	case nil:
+		if blk == nil {
+			return nil, consensusAPI.ErrNoCommittedBlocks
+		}
+		height = blk.Header.Height
+		genesisTime = blk.Header.Time
+	default:
+		// The block may have been pruned while the state has been retained as a snapshot.
+		snapshot, _, serr := n.mux.State().GetStateSnapshot(height)
+		if serr != nil {
+			return nil, err
+		}
+		genesisTime = snapshot.Time
 	}
-	height = blk.Header.Height
 
 	// Query root consensus parameters.
 	q, err := n.querier.QueryAt(ctx, height)
@@ -411,7 +421,7 @@ func (n *commonNode) StateToGenesis(ctx context.Context, height int64) (*genesis
 	return &genesisAPI.Document{
 		Height:     height,
 		ChainID:    n.chainID,
-		Time:       blk.Header.Time,
+		Time:       genesisTime,
 		Beacon:     *beaconGenesis,
 		Registry:   *registryGenesis,
 		RootHash:   *roothashGenesis,
@@ -500,6 +510,11 @@ func (n *commonNode) MinGasPrice(ctx context.Context) (*quantity.Quantity, error
 	return quantity.NewFromUint64(cp.MinGasPrice), nil
 }
 
+// Implements consensusAPI.Backend.
+func (n *commonNode) EstimateGasPrice(context.Context) (*consensusAPI.GasPriceEstimate, error) {
+	return nil, consensusAPI.ErrUnsupported
+}
+
 // Implements consensusAPI.Backend.
 func (n *commonNode) Pruner() consensusAPI.StatePruner {
 	return n.mux.Pruner()
@@ -447,6 +447,11 @@ func (t *fullService) GetStatus(ctx context.Context) (*consensusAPI.Status, erro
 	return status, nil
 }
 
+// Implements consensusAPI.Backend.
+func (t *fullService) EstimateGasPrice(context.Context) (*consensusAPI.GasPriceEstimate, error) {
+	return t.submissionMgr.PriceDiscovery().GasPriceEstimate()
+}
+
 // Implements consensusAPI.Backend.
 func (t *fullService) GetNextBlockState(ctx context.Context) (*consensusAPI.NextBlockState, error) {
 	if !t.started() {
@@ -556,6 +561,17 @@ func (t *fullService) lazyInit() error { // nolint: gocyclo
 	}
 	pruneCfg.NumKept = config.GlobalConfig.Consensus.Prune.NumKept
 	pruneCfg.PruneInterval = max(config.GlobalConfig.Consensus.Prune.Interval, time.Second)
+	pruneCfg.EpochInterval = config.GlobalConfig.Consensus.Prune.EpochInterval
+	pruneCfg.BlockTime = func(ctx context.Context, height int64) (time.Time, error) {
+		blk, err := t.GetCometBFTBlock(ctx, height)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if blk == nil {
+			return time.Time{}, consensusAPI.ErrNoCommittedBlocks
+		}
+		return blk.Header.Time, nil
+	}
 
 	appConfig := &abci.ApplicationConfig{
 		DataDir:                   filepath.Join(t.dataDir, tmcommon.StateDir),
@@ -994,7 +1010,10 @@ func New(ctx context.Context, cfg Config) (consensusAPI.Service, error) {
 	t.Logger.Info("starting a full consensus node")
 
 	// Create price discovery mechanism and the submission manager.
-	pd, err := pricediscovery.New(ctx, t, config.GlobalConfig.Consensus.Submission.GasPrice)
+	pd, err := pricediscovery.New(ctx, t,
+		config.GlobalConfig.Consensus.Submission.GasPrice,
+		!config.GlobalConfig.Consensus.Submission.IgnoreBaseFee,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create price discovery: %w", err)
 	}
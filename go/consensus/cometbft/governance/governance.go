@@ -76,6 +76,24 @@ func (sc *ServiceClient) Votes(ctx context.Context, query *api.ProposalQuery) ([
 	return q.Votes(ctx, query.ProposalID)
 }
 
+func (sc *ServiceClient) VoteBreakdown(ctx context.Context, query *api.ProposalQuery) (*api.VoteBreakdown, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.VoteBreakdown(ctx, query.ProposalID)
+}
+
+func (sc *ServiceClient) DelegatorVotes(ctx context.Context, query *api.DelegatorVotesQuery) ([]*api.DelegatorVote, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.DelegatorVotes(ctx, query.ProposalID, query.Delegator)
+}
+
 func (sc *ServiceClient) PendingUpgrades(ctx context.Context, height int64) ([]*upgrade.Descriptor, error) {
 	q, err := sc.querier.QueryAt(ctx, height)
 	if err != nil {
@@ -78,6 +78,7 @@ func CreateTestNodeGenesisDocument(identity *identity.Identity, ent *entity.Enti
 				VotingPeriod:                   10,
 				MinProposalDeposit:             *quantity.NewFromUint64(100),
 				EnableChangeParametersProposal: true,
+				EnableTextProposal:             true,
 			},
 		},
 		RootHash: roothash.Genesis{
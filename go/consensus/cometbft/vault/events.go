@@ -59,6 +59,15 @@ func EventsFromCometBFT(
 				}
 
 				evt.ActionCanceled = &e
+			case eventsAPI.IsAttributeKind(key, &vault.ActionScheduledEvent{}):
+				// Action scheduled event.
+				var e vault.ActionScheduledEvent
+				if err := eventsAPI.DecodeValue(val, &e); err != nil {
+					errs = errors.Join(errs, fmt.Errorf("vault: corrupt ActionScheduled event: %w", err))
+					continue
+				}
+
+				evt.ActionScheduled = &e
 			case eventsAPI.IsAttributeKind(key, &vault.ActionExecutedEvent{}):
 				// Action executed event.
 				var e vault.ActionExecutedEvent
@@ -68,6 +77,15 @@ func EventsFromCometBFT(
 				}
 
 				evt.ActionExecuted = &e
+			case eventsAPI.IsAttributeKind(key, &vault.ActionExpiredEvent{}):
+				// Action expired event.
+				var e vault.ActionExpiredEvent
+				if err := eventsAPI.DecodeValue(val, &e); err != nil {
+					errs = errors.Join(errs, fmt.Errorf("vault: corrupt ActionExpired event: %w", err))
+					continue
+				}
+
+				evt.ActionExpired = &e
 			case eventsAPI.IsAttributeKind(key, &vault.StateChangedEvent{}):
 				// State changed event.
 				var e vault.StateChangedEvent
@@ -95,6 +113,33 @@ func EventsFromCometBFT(
 				}
 
 				evt.AuthorityUpdated = &e
+			case eventsAPI.IsAttributeKind(key, &vault.SpendingPolicyUpdatedEvent{}):
+				// Spending policy updated event.
+				var e vault.SpendingPolicyUpdatedEvent
+				if err := eventsAPI.DecodeValue(val, &e); err != nil {
+					errs = errors.Join(errs, fmt.Errorf("vault: corrupt SpendingPolicyUpdated event: %w", err))
+					continue
+				}
+
+				evt.SpendingPolicyUpdated = &e
+			case eventsAPI.IsAttributeKind(key, &vault.SpendingPolicyViolatedEvent{}):
+				// Spending policy violated event.
+				var e vault.SpendingPolicyViolatedEvent
+				if err := eventsAPI.DecodeValue(val, &e); err != nil {
+					errs = errors.Join(errs, fmt.Errorf("vault: corrupt SpendingPolicyViolated event: %w", err))
+					continue
+				}
+
+				evt.SpendingPolicyViolated = &e
+			case eventsAPI.IsAttributeKind(key, &vault.AccountHooksUpdatedEvent{}):
+				// Account hooks updated event.
+				var e vault.AccountHooksUpdatedEvent
+				if err := eventsAPI.DecodeValue(val, &e); err != nil {
+					errs = errors.Join(errs, fmt.Errorf("vault: corrupt AccountHooksUpdated event: %w", err))
+					continue
+				}
+
+				evt.AccountHooksUpdated = &e
 			default:
 				errs = errors.Join(errs, fmt.Errorf("vault: unknown event type: key: %s, val: %s", key, val))
 				continue
@@ -66,6 +66,15 @@ func (sc *ServiceClient) AddressState(ctx context.Context, query *vault.AddressQ
 	return q.AddressState(ctx, query.Vault, query.Address)
 }
 
+func (sc *ServiceClient) SpendingState(ctx context.Context, query *vault.VaultQuery) (*vault.SpendingState, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.SpendingState(ctx, query.Address)
+}
+
 func (sc *ServiceClient) PendingActions(ctx context.Context, query *vault.VaultQuery) ([]*vault.PendingAction, error) {
 	q, err := sc.querier.QueryAt(ctx, query.Height)
 	if err != nil {
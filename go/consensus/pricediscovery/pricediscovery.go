@@ -4,12 +4,15 @@ package pricediscovery
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 )
 
 const (
@@ -18,22 +21,55 @@ const (
 	//
 	// NOTE: Code assumes that this is relatively small.
 	windowSize int = 6
+
+	// maxFullness is the block fullness (in basis points) of a completely full block.
+	maxFullness uint64 = 10_000
+	// fullBlockThreshold is the block fullness (in basis points) at or above which a block is
+	// considered full.
+	fullBlockThreshold uint64 = 9_000
+	// targetFullness is the block fullness (in basis points) at which the base fee stays the same.
+	targetFullness uint64 = 5_000
+	// baseFeeChangeDenominator bounds the amount by which the base fee can change from one block
+	// to the next (1/8 = 12.5%), same as in EIP-1559.
+	baseFeeChangeDenominator uint64 = 8
+
+	// lowPercentile is the percentile of recent gas prices used for the low estimate when
+	// blocks are congested.
+	lowPercentile = 10
+	// medianPercentile is the percentile of recent gas prices used for the median estimate.
+	medianPercentile = 50
+	// highPercentile is the percentile of recent gas prices used for the high estimate.
+	highPercentile = 90
 )
 
+// blockStats are the gas price statistics of a single block.
+type blockStats struct {
+	// prices are the gas prices of all transactions in the block, sorted in ascending order.
+	prices []*quantity.Quantity
+	// fullness is the block fullness in basis points.
+	fullness uint64
+}
+
 type priceDiscovery struct {
 	mu sync.RWMutex
 
 	// finalGasPrice is protected by the mutex.
 	finalGasPrice *quantity.Quantity
+	// estimate is protected by the mutex.
+	estimate *consensus.GasPriceEstimate
 
 	fallbackGasPrice *quantity.Quantity
 	minGasPrice      *quantity.Quantity
 	computedGasPrice *quantity.Quantity
-	// blockPrices is a rolling-array containing minimum transaction prices for last up to
-	// `windowSize` blocks.
-	blockPrices []*quantity.Quantity
-	// tracks the current index of the blockPrices rolling array.
-	blockPricesCurrentIdx int
+	baseFee          *quantity.Quantity
+	// useBaseFee specifies whether the gas price should be at least the base fee.
+	useBaseFee   bool
+	maxBlockGas  transaction.Gas
+	maxBlockSize uint64
+	// blocks is a rolling-array containing statistics for last up to `windowSize` blocks.
+	blocks []blockStats
+	// tracks the current index of the blocks rolling array.
+	blocksCurrentIdx int
 
 	consensus consensus.Backend
 
@@ -48,42 +84,82 @@ func (pd *priceDiscovery) GasPrice() (*quantity.Quantity, error) {
 	return pd.finalGasPrice.Clone(), nil
 }
 
-// refreshMinGasPrice refreshes minimum gas price reported by the consensus layer.
-func (pd *priceDiscovery) refreshMinGasPrice(ctx context.Context) {
-	mgp, err := pd.consensus.MinGasPrice(ctx)
+// GasPriceEstimate implements consensus.PriceDiscovery.
+func (pd *priceDiscovery) GasPriceEstimate() (*consensus.GasPriceEstimate, error) {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+
+	est := *pd.estimate
+	return &est, nil
+}
+
+// refreshParameters refreshes the minimum gas price and block limits reported by the consensus
+// layer.
+func (pd *priceDiscovery) refreshParameters(ctx context.Context, height int64) {
+	params, err := pd.consensus.GetParameters(ctx, height)
 	if err != nil {
-		pd.logger.Warn("failed to fetch minimum gas price",
+		pd.logger.Warn("failed to fetch consensus parameters",
 			"err", err,
+			"height", height,
 		)
 		return
 	}
 
-	pd.minGasPrice = mgp
+	pd.minGasPrice = quantity.NewFromUint64(params.Parameters.MinGasPrice)
+	pd.maxBlockGas = params.Parameters.MaxBlockGas
+	pd.maxBlockSize = params.Parameters.MaxBlockSize
 }
 
-// processBlock computes the gas price based on transactions in a block.
-func (pd *priceDiscovery) processBlock(_ context.Context, _ *consensus.Block) {
-	// Currently transactions are not ordered by price, so track price as zero. After the mempool
-	// is refactored, change this to properly compute the median gas price. Note that simply sorting
-	// transactions here wouldn't work as it wouldn't reflect the actual queuing process until the
-	// mempool is updated.
-	//
-	// We should also make sure to add some margin over the median in case of full blocks.
-	pd.trackPrice(quantity.NewQuantity())
+// processBlock computes the gas price statistics based on transactions in a block.
+func (pd *priceDiscovery) processBlock(ctx context.Context, blk *consensus.Block) {
+	txs, err := pd.consensus.GetTransactionsWithResults(ctx, blk.Height)
+	if err != nil {
+		pd.logger.Warn("failed to fetch block transactions",
+			"err", err,
+			"height", blk.Height,
+		)
+		pd.trackBlock(blockStats{})
+		return
+	}
+
+	prices, gasUsed := transactionPrices(txs)
+
+	var fullness uint64
+	switch {
+	case pd.maxBlockGas > 0:
+		fullness = uint64(gasUsed) * maxFullness / uint64(pd.maxBlockGas)
+	case pd.maxBlockSize > 0:
+		fullness = blk.Size * maxFullness / pd.maxBlockSize
+	}
+
+	pd.trackBlock(blockStats{
+		prices:   prices,
+		fullness: min(fullness, maxFullness),
+	})
 }
 
-// trackPrice records the price for a block.
-func (pd *priceDiscovery) trackPrice(price *quantity.Quantity) {
-	pd.blockPrices[pd.blockPricesCurrentIdx] = price
-	pd.blockPricesCurrentIdx = (pd.blockPricesCurrentIdx + 1) % windowSize
+// trackBlock records the statistics for a block and recomputes the gas prices.
+func (pd *priceDiscovery) trackBlock(stats blockStats) {
+	pd.blocks[pd.blocksCurrentIdx] = stats
+	pd.blocksCurrentIdx = (pd.blocksCurrentIdx + 1) % windowSize
 
-	// Find maximum gas price.
+	pd.baseFee = nextBaseFee(pd.baseFee, pd.minGasPrice, stats.fullness)
+
+	// Find maximum gas price among full blocks. In order to get included, one needs to pay more
+	// than the median price in a full block.
 	maxPrice := quantity.NewFromUint64(0)
-	for _, price := range pd.blockPrices {
+	for _, blk := range pd.blocks {
+		if blk.fullness < fullBlockThreshold {
+			continue
+		}
+		price := withMargin(percentile(blk.prices, medianPercentile))
 		if price.Cmp(maxPrice) > 0 {
 			maxPrice = price
 		}
 	}
+	if pd.useBaseFee && pd.baseFee.Cmp(maxPrice) > 0 {
+		maxPrice = pd.baseFee
+	}
 
 	// No full blocks among last `windowSize` blocks.
 	if maxPrice.IsZero() {
@@ -92,6 +168,41 @@ func (pd *priceDiscovery) trackPrice(price *quantity.Quantity) {
 	pd.computedGasPrice = maxPrice
 }
 
+// computeEstimate computes the gas price estimate based on recent blocks.
+func (pd *priceDiscovery) computeEstimate() *consensus.GasPriceEstimate {
+	var (
+		prices        []*quantity.Quantity
+		totalFullness uint64
+	)
+	for _, blk := range pd.blocks {
+		prices = append(prices, blk.prices...)
+		totalFullness += blk.fullness
+	}
+	slices.SortFunc(prices, func(a, b *quantity.Quantity) int {
+		return a.Cmp(b)
+	})
+	avgFullness := totalFullness / uint64(windowSize)
+	congested := avgFullness >= fullBlockThreshold
+
+	low := maxQuantity(pd.minGasPrice, pd.baseFee)
+	if congested {
+		low = maxQuantity(low, percentile(prices, lowPercentile))
+	}
+	median := maxQuantity(low, percentile(prices, medianPercentile))
+	high := maxQuantity(median, percentile(prices, highPercentile))
+	if congested {
+		high = withMargin(high)
+	}
+
+	return &consensus.GasPriceEstimate{
+		Low:           *low.Clone(),
+		Median:        *median.Clone(),
+		High:          *high.Clone(),
+		BaseFee:       *pd.baseFee.Clone(),
+		BlockFullness: uint8(avgFullness * 100 / maxFullness),
+	}
+}
+
 func (pd *priceDiscovery) worker(ctx context.Context, ch <-chan *consensus.Block, sub pubsub.ClosableSubscription) {
 	defer sub.Close()
 
@@ -100,7 +211,7 @@ func (pd *priceDiscovery) worker(ctx context.Context, ch <-chan *consensus.Block
 		case <-ctx.Done():
 			return
 		case blk := <-ch:
-			pd.refreshMinGasPrice(ctx)
+			pd.refreshParameters(ctx, blk.Height)
 			pd.processBlock(ctx, blk)
 
 			// Choose the maximum of (fallback, min, computed) gas prices.
@@ -111,29 +222,116 @@ func (pd *priceDiscovery) worker(ctx context.Context, ch <-chan *consensus.Block
 			if pd.minGasPrice.Cmp(gasPrice) > 0 {
 				gasPrice = pd.minGasPrice
 			}
+			estimate := pd.computeEstimate()
 
 			pd.mu.Lock()
 			pd.finalGasPrice = gasPrice.Clone()
+			pd.estimate = estimate
 			pd.mu.Unlock()
 		}
 	}
 }
 
-// New creates a new dynamic price discovery implementation.
-func New(ctx context.Context, consensus consensus.Backend, fallbackGasPrice uint64) (consensus.PriceDiscovery, error) {
-	pd := &priceDiscovery{
-		finalGasPrice:    quantity.NewFromUint64(fallbackGasPrice),
-		fallbackGasPrice: quantity.NewFromUint64(fallbackGasPrice),
-		minGasPrice:      quantity.NewQuantity(),
-		computedGasPrice: quantity.NewQuantity(),
-		consensus:        consensus,
-		logger:           logging.GetLogger("consensus/pricediscovery"),
+// transactionPrices returns the sorted gas prices of the given transactions together with the
+// total amount of gas used.
+func transactionPrices(txs *consensus.TransactionsWithResults) ([]*quantity.Quantity, transaction.Gas) {
+	var (
+		prices  []*quantity.Quantity
+		gasUsed transaction.Gas
+	)
+	for i, rawTx := range txs.Transactions {
+		var sigTx transaction.SignedTransaction
+		if err := cbor.Unmarshal(rawTx, &sigTx); err != nil {
+			continue
+		}
+		var tx transaction.Transaction
+		if err := cbor.Unmarshal(sigTx.Blob, &tx); err != nil {
+			continue
+		}
+
+		var fee transaction.Fee
+		if tx.Fee != nil {
+			fee = *tx.Fee
+		}
+		prices = append(prices, fee.GasPrice())
+
+		switch {
+		case i < len(txs.Results) && txs.Results[i].GasUsed > 0:
+			gasUsed += transaction.Gas(txs.Results[i].GasUsed)
+		default:
+			gasUsed += fee.Gas
+		}
 	}
+	slices.SortFunc(prices, func(a, b *quantity.Quantity) int {
+		return a.Cmp(b)
+	})
+
+	return prices, gasUsed
+}
 
-	pd.blockPrices = make([]*quantity.Quantity, windowSize)
-	for i := range windowSize {
-		pd.blockPrices[i] = quantity.NewQuantity()
+// percentile returns the given percentile of the sorted prices or zero if there are no prices.
+func percentile(prices []*quantity.Quantity, p int) *quantity.Quantity {
+	if len(prices) == 0 {
+		return quantity.NewQuantity()
 	}
+	return prices[(len(prices)-1)*p/100]
+}
+
+// nextBaseFee computes the base fee for the next block based on the fullness of the current
+// block. The base fee increases when blocks are more than half full and decreases otherwise, but
+// is never lower than the minimum gas price.
+func nextBaseFee(baseFee, minGasPrice *quantity.Quantity, fullness uint64) *quantity.Quantity {
+	next := baseFee.Clone()
+
+	var diff uint64
+	switch {
+	case fullness > targetFullness:
+		diff = fullness - targetFullness
+	default:
+		diff = targetFullness - fullness
+	}
+
+	delta := baseFee.Clone()
+	_ = delta.Mul(quantity.NewFromUint64(diff))
+	_ = delta.Quo(quantity.NewFromUint64(targetFullness * baseFeeChangeDenominator))
+
+	switch {
+	case fullness > targetFullness:
+		// Make sure that the base fee can grow even if it is currently zero.
+		if delta.IsZero() {
+			delta = quantity.NewFromUint64(1)
+		}
+		_ = next.Add(delta)
+	case fullness < targetFullness:
+		_, _ = next.SubUpTo(delta)
+	}
+
+	return maxQuantity(next, minGasPrice)
+}
+
+// withMargin returns the given price increased by a safety margin of 12.5%, rounded up.
+func withMargin(price *quantity.Quantity) *quantity.Quantity {
+	margin := price.Clone()
+	_ = margin.Add(quantity.NewFromUint64(baseFeeChangeDenominator - 1))
+	_ = margin.Quo(quantity.NewFromUint64(baseFeeChangeDenominator))
+
+	res := price.Clone()
+	_ = res.Add(margin)
+	return res
+}
+
+func maxQuantity(a, b *quantity.Quantity) *quantity.Quantity {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// New creates a new dynamic price discovery implementation.
+//
+// In case useBaseFee is set, the reported gas price is never lower than the current base fee.
+func New(ctx context.Context, consensus consensus.Backend, fallbackGasPrice uint64, useBaseFee bool) (consensus.PriceDiscovery, error) {
+	pd := newPriceDiscovery(consensus, fallbackGasPrice, useBaseFee)
 
 	// Subscribe to consensus layer blocks and start watching.
 	ch, sub, err := pd.consensus.WatchBlocks(ctx)
@@ -144,3 +342,20 @@ func New(ctx context.Context, consensus consensus.Backend, fallbackGasPrice uint
 
 	return pd, nil
 }
+
+func newPriceDiscovery(consensus consensus.Backend, fallbackGasPrice uint64, useBaseFee bool) *priceDiscovery {
+	pd := &priceDiscovery{
+		finalGasPrice:    quantity.NewFromUint64(fallbackGasPrice),
+		fallbackGasPrice: quantity.NewFromUint64(fallbackGasPrice),
+		minGasPrice:      quantity.NewQuantity(),
+		computedGasPrice: quantity.NewQuantity(),
+		baseFee:          quantity.NewQuantity(),
+		useBaseFee:       useBaseFee,
+		blocks:           make([]blockStats, windowSize),
+		consensus:        consensus,
+		logger:           logging.GetLogger("consensus/pricediscovery"),
+	}
+	pd.estimate = pd.computeEstimate()
+
+	return pd
+}
@@ -0,0 +1,141 @@
+package pricediscovery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func sortedPrices(prices ...uint64) []*quantity.Quantity {
+	qs := make([]*quantity.Quantity, 0, len(prices))
+	for _, p := range prices {
+		qs = append(qs, quantity.NewFromUint64(p))
+	}
+	return qs
+}
+
+func TestPercentile(t *testing.T) {
+	require := require.New(t)
+
+	require.True(percentile(nil, medianPercentile).IsZero())
+
+	prices := sortedPrices(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11)
+	require.EqualValues(quantity.NewFromUint64(2), percentile(prices, lowPercentile))
+	require.EqualValues(quantity.NewFromUint64(6), percentile(prices, medianPercentile))
+	require.EqualValues(quantity.NewFromUint64(10), percentile(prices, highPercentile))
+}
+
+func TestNextBaseFee(t *testing.T) {
+	require := require.New(t)
+
+	minGasPrice := quantity.NewFromUint64(10)
+	baseFee := quantity.NewFromUint64(800)
+
+	// Full blocks increase the base fee by 12.5%.
+	require.EqualValues(quantity.NewFromUint64(900), nextBaseFee(baseFee, minGasPrice, maxFullness))
+	// Empty blocks decrease the base fee by 12.5%.
+	require.EqualValues(quantity.NewFromUint64(700), nextBaseFee(baseFee, minGasPrice, 0))
+	// Blocks at target fullness keep the base fee.
+	require.EqualValues(baseFee, nextBaseFee(baseFee, minGasPrice, targetFullness))
+
+	// Base fee should never be lower than the minimum gas price.
+	require.EqualValues(minGasPrice, nextBaseFee(quantity.NewFromUint64(11), minGasPrice, 0))
+
+	// Base fee should be able to grow from zero.
+	require.EqualValues(quantity.NewFromUint64(1), nextBaseFee(quantity.NewQuantity(), quantity.NewQuantity(), maxFullness))
+}
+
+func TestWithMargin(t *testing.T) {
+	require := require.New(t)
+
+	require.EqualValues(quantity.NewQuantity(), withMargin(quantity.NewQuantity()))
+	require.EqualValues(quantity.NewFromUint64(2), withMargin(quantity.NewFromUint64(1)))
+	require.EqualValues(quantity.NewFromUint64(900), withMargin(quantity.NewFromUint64(800)))
+}
+
+func TestTransactionPrices(t *testing.T) {
+	require := require.New(t)
+
+	var txs consensus.TransactionsWithResults
+	for _, fee := range []*transaction.Fee{
+		{Amount: *quantity.NewFromUint64(3000), Gas: 1000},
+		{Amount: *quantity.NewFromUint64(1000), Gas: 1000},
+		nil,
+	} {
+		tx := transaction.NewTransaction(0, fee, staking.MethodTransfer, &staking.Transfer{})
+		sigTx := transaction.SignedTransaction{
+			Signed: signature.Signed{
+				Blob: cbor.Marshal(tx),
+			},
+		}
+
+		txs.Transactions = append(txs.Transactions, cbor.Marshal(sigTx))
+		txs.Results = append(txs.Results, &results.Result{GasUsed: 500})
+	}
+	// Malformed transactions should be ignored.
+	txs.Transactions = append(txs.Transactions, []byte("malformed"))
+	txs.Results = append(txs.Results, &results.Result{})
+
+	prices, gasUsed := transactionPrices(&txs)
+	require.EqualValues(sortedPrices(0, 1, 3), prices)
+	require.EqualValues(1500, gasUsed)
+}
+
+func TestGasPriceEstimate(t *testing.T) {
+	require := require.New(t)
+
+	pd := newPriceDiscovery(nil, 0, true)
+
+	est, err := pd.GasPriceEstimate()
+	require.NoError(err)
+	require.True(est.Low.IsZero())
+	require.True(est.High.IsZero())
+
+	// Blocks that are not congested.
+	pd.minGasPrice = quantity.NewFromUint64(5)
+	for range windowSize {
+		pd.trackBlock(blockStats{
+			prices:   sortedPrices(5, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100),
+			fullness: targetFullness,
+		})
+	}
+	require.EqualValues(pd.minGasPrice, pd.computedGasPrice, "computed gas price should be the base fee without full blocks")
+
+	// The base fee should not be taken into account when disabled.
+	noBaseFee := newPriceDiscovery(nil, 0, false)
+	noBaseFee.minGasPrice = quantity.NewFromUint64(5)
+	noBaseFee.trackBlock(blockStats{fullness: targetFullness})
+	require.Nil(noBaseFee.computedGasPrice, "computed gas price should ignore the base fee")
+
+	est = pd.computeEstimate()
+	require.EqualValues(*quantity.NewFromUint64(5), est.Low)
+	require.EqualValues(*quantity.NewFromUint64(50), est.Median)
+	require.EqualValues(*quantity.NewFromUint64(90), est.High)
+	require.EqualValues(*quantity.NewFromUint64(5), est.BaseFee)
+	require.EqualValues(50, est.BlockFullness)
+
+	// Congested blocks.
+	for range windowSize {
+		pd.trackBlock(blockStats{
+			prices:   sortedPrices(5, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100),
+			fullness: maxFullness,
+		})
+	}
+	require.NotNil(pd.computedGasPrice)
+	require.True(pd.computedGasPrice.Cmp(quantity.NewFromUint64(50)) > 0, "computed gas price should exceed median")
+
+	est = pd.computeEstimate()
+	require.True(est.Low.Cmp(quantity.NewFromUint64(10)) >= 0)
+	require.True(est.BaseFee.Cmp(quantity.NewFromUint64(5)) > 0, "base fee should grow")
+	require.EqualValues(*quantity.NewFromUint64(50), est.Median)
+	require.EqualValues(*withMargin(quantity.NewFromUint64(90)), est.High)
+	require.EqualValues(100, est.BlockFullness)
+}
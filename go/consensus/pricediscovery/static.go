@@ -24,3 +24,12 @@ func NewStatic(price uint64) (consensus.PriceDiscovery, error) {
 func (pd *staticPriceDiscovery) GasPrice() (*quantity.Quantity, error) {
 	return pd.price.Clone(), nil
 }
+
+func (pd *staticPriceDiscovery) GasPriceEstimate() (*consensus.GasPriceEstimate, error) {
+	return &consensus.GasPriceEstimate{
+		Low:     *pd.price.Clone(),
+		Median:  *pd.price.Clone(),
+		High:    *pd.price.Clone(),
+		BaseFee: *pd.price.Clone(),
+	}, nil
+}
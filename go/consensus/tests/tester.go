@@ -131,6 +131,11 @@ func ConsensusImplementationTests(t *testing.T, consensus api.Backend) {
 	})
 	require.NoError(err, "EstimateGas")
 
+	gasPriceEstimate, err := consensus.EstimateGasPrice(ctx)
+	require.NoError(err, "EstimateGasPrice")
+	require.True(gasPriceEstimate.Low.Cmp(&gasPriceEstimate.Median) <= 0, "low gas price should not exceed median")
+	require.True(gasPriceEstimate.Median.Cmp(&gasPriceEstimate.High) <= 0, "median gas price should not exceed high")
+
 	nonce, err := consensus.GetSignerNonce(ctx, &api.GetSignerNonceRequest{
 		AccountAddress: staking.NewAddress(
 			signature.NewPublicKey("badfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
@@ -19,6 +19,7 @@ import (
 	block "github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 	"github.com/oasisprotocol/oasis-core/go/runtime/history"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	commonWorker "github.com/oasisprotocol/oasis-core/go/worker/common/api"
@@ -69,6 +70,17 @@ type NodeController interface {
 	// If the bundle upgrades an existing ROFL component, the latter will
 	// be upgraded to the new version.
 	AddBundle(ctx context.Context, path string) error
+
+	// GetStorageUsage returns a breakdown of the storage space used by the node database of
+	// each runtime with local storage.
+	//
+	// Backends that share tree nodes between roots of different types (e.g. badger) cannot
+	// attribute tree nodes to a root type and report them as unattributed instead.
+	GetStorageUsage(ctx context.Context) (map[common.Namespace]*storage.Usage, error)
+
+	// CompactStorage compacts the node database of the given runtime, reclaiming any space that
+	// has been left behind by pruning. The call returns once compaction completes.
+	CompactStorage(ctx context.Context, runtimeID common.Namespace) error
 }
 
 // Status is the current status overview.
@@ -218,6 +230,9 @@ type ComponentStatus struct {
 	// Disabled specifies whether the component is disabled by default
 	// and needs to be explicitly enabled via node configuration to be used.
 	Disabled bool `json:"disabled,omitempty"`
+
+	// ResourceUsage is the resource usage of the running component in case it is available.
+	ResourceUsage *host.ResourceUsage `json:"resource_usage,omitempty"`
 }
 
 // SeedStatus is the status of the seed node.
@@ -5,7 +5,9 @@ import (
 
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
 	upgradeApi "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
@@ -31,6 +33,10 @@ var (
 	methodGetStatus = serviceName.NewMethod("GetStatus", nil)
 	// methodAddBundle is the AddBundle method.
 	methodAddBundle = serviceName.NewMethod("AddBundle", nil)
+	// methodGetStorageUsage is the GetStorageUsage method.
+	methodGetStorageUsage = serviceName.NewMethod("GetStorageUsage", nil)
+	// methodCompactStorage is the CompactStorage method.
+	methodCompactStorage = serviceName.NewMethod("CompactStorage", common.Namespace{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -73,6 +79,14 @@ var (
 				MethodName: methodAddBundle.ShortName(),
 				Handler:    handlerAddBundle,
 			},
+			{
+				MethodName: methodGetStorageUsage.ShortName(),
+				Handler:    handlerGetStorageUsage,
+			},
+			{
+				MethodName: methodCompactStorage.ShortName(),
+				Handler:    handlerCompactStorage,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -265,6 +279,48 @@ func handlerAddBundle(
 	return interceptor(ctx, &path, info, handler)
 }
 
+func handlerGetStorageUsage(
+	srv any,
+	ctx context.Context,
+	_ func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	if interceptor == nil {
+		return srv.(NodeController).GetStorageUsage(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetStorageUsage.FullName(),
+	}
+	handler := func(ctx context.Context, _ any) (any, error) {
+		return srv.(NodeController).GetStorageUsage(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerCompactStorage(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).CompactStorage(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodCompactStorage.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(NodeController).CompactStorage(ctx, *req.(*common.Namespace))
+	}
+	return interceptor(ctx, &runtimeID, info, handler)
+}
+
 // RegisterService registers a new node controller service with the given gRPC server.
 func RegisterService(server *grpc.Server, service NodeController) {
 	server.RegisterService(&serviceDesc, service)
@@ -333,3 +389,15 @@ func (c *NodeControllerClient) AddBundle(ctx context.Context, path string) error
 	}
 	return nil
 }
+
+func (c *NodeControllerClient) GetStorageUsage(ctx context.Context) (map[common.Namespace]*storageApi.Usage, error) {
+	var rsp map[common.Namespace]*storageApi.Usage
+	if err := c.conn.Invoke(ctx, methodGetStorageUsage.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *NodeControllerClient) CompactStorage(ctx context.Context, runtimeID common.Namespace) error {
+	return c.conn.Invoke(ctx, methodCompactStorage.FullName(), runtimeID, nil)
+}
@@ -54,6 +54,7 @@ var (
 	_ prettyprint.PrettyPrinter = (*UpgradeProposal)(nil)
 	_ prettyprint.PrettyPrinter = (*CancelUpgradeProposal)(nil)
 	_ prettyprint.PrettyPrinter = (*ChangeParametersProposal)(nil)
+	_ prettyprint.PrettyPrinter = (*TextProposal)(nil)
 	_ prettyprint.PrettyPrinter = (*ProposalVote)(nil)
 )
 
@@ -65,6 +66,7 @@ type ProposalContent struct {
 	Upgrade          *UpgradeProposal          `json:"upgrade,omitempty"`
 	CancelUpgrade    *CancelUpgradeProposal    `json:"cancel_upgrade,omitempty"`
 	ChangeParameters *ChangeParametersProposal `json:"change_parameters,omitempty"`
+	Text             *TextProposal             `json:"text,omitempty"`
 }
 
 // ValidateBasic performs basic proposal content validity checks.
@@ -95,6 +97,9 @@ func (p *ProposalContent) ValidateBasic(params *ConsensusParameters) error {
 	if p.ChangeParameters != nil {
 		numProposals++
 	}
+	if p.Text != nil {
+		numProposals++
+	}
 
 	switch {
 	case numProposals > 1:
@@ -109,6 +114,10 @@ func (p *ProposalContent) ValidateBasic(params *ConsensusParameters) error {
 		if err := p.ChangeParameters.ValidateBasic(); err != nil {
 			return fmt.Errorf("change parameters proposal validation failed: %w", err)
 		}
+	case p.Text != nil:
+		if err := p.Text.ValidateBasic(); err != nil {
+			return fmt.Errorf("text proposal validation failed: %w", err)
+		}
 	default:
 		return fmt.Errorf("proposal content has no fields set")
 	}
@@ -136,6 +145,9 @@ func (p *ProposalContent) Equals(other *ProposalContent) bool {
 	if !p.ChangeParameters.Equals(other.ChangeParameters) {
 		return false
 	}
+	if !p.Text.Equals(other.Text) {
+		return false
+	}
 	return true
 }
 
@@ -157,6 +169,10 @@ func (p ProposalContent) PrettyPrint(ctx context.Context, prefix string, w io.Wr
 		fmt.Fprintf(w, "%sChange Parameters:\n", prefix)
 		p.ChangeParameters.PrettyPrint(ctx, prefix+"  ", w)
 	}
+	if p.Text != nil {
+		fmt.Fprintf(w, "%sText:\n", prefix)
+		p.Text.PrettyPrint(ctx, prefix+"  ", w)
+	}
 }
 
 // PrettyType returns a representation of ProposalContent that can be used for
@@ -170,6 +186,9 @@ const (
 	MinProposalTitleLength = 3
 	// MaxProposalTitleLength is the maximum length of a proposal's title.
 	MaxProposalTitleLength = 100
+
+	// MaxTextProposalURILength is the maximum length of a text proposal's URI.
+	MaxTextProposalURILength = 1024
 )
 
 // ProposalMetadata contains metadata about a proposal.
@@ -332,6 +351,69 @@ func (p *ChangeParametersProposal) ValidateBasic() error {
 	return nil
 }
 
+// TextProposal is a non-binding signaling proposal which references off-chain content. Passing
+// a text proposal has no effect on the consensus state besides the proposal being recorded as
+// passed.
+type TextProposal struct {
+	// Title is the human-readable proposal title.
+	Title string `json:"title"`
+	// ContentHash is the hash of the off-chain proposal content.
+	ContentHash hash.Hash `json:"content_hash"`
+	// URI is the location of the off-chain proposal content.
+	URI string `json:"uri,omitempty"`
+}
+
+// Equals checks if text proposals are equal.
+func (p *TextProposal) Equals(other *TextProposal) bool {
+	if p == other {
+		return true
+	}
+	if p == nil || other == nil {
+		return false
+	}
+	if p.Title != other.Title {
+		return false
+	}
+	if !p.ContentHash.Equal(&other.ContentHash) {
+		return false
+	}
+	if p.URI != other.URI {
+		return false
+	}
+	return true
+}
+
+// ValidateBasic performs a basic validation on the text proposal.
+func (p *TextProposal) ValidateBasic() error {
+	if len(p.Title) < MinProposalTitleLength {
+		return fmt.Errorf("invalid title: title too short")
+	}
+	if len(p.Title) > MaxProposalTitleLength {
+		return fmt.Errorf("invalid title: title too long")
+	}
+	if p.ContentHash.IsEmpty() || p.ContentHash == (hash.Hash{}) {
+		return fmt.Errorf("invalid content hash: hash should not be empty")
+	}
+	if len(p.URI) > MaxTextProposalURILength {
+		return fmt.Errorf("invalid URI: URI too long")
+	}
+	return nil
+}
+
+// PrettyPrint writes a pretty-printed representation of TextProposal to the given writer.
+func (p TextProposal) PrettyPrint(_ context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sTitle: %s\n", prefix, p.Title)
+	fmt.Fprintf(w, "%sContent Hash: %s\n", prefix, p.ContentHash)
+	if len(p.URI) > 0 {
+		fmt.Fprintf(w, "%sURI: %s\n", prefix, p.URI)
+	}
+}
+
+// PrettyType returns a representation of TextProposal that can be used for pretty printing.
+func (p TextProposal) PrettyType() (any, error) {
+	return p, nil
+}
+
 // ProposalVote is a vote for a proposal.
 type ProposalVote struct {
 	// ID is the unique identifier of a proposal.
@@ -367,6 +449,18 @@ type Backend interface {
 	// Votes looks up votes for a specific proposal.
 	Votes(ctx context.Context, query *ProposalQuery) ([]*VoteEntry, error)
 
+	// VoteBreakdown computes the per-validator breakdown of votes for a specific proposal,
+	// including the votes of delegators that override the votes of their validators.
+	//
+	// The breakdown is computed based on the validator set and escrow at the given height. For
+	// closed proposals, only the total results are final and the per-validator breakdown is
+	// approximate unless queried at the height before the proposal was closed.
+	VoteBreakdown(ctx context.Context, query *ProposalQuery) (*VoteBreakdown, error)
+
+	// DelegatorVotes computes the effective votes of a specific delegator for a specific
+	// proposal, one for each validator that the delegator delegates to.
+	DelegatorVotes(ctx context.Context, query *DelegatorVotesQuery) ([]*DelegatorVote, error)
+
 	// PendingUpgrades returns a list of all pending upgrades.
 	PendingUpgrades(ctx context.Context, height int64) ([]*upgrade.Descriptor, error)
 
@@ -395,6 +489,61 @@ type VoteEntry struct {
 	Vote  Vote            `json:"vote"`
 }
 
+// DelegatorVotesQuery is a delegator votes query.
+type DelegatorVotesQuery struct {
+	Height     int64           `json:"height"`
+	ProposalID uint64          `json:"id"`
+	Delegator  staking.Address `json:"delegator"`
+}
+
+// DelegatorVote is the effective vote of a delegation to a validator.
+type DelegatorVote struct {
+	// Delegator is the address of the delegator.
+	Delegator staking.Address `json:"delegator"`
+	// Validator is the address of the validator entity the delegator delegates to.
+	Validator staking.Address `json:"validator"`
+	// Vote is the effective vote of the delegation. In case the delegator has not voted, this is
+	// the vote of the validator (if any).
+	Vote *Vote `json:"vote,omitempty"`
+	// Override is true iff the delegator has voted, overriding the vote of the validator.
+	Override bool `json:"override,omitempty"`
+	// Shares is the number of escrow shares of the delegation.
+	Shares quantity.Quantity `json:"shares"`
+	// Stake is the amount of stake corresponding to the escrow shares of the delegation.
+	Stake quantity.Quantity `json:"stake"`
+}
+
+// ValidatorVoteBreakdown is the breakdown of votes for a single validator entity.
+type ValidatorVoteBreakdown struct {
+	// Validator is the address of the validator entity.
+	Validator staking.Address `json:"validator"`
+	// Vote is the vote of the validator (if any).
+	Vote *Vote `json:"vote,omitempty"`
+	// Stake is the total active escrow of the validator.
+	Stake quantity.Quantity `json:"stake"`
+	// Results are the amounts of stake of the validator that count towards each vote.
+	Results map[Vote]quantity.Quantity `json:"results,omitempty"`
+	// Overrides are the votes of delegators that have voted themselves.
+	Overrides []*DelegatorVote `json:"overrides,omitempty"`
+}
+
+// VoteBreakdown is the breakdown of votes for a proposal.
+type VoteBreakdown struct {
+	// TotalVotingStake is the total stake of all validator entities.
+	TotalVotingStake quantity.Quantity `json:"total_voting_stake"`
+	// Results are the amounts of stake that count towards each vote.
+	Results map[Vote]quantity.Quantity `json:"results,omitempty"`
+	// InvalidVotes is the number of votes cast by accounts that are neither validators nor
+	// delegate to any validator.
+	InvalidVotes uint64 `json:"invalid_votes,omitempty"`
+	// Validators is the per-validator breakdown of votes, ordered by validator address.
+	Validators []*ValidatorVoteBreakdown `json:"validators,omitempty"`
+	// Closed is true iff the proposal has been closed. In this case, Results and InvalidVotes are
+	// the final results recorded when the proposal was closed, while the per-validator breakdown
+	// is based on the validator set and escrow at the queried height.
+	Closed bool `json:"closed,omitempty"`
+}
+
 // Genesis is the initial governance state for use in the genesis block.
 //
 // Note: PendingProposalUpgrades are not included in genesis, but are instead
@@ -440,6 +589,9 @@ type ConsensusParameters struct {
 	// EnableChangeParametersProposal is true iff change parameters proposals are allowed.
 	EnableChangeParametersProposal bool `json:"enable_change_parameters_proposal,omitempty"`
 
+	// EnableTextProposal is true iff text proposals are allowed.
+	EnableTextProposal bool `json:"enable_text_proposal,omitempty"`
+
 	// AllowVoteWithoutEntity is true iff casting votes without a registered entity is allowed.
 	AllowVoteWithoutEntity bool `json:"allow_vote_without_entity,omitempty"`
 
@@ -470,6 +622,9 @@ type ConsensusParameterChanges struct {
 
 	// EnableChangeParametersProposal is the new enable change parameters proposal flag.
 	EnableChangeParametersProposal *bool `json:"enable_change_parameters_proposal,omitempty"`
+
+	// EnableTextProposal is the new enable text proposal flag.
+	EnableTextProposal *bool `json:"enable_text_proposal,omitempty"`
 }
 
 // Apply applies changes to the given consensus parameters.
@@ -495,6 +650,9 @@ func (c *ConsensusParameterChanges) Apply(params *ConsensusParameters) error {
 	if c.EnableChangeParametersProposal != nil {
 		params.EnableChangeParametersProposal = *c.EnableChangeParametersProposal
 	}
+	if c.EnableTextProposal != nil {
+		params.EnableTextProposal = *c.EnableTextProposal
+	}
 	return nil
 }
 
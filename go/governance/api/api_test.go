@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
@@ -81,6 +83,56 @@ func TestValidateBasic(t *testing.T) {
 			},
 			shouldErr: false,
 		},
+		{
+			msg: "text proposal without content hash should fail",
+			p: &ProposalContent{
+				Text: &TextProposal{Title: "Signaling proposal"},
+			},
+			shouldErr: true,
+		},
+		{
+			msg: "text proposal with a too short title should fail",
+			p: &ProposalContent{
+				Text: &TextProposal{
+					Title:       "A",
+					ContentHash: hash.NewFromBytes([]byte("content")),
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			msg: "text proposal with a too long URI should fail",
+			p: &ProposalContent{
+				Text: &TextProposal{
+					Title:       "Signaling proposal",
+					ContentHash: hash.NewFromBytes([]byte("content")),
+					URI:         strings.Repeat("a", MaxTextProposalURILength+1),
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			msg: "valid text proposal should not fail",
+			p: &ProposalContent{
+				Text: &TextProposal{
+					Title:       "Signaling proposal",
+					ContentHash: hash.NewFromBytes([]byte("content")),
+					URI:         "https://example.com/proposal.md",
+				},
+			},
+			shouldErr: false,
+		},
+		{
+			msg: "only one of Text/CancelUpgrade fields should be set",
+			p: &ProposalContent{
+				CancelUpgrade: &CancelUpgradeProposal{},
+				Text: &TextProposal{
+					Title:       "Signaling proposal",
+					ContentHash: hash.NewFromBytes([]byte("content")),
+				},
+			},
+			shouldErr: true,
+		},
 	} {
 		err := tc.p.ValidateBasic(&tc.params) //nolint: gosec
 		if tc.shouldErr {
@@ -158,6 +210,26 @@ func TestProposalContentEquals(t *testing.T) {
 			},
 			equals: false,
 		},
+		{
+			msg: "text proposals should be equal",
+			p1: &ProposalContent{
+				Text: &TextProposal{Title: "test", ContentHash: hash.NewFromBytes([]byte("content"))},
+			},
+			p2: &ProposalContent{
+				Text: &TextProposal{Title: "test", ContentHash: hash.NewFromBytes([]byte("content"))},
+			},
+			equals: true,
+		},
+		{
+			msg: "text proposals should not be equal",
+			p1: &ProposalContent{
+				Text: &TextProposal{Title: "test", ContentHash: hash.NewFromBytes([]byte("content"))},
+			},
+			p2: &ProposalContent{
+				Text: &TextProposal{Title: "test", ContentHash: hash.NewFromBytes([]byte("other content"))},
+			},
+			equals: false,
+		},
 	} {
 		require.Equal(t, tc.equals, tc.p1.Equals(tc.p2), tc.msg)
 	}
@@ -197,6 +269,12 @@ func TestProposalContentPrettyPrint(t *testing.T) {
 				},
 			},
 		},
+		{
+			expRegex: "^Text:",
+			p: &ProposalContent{
+				Text: &TextProposal{Title: "test", ContentHash: hash.NewFromBytes([]byte("content"))},
+			},
+		},
 	} {
 		var actualPrettyPrint bytes.Buffer
 		tc.p.PrettyPrint(context.Background(), "", &actualPrettyPrint)
@@ -268,6 +346,15 @@ func TestProposalContentSerialization(t *testing.T) {
 				},
 			}, "oXFjaGFuZ2VfcGFyYW1ldGVyc6JmbW9kdWxla3Rlc3QtbW9kdWxlZ2NoYW5nZXOhbXZvdGluZ19wZXJpb2QYew==",
 		},
+		{
+			ProposalContent{
+				Text: &TextProposal{
+					Title:       "test-title",
+					ContentHash: hash.NewFromBytes([]byte("test-content")),
+					URI:         "https://example.com/test",
+				},
+			}, "oWR0ZXh0o2N1cml4GGh0dHBzOi8vZXhhbXBsZS5jb20vdGVzdGV0aXRsZWp0ZXN0LXRpdGxlbGNvbnRlbnRfaGFzaFgggJ38dP9Kga8AHrJJ7GBEiDqFoNwHSTKAkaI3YXr8k0U=",
+		},
 	} {
 		enc := cbor.Marshal(tc.content)
 		require.Equal(tc.expectedBase64, base64.StdEncoding.EncodeToString(enc), "serialization should match")
@@ -22,6 +22,10 @@ var (
 	methodProposal = serviceName.NewMethod("Proposal", ProposalQuery{})
 	// methodVotes is the Votes method.
 	methodVotes = serviceName.NewMethod("Votes", ProposalQuery{})
+	// methodVoteBreakdown is the VoteBreakdown method.
+	methodVoteBreakdown = serviceName.NewMethod("VoteBreakdown", ProposalQuery{})
+	// methodDelegatorVotes is the DelegatorVotes method.
+	methodDelegatorVotes = serviceName.NewMethod("DelegatorVotes", DelegatorVotesQuery{})
 	// methodPendingUpgrades is the PendingUpgrades method.
 	methodPendingUpgrades = serviceName.NewMethod("PendingUpgrades", int64(0))
 	// methodStateToGenesis is the StateToGenesis method.
@@ -55,6 +59,14 @@ var (
 				MethodName: methodVotes.ShortName(),
 				Handler:    handlerVotes,
 			},
+			{
+				MethodName: methodVoteBreakdown.ShortName(),
+				Handler:    handlerVoteBreakdown,
+			},
+			{
+				MethodName: methodDelegatorVotes.ShortName(),
+				Handler:    handlerDelegatorVotes,
+			},
 			{
 				MethodName: methodPendingUpgrades.ShortName(),
 				Handler:    handlerPendingUpgrades,
@@ -151,6 +163,52 @@ func handlerVotes(
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerVoteBreakdown(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var query ProposalQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).VoteBreakdown(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodVoteBreakdown.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).VoteBreakdown(ctx, req.(*ProposalQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerDelegatorVotes(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var query DelegatorVotesQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).DelegatorVotes(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodDelegatorVotes.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).DelegatorVotes(ctx, req.(*DelegatorVotesQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerProposal(
 	srv any,
 	ctx context.Context,
@@ -342,6 +400,22 @@ func (c *Client) Votes(ctx context.Context, request *ProposalQuery) ([]*VoteEntr
 	return rsp, nil
 }
 
+func (c *Client) VoteBreakdown(ctx context.Context, request *ProposalQuery) (*VoteBreakdown, error) {
+	var rsp VoteBreakdown
+	if err := c.conn.Invoke(ctx, methodVoteBreakdown.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *Client) DelegatorVotes(ctx context.Context, request *DelegatorVotesQuery) ([]*DelegatorVote, error) {
+	var rsp []*DelegatorVote
+	if err := c.conn.Invoke(ctx, methodDelegatorVotes.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *Client) PendingUpgrades(ctx context.Context, height int64) ([]*upgrade.Descriptor, error) {
 	var rsp []*upgrade.Descriptor
 	if err := c.conn.Invoke(ctx, methodPendingUpgrades.FullName(), height, &rsp); err != nil {
@@ -41,7 +41,8 @@ func (c *ConsensusParameterChanges) SanityCheck() error {
 		c.StakeThreshold == nil &&
 		c.UpgradeMinEpochDiff == nil &&
 		c.UpgradeCancelMinEpochDiff == nil &&
-		c.EnableChangeParametersProposal == nil {
+		c.EnableChangeParametersProposal == nil &&
+		c.EnableTextProposal == nil {
 		return fmt.Errorf("consensus parameter changes should not be empty")
 	}
 	return nil
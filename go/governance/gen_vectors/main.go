@@ -117,6 +117,22 @@ func main() {
 				}
 			}
 
+			// Generate text proposal transactions.
+			for _, title := range []string{"", "Text proposal", string(make([]byte, governance.MaxProposalTitleLength+1))} {
+				for _, uri := range []string{"", "https://example.com/proposal.md"} {
+					text := &governance.TextProposal{
+						Title:       title,
+						ContentHash: hash.NewFromBytes([]byte("text proposal content")),
+						URI:         uri,
+					}
+					tx := governance.NewSubmitProposalTx(nonce, fee, &governance.ProposalContent{
+						Text: text,
+					})
+					valid := text.ValidateBasic() == nil
+					vectors = append(vectors, testvectors.MakeTestVector("SubmitProposal", tx, valid))
+				}
+			}
+
 			// Generate cast vote transactions.
 			for _, id := range []uint64{0, 1000, 10_000_000, math.MaxUint64} {
 				for _, vote := range []governance.Vote{
@@ -0,0 +1,77 @@
+// Package bundle implements the runtime bundle sub-commands.
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+)
+
+var (
+	bundleCmd = &cobra.Command{
+		Use:   "bundle",
+		Short: "runtime bundle utilities",
+	}
+
+	bundleSignCmd = &cobra.Command{
+		Use:   "sign <bundle> [<output>]",
+		Args:  cobra.RangeArgs(1, 2),
+		Short: "sign a runtime bundle manifest as a publisher",
+		Long: "Sign the manifest of a runtime bundle using the entity signer and attach the\n" +
+			"signature to the bundle, so that it is accepted by nodes that trust the signer as\n" +
+			"a publisher of the runtime.\n\n" +
+			"The signed bundle is written to the output path, if given, or replaces the\n" +
+			"original bundle. Existing publisher signatures are preserved.",
+		RunE: doSign,
+	}
+)
+
+func doSign(_ *cobra.Command, args []string) error {
+	src := args[0]
+	dst := src
+	if len(args) > 1 {
+		dst = args[1]
+	}
+
+	signerDir, err := cmdSigner.CLIDirOrPwd()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve signer dir: %w", err)
+	}
+	factory, err := cmdSigner.NewFactory(cmdSigner.Backend(), signerDir, signature.SignerEntity)
+	if err != nil {
+		return fmt.Errorf("failed to create signer factory for %s: %w", cmdSigner.Backend(), err)
+	}
+	signer, err := factory.Load(signature.SignerEntity)
+	if err != nil {
+		return fmt.Errorf("failed to load signer: %w", err)
+	}
+
+	bnd, err := bundle.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer bnd.Close()
+
+	if err = bnd.Sign(signer); err != nil {
+		return err
+	}
+	if err = bnd.Write(dst); err != nil {
+		return fmt.Errorf("failed to write signed bundle: %w", err)
+	}
+
+	fmt.Printf("Signed bundle manifest %s as publisher %s.\n", bnd.Manifest.Hash(), signer.Public())
+	return nil
+}
+
+// Register registers the bundle sub-command and all of its children.
+func Register(parentCmd *cobra.Command) {
+	bundleSignCmd.Flags().AddFlagSet(cmdSigner.Flags)
+	bundleSignCmd.Flags().AddFlagSet(cmdSigner.CLIFlags)
+
+	bundleCmd.AddCommand(bundleSignCmd)
+	parentCmd.AddCommand(bundleCmd)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
@@ -25,10 +26,14 @@ import (
 const (
 	// CfgSignerPub is the public key of the account that will sign an unsigned transaction in estimate gas.
 	CfgSignerPub = "consensus.signer_pub"
+
+	// CfgSuggestFees configures whether suggested fees should also be printed in estimate gas.
+	CfgSuggestFees = "consensus.suggest_fees"
 )
 
 var (
-	signerPub string
+	signerPub   string
+	suggestFees bool
 
 	consensusCmd = &cobra.Command{
 		Use:        "consensus",
@@ -179,6 +184,36 @@ func doEstimateGas(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 	fmt.Println(gas)
+
+	if !suggestFees {
+		return
+	}
+
+	// Also print suggested fees in case the node supports gas price estimation.
+	est, err := client.Core().EstimateGasPrice(context.Background())
+	if err != nil {
+		logger.Warn("failed to estimate gas price",
+			"err", err,
+		)
+		return
+	}
+	for _, v := range []struct {
+		name     string
+		gasPrice quantity.Quantity
+	}{
+		{"low", est.Low},
+		{"median", est.Median},
+		{"high", est.High},
+	} {
+		fee := v.gasPrice.Clone()
+		if err = fee.Mul(quantity.NewFromUint64(uint64(gas))); err != nil {
+			logger.Error("failed to compute fee",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		fmt.Printf("Suggested fee (%s): %s (gas price: %s)\n", v.name, fee, &v.gasPrice)
+	}
 }
 
 func doNextBlockState(cmd *cobra.Command, _ []string) {
@@ -225,6 +260,7 @@ func Register(parentCmd *cobra.Command) {
 	showTxCmd.Flags().AddFlagSet(cmdFlags.GenesisFileFlags)
 
 	estimateGasCmd.Flags().StringVar(&signerPub, CfgSignerPub, "", "public key of the signer, in base64")
+	estimateGasCmd.Flags().BoolVar(&suggestFees, CfgSuggestFees, false, "also print suggested fees based on recent gas prices")
 	estimateGasCmd.Flags().AddFlagSet(cmdConsensus.TxFileFlags)
 	estimateGasCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 
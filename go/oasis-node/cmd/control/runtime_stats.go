@@ -14,10 +14,12 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	registryAPI "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothashAPI "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 	schedulerAPI "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 )
 
@@ -44,6 +46,10 @@ type stats struct {
 
 	entitiesOutput [][]string
 	entitiesHeader []string
+
+	// Resource usage of locally hosted runtime components.
+	componentsOutput [][]string
+	componentsHeader []string
 }
 
 type entityStats struct {
@@ -116,6 +122,36 @@ func (s *stats) prepareEntitiesOutput() {
 	}
 }
 
+func (s *stats) prepareComponentsOutput(components []control.ComponentStatus) {
+	s.componentsOutput = make([][]string, 0)
+
+	s.componentsHeader = []string{
+		"Component",
+		"Version",
+		"Memory (bytes)",
+		"CPU time",
+		"Pids",
+	}
+
+	for _, comp := range components {
+		usage := comp.ResourceUsage
+		if usage == nil {
+			continue
+		}
+		id := component.ID{Kind: comp.Kind, Name: comp.Name}
+
+		var line []string
+		line = append(line,
+			id.String(),
+			comp.Version.String(),
+			strconv.FormatUint(usage.Memory, 10),
+			usage.CPUTime.String(),
+			strconv.FormatUint(usage.Pids, 10),
+		)
+		s.componentsOutput = append(s.componentsOutput, line)
+	}
+}
+
 func (s *stats) printStats() {
 	fmt.Printf("Runtime rounds: %d\n", s.rounds)
 	fmt.Printf("Successful rounds: %d\n", s.successfulRounds)
@@ -133,6 +169,18 @@ func (s *stats) printStats() {
 	table.SetHeader(s.entitiesHeader)
 	table.AppendBulk(s.entitiesOutput)
 	table.Render()
+
+	if len(s.componentsOutput) == 0 {
+		return
+	}
+
+	fmt.Println("Component resource usage")
+	table = tablewriter.NewWriter(os.Stdout)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.SetHeader(s.componentsHeader)
+	table.AppendBulk(s.componentsOutput)
+	table.Render()
 }
 
 func doRuntimeStats(cmd *cobra.Command, args []string) { //nolint:gocyclo
@@ -187,7 +235,7 @@ func doRuntimeStats(cmd *cobra.Command, args []string) { //nolint:gocyclo
 	}
 
 	// Connect to the node
-	conn, _ := doConnectOnly(cmd)
+	conn, client := doConnectOnly(cmd)
 	consensus := consensusAPI.NewClient(conn)
 
 	// Fixup the start/end heights if they were not specified (or are 0)
@@ -470,6 +518,19 @@ func doRuntimeStats(cmd *cobra.Command, args []string) { //nolint:gocyclo
 		}
 	}
 
+	// Fetch resource usage of the runtime components hosted by the node.
+	nodeStatus, err := client.GetStatus(ctx)
+	switch err {
+	case nil:
+		if rtStatus, ok := nodeStatus.Runtimes[runtimeID]; ok {
+			stats.prepareComponentsOutput(rtStatus.Components)
+		}
+	default:
+		logger.Warn("failed to query node status",
+			"err", err,
+		)
+	}
+
 	// Prepare and printout stats.
 	stats.prepareEntitiesOutput()
 	stats.printStats()
@@ -89,6 +89,22 @@ func (w *storageWorker) SyncIterate(ctx context.Context, request *syncer.Iterate
 	return w.backend.SyncIterate(ctx, request)
 }
 
+func (w *storageWorker) SyncGetMulti(ctx context.Context, request *syncer.GetMultiRequest) (*syncer.ProofResponse, error) {
+	if w.failReadRequests {
+		return nil, errByzantine
+	}
+
+	return w.backend.SyncGetMulti(ctx, request)
+}
+
+func (w *storageWorker) SyncGetRange(ctx context.Context, request *syncer.GetRangeRequest) (*syncer.ProofResponse, error) {
+	if w.failReadRequests {
+		return nil, errByzantine
+	}
+
+	return w.backend.SyncGetRange(ctx, request)
+}
+
 type corruptIterator struct {
 	it        storage.WriteLogIterator
 	corrupted bool
@@ -431,6 +431,11 @@ func (qs *dumpQueryState) LastRetainedVersion() (int64, error) {
 	return 0, fmt.Errorf("dumpdb/dumpQueryState: LastRetainedEpoch not supported")
 }
 
+func (qs *dumpQueryState) GetStateSnapshot(int64) (*cmtAPI.StateSnapshot, storage.NodeDB, error) {
+	// This is not required in the dump process.
+	return nil, nil, fmt.Errorf("dumpdb/dumpQueryState: GetStateSnapshot not supported")
+}
+
 // Register registers the dumpdb sub-commands.
 func Register(parentCmd *cobra.Command) {
 	dumpDBCmd.Flags().AddFlagSet(flags.GenesisFileFlags)
@@ -661,6 +661,11 @@ func (q *queries) doGovernanceQueries(ctx context.Context, _ *rand.Rand, height
 			if err != nil {
 				return fmt.Errorf("governance.Votes: %w", err)
 			}
+
+			_, err = q.governance.VoteBreakdown(ctx, &governance.ProposalQuery{Height: height, ProposalID: p.ID})
+			if err != nil {
+				return fmt.Errorf("governance.VoteBreakdown: %w", err)
+			}
 		}
 	}
 
@@ -81,6 +81,7 @@ const (
 	CfgGovernanceUpgradeMinEpochDiff            = "governance.upgrade_min_epoch_diff"
 	CfgGovernanceVotingPeriod                   = "governance.voting_period"
 	CfgGovernanceEnableChangeParametersProposal = "governance.enable_change_parameters_proposal"
+	CfgGovernanceEnableTextProposal             = "governance.enable_text_proposal"
 
 	// Beacon config flags.
 	CfgBeaconBackend                  = "beacon.backend"
@@ -249,6 +250,7 @@ func doInitGenesis(*cobra.Command, []string) {
 			UpgradeMinEpochDiff:            beacon.EpochTime(viper.GetUint64(CfgGovernanceUpgradeMinEpochDiff)),
 			VotingPeriod:                   beacon.EpochTime(viper.GetUint64(CfgGovernanceVotingPeriod)),
 			EnableChangeParametersProposal: viper.GetBool(CfgGovernanceEnableChangeParametersProposal),
+			EnableTextProposal:             viper.GetBool(CfgGovernanceEnableTextProposal),
 		},
 	}
 
@@ -830,6 +832,7 @@ func init() {
 	initGenesisFlags.Uint64(CfgGovernanceUpgradeMinEpochDiff, 300, "minimum number of epochs the upgrade needs to be scheduled in advance")
 	initGenesisFlags.Uint64(CfgGovernanceVotingPeriod, 100, "voting period (in epochs)")
 	initGenesisFlags.Bool(CfgGovernanceEnableChangeParametersProposal, true, "enable change parameters proposals")
+	initGenesisFlags.Bool(CfgGovernanceEnableTextProposal, true, "enable text proposals")
 
 	// Beacon config flags.
 	initGenesisFlags.String(CfgBeaconBackend, "insecure", "beacon backend")
@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
@@ -22,18 +23,24 @@ import (
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
 	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
 const (
 	cfgProposalCancelUpgradeID   = "proposal.cancel_upgrade.id"
 	cfgProposalUpgradeDescriptor = "proposal.upgrade.descriptor"
+	cfgProposalTextTitle         = "proposal.text.title"
+	cfgProposalTextContentHash   = "proposal.text.content_hash"
+	cfgProposalTextURI           = "proposal.text.uri"
 
 	cfgVote           = "vote"
 	cfgVoteProposalID = "vote.proposal.id"
 
 	cfgProposalID = "proposal.id"
 
+	cfgDelegator = "delegator"
+
 	cfgIncludeClosed = "include_closed"
 )
 
@@ -43,6 +50,7 @@ var (
 	castVoteFlags       = flag.NewFlagSet("", flag.ContinueOnError)
 	proposalFlags       = flag.NewFlagSet("", flag.ContinueOnError)
 	listProposalsFlags  = flag.NewFlagSet("", flag.ContinueOnError)
+	delegatorVotesFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
 	governanceCmd = &cobra.Command{
 		Use:        "governance",
@@ -78,6 +86,20 @@ var (
 		Deprecated: "use the `oasis` CLI instead.",
 	}
 
+	voteBreakdownCmd = &cobra.Command{
+		Use:        "vote_breakdown",
+		Short:      "displays per-validator vote breakdown for a proposal",
+		Run:        doVoteBreakdown,
+		Deprecated: "use the `oasis` CLI instead.",
+	}
+
+	delegatorVotesCmd = &cobra.Command{
+		Use:        "delegator_votes",
+		Short:      "displays effective votes of a delegator for a proposal",
+		Run:        doDelegatorVotes,
+		Deprecated: "use the `oasis` CLI instead.",
+	}
+
 	listProposalsCmd = &cobra.Command{
 		Use:        "list_proposals",
 		Short:      "lists active proposals",
@@ -148,9 +170,34 @@ func doGenSubmitProposal(*cobra.Command, []string) {
 				ProposalID: viper.GetUint64(cfgProposalCancelUpgradeID),
 			},
 		})
+	// Text proposal.
+	case viper.GetString(cfgProposalTextTitle) != "":
+		var contentHash hash.Hash
+		if err := contentHash.UnmarshalHex(viper.GetString(cfgProposalTextContentHash)); err != nil {
+			logger.Error("failed to parse text proposal content hash",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+
+		text := &governance.TextProposal{
+			Title:       viper.GetString(cfgProposalTextTitle),
+			ContentHash: contentHash,
+			URI:         viper.GetString(cfgProposalTextURI),
+		}
+		if err := text.ValidateBasic(); err != nil {
+			logger.Error("submitted text proposal is not valid",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+
+		tx = governance.NewSubmitProposalTx(nonce, fee, &governance.ProposalContent{
+			Text: text,
+		})
 	default:
-		logger.Error(fmt.Sprintf("missing required arguments: either '%v' or '%v' required",
-			cfgProposalUpgradeDescriptor, cfgProposalCancelUpgradeID,
+		logger.Error(fmt.Sprintf("missing required arguments: either '%v', '%v' or '%v' required",
+			cfgProposalUpgradeDescriptor, cfgProposalCancelUpgradeID, cfgProposalTextTitle,
 		))
 		os.Exit(1)
 	}
@@ -250,6 +297,80 @@ func doProposalVotes(cmd *cobra.Command, _ []string) {
 	fmt.Println(string(prettyVotes))
 }
 
+func doVoteBreakdown(cmd *cobra.Command, _ []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	id := viper.GetUint64(cfgProposalID)
+	if id == 0 {
+		logger.Error("proposal ID required")
+		os.Exit(1)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	ctx := context.Background()
+	breakdown, err := client.VoteBreakdown(ctx, &governance.ProposalQuery{Height: consensus.HeightLatest, ProposalID: id})
+	if err != nil {
+		logger.Error("error querying proposal vote breakdown", "err", err)
+		os.Exit(1)
+	}
+
+	prettyBreakdown, err := cmdCommon.PrettyJSONMarshal(breakdown)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of vote breakdown",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyBreakdown))
+}
+
+func doDelegatorVotes(cmd *cobra.Command, _ []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	id := viper.GetUint64(cfgProposalID)
+	if id == 0 {
+		logger.Error("proposal ID required")
+		os.Exit(1)
+	}
+
+	var delegator staking.Address
+	if err := delegator.UnmarshalText([]byte(viper.GetString(cfgDelegator))); err != nil {
+		logger.Error("failed to parse delegator address",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	ctx := context.Background()
+	votes, err := client.DelegatorVotes(ctx, &governance.DelegatorVotesQuery{
+		Height:     consensus.HeightLatest,
+		ProposalID: id,
+		Delegator:  delegator,
+	})
+	if err != nil {
+		logger.Error("error querying delegator votes", "err", err)
+		os.Exit(1)
+	}
+
+	prettyVotes, err := cmdCommon.PrettyJSONMarshal(votes)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of delegator votes",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyVotes))
+}
+
 func doListProposals(cmd *cobra.Command, _ []string) {
 	var err error
 	if err = cmdCommon.Init(); err != nil {
@@ -290,6 +411,8 @@ func Register(parentCmd *cobra.Command) {
 		castVoteCmd,
 		proposalInfoCmd,
 		proposalVotesCmd,
+		voteBreakdownCmd,
+		delegatorVotesCmd,
 		listProposalsCmd,
 	} {
 		governanceCmd.AddCommand(c)
@@ -304,6 +427,13 @@ func Register(parentCmd *cobra.Command) {
 	proposalVotesCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 	proposalVotesCmd.Flags().AddFlagSet(proposalFlags)
 
+	voteBreakdownCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+	voteBreakdownCmd.Flags().AddFlagSet(proposalFlags)
+
+	delegatorVotesCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+	delegatorVotesCmd.Flags().AddFlagSet(proposalFlags)
+	delegatorVotesCmd.Flags().AddFlagSet(delegatorVotesFlags)
+
 	listProposalsCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 	listProposalsCmd.Flags().AddFlagSet(listProposalsFlags)
 
@@ -317,6 +447,9 @@ func init() {
 
 	submitProposalFlags.String(cfgProposalUpgradeDescriptor, "", "Path to the proposal upgrade descriptor")
 	submitProposalFlags.Uint64(cfgProposalCancelUpgradeID, 0, "Cancel upgrade proposal ID")
+	submitProposalFlags.String(cfgProposalTextTitle, "", "Text proposal title")
+	submitProposalFlags.String(cfgProposalTextContentHash, "", "Hex-encoded hash of the text proposal content")
+	submitProposalFlags.String(cfgProposalTextURI, "", "URI of the text proposal content")
 	_ = viper.BindPFlags(submitProposalFlags)
 	submitProposalFlags.AddFlagSet(cmdConsensus.TxFlags)
 	submitProposalFlags.AddFlagSet(cmdFlags.AssumeYesFlag)
@@ -330,6 +463,9 @@ func init() {
 	proposalFlags.Uint64(cfgProposalID, 0, "Proposal ID")
 	_ = viper.BindPFlags(proposalFlags)
 
+	delegatorVotesFlags.String(cfgDelegator, "", "Delegator account address")
+	_ = viper.BindPFlags(delegatorVotesFlags)
+
 	listProposalsFlags.Bool(cfgIncludeClosed, false, "Include closed proposals.")
 	_ = viper.BindPFlags(listProposalsFlags)
 }
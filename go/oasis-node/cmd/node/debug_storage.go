@@ -36,6 +36,22 @@ func (s *debugStorage) SyncIterate(ctx context.Context, request *storage.Iterate
 	return rt.Storage().SyncIterate(ctx, request)
 }
 
+func (s *debugStorage) SyncGetMulti(ctx context.Context, request *storage.GetMultiRequest) (*storage.ProofResponse, error) {
+	rt, err := s.n.RuntimeRegistry.GetRuntime(request.Tree.Root.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return rt.Storage().SyncGetMulti(ctx, request)
+}
+
+func (s *debugStorage) SyncGetRange(ctx context.Context, request *storage.GetRangeRequest) (*storage.ProofResponse, error) {
+	rt, err := s.n.RuntimeRegistry.GetRuntime(request.Tree.Root.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return rt.Storage().SyncGetRange(ctx, request)
+}
+
 func (s *debugStorage) GetDiff(ctx context.Context, request *storage.GetDiffRequest) (storage.WriteLogIterator, error) {
 	rt, err := s.n.RuntimeRegistry.GetRuntime(request.StartRoot.Namespace)
 	if err != nil {
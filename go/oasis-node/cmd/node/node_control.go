@@ -13,6 +13,8 @@ import (
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	p2p "github.com/oasisprotocol/oasis-core/go/p2p/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	keymanagerWorker "github.com/oasisprotocol/oasis-core/go/worker/keymanager/api"
@@ -177,6 +179,50 @@ func (n *Node) AddBundle(_ context.Context, path string) error {
 	return n.RuntimeRegistry.GetBundleManager().Add(path)
 }
 
+// GetStorageUsage implements control.NodeController.
+func (n *Node) GetStorageUsage(ctx context.Context) (map[common.Namespace]*storage.Usage, error) {
+	usage := make(map[common.Namespace]*storage.Usage)
+	for _, rt := range n.RuntimeRegistry.Runtimes() {
+		lsb, ok := rt.Storage().(storage.LocalBackend)
+		if !ok {
+			continue
+		}
+
+		rtUsage, err := lsb.NodeDB().Usage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get storage usage of runtime %s: %w", rt.ID(), err)
+		}
+		usage[rt.ID()] = rtUsage
+	}
+	return usage, nil
+}
+
+// CompactStorage implements control.NodeController.
+func (n *Node) CompactStorage(ctx context.Context, runtimeID common.Namespace) error {
+	rt, err := n.RuntimeRegistry.GetRuntime(runtimeID)
+	if err != nil {
+		return err
+	}
+	lsb, ok := rt.Storage().(storage.LocalBackend)
+	if !ok {
+		return fmt.Errorf("runtime %s has no local storage", runtimeID)
+	}
+
+	logger := n.logger.With("runtime_id", runtimeID)
+	logger.Info("compacting storage")
+
+	start := time.Now()
+	if err = lsb.NodeDB().Compact(ctx); err != nil {
+		logger.Error("failed to compact storage", "err", err)
+		return err
+	}
+
+	logger.Info("storage compaction completed",
+		"duration", time.Since(start),
+	)
+	return nil
+}
+
 func (n *Node) getIdentityStatus() control.IdentityStatus {
 	return control.IdentityStatus{
 		Node:      n.Identity.NodeSigner.Public(),
@@ -330,11 +376,12 @@ func (n *Node) getRuntimeStatus(ctx context.Context) (map[common.Namespace]contr
 		// Fetch the status of all components associated with the runtime.
 		for _, comp := range n.RuntimeRegistry.GetBundleRegistry().Components(rt.ID()) {
 			status.Components = append(status.Components, control.ComponentStatus{
-				Kind:     comp.Kind,
-				Name:     comp.Name,
-				Version:  comp.Version,
-				Detached: comp.Detached,
-				Disabled: comp.Disabled,
+				Kind:          comp.Kind,
+				Name:          comp.Name,
+				Version:       comp.Version,
+				Detached:      comp.Detached,
+				Disabled:      comp.Disabled,
+				ResourceUsage: n.getComponentResourceUsage(rt.ID(), comp.ID(), comp.Version),
 			})
 		}
 
@@ -344,6 +391,32 @@ func (n *Node) getRuntimeStatus(ctx context.Context) (map[common.Namespace]contr
 	return runtimes, nil
 }
 
+// getComponentResourceUsage returns the resource usage of the given runtime component version in
+// case it is running and its resources are accounted for.
+func (n *Node) getComponentResourceUsage(runtimeID common.Namespace, id component.ID, version version.Version) *host.ResourceUsage {
+	rtNode := n.CommonWorker.GetRuntime(runtimeID)
+	if rtNode == nil {
+		return nil
+	}
+	comp, ok := rtNode.GetHostedRuntime().Component(id)
+	if !ok {
+		return nil
+	}
+	rt, err := comp.Version(version)
+	if err != nil {
+		return nil
+	}
+	reporter, ok := rt.(host.ResourceReporter)
+	if !ok {
+		return nil
+	}
+	usage, err := reporter.GetResourceUsage()
+	if err != nil {
+		return nil
+	}
+	return usage
+}
+
 func (n *Node) getKeymanagerStatus() (*keymanagerWorker.Status, error) {
 	if n.KeymanagerWorker == nil || !n.KeymanagerWorker.Enabled() {
 		return nil, nil
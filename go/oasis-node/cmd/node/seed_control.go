@@ -3,9 +3,11 @@ package node
 import (
 	"context"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
@@ -86,3 +88,13 @@ func (n *SeedNode) GetStatus(context.Context) (*control.Status, error) {
 func (n *SeedNode) AddBundle(context.Context, string) error {
 	return control.ErrNotImplemented
 }
+
+// GetStorageUsage implements control.NodeController.
+func (n *SeedNode) GetStorageUsage(context.Context) (map[common.Namespace]*storage.Usage, error) {
+	return nil, control.ErrNotImplemented
+}
+
+// CompactStorage implements control.NodeController.
+func (n *SeedNode) CompactStorage(context.Context, common.Namespace) error {
+	return control.ErrNotImplemented
+}
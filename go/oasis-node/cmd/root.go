@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/bundle"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/consensus"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/control"
@@ -72,6 +73,7 @@ func init() {
 
 	// Register all of the sub-commands.
 	for _, v := range []func(*cobra.Command){
+		bundle.Register,
 		control.Register,
 		debug.Register,
 		genesis.Register,
@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/config"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	runtimeConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+	storageAPI "github.com/oasisprotocol/oasis-core/go/storage/api"
+	storageDatabase "github.com/oasisprotocol/oasis-core/go/storage/database"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	workerStorage "github.com/oasisprotocol/oasis-core/go/worker/storage"
+	storageConfig "github.com/oasisprotocol/oasis-core/go/worker/storage/config"
+)
+
+const (
+	// CfgExportStateCompression configures the chunk compression used for state archives.
+	CfgExportStateCompression = "storage.export_state.compression"
+	// CfgExportStateChunkSize configures the chunk size used for state archives.
+	CfgExportStateChunkSize = "storage.export_state.chunk_size"
+)
+
+var (
+	storageExportStateCmd = &cobra.Command{
+		Use:   "export-state <runtime> <round> <archive>",
+		Args:  cobra.ExactArgs(3),
+		Short: "export all storage roots of a runtime round into a state archive",
+		RunE:  doExportState,
+	}
+
+	storageImportStateCmd = &cobra.Command{
+		Use:   "import-state <runtime> <archive>",
+		Args:  cobra.ExactArgs(2),
+		Short: "import a state archive into the runtime's storage database",
+		Long: "Import a state archive into the runtime's storage database.\n\n" +
+			"The archive is restored into the node database backend selected by the node\n" +
+			"configuration, which need not be the backend the archive was exported from. The\n" +
+			"archived round must be newer than any round already present in the database.",
+		RunE: doImportState,
+	}
+
+	storageExportStateFlags = flag.NewFlagSet("", flag.ContinueOnError)
+)
+
+func openLocalBackend(dataDir string, rt common.Namespace, readOnly bool) (storageAPI.LocalBackend, error) {
+	runtimeDir := runtimeConfig.GetRuntimeStateDir(dataDir, rt)
+	if !readOnly {
+		if err := common.Mkdir(runtimeDir); err != nil {
+			return nil, fmt.Errorf("failed to create runtime state directory: %w", err)
+		}
+	}
+
+	return storageDatabase.New(&storageAPI.Config{
+		Backend:   config.GlobalConfig.Storage.Backend,
+		DB:        workerStorage.GetLocalBackendDBDir(runtimeDir, config.GlobalConfig.Storage.Backend),
+		Namespace: rt,
+		ReadOnly:  readOnly,
+	})
+}
+
+func doExportState(_ *cobra.Command, args []string) error {
+	dataDir := cmdCommon.DataDir()
+	ctx := context.Background()
+
+	runtimes, err := parseRuntimes(args[:1])
+	if err != nil {
+		return err
+	}
+	rt := runtimes[0]
+	round, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed round '%s': %w", args[1], err)
+	}
+	archivePath := args[2]
+
+	cpCfg := storageConfig.CheckpointerConfig{Compression: viper.GetString(CfgExportStateCompression)}
+	version, err := cpCfg.CheckpointVersion()
+	if err != nil {
+		return err
+	}
+	chunkSize := uint64(config.ParseSizeInBytes(viper.GetString(CfgExportStateChunkSize)))
+	if chunkSize == 0 {
+		return fmt.Errorf("invalid chunk size: %s", viper.GetString(CfgExportStateChunkSize))
+	}
+
+	backend, err := openLocalBackend(dataDir, rt, true)
+	if err != nil {
+		return fmt.Errorf("failed to open storage database: %w", err)
+	}
+	defer backend.Cleanup()
+	ndb := backend.NodeDB()
+
+	roots, err := ndb.GetRootsForVersion(round)
+	if err != nil {
+		return fmt.Errorf("failed to get roots for round %d: %w", round, err)
+	}
+	if len(roots) == 0 {
+		return fmt.Errorf("no roots found for round %d", round)
+	}
+
+	// Checkpoints are created in a scratch directory so that they do not interfere with the
+	// checkpoints served by the node.
+	tmpDir, err := os.MkdirTemp(filepath.Dir(archivePath), ".export-state-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	creator, err := checkpoint.NewFileCreator(tmpDir, ndb)
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint creator: %w", err)
+	}
+
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+
+	display := &displayHelper{}
+	display.DisplayStepBegin(fmt.Sprintf("exporting %d roots of runtime %s at round %d", len(roots), rt, round))
+	manifest, err := checkpoint.WriteArchive(ctx, w, ndb, creator, version, roots, chunkSize)
+	if err != nil {
+		display.DisplayStepEnd("failed")
+		_ = os.Remove(archivePath)
+		return fmt.Errorf("failed to export state: %w", err)
+	}
+	if err = w.Flush(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err = f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync archive: %w", err)
+	}
+	display.DisplayStepEnd("done")
+
+	for _, ar := range manifest.Roots {
+		logger.Info("exported root",
+			"root", ar.Checkpoint.Root,
+			"num_keys", ar.NumKeys,
+			"num_chunks", len(ar.Checkpoint.Chunks),
+		)
+	}
+	return nil
+}
+
+func doImportState(_ *cobra.Command, args []string) error {
+	dataDir := cmdCommon.DataDir()
+	ctx := context.Background()
+
+	runtimes, err := parseRuntimes(args[:1])
+	if err != nil {
+		return err
+	}
+	rt := runtimes[0]
+	archivePath := args[1]
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	// Validate the manifest before touching the database.
+	manifest, err := checkpoint.ReadArchiveManifest(tar.NewReader(bufio.NewReader(f)))
+	if err != nil {
+		return fmt.Errorf("failed to read archive manifest: %w", err)
+	}
+	if !manifest.Namespace.Equal(&rt) {
+		return fmt.Errorf("archive is for runtime %s, not %s", manifest.Namespace, rt)
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind archive: %w", err)
+	}
+
+	backend, err := openLocalBackend(dataDir, rt, false)
+	if err != nil {
+		return fmt.Errorf("failed to open storage database: %w", err)
+	}
+	defer backend.Cleanup()
+
+	display := &displayHelper{}
+	display.DisplayStepBegin(fmt.Sprintf("importing %d roots of runtime %s at round %d", len(manifest.Roots), rt, manifest.Version))
+	if _, err = checkpoint.RestoreArchive(ctx, bufio.NewReader(f), backend.NodeDB(), backend.Checkpointer()); err != nil {
+		display.DisplayStepEnd("failed")
+		return fmt.Errorf("failed to import state: %w", err)
+	}
+	display.DisplayStepEnd("done")
+
+	for _, ar := range manifest.Roots {
+		logger.Info("imported root",
+			"root", ar.Checkpoint.Root,
+			"num_keys", ar.NumKeys,
+		)
+	}
+	return nil
+}
+
+func init() {
+	storageExportStateFlags.String(CfgExportStateCompression, "zstd", "chunk compression (snappy or zstd)")
+	storageExportStateFlags.String(CfgExportStateChunkSize, "8mb", "maximum size of a single chunk")
+	_ = viper.BindPFlags(storageExportStateFlags)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
 	runtimeConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
@@ -289,6 +290,13 @@ func Register(parentCmd *cobra.Command) {
 	storageCheckCmd.Flags().AddFlagSet(bundle.Flags)
 	storageCmd.AddCommand(storageMigrateCmd)
 	storageCmd.AddCommand(storageCheckCmd)
+	storageExportStateCmd.Flags().AddFlagSet(storageExportStateFlags)
 	storageCmd.AddCommand(storageRenameNsCmd)
+	storageCmd.AddCommand(storageExportStateCmd)
+	storageCmd.AddCommand(storageImportStateCmd)
+	storageUsageCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+	storageCompactCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+	storageCmd.AddCommand(storageUsageCmd)
+	storageCmd.AddCommand(storageCompactCmd)
 	parentCmd.AddCommand(storageCmd)
 }
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdControl "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/control"
+)
+
+var (
+	storageUsageCmd = &cobra.Command{
+		Use:   "usage",
+		Args:  cobra.NoArgs,
+		Short: "show storage usage of a running node per runtime and root type",
+		RunE:  doUsage,
+	}
+
+	storageCompactCmd = &cobra.Command{
+		Use:   "compact <runtime...>",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "compact the node databases of a running node",
+		Long: "Compact the node databases of a running node.\n\n" +
+			"Compaction garbage collects the value log to reclaim the space left behind by\n" +
+			"pruning without waiting for the periodic background garbage collection. It may\n" +
+			"take a long time and cause increased disk I/O on large databases.",
+		RunE: doCompact,
+	}
+)
+
+func doUsage(cmd *cobra.Command, _ []string) error {
+	conn, client := cmdControl.DoConnect(cmd)
+	defer conn.Close()
+
+	usage, err := client.GetStorageUsage(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to query storage usage: %w", err)
+	}
+
+	prettyUsage, err := cmdCommon.PrettyJSONMarshal(usage)
+	if err != nil {
+		return fmt.Errorf("failed to get pretty JSON of storage usage: %w", err)
+	}
+	fmt.Println(string(prettyUsage))
+
+	for _, rtUsage := range usage {
+		if rtUsage.Breakdown != nil && rtUsage.Breakdown.UnattributedNodes > 0 {
+			fmt.Fprintln(os.Stderr, "NOTE: The node database shares tree nodes between roots of different types, so tree\n"+
+				"nodes are reported as unattributed and per root type sizes only include write logs and root\n"+
+				"indices.")
+			break
+		}
+	}
+	return nil
+}
+
+func doCompact(cmd *cobra.Command, args []string) error {
+	runtimes, err := parseRuntimes(args)
+	if err != nil {
+		return err
+	}
+
+	conn, client := cmdControl.DoConnect(cmd)
+	defer conn.Close()
+
+	for _, rt := range runtimes {
+		display := &displayHelper{}
+		display.DisplayStepBegin(fmt.Sprintf("compacting storage database for runtime %s", rt))
+		if err = client.CompactStorage(context.Background(), rt); err != nil {
+			display.DisplayStepEnd("failed")
+			return fmt.Errorf("failed to compact storage database for runtime %s: %w", rt, err)
+		}
+		display.DisplayStepEnd("done")
+	}
+	return nil
+}
@@ -826,6 +826,7 @@ func (net *Network) MakeGenesis() error {
 			"--" + genesis.CfgGovernanceUpgradeMinEpochDiff, strconv.FormatUint(uint64(cfg.UpgradeMinEpochDiff), 10),
 			"--" + genesis.CfgGovernanceVotingPeriod, strconv.FormatUint(uint64(cfg.VotingPeriod), 10),
 			"--" + genesis.CfgGovernanceEnableChangeParametersProposal, strconv.FormatBool(cfg.EnableChangeParametersProposal),
+			"--" + genesis.CfgGovernanceEnableTextProposal, strconv.FormatBool(cfg.EnableTextProposal),
 		}...)
 	}
 	if cfg := net.cfg.RoothashParameters; cfg != nil {
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx/sigstruct"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
@@ -25,6 +26,9 @@ type Bundle struct {
 	Manifest *Manifest
 	Data     map[string]Data
 
+	// Signatures are the detached publisher signatures over the manifest hash.
+	Signatures []signature.Signature
+
 	// archive is the underlying ZIP archive.
 	archive *zip.ReadCloser
 	// manifestHash is the original manifest hash of the bundle at time the bundle was loaded.
@@ -142,6 +146,11 @@ func (bnd *Bundle) Validate() error {
 		}
 	}
 
+	// Ensure all publisher signatures are valid.
+	if err := bnd.verifySignatures(); err != nil {
+		return err
+	}
+
 	for _, comp := range bnd.Manifest.GetAvailableComponents() {
 		// Make sure the SGX signature is valid if it exists.
 		if err := bnd.verifySgxSignature(comp); err != nil {
@@ -353,6 +362,16 @@ func (bnd *Bundle) Write(fn string) error {
 			d:  NewBytesData(rawManifest),
 		},
 	}
+	if len(bnd.Signatures) > 0 {
+		rawSignatures, sErr := json.Marshal(bnd.Signatures)
+		if sErr != nil {
+			return fmt.Errorf("runtime/bundle: failed to serialize signatures: %w", sErr)
+		}
+		writeFiles = append(writeFiles, writeFile{
+			fn: manifestSignaturesName,
+			d:  NewBytesData(rawSignatures),
+		})
+	}
 	for f := range bnd.Data {
 		writeFiles = append(writeFiles, writeFile{
 			fn: f,
@@ -468,6 +487,17 @@ func (bnd *Bundle) WriteExploded(dir string) error {
 		}
 	}
 
+	// Keep detached signatures so that the exploded bundle can be served to peers.
+	if len(bnd.Signatures) > 0 {
+		rawSignatures, err := json.Marshal(bnd.Signatures)
+		if err != nil {
+			return fmt.Errorf("runtime/bundle: failed to serialize signatures: %w", err)
+		}
+		if err = os.WriteFile(filepath.Join(dir, manifestSignaturesName), rawSignatures, 0o600); err != nil {
+			return fmt.Errorf("runtime/bundle: failed to write signatures: %w", err)
+		}
+	}
+
 	// Fix executable permissions.
 	for id, comp := range bnd.Manifest.GetAvailableComponents() {
 		if comp.ELF != nil {
@@ -484,6 +514,53 @@ func (bnd *Bundle) WriteExploded(dir string) error {
 	return nil
 }
 
+// OpenExploded opens a runtime bundle previously extracted to the given directory.
+//
+// The bundle data is read lazily from the directory and is not validated against the manifest
+// digests, so callers that need verified contents must call Validate.
+func OpenExploded(dir string) (*Bundle, error) {
+	rawManifest, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err != nil {
+		return nil, fmt.Errorf("runtime/bundle: failed to read manifest: %w", err)
+	}
+	var manifest Manifest
+	if err = json.Unmarshal(rawManifest, &manifest); err != nil {
+		return nil, fmt.Errorf("runtime/bundle: failed to parse manifest: %w", err)
+	}
+
+	var signatures []signature.Signature
+	rawSignatures, err := os.ReadFile(filepath.Join(dir, manifestSignaturesName))
+	switch {
+	case err == nil:
+		if err = json.Unmarshal(rawSignatures, &signatures); err != nil {
+			return nil, fmt.Errorf("runtime/bundle: failed to parse signatures: %w", err)
+		}
+	case os.IsNotExist(err):
+	default:
+		return nil, fmt.Errorf("runtime/bundle: failed to read signatures: %w", err)
+	}
+
+	data := map[string]Data{
+		manifestName: NewBytesData(rawManifest),
+	}
+	for fn := range manifest.Digests {
+		if fn == manifestName {
+			continue
+		}
+		if filepath.Dir(fn) != "." {
+			return nil, fmt.Errorf("runtime/bundle: failed to sanitize path '%s'", fn)
+		}
+		data[fn] = NewFileData(filepath.Join(dir, fn))
+	}
+
+	return &Bundle{
+		Manifest:     &manifest,
+		Data:         data,
+		Signatures:   signatures,
+		manifestHash: manifest.Hash(),
+	}, nil
+}
+
 // Rewrite invokes the passed manifest rewriter function to rewrite the manifest then reserializes
 // it and recomputes its hash.
 //
@@ -496,6 +573,9 @@ func (bnd *Bundle) Rewrite(manifestRewriter ManifestRewriterFunc) {
 	}
 	manifestRewriter(bnd.Manifest)
 
+	// Publisher signatures are no longer valid for the rewritten manifest.
+	bnd.Signatures = nil
+
 	// Recompute the manifest hash and change the underlying serialized manifest.
 	bnd.manifestHash = bnd.Manifest.Hash()
 	rawManifest, _ := json.Marshal(bnd.Manifest)
@@ -506,6 +586,7 @@ func (bnd *Bundle) Rewrite(manifestRewriter ManifestRewriterFunc) {
 func (bnd *Bundle) Close() error {
 	bnd.Manifest = nil
 	bnd.Data = nil
+	bnd.Signatures = nil
 	if bnd.archive != nil {
 		bnd.archive.Close()
 		bnd.archive = nil
@@ -530,16 +611,27 @@ func Open(fn string, opts ...OpenOption) (_ *Bundle, err error) {
 	}()
 
 	// Read the contents.
+	var signatures []signature.Signature
 	data := make(map[string]Data)
 	for i, v := range r.File {
 		// Sanitize the file name by ensuring that all names are rooted
 		// at the correct location.
-		switch i {
-		case 0:
+		switch {
+		case i == 0:
 			// Much like the JAR files, the manifest MUST come first.
 			if v.Name != manifestName {
 				return nil, fmt.Errorf("runtime/bundle: invalid manifest file name: '%s'", v.Name)
 			}
+		case v.Name == manifestSignaturesName:
+			// Detached signatures are not part of the bundle data.
+			b, rErr := ReadAllData(v)
+			if rErr != nil {
+				return nil, fmt.Errorf("runtime/bundle: failed to read signatures: %w", rErr)
+			}
+			if rErr = json.Unmarshal(b, &signatures); rErr != nil {
+				return nil, fmt.Errorf("runtime/bundle: failed to parse signatures: %w", rErr)
+			}
+			continue
 		default:
 			if filepath.Dir(v.Name) != "." {
 				return nil, fmt.Errorf("runtime/bundle: failed to sanitize path '%s'", v.Name)
@@ -573,6 +665,7 @@ func Open(fn string, opts ...OpenOption) (_ *Bundle, err error) {
 	bnd := &Bundle{
 		Manifest:     &manifest,
 		Data:         data,
+		Signatures:   signatures,
 		archive:      r,
 		manifestHash: manifestHash,
 	}
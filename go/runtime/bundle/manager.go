@@ -39,6 +39,9 @@ const (
 	// in bytes.
 	maxDefaultBundleSizeBytes = 20 * 1024 * 1024 // 20 MB
 
+	// peerDownloadTimeout is the time limit for fetching a single bundle from peers.
+	peerDownloadTimeout = 10 * time.Minute
+
 	// maxLabelSize is the maximum size of a single label key or value.
 	maxLabelSize = 512
 	// maxLabelCount is the maximum number of labels.
@@ -72,6 +75,13 @@ type VolumeManager interface {
 	GetOrCreate(labels map[string]string) (*volume.Volume, error)
 }
 
+// PeerFetcher is an interface for fetching bundles from peers of a runtime.
+type PeerFetcher interface {
+	// FetchBundle fetches the bundle with the given manifest hash from peers and writes it to
+	// the given file. The total size of the bundle contents must not exceed maxSize bytes.
+	FetchBundle(ctx context.Context, manifestHash hash.Hash, fn string, maxSize int64) error
+}
+
 // ValidatorFunc is a function that validates a bundle.
 type ValidatorFunc func(*Bundle) error
 
@@ -146,6 +156,9 @@ type Manager struct {
 	runtimeBaseURLs map[common.Namespace][]string
 	globalBaseURLs  []string
 
+	trustValidators map[common.Namespace]ValidatorFunc
+	peerFetchers    map[common.Namespace]PeerFetcher
+
 	triggerCh     chan struct{}
 	downloadQueue map[common.Namespace][]hash.Hash
 	cleanupQueue  map[common.Namespace]version.Version
@@ -191,6 +204,15 @@ func NewManager(dataDir string, runtimeIDs []common.Namespace, store ManifestSto
 		runtimeBaseURLs[runtime.ID] = urls
 	}
 
+	// Configure each runtime's bundle trust policy.
+	trustValidators := make(map[common.Namespace]ValidatorFunc)
+	for _, runtime := range config.GlobalConfig.Runtime.Runtimes {
+		if len(runtime.TrustedPublishers) == 0 {
+			continue
+		}
+		trustValidators[runtime.ID] = NewTrustPolicyValidator(runtime.TrustedPublishers)
+	}
+
 	// Remember which runtimes to follow.
 	runtimes := make(map[common.Namespace]struct{})
 	for _, runtimeID := range runtimeIDs {
@@ -206,6 +228,8 @@ func NewManager(dataDir string, runtimeIDs []common.Namespace, store ManifestSto
 		runtimeIDs:         runtimes,
 		globalBaseURLs:     globalBaseURLs,
 		runtimeBaseURLs:    runtimeBaseURLs,
+		trustValidators:    trustValidators,
+		peerFetchers:       make(map[common.Namespace]PeerFetcher),
 		triggerCh:          make(chan struct{}, 1),
 		downloadQueue:      make(map[common.Namespace][]hash.Hash),
 		cleanupQueue:       make(map[common.Namespace]version.Version),
@@ -301,7 +325,7 @@ func (m *Manager) run(ctx context.Context) {
 			return
 		}
 
-		m.download()
+		m.download(ctx)
 		m.clean()
 	}
 }
@@ -470,14 +494,42 @@ func (m *Manager) Cleanup(runtimeID common.Namespace, version version.Version) {
 	}
 }
 
-func (m *Manager) download() {
+// RegisterPeerFetcher registers a fetcher used to download bundles of the given runtime from
+// peers when none of the configured registries can provide them.
+func (m *Manager) RegisterPeerFetcher(runtimeID common.Namespace, fetcher PeerFetcher) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.peerFetchers[runtimeID] = fetcher
+}
+
+// GetExplodedBundle returns the registered bundle of the given runtime with the given manifest
+// hash, as extracted in the bundle directory.
+//
+// The returned bundle is not validated.
+func (m *Manager) GetExplodedBundle(runtimeID common.Namespace, manifestHash hash.Hash) (*Bundle, error) {
+	if !m.store.HasManifest(manifestHash) {
+		return nil, fmt.Errorf("bundle not found: %s", manifestHash.Hex())
+	}
+
+	bnd, err := OpenExploded(filepath.Join(m.bundleDir, manifestHash.String()))
+	if err != nil {
+		return nil, err
+	}
+	if bnd.Manifest.ID != runtimeID {
+		return nil, fmt.Errorf("bundle not found: %s", manifestHash.Hex())
+	}
+	return bnd, nil
+}
+
+func (m *Manager) download(ctx context.Context) {
 	m.logger.Info("downloading bundles")
 	for runtimeID := range m.runtimeIDs {
-		m.downloadBundles(runtimeID)
+		m.downloadBundles(ctx, runtimeID)
 	}
 }
 
-func (m *Manager) downloadBundles(runtimeID common.Namespace) {
+func (m *Manager) downloadBundles(ctx context.Context, runtimeID common.Namespace) {
 	// Try to download queued bundles.
 	m.mu.RLock()
 	hashes := m.downloadQueue[runtimeID]
@@ -485,7 +537,7 @@ func (m *Manager) downloadBundles(runtimeID common.Namespace) {
 
 	downloaded := make(map[hash.Hash]struct{})
 	for _, hash := range hashes {
-		if err := m.downloadBundle(runtimeID, hash); err != nil {
+		if err := m.downloadBundle(ctx, runtimeID, hash); err != nil {
 			m.logger.Error("failed to download bundle",
 				"err", err,
 				"runtime_id", runtimeID,
@@ -514,7 +566,7 @@ func (m *Manager) downloadBundles(runtimeID common.Namespace) {
 	m.downloadQueue[runtimeID] = pending
 }
 
-func (m *Manager) downloadBundle(runtimeID common.Namespace, manifestHash hash.Hash) error {
+func (m *Manager) downloadBundle(ctx context.Context, runtimeID common.Namespace, manifestHash hash.Hash) error {
 	var errs error
 
 	if m.store.HasManifest(manifestHash) {
@@ -532,7 +584,60 @@ func (m *Manager) downloadBundle(runtimeID common.Namespace, manifestHash hash.H
 		}
 	}
 
-	return errs
+	// Fall back to peers in case registries are unavailable.
+	m.mu.RLock()
+	fetcher, ok := m.peerFetchers[runtimeID]
+	m.mu.RUnlock()
+	if !ok {
+		return errs
+	}
+	if err := m.tryPeerDownloadBundle(ctx, fetcher, manifestHash); err != nil {
+		return errors.Join(errs, err)
+	}
+
+	return nil
+}
+
+func (m *Manager) tryPeerDownloadBundle(ctx context.Context, fetcher PeerFetcher, manifestHash hash.Hash) error {
+	m.logger.Info("downloading bundle from peers",
+		"manifest_hash", manifestHash.Hex(),
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, peerDownloadTimeout)
+	defer cancel()
+
+	src := filepath.Join(m.tmpBundleDir, manifestHash.Hex()+FileExtension)
+	defer os.Remove(src)
+
+	if err := fetcher.FetchBundle(ctx, manifestHash, src, m.maxBundleSizeBytes); err != nil {
+		m.logger.Error("failed to download bundle from peers",
+			"err", err,
+			"manifest_hash", manifestHash.Hex(),
+		)
+		return fmt.Errorf("failed to download bundle from peers: %w", err)
+	}
+
+	manifest, err := m.explodeBundle(src, WithBundleManifestHash(manifestHash))
+	if err != nil {
+		m.logger.Error("failed to explode bundle",
+			"err", err,
+			"src", src,
+		)
+		return err
+	}
+
+	if err := m.registerManifest(manifest); err != nil {
+		m.logger.Error("failed to register manifest",
+			"err", err,
+		)
+		return fmt.Errorf("failed to register manifest: %w", err)
+	}
+
+	m.logger.Info("bundle downloaded from peers",
+		"manifest_hash", manifestHash.Hex(),
+	)
+
+	return nil
 }
 
 func (m *Manager) tryDownloadBundle(manifestHash hash.Hash, baseURL string) error {
@@ -702,6 +807,18 @@ func (m *Manager) loadManifests() ([]*ExplodedManifest, error) {
 			continue
 		}
 
+		// The trust policy may have changed since the bundle was exploded.
+		if err = m.checkExplodedTrustPolicy(dir, manifest.ID); err != nil {
+			m.logger.Warn("removing bundle rejected by trust policy",
+				"path", dir,
+				"err", err,
+			)
+			if err = m.removeBundle(dir); err != nil {
+				return nil, fmt.Errorf("failed to remove bundle: %w", err)
+			}
+			continue
+		}
+
 		m.logger.Info("manifest loaded",
 			"name", manifest.Name,
 			"hash", manifest.Hash(),
@@ -716,6 +833,23 @@ func (m *Manager) loadManifests() ([]*ExplodedManifest, error) {
 	return manifests, nil
 }
 
+// checkExplodedTrustPolicy checks the bundle exploded in the given directory against the trust
+// policy of the given runtime, if one is configured.
+func (m *Manager) checkExplodedTrustPolicy(dir string, runtimeID common.Namespace) error {
+	validator, ok := m.trustValidators[runtimeID]
+	if !ok {
+		return nil
+	}
+
+	bnd, err := OpenExploded(dir)
+	if err != nil {
+		return err
+	}
+	defer bnd.Close()
+
+	return validator(bnd)
+}
+
 func (m *Manager) cleanOnStartup(manifests, exploded []*ExplodedManifest) ([]*ExplodedManifest, error) {
 	m.logger.Info("cleaning bundles")
 
@@ -867,6 +1001,11 @@ func (m *Manager) explodeBundle(path string, opts ...AddOption) (*ExplodedManife
 	}
 	defer bnd.Close()
 
+	if validator, ok := m.trustValidators[bnd.Manifest.ID]; ok {
+		if err = validator(bnd); err != nil {
+			return nil, fmt.Errorf("bundle rejected by trust policy: %w", err)
+		}
+	}
 	if options.validator != nil {
 		if err = options.validator(bnd); err != nil {
 			return nil, err
@@ -1,11 +1,16 @@
 package bundle
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 	"github.com/oasisprotocol/oasis-core/go/runtime/volume"
 )
 
@@ -76,3 +81,65 @@ func TestRegisterManifest(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, len(manifests), len(store.manifestHashes))
 }
+
+func TestLoadManifestsTrustPolicy(t *testing.T) {
+	require := require.New(t)
+
+	dataDir := t.TempDir()
+	manager, err := NewManager(dataDir, nil, newMockStore(), newMockVolumeManager())
+	require.NoError(err, "NewManager")
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("bundle manager trust policy"), 0)
+	publisher := memorySigner.NewTestSigner("bundle publisher")
+
+	explode := func(name string, signer signature.Signer) string {
+		bnd := &Bundle{
+			Manifest: &Manifest{
+				Name: name,
+				ID:   runtimeID,
+				Components: []*Component{
+					{
+						Kind: component.ROFL,
+						Name: "my-rofl-comp",
+						ELF: &ELFMetadata{
+							Executable: "rofl.bin",
+						},
+					},
+				},
+			},
+		}
+		err = bnd.Add("rofl.bin", NewBytesData(randBuffer(1024)))
+		require.NoError(err, "bundle.Add")
+		if signer != nil {
+			err = bnd.Sign(signer)
+			require.NoError(err, "Sign")
+		}
+
+		fn := filepath.Join(t.TempDir(), name+".orc")
+		err = bnd.Write(fn)
+		require.NoError(err, "bundle.Write")
+		bnd, err = Open(fn)
+		require.NoError(err, "Open")
+		defer bnd.Close()
+
+		dir := bnd.ExplodedPath(dataDir)
+		err = bnd.WriteExploded(dir)
+		require.NoError(err, "WriteExploded")
+		return dir
+	}
+	signedDir := explode("signed", publisher)
+	unsignedDir := explode("unsigned", nil)
+
+	// Without a trust policy, all bundles should be loaded.
+	manifests, err := manager.loadManifests()
+	require.NoError(err, "loadManifests")
+	require.Len(manifests, 2)
+
+	// Bundles rejected by the trust policy should be removed.
+	manager.trustValidators[runtimeID] = NewTrustPolicyValidator([]signature.PublicKey{publisher.Public()})
+	manifests, err = manager.loadManifests()
+	require.NoError(err, "loadManifests")
+	require.Len(manifests, 1)
+	require.Equal(signedDir, manifests[0].ExplodedDataDir)
+	require.NoDirExists(unsignedDir, "rejected bundle should be removed")
+}
@@ -0,0 +1,83 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+// ManifestSignatureContext is the signature context used for signing bundle manifests.
+var ManifestSignatureContext = signature.NewContext("oasis-core/runtime/bundle: manifest")
+
+var (
+	// ErrUnsignedBundle is the error returned when a bundle without publisher signatures is
+	// rejected by a trust policy.
+	ErrUnsignedBundle = errors.New("runtime/bundle: bundle is not signed by any publisher")
+	// ErrUntrustedPublisher is the error returned when a bundle is not signed by any trusted
+	// publisher.
+	ErrUntrustedPublisher = errors.New("runtime/bundle: bundle is not signed by a trusted publisher")
+)
+
+// manifestSignaturesName is the name of the file containing detached publisher signatures over
+// the manifest. The file is not covered by the manifest digests.
+const manifestSignaturesName = manifestPath + "/MANIFEST.SIG"
+
+// Sign signs the bundle manifest using the given publisher signer and attaches the detached
+// signature to the bundle.
+//
+// Any modifications to the manifest after signing invalidate the signature.
+func (bnd *Bundle) Sign(signer signature.Signer) error {
+	h := bnd.Manifest.Hash()
+	sig, err := signature.Sign(signer, ManifestSignatureContext, h[:])
+	if err != nil {
+		return fmt.Errorf("runtime/bundle: failed to sign manifest: %w", err)
+	}
+	bnd.Signatures = append(bnd.Signatures, *sig)
+	return nil
+}
+
+// Publishers returns the public keys of all publishers that signed the bundle manifest.
+func (bnd *Bundle) Publishers() ([]signature.PublicKey, error) {
+	if err := bnd.verifySignatures(); err != nil {
+		return nil, err
+	}
+
+	publishers := make([]signature.PublicKey, 0, len(bnd.Signatures))
+	for _, sig := range bnd.Signatures {
+		publishers = append(publishers, sig.PublicKey)
+	}
+	return publishers, nil
+}
+
+func (bnd *Bundle) verifySignatures() error {
+	h := bnd.Manifest.Hash()
+	for _, sig := range bnd.Signatures {
+		if !sig.Verify(ManifestSignatureContext, h[:]) {
+			return fmt.Errorf("runtime/bundle: invalid manifest signature by '%s'", sig.PublicKey)
+		}
+	}
+	return nil
+}
+
+// NewTrustPolicyValidator creates a bundle validator which only accepts bundles signed by at
+// least one of the given trusted publishers.
+func NewTrustPolicyValidator(trusted []signature.PublicKey) ValidatorFunc {
+	return func(bnd *Bundle) error {
+		publishers, err := bnd.Publishers()
+		if err != nil {
+			return err
+		}
+		if len(publishers) == 0 {
+			return ErrUnsignedBundle
+		}
+		for _, pk := range publishers {
+			for _, tpk := range trusted {
+				if pk.Equal(tpk) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("%w (signed by: %v)", ErrUntrustedPublisher, publishers)
+	}
+}
@@ -0,0 +1,104 @@
+package bundle
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+func TestBundleSignatures(t *testing.T) {
+	require := require.New(t)
+
+	publisher := memorySigner.NewTestSigner("bundle publisher")
+	untrusted := memorySigner.NewTestSigner("untrusted bundle publisher")
+
+	tmpDir := t.TempDir()
+	newBundle := func(name string) *Bundle {
+		bnd := &Bundle{
+			Manifest: &Manifest{
+				Name: name,
+				ID:   common.NewTestNamespaceFromSeed([]byte("bundle signatures"), 0),
+				Components: []*Component{
+					{
+						Kind: component.ROFL,
+						Name: "my-rofl-comp",
+						ELF: &ELFMetadata{
+							Executable: "rofl.bin",
+						},
+					},
+				},
+			},
+		}
+		err := bnd.Add("rofl.bin", NewBytesData(randBuffer(1024)))
+		require.NoError(err, "bundle.Add")
+		return bnd
+	}
+	writeAndOpen := func(bnd *Bundle, fn string) *Bundle {
+		fn = filepath.Join(tmpDir, fn)
+		err := bnd.Write(fn)
+		require.NoError(err, "bundle.Write")
+		opened, err := Open(fn)
+		require.NoError(err, "Open")
+		t.Cleanup(func() { opened.Close() })
+		return opened
+	}
+
+	validator := NewTrustPolicyValidator([]signature.PublicKey{publisher.Public()})
+
+	// Unsigned bundles should be rejected.
+	unsigned := writeAndOpen(newBundle("unsigned"), "unsigned.orc")
+	require.Empty(unsigned.Signatures)
+	require.ErrorIs(validator(unsigned), ErrUnsignedBundle)
+
+	// Bundles signed by a trusted publisher should be accepted.
+	bnd := newBundle("signed")
+	err := bnd.Sign(untrusted)
+	require.NoError(err, "Sign")
+	err = bnd.Sign(publisher)
+	require.NoError(err, "Sign")
+	signed := writeAndOpen(bnd, "signed.orc")
+	publishers, err := signed.Publishers()
+	require.NoError(err, "Publishers")
+	require.Equal([]signature.PublicKey{untrusted.Public(), publisher.Public()}, publishers)
+	require.NoError(validator(signed), "bundle signed by a trusted publisher should be accepted")
+	_, ok := signed.Data[manifestSignaturesName]
+	require.False(ok, "signatures should not be part of bundle data")
+
+	// Signatures should be preserved in exploded bundles.
+	explodedDir := filepath.Join(tmpDir, "exploded")
+	err = signed.WriteExploded(explodedDir)
+	require.NoError(err, "WriteExploded")
+	exploded, err := OpenExploded(explodedDir)
+	require.NoError(err, "OpenExploded")
+	require.Equal(signed.Manifest.Hash(), exploded.Manifest.Hash())
+	require.Equal(signed.Signatures, exploded.Signatures)
+	require.Len(exploded.Data, len(signed.Data))
+	require.NoError(exploded.Validate(), "exploded bundle should be valid")
+	require.NoError(validator(exploded), "exploded bundle should retain publisher signatures")
+
+	// Bundles signed only by untrusted publishers should be rejected.
+	bnd = newBundle("untrusted")
+	err = bnd.Sign(untrusted)
+	require.NoError(err, "Sign")
+	untrustedBnd := writeAndOpen(bnd, "untrusted.orc")
+	require.ErrorIs(validator(untrustedBnd), ErrUntrustedPublisher)
+
+	// Signatures should not survive manifest modifications.
+	bnd = newBundle("modified")
+	err = bnd.Sign(publisher)
+	require.NoError(err, "Sign")
+	bnd.Manifest.Name = "modified after signing"
+	err = bnd.Write(filepath.Join(tmpDir, "modified.orc"))
+	require.ErrorContains(err, "invalid manifest signature")
+
+	signed.Rewrite(func(m *Manifest) {
+		m.Name = "rewritten"
+	})
+	require.ErrorIs(validator(signed), ErrUnsignedBundle)
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
@@ -20,6 +21,11 @@ const (
 
 	// RoundLatest is a special round number always referring to the latest round.
 	RoundLatest = roothash.RoundLatest
+
+	// AffinityKeyMetadataKey is the gRPC metadata key under which clients may pass an opaque
+	// session key. Queries with the same session key are routed to the same runtime instance
+	// when the node load-balances queries using the consistent hashing strategy.
+	AffinityKeyMetadataKey = "oasis-affinity-key"
 )
 
 var (
@@ -36,6 +42,11 @@ var (
 	ErrCheckTxFailed = errors.New(ModuleName, 5, "client: transaction check failed")
 	// ErrNoHostedRuntime is returned when the hosted runtime is not available locally.
 	ErrNoHostedRuntime = errors.New(ModuleName, 6, "client: no hosted runtime is available")
+	// ErrStateDiffUnavailable is returned when state diffs cannot be computed for the requested
+	// rounds, e.g. because the node has no local storage or the state has already been pruned.
+	ErrStateDiffUnavailable = errors.New(ModuleName, 7, "client: state diff not available")
+	// ErrInvalidCursor is returned when a state diff cursor does not match the local history.
+	ErrInvalidCursor = errors.New(ModuleName, 8, "client: invalid cursor")
 )
 
 // RuntimeClient is the runtime client interface.
@@ -78,6 +89,17 @@ type RuntimeClient interface {
 	// that are currently pending to be included in a block.
 	GetUnconfirmedTransactions(ctx context.Context, runtimeID common.Namespace) ([][]byte, error)
 
+	// GetTransactionPoolEntries returns all transactions currently in the local transaction pool,
+	// including the ones that are still pending checks, together with their queuing metadata.
+	GetTransactionPoolEntries(ctx context.Context, runtimeID common.Namespace) ([]*TransactionPoolEntry, error)
+
+	// WatchTransactionPoolEvents subscribes to transactions being admitted into, evicted from or
+	// rejected by the local transaction pool.
+	//
+	// Events are buffered per subscriber and the oldest events are dropped in case the
+	// subscriber is not able to keep up.
+	WatchTransactionPoolEvents(ctx context.Context, runtimeID common.Namespace) (<-chan *TransactionPoolEvent, pubsub.ClosableSubscription, error)
+
 	// GetEvents returns all events emitted in a given block.
 	GetEvents(ctx context.Context, request *GetEventsRequest) ([]*Event, error)
 
@@ -87,6 +109,16 @@ type RuntimeClient interface {
 	// WatchBlocks subscribes to blocks for a specific runtimes.
 	WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error)
 
+	// WatchStateDiffs subscribes to per-round runtime state diffs, starting at the given round or
+	// cursor. Diffs for rounds which have already been synced are streamed first, followed by
+	// diffs for new rounds as they are synced. A diff is emitted for every round, even when the
+	// state did not change, so that the cursor of the last received diff can always be used to
+	// resume the stream without gaps.
+	//
+	// The stream is closed when the context is canceled, the subscription is closed or when a
+	// diff cannot be computed.
+	WatchStateDiffs(ctx context.Context, request *WatchStateDiffsRequest) (<-chan *StateDiff, pubsub.ClosableSubscription, error)
+
 	// State returns a MKVS read syncer that can be used to read runtime state from a remote node
 	// and verify it against the trusted local root.
 	State() syncer.ReadSyncer
@@ -180,3 +212,87 @@ type QueryRequest struct {
 type QueryResponse struct {
 	Data []byte `json:"data"`
 }
+
+// WatchStateDiffsRequest is a WatchStateDiffs request.
+type WatchStateDiffsRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+
+	// StartRound is the first round to emit the state diff for. Rounds before the runtime
+	// genesis round are treated as the genesis round.
+	StartRound uint64 `json:"start_round,omitempty"`
+	// Cursor is the cursor of the last processed state diff. If set, the stream resumes with
+	// the round following the cursor and StartRound is ignored.
+	Cursor *StateDiffCursor `json:"cursor,omitempty"`
+}
+
+// StateDiffCursor identifies the position in the state diff stream.
+type StateDiffCursor struct {
+	// Round is the round of the last processed state diff.
+	Round uint64 `json:"round"`
+	// StateRoot is the state root at the given round. It is used to make sure that the stream
+	// is resumed on the same chain of blocks.
+	StateRoot hash.Hash `json:"state_root"`
+}
+
+// StateDiff is the runtime state diff of a single round.
+type StateDiff struct {
+	// Round is the round the diff is for.
+	Round uint64 `json:"round"`
+	// PreviousStateRoot is the state root at the previous round. For the genesis round this is
+	// the empty root.
+	PreviousStateRoot hash.Hash `json:"previous_state_root"`
+	// StateRoot is the state root at this round.
+	StateRoot hash.Hash `json:"state_root"`
+	// Entries are the changed keys in key order.
+	Entries []*StateDiffEntry `json:"entries,omitempty"`
+	// Cursor is the cursor that can be used to resume the stream after this diff.
+	Cursor StateDiffCursor `json:"cursor"`
+}
+
+// StateDiffEntry is a single changed key in a state diff.
+type StateDiffEntry struct {
+	// Key is the changed key.
+	Key []byte `json:"key"`
+	// OldValue is the value at the previous round or nil if the key has been inserted.
+	OldValue []byte `json:"old_value,omitempty"`
+	// NewValue is the value at this round or nil if the key has been removed.
+	NewValue []byte `json:"new_value,omitempty"`
+}
+
+// TransactionPoolEntry is a transaction in the local transaction pool.
+type TransactionPoolEntry struct {
+	// Hash is the transaction hash.
+	Hash hash.Hash `json:"hash"`
+	// Size is the size of the raw transaction in bytes.
+	Size uint64 `json:"size"`
+	// Queue is the transaction pool queue the transaction is in. It is one of check (pending
+	// checks), local (submitted via the local node), main (checked transactions from all other
+	// sources) or rim (roothash incoming messages).
+	Queue string `json:"queue"`
+	// Priority is the transaction priority as reported by the runtime. It is only available for
+	// transactions in the main queue.
+	Priority uint64 `json:"priority,omitempty"`
+	// Sender is the transaction sender as reported by the runtime. It is only available for
+	// transactions in the main queue.
+	Sender []byte `json:"sender,omitempty"`
+	// SenderSeq is the per-sender sequence number as reported by the runtime. It is only
+	// available for transactions in the main queue.
+	SenderSeq uint64 `json:"sender_seq,omitempty"`
+	// FirstSeen is the time the transaction was first seen by the node.
+	FirstSeen time.Time `json:"first_seen"`
+	// RecheckCount is the number of times the transaction has been rechecked.
+	RecheckCount uint64 `json:"recheck_count,omitempty"`
+}
+
+// TransactionPoolEvent is a local transaction pool event.
+type TransactionPoolEvent struct {
+	// Kind is the kind of the event. It is one of admitted, evicted or rejected.
+	Kind string `json:"kind"`
+	// Hash is the transaction hash.
+	Hash hash.Hash `json:"hash"`
+	// Queue is the transaction pool queue the transaction has been admitted into or evicted
+	// from, if any.
+	Queue string `json:"queue,omitempty"`
+	// Reason is the reason why the transaction has been evicted or rejected.
+	Reason string `json:"reason,omitempty"`
+}
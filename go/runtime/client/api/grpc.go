@@ -40,6 +40,8 @@ var (
 	methodGetTransactionsWithResults = serviceName.NewMethod("GetTransactionsWithResults", GetTransactionsRequest{})
 	// methodGetUnconfirmedTransactions is the GetUnconfirmedTransactions method.
 	methodGetUnconfirmedTransactions = serviceName.NewMethod("GetUnconfirmedTransactions", common.Namespace{})
+	// methodGetTransactionPoolEntries is the GetTransactionPoolEntries method.
+	methodGetTransactionPoolEntries = serviceName.NewMethod("GetTransactionPoolEntries", common.Namespace{})
 	// methodGetEvents is the GetEvents method.
 	methodGetEvents = serviceName.NewMethod("GetEvents", GetEventsRequest{})
 	// methodQuery is the Query method.
@@ -50,9 +52,17 @@ var (
 	methodStateSyncGetPrefixes = serviceName.NewMethod("StateSyncGetPrefixes", syncer.GetPrefixesRequest{})
 	// methodStateSyncIterate is the StateSyncIterate method.
 	methodStateSyncIterate = serviceName.NewMethod("StateSyncIterate", syncer.IterateRequest{})
+	// methodStateSyncGetMulti is the StateSyncGetMulti method.
+	methodStateSyncGetMulti = serviceName.NewMethod("StateSyncGetMulti", syncer.GetMultiRequest{})
+	// methodStateSyncGetRange is the StateSyncGetRange method.
+	methodStateSyncGetRange = serviceName.NewMethod("StateSyncGetRange", syncer.GetRangeRequest{})
 
 	// methodWatchBlocks is the WatchBlocks method.
 	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", common.Namespace{})
+	// methodWatchStateDiffs is the WatchStateDiffs method.
+	methodWatchStateDiffs = serviceName.NewMethod("WatchStateDiffs", WatchStateDiffsRequest{})
+	// methodWatchTransactionPoolEvents is the WatchTransactionPoolEvents method.
+	methodWatchTransactionPoolEvents = serviceName.NewMethod("WatchTransactionPoolEvents", common.Namespace{})
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -99,6 +109,10 @@ var (
 				MethodName: methodGetUnconfirmedTransactions.ShortName(),
 				Handler:    handlerGetUnconfirmedTransactions,
 			},
+			{
+				MethodName: methodGetTransactionPoolEntries.ShortName(),
+				Handler:    handlerGetTransactionPoolEntries,
+			},
 			{
 				MethodName: methodGetEvents.ShortName(),
 				Handler:    handlerGetEvents,
@@ -119,6 +133,14 @@ var (
 				MethodName: methodStateSyncIterate.ShortName(),
 				Handler:    handlerStateSyncIterate,
 			},
+			{
+				MethodName: methodStateSyncGetMulti.ShortName(),
+				Handler:    handlerStateSyncGetMulti,
+			},
+			{
+				MethodName: methodStateSyncGetRange.ShortName(),
+				Handler:    handlerStateSyncGetRange,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -126,6 +148,16 @@ var (
 				Handler:       handlerWatchBlocks,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodWatchStateDiffs.ShortName(),
+				Handler:       handlerWatchStateDiffs,
+				ServerStreams: true,
+			},
+			{
+				StreamName:    methodWatchTransactionPoolEvents.ShortName(),
+				Handler:       handlerWatchTransactionPoolEvents,
+				ServerStreams: true,
+			},
 		},
 	}
 )
@@ -393,6 +425,29 @@ func handlerGetUnconfirmedTransactions(
 	return interceptor(ctx, runtimeID, info, handler)
 }
 
+func handlerGetTransactionPoolEntries(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeClient).GetTransactionPoolEntries(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTransactionPoolEntries.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(RuntimeClient).GetTransactionPoolEntries(ctx, req.(common.Namespace))
+	}
+	return interceptor(ctx, runtimeID, info, handler)
+}
+
 func handlerGetEvents(
 	srv any,
 	ctx context.Context,
@@ -510,6 +565,55 @@ func handlerStateSyncIterate(
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerStateSyncGetMulti(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	rq := new(syncer.GetMultiRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if err := rq.Validate(); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeClient).State().SyncGetMulti(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodStateSyncGetMulti.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(RuntimeClient).State().SyncGetMulti(ctx, req.(*syncer.GetMultiRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
+func handlerStateSyncGetRange(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	rq := new(syncer.GetRangeRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeClient).State().SyncGetRange(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodStateSyncGetRange.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(RuntimeClient).State().SyncGetRange(ctx, req.(*syncer.GetRangeRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 func handlerWatchBlocks(srv any, stream grpc.ServerStream) error {
 	var runtimeID common.Namespace
 	if err := stream.RecvMsg(&runtimeID); err != nil {
@@ -539,6 +643,64 @@ func handlerWatchBlocks(srv any, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerWatchStateDiffs(srv any, stream grpc.ServerStream) error {
+	var req WatchStateDiffsRequest
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(RuntimeClient).WatchStateDiffs(ctx, &req)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case sd, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(sd); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func handlerWatchTransactionPoolEvents(srv any, stream grpc.ServerStream) error {
+	var runtimeID common.Namespace
+	if err := stream.RecvMsg(&runtimeID); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(RuntimeClient).WatchTransactionPoolEvents(ctx, runtimeID)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // RegisterService registers a new runtime client service with the given gRPC server.
 func RegisterService(server *grpc.Server, service RuntimeClient) {
 	server.RegisterService(&serviceDesc, service)
@@ -628,6 +790,14 @@ func (c *Client) GetUnconfirmedTransactions(ctx context.Context, runtimeID commo
 	return rsp, nil
 }
 
+func (c *Client) GetTransactionPoolEntries(ctx context.Context, runtimeID common.Namespace) ([]*TransactionPoolEntry, error) {
+	var rsp []*TransactionPoolEntry
+	if err := c.conn.Invoke(ctx, methodGetTransactionPoolEntries.FullName(), runtimeID, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *Client) GetEvents(ctx context.Context, request *GetEventsRequest) ([]*Event, error) {
 	var rsp []*Event
 	if err := c.conn.Invoke(ctx, methodGetEvents.FullName(), request, &rsp); err != nil {
@@ -675,6 +845,24 @@ func (rs *stateReadSync) SyncIterate(ctx context.Context, request *syncer.Iterat
 	return &rsp, nil
 }
 
+// Implements syncer.ReadSyncer.
+func (rs *stateReadSync) SyncGetMulti(ctx context.Context, request *syncer.GetMultiRequest) (*syncer.ProofResponse, error) {
+	var rsp syncer.ProofResponse
+	if err := rs.c.conn.Invoke(ctx, methodStateSyncGetMulti.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+// Implements syncer.ReadSyncer.
+func (rs *stateReadSync) SyncGetRange(ctx context.Context, request *syncer.GetRangeRequest) (*syncer.ProofResponse, error) {
+	var rsp syncer.ProofResponse
+	if err := rs.c.conn.Invoke(ctx, methodStateSyncGetRange.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) State() syncer.ReadSyncer {
 	return &stateReadSync{c}
 }
@@ -713,3 +901,73 @@ func (c *Client) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<
 
 	return ch, sub, nil
 }
+
+func (c *Client) WatchStateDiffs(ctx context.Context, request *WatchStateDiffsRequest) (<-chan *StateDiff, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[1], methodWatchStateDiffs.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(request); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *StateDiff)
+	go func() {
+		defer close(ch)
+
+		for {
+			var sd StateDiff
+			if serr := stream.RecvMsg(&sd); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &sd:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
+func (c *Client) WatchTransactionPoolEvents(ctx context.Context, runtimeID common.Namespace) (<-chan *TransactionPoolEvent, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[2], methodWatchTransactionPoolEvents.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(runtimeID); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *TransactionPoolEvent)
+	go func() {
+		defer close(ch)
+
+		for {
+			var ev TransactionPoolEvent
+			if serr := stream.RecvMsg(&ev); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
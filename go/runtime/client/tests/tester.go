@@ -234,6 +234,11 @@ func testQuery(
 	utxs, err := c.GetUnconfirmedTransactions(ctx, runtimeID)
 	require.NoError(t, err, "GetUnconfirmedTransactions")
 	require.True(t, len(utxs) == 0)
+
+	// Transactions that are only checked should not end up in the transaction pool.
+	entries, err := c.GetTransactionPoolEntries(ctx, runtimeID)
+	require.NoError(t, err, "GetTransactionPoolEntries")
+	require.Empty(t, entries, "transaction pool should be empty")
 }
 
 func testSubmitTransactionNoWait(
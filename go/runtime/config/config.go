@@ -10,6 +10,8 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/bytesize"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 	tpConfig "github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
 )
@@ -224,10 +226,22 @@ type RuntimeConfig struct {
 	// to the base URL. Therefore, the provided URLs don't need to be valid
 	// endpoints themselves, only the constructed URLs need to be valid.
 	Registries []string `yaml:"registries,omitempty"`
+
+	// TrustedPublishers is the list of public keys of trusted bundle publishers.
+	//
+	// If non-empty, only bundles with a manifest signed by at least one of the trusted publishers
+	// are accepted for this runtime, regardless of whether they are configured locally, downloaded
+	// from registries or added by ROFL components.
+	TrustedPublishers []signature.PublicKey `yaml:"trusted_publishers,omitempty"`
 }
 
 // Validate validates the runtime configuration.
 func (c *RuntimeConfig) Validate() error {
+	for _, pk := range c.TrustedPublishers {
+		if !pk.IsValid() {
+			return fmt.Errorf("trusted publisher key '%s' is invalid", pk)
+		}
+	}
 	for _, comp := range c.Components {
 		if err := comp.Validate(); err != nil {
 			return err
@@ -259,6 +273,12 @@ type ComponentConfig struct {
 
 	// Config contains component local configuration.
 	Config map[string]any `yaml:"config,omitempty"`
+
+	// Upgrade configures how new versions of the component are rolled out.
+	Upgrade ComponentUpgradeConfig `yaml:"upgrade,omitempty"`
+
+	// Resources configures the resource limits of the component processes.
+	Resources ComponentResourcesConfig `yaml:"resources,omitempty"`
 }
 
 // Validate validates the component configuration.
@@ -272,6 +292,17 @@ func (c *ComponentConfig) Validate() error {
 		return fmt.Errorf("unknown TEE select mode: %s", c.TEE)
 	}
 
+	if err := c.Upgrade.Validate(); err != nil {
+		return fmt.Errorf("upgrade: %w", err)
+	}
+	if c.Upgrade.Mode == ComponentUpgradeModeHealthGated && c.ID.Kind != component.ROFL {
+		return fmt.Errorf("upgrade: mode '%s' is only supported for ROFL components", c.Upgrade.Mode)
+	}
+
+	if err := c.Resources.Validate(); err != nil {
+		return fmt.Errorf("resources: %w", err)
+	}
+
 	return nil
 }
 
@@ -310,6 +341,117 @@ func (c *ComponentConfig) HasPermission(perm ComponentPermission) bool {
 	return slices.Contains(c.Permissions, perm)
 }
 
+// ComponentUpgradeMode is the mode in which new component versions are rolled out.
+type ComponentUpgradeMode string
+
+const (
+	// ComponentUpgradeModeImmediate replaces the running component version as soon as a new
+	// version becomes available.
+	ComponentUpgradeModeImmediate ComponentUpgradeMode = "immediate"
+
+	// ComponentUpgradeModeHealthGated starts a new component version alongside the running one
+	// and only switches over once the new version is healthy, reverting to the previous version
+	// in case the new one fails within the rollback window.
+	//
+	// Components with persistent volumes are instead stopped before the new version is started
+	// and their volumes are restored from a snapshot when reverting.
+	ComponentUpgradeModeHealthGated ComponentUpgradeMode = "health_gated"
+)
+
+const (
+	// DefaultComponentReadinessTimeout is the default time limit for a new component version to
+	// become healthy.
+	DefaultComponentReadinessTimeout = 5 * time.Minute
+
+	// DefaultComponentRollbackWindow is the default time after switching to a new component
+	// version during which a failure causes a revert to the previous version.
+	DefaultComponentRollbackWindow = 10 * time.Minute
+)
+
+// ComponentUpgradeConfig is the component upgrade configuration.
+type ComponentUpgradeConfig struct {
+	// Mode is the upgrade mode (immediate, health_gated).
+	//
+	// If not provided, components are upgraded immediately.
+	Mode ComponentUpgradeMode `yaml:"mode,omitempty"`
+
+	// ReadinessTimeout is the time limit for a new version to become healthy before the upgrade
+	// is aborted.
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout,omitempty"`
+
+	// ReadinessMethod is the optional name of the component local RPC method that must succeed
+	// before the new version is considered healthy.
+	ReadinessMethod string `yaml:"readiness_method,omitempty"`
+
+	// RollbackWindow is the time after switching to a new version during which a failure of
+	// the new version causes a revert to the previous version.
+	RollbackWindow time.Duration `yaml:"rollback_window,omitempty"`
+}
+
+// Validate validates the component upgrade configuration.
+func (c *ComponentUpgradeConfig) Validate() error {
+	switch c.Mode {
+	case "", ComponentUpgradeModeImmediate, ComponentUpgradeModeHealthGated:
+	default:
+		return fmt.Errorf("unknown mode: %s", c.Mode)
+	}
+	if c.ReadinessTimeout < 0 {
+		return fmt.Errorf("readiness timeout must be non-negative")
+	}
+	if c.RollbackWindow < 0 {
+		return fmt.Errorf("rollback window must be non-negative")
+	}
+	return nil
+}
+
+// GetReadinessTimeout returns the configured readiness timeout or the default.
+func (c *ComponentUpgradeConfig) GetReadinessTimeout() time.Duration {
+	if c.ReadinessTimeout == 0 {
+		return DefaultComponentReadinessTimeout
+	}
+	return c.ReadinessTimeout
+}
+
+// GetRollbackWindow returns the configured rollback window or the default.
+func (c *ComponentUpgradeConfig) GetRollbackWindow() time.Duration {
+	if c.RollbackWindow == 0 {
+		return DefaultComponentRollbackWindow
+	}
+	return c.RollbackWindow
+}
+
+// ComponentResourcesConfig is the component resource limits configuration.
+//
+// Limits are enforced using cgroup v2 which requires the node's cgroup to be delegated to it (e.g.
+// using the Delegate option of systemd) with the cpu, memory and pids controllers available.
+type ComponentResourcesConfig struct {
+	// Memory is the maximum amount of memory (e.g. 2GB) that the component processes may use.
+	Memory string `yaml:"memory,omitempty"`
+
+	// CPUShares is the relative CPU weight of the component processes in the range 1-10000
+	// where 100 is the default weight of other processes.
+	CPUShares uint64 `yaml:"cpu_shares,omitempty"`
+
+	// Pids is the maximum number of processes and threads that the component may create.
+	Pids uint64 `yaml:"pids,omitempty"`
+}
+
+// Validate validates the component resource limits configuration.
+func (c *ComponentResourcesConfig) Validate() error {
+	if c.Memory != "" && bytesize.Parse(c.Memory) == 0 {
+		return fmt.Errorf("memory must be a positive size (e.g. 2GB)")
+	}
+	if c.CPUShares > 10000 {
+		return fmt.Errorf("cpu shares must be in range 1-10000")
+	}
+	return nil
+}
+
+// IsEmpty returns true iff no resource limits are configured.
+func (c *ComponentResourcesConfig) IsEmpty() bool {
+	return c.Memory == "" && c.CPUShares == 0 && c.Pids == 0
+}
+
 // ComponentPermission represents a permission given to a component.
 type ComponentPermission string
 
@@ -353,8 +495,14 @@ type PruneConfig struct {
 	Strategy string `yaml:"strategy"`
 	// History pruning interval.
 	Interval time.Duration `yaml:"interval"`
-	// Number of last rounds to keep.
+	// Number of last rounds to keep. When using the disk budget strategy, this is the minimum
+	// number of last rounds that are always kept.
 	NumKept uint64 `yaml:"num_kept"`
+	// Maximum age of kept rounds when using the keep recent strategy.
+	MaxAge time.Duration `yaml:"max_age,omitempty"`
+	// Disk budget (e.g. 100GB) for the runtime history and storage when using the disk budget
+	// strategy.
+	DiskBudget string `yaml:"disk_budget,omitempty"`
 }
 
 // IndexerConfig is history indexer configuration.
@@ -369,8 +517,90 @@ type IndexerConfig struct {
 type LoadBalancerConfig struct {
 	// NumInstances is the number of runtime instances to provision for load-balancing.
 	//
-	// Setting it to zero or one disables load balancing.
+	// Setting it to zero or one disables load balancing, unless autoscaling is enabled. When
+	// autoscaling is enabled, this is the minimum number of instances.
 	NumInstances uint64 `yaml:"num_instances,omitempty"`
+
+	// MaxInstances is the maximum number of runtime instances to provision for load-balancing.
+	//
+	// Setting it to a value greater than NumInstances enables autoscaling, where instances are
+	// added and removed based on load.
+	MaxInstances uint64 `yaml:"max_instances,omitempty"`
+
+	// Autoscale is the load balancer autoscaling configuration.
+	Autoscale LoadBalancerAutoscaleConfig `yaml:"autoscale,omitempty"`
+
+	// Strategy is the instance selection strategy (round_robin, least_loaded, latency,
+	// consistent_hash).
+	//
+	// If not specified, round-robin selection is used.
+	Strategy LoadBalancerStrategy `yaml:"strategy,omitempty"`
+}
+
+// LoadBalancerAutoscaleConfig is the load balancer autoscaling configuration.
+type LoadBalancerAutoscaleConfig struct {
+	// Interval is the interval at which the load is evaluated.
+	Interval time.Duration `yaml:"interval,omitempty"`
+
+	// ScaleUpQueueDepth is the average number of in-flight requests per instance at or above
+	// which an additional instance is provisioned.
+	ScaleUpQueueDepth uint64 `yaml:"scale_up_queue_depth,omitempty"`
+
+	// ScaleUpLatency is the average request latency at or above which an additional instance is
+	// provisioned. Zero disables latency-based scaling.
+	ScaleUpLatency time.Duration `yaml:"scale_up_latency,omitempty"`
+
+	// ScaleDownIdle is the duration for which the load must remain low before an additional
+	// instance is drained and stopped.
+	ScaleDownIdle time.Duration `yaml:"scale_down_idle,omitempty"`
+}
+
+// LoadBalancerStrategy is the load balancer instance selection strategy.
+type LoadBalancerStrategy string
+
+const (
+	// LoadBalancerStrategyRoundRobin selects healthy instances in a round-robin fashion.
+	LoadBalancerStrategyRoundRobin LoadBalancerStrategy = "round_robin"
+
+	// LoadBalancerStrategyLeastLoaded selects the healthy instance with the least number of
+	// in-flight requests.
+	LoadBalancerStrategyLeastLoaded LoadBalancerStrategy = "least_loaded"
+
+	// LoadBalancerStrategyLatency selects the healthy instance with the lowest expected latency
+	// based on an exponentially weighted moving average of its request latencies.
+	LoadBalancerStrategyLatency LoadBalancerStrategy = "latency"
+
+	// LoadBalancerStrategyConsistentHash selects the healthy instance based on a consistent hash
+	// of the caller, improving cache locality.
+	LoadBalancerStrategyConsistentHash LoadBalancerStrategy = "consistent_hash"
+)
+
+// Validate validates the load balancer configuration.
+func (c *LoadBalancerConfig) Validate() error {
+	if c.NumInstances > 128 || c.MaxInstances > 128 {
+		return fmt.Errorf("cannot specify more than 128 instances for load balancing")
+	}
+
+	if c.MaxInstances > max(c.NumInstances, 1) {
+		if c.Autoscale.Interval < 1*time.Second {
+			return fmt.Errorf("load_balancer.autoscale.interval must be >= 1 second")
+		}
+		if c.Autoscale.ScaleUpQueueDepth < 1 {
+			return fmt.Errorf("load_balancer.autoscale.scale_up_queue_depth must be >= 1")
+		}
+	}
+
+	switch c.Strategy {
+	case "":
+	case LoadBalancerStrategyRoundRobin:
+	case LoadBalancerStrategyLeastLoaded:
+	case LoadBalancerStrategyLatency:
+	case LoadBalancerStrategyConsistentHash:
+	default:
+		return fmt.Errorf("unknown load balancer strategy: %s", c.Strategy)
+	}
+
+	return nil
 }
 
 // Validate validates the configuration settings.
@@ -406,12 +636,30 @@ func (c *Config) Validate() error {
 		if c.Prune.Interval < 1*time.Second {
 			return fmt.Errorf("prune.interval must be >= 1 second")
 		}
+	case "keep_recent":
+		if c.Prune.Interval < 1*time.Second {
+			return fmt.Errorf("prune.interval must be >= 1 second")
+		}
+		if c.Prune.MaxAge <= 0 {
+			return fmt.Errorf("prune.max_age must be positive")
+		}
+	case "disk_budget":
+		if c.Prune.Interval < 1*time.Second {
+			return fmt.Errorf("prune.interval must be >= 1 second")
+		}
+		if bytesize.Parse(c.Prune.DiskBudget) == 0 {
+			return fmt.Errorf("prune.disk_budget must be a positive size (e.g. 100GB)")
+		}
 	default:
 		return fmt.Errorf("unknown runtime history pruner strategy: %s", c.Prune.Strategy)
 	}
 
-	if c.LoadBalancer.NumInstances > 128 {
-		return fmt.Errorf("cannot specify more than 128 instances for load balancing")
+	if err := c.LoadBalancer.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.TxPool.Validate(); err != nil {
+		return fmt.Errorf("tx_pool: %w", err)
 	}
 
 	for _, rt := range c.Runtimes {
@@ -504,15 +752,26 @@ func DefaultConfig() Config {
 		},
 		SentryAddresses: []string{},
 		TxPool: tpConfig.Config{
-			MaxPoolSize:          50_000,
-			MaxLastSeenCacheSize: 100_000,
-			MaxCheckTxBatchSize:  128,
-			RecheckInterval:      5,
-			RepublishInterval:    60 * time.Second,
+			MaxPoolSize:            50_000,
+			MaxLastSeenCacheSize:   100_000,
+			MaxCheckTxBatchSize:    128,
+			RecheckInterval:        5,
+			RepublishInterval:      60 * time.Second,
+			MinPriorityBumpPercent: 10,
+			JournalInterval:        time.Minute,
+			JournalMaxAge:          3 * time.Hour,
 		},
 		PreWarmEpochs: 3,
 		LoadBalancer: LoadBalancerConfig{
 			NumInstances: 0,
+			MaxInstances: 0,
+			Strategy:     LoadBalancerStrategyRoundRobin,
+			Autoscale: LoadBalancerAutoscaleConfig{
+				Interval:          10 * time.Second,
+				ScaleUpQueueDepth: 2,
+				ScaleUpLatency:    0,
+				ScaleDownIdle:     5 * time.Minute,
+			},
 		},
 		Registries: []string{oasisBundleRegistryURL},
 		SGX: SgxConfig{
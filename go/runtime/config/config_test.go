@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -67,6 +68,71 @@ runtimes:
 	require.True(compCfg.Disabled)
 }
 
+func TestComponentUpgradeConfig(t *testing.T) {
+	require := require.New(t)
+
+	yamlCfg := `
+id: rofl.foo-test
+upgrade:
+    mode: health_gated
+    readiness_method: health
+    rollback_window: 1m
+`
+	var compCfg ComponentConfig
+	err := yaml.Unmarshal([]byte(yamlCfg), &compCfg)
+	require.NoError(err, "yaml.Unmarshal")
+	require.NoError(compCfg.Validate())
+	require.Equal(ComponentUpgradeModeHealthGated, compCfg.Upgrade.Mode)
+	require.Equal("health", compCfg.Upgrade.ReadinessMethod)
+	require.Equal(DefaultComponentReadinessTimeout, compCfg.Upgrade.GetReadinessTimeout())
+	require.Equal(time.Minute, compCfg.Upgrade.GetRollbackWindow())
+
+	// Health-gated upgrades are only supported for ROFL components.
+	compCfg.ID = component.ID_RONL
+	require.Error(compCfg.Validate())
+
+	compCfg = ComponentConfig{
+		ID:      component.ID{Kind: component.ROFL, Name: "foo-test"},
+		Upgrade: ComponentUpgradeConfig{Mode: "unknown"},
+	}
+	require.Error(compCfg.Validate())
+
+	compCfg.Upgrade = ComponentUpgradeConfig{ReadinessTimeout: -time.Second}
+	require.Error(compCfg.Validate())
+}
+
+func TestComponentResourcesConfig(t *testing.T) {
+	require := require.New(t)
+
+	yamlCfg := `
+id: rofl.foo-test
+resources:
+    memory: 512MB
+    cpu_shares: 50
+    pids: 128
+`
+	var compCfg ComponentConfig
+	err := yaml.Unmarshal([]byte(yamlCfg), &compCfg)
+	require.NoError(err, "yaml.Unmarshal")
+	require.NoError(compCfg.Validate())
+	require.False(compCfg.Resources.IsEmpty())
+	require.Equal("512MB", compCfg.Resources.Memory)
+	require.EqualValues(50, compCfg.Resources.CPUShares)
+	require.EqualValues(128, compCfg.Resources.Pids)
+
+	compCfg.Resources.CPUShares = 10001
+	require.Error(compCfg.Validate(), "cpu shares out of range should be rejected")
+	compCfg.Resources.CPUShares = 50
+
+	for _, memory := range []string{"lots", "0", "-1GB"} {
+		compCfg.Resources.Memory = memory
+		require.Error(compCfg.Validate(), "malformed memory limit '%s' should be rejected", memory)
+	}
+
+	compCfg = ComponentConfig{ID: component.ID{Kind: component.ROFL, Name: "foo-test"}}
+	require.True(compCfg.Resources.IsEmpty())
+}
+
 func TestNetworkingConfig(t *testing.T) {
 	require := require.New(t)
 
@@ -137,3 +203,94 @@ func TestNetworkingConfig(t *testing.T) {
 	err = cfg.Validate()
 	require.ErrorContains(err, "component rofl (foo-test): overlapping incoming IP/protocol/port")
 }
+
+func TestLoadBalancerConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	cfg.Provisioner = RuntimeProvisionerMock
+	err := cfg.Validate()
+	require.NoError(err)
+
+	for _, strategy := range []LoadBalancerStrategy{
+		"",
+		LoadBalancerStrategyRoundRobin,
+		LoadBalancerStrategyLeastLoaded,
+		LoadBalancerStrategyLatency,
+		LoadBalancerStrategyConsistentHash,
+	} {
+		cfg.LoadBalancer.Strategy = strategy
+		err = cfg.Validate()
+		require.NoError(err, "strategy %s should be valid", strategy)
+	}
+
+	cfg.LoadBalancer.Strategy = "random"
+	err = cfg.Validate()
+	require.ErrorContains(err, "unknown load balancer strategy: random")
+
+	cfg.LoadBalancer.Strategy = LoadBalancerStrategyRoundRobin
+	cfg.LoadBalancer.NumInstances = 129
+	err = cfg.Validate()
+	require.ErrorContains(err, "cannot specify more than 128 instances for load balancing")
+
+	// Autoscaling.
+	cfg.LoadBalancer.NumInstances = 2
+	cfg.LoadBalancer.MaxInstances = 8
+	err = cfg.Validate()
+	require.NoError(err)
+
+	cfg.LoadBalancer.Autoscale.Interval = 0
+	err = cfg.Validate()
+	require.ErrorContains(err, "load_balancer.autoscale.interval must be >= 1 second")
+
+	// Autoscaling settings are ignored when autoscaling is disabled.
+	cfg.LoadBalancer.MaxInstances = 2
+	err = cfg.Validate()
+	require.NoError(err)
+}
+
+func TestPruneConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	cfg.Provisioner = RuntimeProvisionerMock
+	cfg.Prune.Strategy = "disk_budget"
+	err := cfg.Validate()
+	require.ErrorContains(err, "prune.disk_budget must be a positive size")
+
+	cfg.Prune.DiskBudget = "lots"
+	err = cfg.Validate()
+	require.ErrorContains(err, "prune.disk_budget must be a positive size")
+
+	cfg.Prune.DiskBudget = "100GB"
+	err = cfg.Validate()
+	require.NoError(err)
+}
+
+func TestTrustedPublishersConfig(t *testing.T) {
+	require := require.New(t)
+
+	yamlCfg := `
+runtimes:
+    - id: 8000000000000000000000000000000000000000000000000000000000000000
+      trusted_publishers:
+          - 4ZCLlCkaHxDrldH2ZNuTvo7RO9/5Dp3PN7RafqEZhy8=
+`
+	var decCfg Config
+	err := yaml.Unmarshal([]byte(yamlCfg), &decCfg)
+	require.NoError(err, "yaml.Unmarshal")
+	require.Len(decCfg.Runtimes, 1)
+	require.Len(decCfg.Runtimes[0].TrustedPublishers, 1)
+	require.Equal("4ZCLlCkaHxDrldH2ZNuTvo7RO9/5Dp3PN7RafqEZhy8=", decCfg.Runtimes[0].TrustedPublishers[0].String())
+	require.NoError(decCfg.Runtimes[0].Validate(), "Validate")
+
+	// Invalid keys should be rejected.
+	yamlCfg = `
+runtimes:
+    - id: 8000000000000000000000000000000000000000000000000000000000000000
+      trusted_publishers:
+          - not-a-key
+`
+	err = yaml.Unmarshal([]byte(yamlCfg), &decCfg)
+	require.Error(err, "yaml.Unmarshal should fail for malformed keys")
+}
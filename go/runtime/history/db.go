@@ -270,6 +270,12 @@ func (d *DB) getLastBlock() (*roothash.AnnotatedBlock, error) {
 	return &blk, nil
 }
 
+// size returns the approximate size of the database on disk.
+func (d *DB) size() uint64 {
+	lsm, vlog := d.db.Size()
+	return uint64(lsm + vlog)
+}
+
 func (d *DB) close() {
 	d.gc.Stop()
 	d.db.Close()
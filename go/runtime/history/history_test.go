@@ -558,3 +558,136 @@ func createBlocks(runtimeID common.Namespace) []*roothash.AnnotatedBlock {
 	}
 	return blks
 }
+
+type testDiskUsageHandler struct {
+	testPruneHandler
+
+	usage uint64
+}
+
+func (h *testDiskUsageHandler) DiskUsage() (uint64, error) {
+	return h.usage, nil
+}
+
+func newTestPruneDB(t *testing.T, runtimeID common.Namespace, n int) *DB {
+	require := require.New(t)
+
+	dataDir, err := os.MkdirTemp("", "oasis-runtime-history-test_")
+	require.NoError(err, "TempDir")
+	t.Cleanup(func() { os.RemoveAll(dataDir) })
+
+	db, err := newDB(dataDir+"/history.db", runtimeID)
+	require.NoError(err, "newDB")
+	t.Cleanup(db.close)
+
+	blks := make([]*roothash.AnnotatedBlock, n)
+	for i := 0; i < n; i++ {
+		blk := roothash.AnnotatedBlock{
+			Height: int64(i),
+			Block:  block.NewGenesisBlock(runtimeID, 0),
+		}
+		blk.Block.Header.Round = uint64(i)
+		blk.Block.Header.Timestamp = block.Timestamp(1000 + 10*i)
+		blks[i] = &blk
+	}
+	err = db.commit(blks)
+	require.NoError(err, "commit")
+
+	return db
+}
+
+func TestHistoryPruneKeepRecent(t *testing.T) {
+	require := require.New(t)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("history prune keep recent test ns"), 0)
+	db := newTestPruneDB(t, runtimeID, 100)
+
+	_, err := NewKeepRecentPruner(runtimeID, 0, time.Second, db)
+	require.Error(err, "NewKeepRecentPruner should fail with zero maximum age")
+
+	pruner, err := NewKeepRecentPruner(runtimeID, 100*time.Second, time.Second, db)
+	require.NoError(err, "NewKeepRecentPruner")
+	ph := testPruneHandler{doneCh: make(chan struct{}), waitRounds: 100}
+	pruner.RegisterHandler(&ph)
+
+	// Blocks older than 100 seconds should be pruned (rounds 0-68), in
+	// batches of at most maxBatchSize.
+	pruner.(*keepRecentPruner).nowFn = func() time.Time { return time.Unix(1790, 0) }
+	err = pruner.Prune(99)
+	require.NoError(err, "Prune")
+	require.Len(ph.prunedRounds, maxBatchSize)
+	err = pruner.Prune(99)
+	require.NoError(err, "Prune")
+	require.Len(ph.prunedRounds, 69)
+	require.EqualValues(68, ph.prunedRounds[68])
+	err = pruner.Prune(99)
+	require.NoError(err, "Prune")
+	require.Len(ph.prunedRounds, 69, "nothing more should be pruned")
+
+	_, err = db.getBlock(68)
+	require.ErrorIs(err, roothash.ErrNotFound)
+	_, err = db.getBlock(69)
+	require.NoError(err, "getBlock")
+
+	// The latest round should never be pruned.
+	pruner.(*keepRecentPruner).nowFn = func() time.Time { return time.Unix(100_000, 0) }
+	err = pruner.Prune(99)
+	require.NoError(err, "Prune")
+	require.Len(ph.prunedRounds, 99)
+	_, err = db.getBlock(99)
+	require.NoError(err, "latest block should be kept")
+}
+
+func TestHistoryPruneDiskBudget(t *testing.T) {
+	require := require.New(t)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("history prune disk budget test ns"), 0)
+	db := newTestPruneDB(t, runtimeID, 100)
+
+	_, err := NewDiskBudgetPruner(runtimeID, 0, 10, time.Second, db)
+	require.Error(err, "NewDiskBudgetPruner should fail with zero budget")
+
+	const budget = 1 << 40
+	pruner, err := NewDiskBudgetPruner(runtimeID, budget, 10, time.Second, db)
+	require.NoError(err, "NewDiskBudgetPruner")
+	ph := testDiskUsageHandler{
+		testPruneHandler: testPruneHandler{doneCh: make(chan struct{}), waitRounds: 100},
+	}
+	pruner.RegisterHandler(&ph)
+
+	// Nothing should be pruned while within budget.
+	err = pruner.Prune(99)
+	require.NoError(err, "Prune")
+	require.Empty(ph.prunedRounds)
+
+	// A single batch should be pruned per pass when over budget.
+	ph.usage = 2 * budget
+	err = pruner.Prune(99)
+	require.NoError(err, "Prune")
+	require.Len(ph.prunedRounds, maxBatchSize)
+	require.EqualValues(maxBatchSize-1, ph.prunedRounds[maxBatchSize-1])
+
+	// Nothing more should be pruned while the freed space is expected to be reclaimed.
+	err = pruner.Prune(99)
+	require.NoError(err, "Prune")
+	require.Len(ph.prunedRounds, maxBatchSize, "pruning should wait for space to be reclaimed")
+	ph.usage -= budget / 100
+	err = pruner.Prune(99)
+	require.NoError(err, "Prune")
+	require.Len(ph.prunedRounds, maxBatchSize, "pruning should wait for space to be reclaimed")
+
+	// Pruning should continue when the space has not been reclaimed in time. The minimum number
+	// of rounds should always be kept.
+	pruner.(*diskBudgetPruner).nowFn = func() time.Time { return time.Now().Add(diskReclaimTimeout + time.Minute) }
+	err = pruner.Prune(99)
+	require.NoError(err, "Prune")
+	require.Len(ph.prunedRounds, 90)
+	err = pruner.Prune(99)
+	require.NoError(err, "Prune")
+	require.Len(ph.prunedRounds, 90, "minimum number of rounds should be kept")
+
+	_, err = db.getBlock(89)
+	require.ErrorIs(err, roothash.ErrNotFound)
+	_, err = db.getBlock(90)
+	require.NoError(err, "getBlock")
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/dgraph-io/badger/v4"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 )
 
 const (
@@ -16,9 +18,18 @@ const (
 	PrunerStrategyNone = "none"
 	// PrunerStrategyKeepLast is the name of the keep last pruner strategy.
 	PrunerStrategyKeepLast = "keep_last"
+	// PrunerStrategyKeepRecent is the name of the keep recent pruner strategy.
+	PrunerStrategyKeepRecent = "keep_recent"
+	// PrunerStrategyDiskBudget is the name of the disk budget pruner strategy.
+	PrunerStrategyDiskBudget = "disk_budget"
 
 	// maxBatchSize is the maximum number of rounds to prune in one pass.
 	maxBatchSize = 64
+
+	// diskReclaimTimeout is the time after which the disk space estimated to be freed by pruning
+	// is expected to be reflected in the reported disk usage, covering a few garbage collection
+	// passes.
+	diskReclaimTimeout = 15 * time.Minute
 )
 
 // PrunerFactory is the runtime history pruner factory interface.
@@ -38,6 +49,14 @@ type PruneHandler interface {
 	Prune(rounds []uint64) error
 }
 
+// DiskUsageReporter is an optional interface that prune handlers can implement to report the
+// disk space used by the data they prune. The reported usage is taken into account by pruners
+// that prune based on a disk budget.
+type DiskUsageReporter interface {
+	// DiskUsage returns the (possibly approximate) number of bytes used on disk.
+	DiskUsage() (uint64, error)
+}
+
 // Pruner is the runtime history pruner interface.
 type Pruner interface {
 	// Prune purges unneeded history, given the latest round.
@@ -79,27 +98,28 @@ func NewNonePrunerFactory() PrunerFactory {
 	}
 }
 
-type keepLastPruner struct {
+// roundFilter decides whether the given round can be pruned. Since rounds are visited in
+// ascending order, returning false stops pruning for the current pass.
+type roundFilter func(round uint64, item *badger.Item) (bool, error)
+
+// basePruner implements the parts common to all pruners that prune the oldest rounds first.
+type basePruner struct {
 	logger *logging.Logger
 	db     *DB
 
-	numKept       uint64
 	pruneInterval time.Duration
 
 	mu       sync.RWMutex
 	handlers []PruneHandler
 }
 
-// Prune implements Pruner.
-func (p *keepLastPruner) Prune(latestRound uint64) error {
-	if latestRound < p.numKept {
-		return nil
-	}
-
-	lastPrunedRound := latestRound - p.numKept
-
-	return p.db.db.Update(func(tx *badger.Txn) error {
-		// NOTE: Do not prefetch values as we are only looking at keys.
+// pruneOldest prunes up to maxBatchSize of the oldest rounds for as long as the given filter
+// allows it, running all prune handlers before the rounds are pruned. It returns the number of
+// pruned rounds.
+func (p *basePruner) pruneOldest(canPrune roundFilter) (int, error) {
+	var numPruned int
+	err := p.db.db.Update(func(tx *badger.Txn) error {
+		// NOTE: Do not prefetch values as filters only rarely need to look at them.
 		it := tx.NewIterator(badger.IteratorOptions{
 			Prefix: blockKeyFmt.Encode(),
 		})
@@ -116,7 +136,11 @@ func (p *keepLastPruner) Prune(latestRound uint64) error {
 				panic("runtime/history: bad iterator")
 			}
 
-			if round > lastPrunedRound {
+			ok, err := canPrune(round, item)
+			if err != nil {
+				return err
+			}
+			if !ok {
 				break
 			}
 
@@ -152,32 +176,64 @@ func (p *keepLastPruner) Prune(latestRound uint64) error {
 				return fmt.Errorf("runtime/history: prune handler failed: %w", err)
 			}
 		}
+		numPruned = len(pruned)
 
 		return nil
 	})
+	if err != nil {
+		return 0, err
+	}
+	return numPruned, nil
 }
 
 // PruneInterval implements Pruner.
-func (p *keepLastPruner) PruneInterval() time.Duration {
+func (p *basePruner) PruneInterval() time.Duration {
 	return p.pruneInterval
 }
 
-func (p *keepLastPruner) RegisterHandler(handler PruneHandler) {
+// RegisterHandler implements Pruner.
+func (p *basePruner) RegisterHandler(handler PruneHandler) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	p.handlers = append(p.handlers, handler)
 }
 
+func newBasePruner(strategy string, runtimeID common.Namespace, pruneInterval time.Duration, db *DB) basePruner {
+	return basePruner{
+		logger:        logging.GetLogger("runtime/prune/"+strategy).With("runtime_id", runtimeID),
+		db:            db,
+		pruneInterval: pruneInterval,
+		handlers:      make([]PruneHandler, 0),
+	}
+}
+
+type keepLastPruner struct {
+	basePruner
+
+	numKept uint64
+}
+
+// Prune implements Pruner.
+func (p *keepLastPruner) Prune(latestRound uint64) error {
+	if latestRound < p.numKept {
+		return nil
+	}
+
+	lastPrunedRound := latestRound - p.numKept
+
+	_, err := p.pruneOldest(func(round uint64, _ *badger.Item) (bool, error) {
+		return round <= lastPrunedRound, nil
+	})
+	return err
+}
+
 // NewKeepLastPruner creates a pruner that keeps the last configured
 // number of rounds.
 func NewKeepLastPruner(runtimeID common.Namespace, numKept uint64, pruneInterval time.Duration, db *DB) (Pruner, error) {
 	return &keepLastPruner{
-		logger:        logging.GetLogger("runtime/prune/keep_last").With("runtime_id", runtimeID),
-		db:            db,
-		numKept:       numKept,
-		pruneInterval: pruneInterval,
-		handlers:      make([]PruneHandler, 0),
+		basePruner: newBasePruner(PrunerStrategyKeepLast, runtimeID, pruneInterval, db),
+		numKept:    numKept,
 	}, nil
 }
 
@@ -188,3 +244,185 @@ func NewKeepLastPrunerFactory(numKept uint64, pruneInterval time.Duration) Prune
 		return NewKeepLastPruner(runtimeID, numKept, pruneInterval, db)
 	}
 }
+
+type keepRecentPruner struct {
+	basePruner
+
+	maxAge time.Duration
+
+	// nowFn returns the current time and can be overridden in tests.
+	nowFn func() time.Time
+}
+
+// Prune implements Pruner.
+func (p *keepRecentPruner) Prune(latestRound uint64) error {
+	cutoff := p.nowFn().Add(-p.maxAge)
+
+	_, err := p.pruneOldest(func(round uint64, item *badger.Item) (bool, error) {
+		// Never prune the latest round.
+		if round >= latestRound {
+			return false, nil
+		}
+
+		var blk roothash.AnnotatedBlock
+		if err := item.Value(func(val []byte) error {
+			return cbor.UnmarshalTrusted(val, &blk)
+		}); err != nil {
+			return false, err
+		}
+		return time.Unix(int64(blk.Block.Header.Timestamp), 0).Before(cutoff), nil
+	})
+	return err
+}
+
+// NewKeepRecentPruner creates a pruner that keeps all rounds with blocks newer than the given
+// maximum age based on the block timestamps. The latest round is always kept.
+func NewKeepRecentPruner(runtimeID common.Namespace, maxAge time.Duration, pruneInterval time.Duration, db *DB) (Pruner, error) {
+	if maxAge <= 0 {
+		return nil, fmt.Errorf("runtime/history: invalid maximum age: %s", maxAge)
+	}
+
+	return &keepRecentPruner{
+		basePruner: newBasePruner(PrunerStrategyKeepRecent, runtimeID, pruneInterval, db),
+		maxAge:     maxAge,
+		nowFn:      time.Now,
+	}, nil
+}
+
+// NewKeepRecentPrunerFactory creates a new pruner factory for pruners that keep rounds newer
+// than the given maximum age.
+func NewKeepRecentPrunerFactory(maxAge time.Duration, pruneInterval time.Duration) PrunerFactory {
+	return func(runtimeID common.Namespace, db *DB) (Pruner, error) {
+		return NewKeepRecentPruner(runtimeID, maxAge, pruneInterval, db)
+	}
+}
+
+type diskBudgetPruner struct {
+	basePruner
+
+	budget     uint64
+	minNumKept uint64
+
+	// pendingReclaim is the estimated disk space freed by pruning that is not yet reflected in
+	// the reported disk usage, as space is only reclaimed after garbage collection.
+	pendingReclaim uint64
+	// pendingSince is the time of the oldest prune pass included in pendingReclaim.
+	pendingSince time.Time
+	// lastUsage is the disk usage reported during the previous pass.
+	lastUsage uint64
+
+	// nowFn returns the current time and can be overridden in tests.
+	nowFn func() time.Time
+}
+
+// diskUsage returns the disk space used by the history database and by the data managed by all
+// prune handlers that report their disk usage.
+func (p *diskBudgetPruner) diskUsage() (uint64, error) {
+	usage := p.db.size()
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, ph := range p.handlers {
+		reporter, ok := ph.(DiskUsageReporter)
+		if !ok {
+			continue
+		}
+		handlerUsage, err := reporter.DiskUsage()
+		if err != nil {
+			return 0, fmt.Errorf("runtime/history: failed to get disk usage: %w", err)
+		}
+		usage += handlerUsage
+	}
+	return usage, nil
+}
+
+// Prune implements Pruner.
+func (p *diskBudgetPruner) Prune(latestRound uint64) error {
+	if latestRound < p.minNumKept {
+		return nil
+	}
+	lastPrunableRound := latestRound - p.minNumKept
+
+	reportedUsage, err := p.diskUsage()
+	if err != nil {
+		return err
+	}
+	usage := p.estimateUsage(reportedUsage)
+	if usage <= p.budget {
+		return nil
+	}
+
+	earliestBlk, err := p.db.getEarliestBlock()
+	if err != nil {
+		return err
+	}
+	numRounds := latestRound - earliestBlk.Block.Header.Round + 1
+
+	p.logger.Debug("disk budget exceeded, pruning",
+		"usage", usage,
+		"reported_usage", reportedUsage,
+		"budget", p.budget,
+	)
+
+	numPruned, err := p.pruneOldest(func(round uint64, _ *badger.Item) (bool, error) {
+		return round <= lastPrunableRound, nil
+	})
+	if err != nil || numPruned == 0 {
+		return err
+	}
+
+	// Assume that disk usage is evenly distributed among rounds to estimate the space that will
+	// be reclaimed once garbage collection catches up.
+	if p.pendingReclaim == 0 {
+		p.pendingSince = p.nowFn()
+	}
+	p.pendingReclaim += usage / numRounds * uint64(numPruned)
+
+	return nil
+}
+
+// estimateUsage returns the estimated disk usage after all space freed by pruning has been
+// reclaimed, given the currently reported disk usage.
+func (p *diskBudgetPruner) estimateUsage(reportedUsage uint64) uint64 {
+	// Any decrease of the reported usage is attributed to space freed by pruning.
+	if reportedUsage < p.lastUsage {
+		p.pendingReclaim -= min(p.pendingReclaim, p.lastUsage-reportedUsage)
+	}
+	p.lastUsage = reportedUsage
+
+	// Do not rely on the estimate indefinitely in case it was too optimistic.
+	if p.pendingReclaim > 0 && p.nowFn().Sub(p.pendingSince) > diskReclaimTimeout {
+		p.pendingReclaim = 0
+	}
+
+	return reportedUsage - min(reportedUsage, p.pendingReclaim)
+}
+
+// NewDiskBudgetPruner creates a pruner that prunes the oldest rounds while the disk space used by
+// the history database and the data managed by prune handlers (e.g., runtime storage) exceeds
+// the given budget. At least the given minimum number of last rounds is always kept.
+//
+// Since disk space is only reclaimed after garbage collection, the space freed by pruning is
+// estimated from the number of pruned rounds until the reported usage reflects it. The usage is
+// therefore allowed to exceed the budget temporarily.
+func NewDiskBudgetPruner(runtimeID common.Namespace, budget uint64, minNumKept uint64, pruneInterval time.Duration, db *DB) (Pruner, error) {
+	if budget == 0 {
+		return nil, fmt.Errorf("runtime/history: invalid disk budget")
+	}
+
+	return &diskBudgetPruner{
+		basePruner: newBasePruner(PrunerStrategyDiskBudget, runtimeID, pruneInterval, db),
+		budget:     budget,
+		minNumKept: minNumKept,
+		nowFn:      time.Now,
+	}, nil
+}
+
+// NewDiskBudgetPrunerFactory creates a new pruner factory for pruners that prune rounds while the
+// disk usage exceeds the given budget.
+func NewDiskBudgetPrunerFactory(budget uint64, minNumKept uint64, pruneInterval time.Duration) PrunerFactory {
+	return func(runtimeID common.Namespace, db *DB) (Pruner, error) {
+		return NewDiskBudgetPruner(runtimeID, budget, minNumKept, pruneInterval, db)
+	}
+}
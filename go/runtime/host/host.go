@@ -3,6 +3,7 @@ package host
 
 import (
 	"context"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
@@ -91,6 +92,24 @@ type CompositeRuntime interface {
 	Component(id component.ID) (Runtime, bool)
 }
 
+// ResourceReporter is a runtime that can report the resources used by its processes.
+type ResourceReporter interface {
+	// GetResourceUsage returns the current resource usage of the runtime.
+	GetResourceUsage() (*ResourceUsage, error)
+}
+
+// ResourceUsage is the resource usage of a runtime.
+type ResourceUsage struct {
+	// Memory is the current amount of memory used in bytes.
+	Memory uint64 `json:"memory"`
+
+	// CPUTime is the total CPU time consumed since the runtime was (re)started.
+	CPUTime time.Duration `json:"cpu_time"`
+
+	// Pids is the current number of processes and threads.
+	Pids uint64 `json:"pids"`
+}
+
 // RuntimeHandler is the message handler for the host side of the runtime host protocol.
 type RuntimeHandler interface {
 	protocol.Handler
@@ -6,7 +6,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -19,13 +21,66 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
 )
 
-type lbHost struct {
-	id        common.Namespace
-	instances []host.Runtime
+// instance is a load-balanced runtime instance together with its load statistics.
+type instance struct {
+	// id is the instance identifier which is stable for the lifetime of the instance.
+	id int
+	rt host.Runtime
+
+	// dynamic is true iff the instance has been provisioned by the autoscaler and may be removed.
+	dynamic bool
+	// quitCh is closed when the instance is removed from the load balancer.
+	quitCh chan struct{}
+
+	// healthy is true iff the instance is available for serving requests.
+	healthy bool
+	// draining is true iff the instance is being removed and should not receive new requests.
+	draining bool
+	// unavailableSince is the time since which the instance has not been available.
+	unavailableSince time.Time
+	// inFlight is the number of calls currently being processed by the instance.
+	inFlight int
+	// latency is the EWMA of call latencies (in seconds). Zero if no calls have been observed.
+	latency float64
+}
+
+// available returns true iff the instance can be selected to serve new requests.
+func (inst *instance) available() bool {
+	return inst.healthy && !inst.draining
+}
+
+// observeLatency updates the latency EWMA with the given observation.
+func (inst *instance) observeLatency(d time.Duration) {
+	if inst.latency == 0 {
+		inst.latency = d.Seconds()
+		return
+	}
+	inst.latency = latencyEWMAWeight*d.Seconds() + (1-latencyEWMAWeight)*inst.latency
+}
+
+func (inst *instance) metricLabels(runtimeID common.Namespace) prometheus.Labels {
+	return prometheus.Labels{
+		"runtime":     runtimeID.String(),
+		"lb_instance": fmt.Sprintf("%d", inst.id),
+	}
+}
 
-	l                sync.Mutex
-	nextIdx          int
-	healthyInstances map[int]struct{}
+type lbHost struct {
+	id common.Namespace
+	// primary is the first instance which is never removed and is used for all requests that are
+	// not load-balanced.
+	primary host.Runtime
+
+	l         sync.Mutex
+	instances []*instance
+	selector  selector
+	scaler    *scaler
+
+	// propagated contains the last request of each kind that has been propagated to all instances
+	// so that it can be replayed to instances added later.
+	propagated map[string]*protocol.Body
+	// propagatedGen is incremented each time propagated is updated.
+	propagatedGen uint64
 
 	startOnce sync.Once
 	stopOnce  sync.Once
@@ -34,15 +89,39 @@ type lbHost struct {
 	logger *logging.Logger
 }
 
-// NewHost creates a new load balancer runtime host.
-func NewHost(id common.Namespace, instances []host.Runtime) host.Runtime {
-	return &lbHost{
-		id:               id,
-		instances:        instances,
-		healthyInstances: make(map[int]struct{}),
-		stopCh:           make(chan struct{}),
-		logger:           logging.GetLogger("runtime/host/loadbalance").With("runtime_id", id),
+// NewHost creates a new load balancer runtime host with a fixed set of instances.
+func NewHost(id common.Namespace, runtimes []host.Runtime, strategy Strategy) (host.Runtime, error) {
+	return newHost(id, runtimes, strategy)
+}
+
+func newHost(id common.Namespace, runtimes []host.Runtime, strategy Strategy) (*lbHost, error) {
+	if len(runtimes) == 0 {
+		return nil, fmt.Errorf("host/loadbalance: at least one instance is required")
+	}
+
+	selector, err := newSelector(strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]*instance, 0, len(runtimes))
+	for idx, rt := range runtimes {
+		instances = append(instances, &instance{
+			id:     idx,
+			rt:     rt,
+			quitCh: make(chan struct{}),
+		})
 	}
+
+	return &lbHost{
+		id:         id,
+		primary:    runtimes[0],
+		instances:  instances,
+		selector:   selector,
+		propagated: make(map[string]*protocol.Body),
+		stopCh:     make(chan struct{}),
+		logger:     logging.GetLogger("runtime/host/loadbalance").With("runtime_id", id),
+	}, nil
 }
 
 // Implements host.Runtime.
@@ -52,18 +131,18 @@ func (h *lbHost) ID() common.Namespace {
 
 // Implements host.Runtime.
 func (h *lbHost) GetInfo(ctx context.Context) (*protocol.RuntimeInfoResponse, error) {
-	return h.instances[0].GetInfo(ctx)
+	return h.primary.GetInfo(ctx)
 }
 
 // Implements host.Runtime.
 func (h *lbHost) GetActiveVersion() (*version.Version, error) {
-	return h.instances[0].GetActiveVersion()
+	return h.primary.GetActiveVersion()
 }
 
 // Implements host.Runtime.
 func (h *lbHost) GetCapabilityTEE() (*node.CapabilityTEE, error) {
 	// TODO: This won't work when registration of all client runtimes is required.
-	return h.instances[0].GetCapabilityTEE()
+	return h.primary.GetCapabilityTEE()
 }
 
 // shouldPropagateToAll checks whether the given runtime request should be propagated to all
@@ -87,15 +166,28 @@ func shouldPropagateToAll(body *protocol.Body) bool {
 func (h *lbHost) Call(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
 	switch {
 	case shouldPropagateToAll(body):
-		// Propagate call to all instances.
+		// Propagate call to all instances. Autoscaled instances that are still starting will get
+		// the request replayed once they are started.
+		h.l.Lock()
+		h.propagated[body.Type()] = body
+		h.propagatedGen++
+		instances := make([]*instance, 0, len(h.instances))
+		for _, inst := range h.instances {
+			if inst.dynamic && !inst.healthy {
+				continue
+			}
+			instances = append(instances, inst)
+		}
+		h.l.Unlock()
+
 		type result struct {
 			rsp *protocol.Body
 			err error
 		}
 		resCh := make(chan *result)
-		for _, rt := range h.instances {
+		for _, inst := range instances {
 			go func() {
-				rsp, err := rt.Call(ctx, body)
+				rsp, err := inst.rt.Call(ctx, body)
 				resCh <- &result{
 					rsp: rsp,
 					err: err,
@@ -107,7 +199,7 @@ func (h *lbHost) Call(ctx context.Context, body *protocol.Body) (*protocol.Body,
 			anyErr error
 			rsp    *protocol.Body
 		)
-		for range h.instances {
+		for range instances {
 			res := <-resCh
 			// Return the response of the instance that finished last. Note that currently all of
 			// the propagated methods return a `protocol.Empty` response so this does not matter.
@@ -120,119 +212,241 @@ func (h *lbHost) Call(ctx context.Context, body *protocol.Body) (*protocol.Body,
 		return rsp, nil
 	case body.RuntimeQueryRequest != nil, body.RuntimeCheckTxBatchRequest != nil:
 		// Load-balance queries.
-		idx, err := h.selectInstance()
-		if err != nil {
-			return nil, err
-		}
-
-		lbRequestCount.With(prometheus.Labels{
-			"runtime":     h.id.String(),
-			"lb_instance": fmt.Sprintf("%d", idx),
-		}).Inc()
-
-		return h.instances[idx].Call(ctx, body)
+		return h.balancedCall(ctx, body)
 	default:
 		// Propagate only to the first instance.
-		return h.instances[0].Call(ctx, body)
+		return h.primary.Call(ctx, body)
+	}
+}
+
+func (h *lbHost) balancedCall(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
+	inst, err := h.selectInstance(affinityKey(ctx, body))
+	if err != nil {
+		return nil, err
 	}
+	labels := inst.metricLabels(h.id)
+
+	lbRequestCount.With(labels).Inc()
+	lbInstanceInFlight.With(labels).Inc()
+
+	start := time.Now()
+	rsp, err := inst.rt.Call(ctx, body)
+	latency := time.Since(start)
+
+	// Update metrics before releasing the instance as it may be removed afterwards.
+	lbInstanceInFlight.With(labels).Dec()
+	lbInstanceLatency.With(labels).Observe(latency.Seconds())
+
+	h.l.Lock()
+	inst.inFlight--
+	inst.observeLatency(latency)
+	h.l.Unlock()
+
+	return rsp, err
 }
 
-func (h *lbHost) selectInstance() (int, error) {
+// selectInstance selects a healthy instance using the configured strategy and accounts for the
+// new in-flight call.
+func (h *lbHost) selectInstance(key uint64) (*instance, error) {
 	h.l.Lock()
 	defer h.l.Unlock()
 
-	for attempt := 0; attempt < len(h.instances); attempt++ {
-		idx := h.nextIdx
-		h.nextIdx = (h.nextIdx + 1) % len(h.instances)
-
-		if _, healthy := h.healthyInstances[idx]; healthy {
-			return idx, nil
-		}
+	idx, ok := h.selector.selectInstance(key, h.instances)
+	if !ok {
+		return nil, fmt.Errorf("host/loadbalance: no healthy instances available")
 	}
+	inst := h.instances[idx]
+	inst.inFlight++
 
-	return 0, fmt.Errorf("host/loadbalance: no healthy instances available")
+	return inst, nil
 }
 
 // Implements host.Runtime.
 func (h *lbHost) UpdateCapabilityTEE() {
-	for _, rt := range h.instances {
-		rt.UpdateCapabilityTEE()
+	for _, inst := range h.getInstances() {
+		inst.rt.UpdateCapabilityTEE()
 	}
 }
 
 // Implements host.Runtime.
 func (h *lbHost) WatchEvents() (<-chan *host.Event, pubsub.ClosableSubscription) {
-	return h.instances[0].WatchEvents()
+	return h.primary.WatchEvents()
 }
 
 // Implements host.Runtime.
 func (h *lbHost) Start() {
 	h.startOnce.Do(func() {
-		for idx, rt := range h.instances {
-			// Subscribe to runtime events before starting runtime to make sure we don't miss the
-			// started event.
-			evCh, sub := rt.WatchEvents()
+		for _, inst := range h.getInstances() {
+			h.startInstance(inst)
+		}
 
-			// Start a goroutine to monitor whether an instance is healthy.
-			go func() {
-				defer sub.Close()
-
-				for {
-					select {
-					case ev := <-evCh:
-						switch {
-						case ev.Started != nil:
-							// Mark instance as available.
-							h.logger.Info("instance is available",
-								"instance", idx,
-							)
+		if h.scaler != nil {
+			go h.scaler.run()
+		}
+	})
+}
 
-							h.l.Lock()
-							h.healthyInstances[idx] = struct{}{}
-							h.l.Unlock()
-						case ev.FailedToStart != nil, ev.Stopped != nil:
-							// Mark instance as failed.
-							h.logger.Warn("instance is no longer available",
-								"instance", idx,
+// startInstance starts the given instance and monitors whether it is healthy.
+func (h *lbHost) startInstance(inst *instance) {
+	// Subscribe to runtime events before starting runtime to make sure we don't miss the
+	// started event.
+	evCh, sub := inst.rt.WatchEvents()
+
+	// Start a goroutine to monitor whether an instance is healthy.
+	go func() {
+		defer sub.Close()
+
+		for {
+			select {
+			case ev := <-evCh:
+				switch {
+				case ev.Started != nil:
+					if inst.dynamic {
+						// Make sure autoscaled instances have received all propagated requests
+						// before accepting any requests.
+						if !h.replayPropagated(inst) {
+							h.logger.Warn("failed to replay propagated requests, draining instance",
+								"instance", inst.id,
 							)
 
 							h.l.Lock()
-							delete(h.healthyInstances, idx)
+							inst.draining = true
 							h.l.Unlock()
-						default:
+							break
 						}
-
-						// Update healthy instance count metrics.
+					} else {
 						h.l.Lock()
-						healthyInstanceCount := len(h.healthyInstances)
+						inst.healthy = true
 						h.l.Unlock()
+					}
 
-						lbHealthyInstanceCount.With(prometheus.Labels{
-							"runtime": h.id.String(),
-						}).Set(float64(healthyInstanceCount))
-					case <-h.stopCh:
-						return
+					// Mark instance as available.
+					h.logger.Info("instance is available",
+						"instance", inst.id,
+					)
+				case ev.FailedToStart != nil, ev.Stopped != nil:
+					// Mark instance as failed.
+					h.logger.Warn("instance is no longer available",
+						"instance", inst.id,
+					)
+
+					h.l.Lock()
+					inst.healthy = false
+					inst.unavailableSince = time.Now()
+					if inst.dynamic && ev.FailedToStart != nil {
+						// Autoscaled instances that fail to start are removed.
+						inst.draining = true
 					}
+					h.l.Unlock()
+				default:
 				}
-			}()
 
-			rt.Start()
+				h.updateInstanceMetrics()
+			case <-inst.quitCh:
+				return
+			case <-h.stopCh:
+				return
+			}
 		}
-	})
+	}()
+
+	inst.rt.Start()
+}
+
+// replayPropagated replays all previously propagated requests to the given instance and marks it
+// as healthy. Returns false in case replaying fails.
+func (h *lbHost) replayPropagated(inst *instance) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+	defer cancel()
+
+	for {
+		h.l.Lock()
+		gen := h.propagatedGen
+		bodies := make([]*protocol.Body, 0, len(h.propagated))
+		for _, body := range h.propagated {
+			bodies = append(bodies, body)
+		}
+		h.l.Unlock()
+
+		for _, body := range bodies {
+			if _, err := inst.rt.Call(ctx, body); err != nil {
+				h.logger.Error("failed to replay propagated request",
+					"err", err,
+					"instance", inst.id,
+					"request", body.Type(),
+				)
+				return false
+			}
+		}
+
+		// Make sure no new requests have been propagated in the meantime, as the instance would
+		// otherwise miss them.
+		h.l.Lock()
+		if gen == h.propagatedGen {
+			inst.healthy = true
+			h.l.Unlock()
+			return true
+		}
+		h.l.Unlock()
+	}
+}
+
+// nextInstanceIDLocked returns the lowest instance identifier that is not in use.
+//
+// The caller must hold the lock.
+func (h *lbHost) nextInstanceIDLocked() int {
+	used := make(map[int]struct{}, len(h.instances))
+	for _, inst := range h.instances {
+		used[inst.id] = struct{}{}
+	}
+	for id := 0; ; id++ {
+		if _, ok := used[id]; !ok {
+			return id
+		}
+	}
+}
+
+// getInstances returns a snapshot of all current instances.
+func (h *lbHost) getInstances() []*instance {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	return slices.Clone(h.instances)
+}
+
+// updateInstanceMetrics updates the instance count metrics.
+func (h *lbHost) updateInstanceMetrics() {
+	h.l.Lock()
+	var healthyInstanceCount int
+	for _, inst := range h.instances {
+		if inst.healthy {
+			healthyInstanceCount++
+		}
+	}
+	instanceCount := len(h.instances)
+	h.l.Unlock()
+
+	lbHealthyInstanceCount.With(prometheus.Labels{
+		"runtime": h.id.String(),
+	}).Set(float64(healthyInstanceCount))
+	lbInstanceCount.With(prometheus.Labels{
+		"runtime": h.id.String(),
+	}).Set(float64(instanceCount))
 }
 
 // Implements host.Runtime.
 func (h *lbHost) Abort(ctx context.Context, force bool) error {
 	// We don't know which instance to abort, so we abort all instances.
+	instances := h.getInstances()
 	errCh := make(chan error)
-	for _, rt := range h.instances {
+	for _, inst := range instances {
 		go func() {
-			errCh <- rt.Abort(ctx, force)
+			errCh <- inst.rt.Abort(ctx, force)
 		}()
 	}
 
 	var anyErr error
-	for range h.instances {
+	for range instances {
 		err := <-errCh
 		anyErr = errors.Join(anyErr, err)
 	}
@@ -244,8 +458,8 @@ func (h *lbHost) Stop() {
 	h.stopOnce.Do(func() {
 		close(h.stopCh)
 
-		for _, rt := range h.instances {
-			rt.Stop()
+		for _, inst := range h.getInstances() {
+			inst.rt.Stop()
 		}
 	})
 }
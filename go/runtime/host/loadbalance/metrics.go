@@ -23,9 +23,41 @@ var (
 		},
 		[]string{"runtime"},
 	)
+	lbInstanceInFlight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_client_lb_in_flight_requests",
+			Help: "Number of in-flight requests of the given load balancer instance.",
+		},
+		[]string{"runtime", "lb_instance"},
+	)
+	lbInstanceLatency = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name: "oasis_worker_client_lb_request_latency",
+			Help: "Request latency of the given load balancer instance (seconds).",
+		},
+		[]string{"runtime", "lb_instance"},
+	)
+	lbInstanceCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_client_lb_instance_count",
+			Help: "Number of instances provisioned by the load balancer.",
+		},
+		[]string{"runtime"},
+	)
+	lbScalingEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_client_lb_scaling_events",
+			Help: "Number of load balancer instances added (up) or removed (down) by autoscaling.",
+		},
+		[]string{"runtime", "direction"},
+	)
 	nodeCollectors = []prometheus.Collector{
 		lbRequestCount,
 		lbHealthyInstanceCount,
+		lbInstanceInFlight,
+		lbInstanceLatency,
+		lbInstanceCount,
+		lbScalingEvents,
 	}
 
 	metricsOnce sync.Once
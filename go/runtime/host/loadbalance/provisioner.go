@@ -7,29 +7,71 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 )
 
+// Config is the load balancer configuration.
+type Config struct {
+	// NumInstances is the number of runtime instances to provision. When autoscaling is enabled
+	// this is the minimum number of instances.
+	NumInstances int
+
+	// Strategy is the instance selection strategy.
+	Strategy Strategy
+
+	// Autoscale is the autoscaling configuration.
+	Autoscale AutoscaleConfig
+}
+
+// minInstances returns the minimum number of instances.
+func (cfg *Config) minInstances() int {
+	return max(cfg.NumInstances, 1)
+}
+
+// maxInstances returns the maximum number of instances.
+func (cfg *Config) maxInstances() int {
+	return max(cfg.minInstances(), cfg.Autoscale.MaxInstances)
+}
+
+// autoscaleEnabled returns true iff autoscaling is enabled.
+func (cfg *Config) autoscaleEnabled() bool {
+	return cfg.maxInstances() > cfg.minInstances()
+}
+
 type lbProvisioner struct {
-	inner        host.Provisioner
-	numInstances int
+	inner host.Provisioner
+	cfg   Config
 }
 
 // NewProvisioner creates a load-balancing runtime provisioner.
-func NewProvisioner(inner host.Provisioner, numInstances int) host.Provisioner {
-	if numInstances < 2 {
+func NewProvisioner(inner host.Provisioner, cfg Config) (host.Provisioner, error) {
+	if cfg.maxInstances() < 2 {
 		// If there is only a single instance configured just return the inner provisioner.
-		return inner
+		return inner, nil
+	}
+
+	// Make sure the strategy is supported.
+	if _, err := newSelector(cfg.Strategy); err != nil {
+		return nil, err
+	}
+
+	if cfg.autoscaleEnabled() {
+		if cfg.Autoscale.Interval <= 0 {
+			return nil, fmt.Errorf("host/loadbalance: autoscale interval must be positive")
+		}
+		if cfg.Autoscale.ScaleUpQueueDepth < 1 {
+			return nil, fmt.Errorf("host/loadbalance: autoscale queue depth must be at least one")
+		}
 	}
 
 	initMetrics()
 
 	return &lbProvisioner{
-		inner:        inner,
-		numInstances: numInstances,
-	}
+		inner: inner,
+		cfg:   cfg,
+	}, nil
 }
 
 // Implements host.Provisioner.
 func (p *lbProvisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
-	if p.numInstances < 2 {
+	if p.cfg.maxInstances() < 2 {
 		// This should never happen as the provisioner constructor made sure, but just to be safe.
 		return nil, fmt.Errorf("host/loadbalance: number of instances must be at least two")
 	}
@@ -41,7 +83,7 @@ func (p *lbProvisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
 
 	// Use the inner provisioner to provision multiple runtimes.
 	var instances []host.Runtime
-	for i := 0; i < p.numInstances; i++ {
+	for i := 0; i < p.cfg.minInstances(); i++ {
 		rt, err := p.inner.NewRuntime(cfg)
 		if err != nil {
 			return nil, fmt.Errorf("host/loadbalance: failed to provision instance %d: %w", i, err)
@@ -50,12 +92,25 @@ func (p *lbProvisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
 		instances = append(instances, rt)
 	}
 
-	host := NewHost(cfg.ID, instances)
+	h, err := newHost(cfg.ID, instances, p.cfg.Strategy)
+	if err != nil {
+		return nil, err
+	}
 
-	return host, nil
+	if p.cfg.autoscaleEnabled() {
+		h.scaler = newScaler(h, p.cfg.minInstances(), p.cfg.Autoscale, func() (host.Runtime, error) {
+			return p.inner.NewRuntime(cfg)
+		})
+	}
+
+	return h, nil
 }
 
 // Implements host.Provisioner.
 func (p *lbProvisioner) Name() string {
-	return fmt.Sprintf("load-balancer[%d]/%s", p.numInstances, p.inner.Name())
+	instances := fmt.Sprintf("%d", p.cfg.minInstances())
+	if p.cfg.autoscaleEnabled() {
+		instances = fmt.Sprintf("%d-%d", p.cfg.minInstances(), p.cfg.maxInstances())
+	}
+	return fmt.Sprintf("load-balancer[%s,%s]/%s", instances, p.cfg.Strategy, p.inner.Name())
 }
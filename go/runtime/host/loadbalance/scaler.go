@@ -0,0 +1,302 @@
+package loadbalance
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+)
+
+const (
+	// replayTimeout is the timeout for replaying propagated requests to a newly started instance.
+	replayTimeout = 30 * time.Second
+
+	// instanceStartTimeout is the time after which an autoscaled instance that has not become
+	// available is drained, so that it does not block further scaling decisions.
+	instanceStartTimeout = 10 * time.Minute
+)
+
+// AutoscaleConfig is the load balancer autoscaling configuration.
+type AutoscaleConfig struct {
+	// MaxInstances is the maximum number of runtime instances. Autoscaling is enabled iff it is
+	// greater than the configured number of instances, which is then used as the minimum.
+	MaxInstances int
+
+	// Interval is the interval at which the load is evaluated.
+	Interval time.Duration
+
+	// ScaleUpQueueDepth is the average number of in-flight requests per healthy instance at or
+	// above which an additional instance is provisioned.
+	ScaleUpQueueDepth int
+
+	// ScaleUpLatency is the average request latency at or above which an additional instance is
+	// provisioned. Zero disables latency-based scaling.
+	ScaleUpLatency time.Duration
+
+	// ScaleDownIdle is the duration for which the load must remain low before an autoscaled
+	// instance is drained and stopped.
+	ScaleDownIdle time.Duration
+}
+
+// scaler grows and shrinks the set of load balancer instances based on load.
+type scaler struct {
+	h   *lbHost
+	cfg AutoscaleConfig
+
+	minInstances int
+	startTimeout time.Duration
+	provisionFn  func() (host.Runtime, error)
+
+	lowLoadSince time.Time
+
+	logger *logging.Logger
+}
+
+func newScaler(h *lbHost, minInstances int, cfg AutoscaleConfig, provisionFn func() (host.Runtime, error)) *scaler {
+	return &scaler{
+		h:            h,
+		cfg:          cfg,
+		minInstances: minInstances,
+		startTimeout: instanceStartTimeout,
+		provisionFn:  provisionFn,
+		logger:       h.logger.With("component", "scaler"),
+	}
+}
+
+func (s *scaler) run() {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.h.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		s.reap()
+		s.evaluate()
+	}
+}
+
+// loadStats is a snapshot of the load balancer load.
+type loadStats struct {
+	// instances is the number of instances that are not being drained.
+	instances int
+	// available is the number of instances that can serve requests.
+	available int
+	// starting is the number of autoscaled instances that have not yet started.
+	starting int
+	// inFlight is the number of in-flight requests over all available instances.
+	inFlight int
+	// latency is the average latency (in seconds) of all available instances.
+	latency float64
+}
+
+func (s *scaler) loadStats() loadStats {
+	s.h.l.Lock()
+	defer s.h.l.Unlock()
+
+	var (
+		stats           loadStats
+		latencySamples  int
+		totalLatencySec float64
+	)
+	for _, inst := range s.h.instances {
+		if inst.draining {
+			continue
+		}
+		stats.instances++
+
+		switch {
+		case inst.healthy:
+			stats.available++
+			stats.inFlight += inst.inFlight
+			if inst.latency > 0 {
+				latencySamples++
+				totalLatencySec += inst.latency
+			}
+		case inst.dynamic:
+			stats.starting++
+		}
+	}
+	if latencySamples > 0 {
+		stats.latency = totalLatencySec / float64(latencySamples)
+	}
+	return stats
+}
+
+// evaluate evaluates the current load and scales the instances up or down when needed.
+func (s *scaler) evaluate() {
+	stats := s.loadStats()
+	if stats.starting > 0 || stats.available == 0 {
+		// Wait for pending instances to start before making any further decisions.
+		return
+	}
+
+	avgInFlight := float64(stats.inFlight) / float64(stats.available)
+	overloaded := avgInFlight >= float64(s.cfg.ScaleUpQueueDepth)
+	if s.cfg.ScaleUpLatency > 0 && stats.inFlight > 0 && stats.latency >= s.cfg.ScaleUpLatency.Seconds() {
+		overloaded = true
+	}
+	underloaded := !overloaded && 2*avgInFlight < float64(s.cfg.ScaleUpQueueDepth)
+
+	switch {
+	case overloaded:
+		s.lowLoadSince = time.Time{}
+
+		if stats.instances >= s.cfg.MaxInstances {
+			return
+		}
+
+		s.logger.Info("load is high, provisioning additional instance",
+			"instances", stats.instances,
+			"avg_in_flight", avgInFlight,
+			"avg_latency", stats.latency,
+		)
+		s.scaleUp()
+	case underloaded:
+		if s.lowLoadSince.IsZero() {
+			s.lowLoadSince = time.Now()
+			return
+		}
+		if time.Since(s.lowLoadSince) < s.cfg.ScaleDownIdle || stats.instances <= s.minInstances {
+			return
+		}
+		// Make sure that the next instance is only removed after another idle period.
+		s.lowLoadSince = time.Now()
+
+		s.logger.Info("load is low, draining instance",
+			"instances", stats.instances,
+			"avg_in_flight", avgInFlight,
+		)
+		s.scaleDown()
+	default:
+		s.lowLoadSince = time.Time{}
+	}
+}
+
+// scaleUp provisions and starts an additional instance.
+func (s *scaler) scaleUp() {
+	rt, err := s.provisionFn()
+	if err != nil {
+		s.logger.Error("failed to provision additional instance",
+			"err", err,
+		)
+		return
+	}
+
+	s.h.l.Lock()
+	select {
+	case <-s.h.stopCh:
+		// Load balancer has been stopped while provisioning.
+		s.h.l.Unlock()
+		rt.Stop()
+		return
+	default:
+	}
+
+	inst := &instance{
+		id:               s.h.nextInstanceIDLocked(),
+		rt:               rt,
+		dynamic:          true,
+		quitCh:           make(chan struct{}),
+		unavailableSince: time.Now(),
+	}
+	s.h.instances = append(s.h.instances, inst)
+	s.h.l.Unlock()
+
+	// Start the instance without holding the lock as starting may take a while. In case the load
+	// balancer has been stopped in the meantime, make sure the instance is stopped as well.
+	s.h.startInstance(inst)
+	select {
+	case <-s.h.stopCh:
+		rt.Stop()
+		return
+	default:
+	}
+
+	s.logger.Info("provisioned additional instance",
+		"instance", inst.id,
+	)
+
+	lbScalingEvents.With(prometheus.Labels{
+		"runtime":   s.h.id.String(),
+		"direction": "up",
+	}).Inc()
+	s.h.updateInstanceMetrics()
+}
+
+// scaleDown starts draining the most recently added autoscaled instance. The instance stops
+// receiving new requests and is removed once all of its in-flight requests complete.
+func (s *scaler) scaleDown() {
+	s.h.l.Lock()
+	defer s.h.l.Unlock()
+
+	for i := len(s.h.instances) - 1; i >= 0; i-- {
+		inst := s.h.instances[i]
+		if !inst.dynamic || inst.draining {
+			continue
+		}
+
+		inst.draining = true
+
+		s.logger.Info("draining instance",
+			"instance", inst.id,
+		)
+		return
+	}
+}
+
+// reap drains autoscaled instances which failed to become available in time and removes and stops
+// all drained instances without in-flight requests.
+func (s *scaler) reap() {
+	var removed []*instance
+
+	s.h.l.Lock()
+	for _, inst := range s.h.instances {
+		if !inst.dynamic || inst.healthy || inst.draining || time.Since(inst.unavailableSince) < s.startTimeout {
+			continue
+		}
+
+		s.logger.Warn("instance failed to become available, draining instance",
+			"instance", inst.id,
+		)
+		inst.draining = true
+	}
+
+	instances := s.h.instances[:0]
+	for _, inst := range s.h.instances {
+		if inst.draining && inst.inFlight == 0 {
+			removed = append(removed, inst)
+			continue
+		}
+		instances = append(instances, inst)
+	}
+	clear(s.h.instances[len(instances):])
+	s.h.instances = instances
+	s.h.l.Unlock()
+
+	for _, inst := range removed {
+		close(inst.quitCh)
+		inst.rt.Stop()
+
+		s.logger.Info("removed instance",
+			"instance", inst.id,
+		)
+
+		labels := inst.metricLabels(s.h.id)
+		lbRequestCount.Delete(labels)
+		lbInstanceInFlight.Delete(labels)
+		lbInstanceLatency.Delete(labels)
+		lbScalingEvents.With(prometheus.Labels{
+			"runtime":   s.h.id.String(),
+			"direction": "down",
+		}).Inc()
+	}
+	if len(removed) > 0 {
+		s.h.updateInstanceMetrics()
+	}
+}
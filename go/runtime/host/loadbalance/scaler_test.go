@@ -0,0 +1,301 @@
+package loadbalance
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+)
+
+// testRuntime is a runtime whose queries block until released.
+type testRuntime struct {
+	sync.Mutex
+
+	notifier  *pubsub.Broker
+	releaseCh chan struct{}
+	// startCh, if set, blocks starting the runtime until closed.
+	startCh chan struct{}
+
+	calls   []string
+	stopped bool
+}
+
+func newTestRuntime() *testRuntime {
+	return &testRuntime{
+		notifier:  pubsub.NewBroker(false),
+		releaseCh: make(chan struct{}),
+	}
+}
+
+func (r *testRuntime) ID() common.Namespace {
+	return common.Namespace{}
+}
+
+func (r *testRuntime) GetInfo(context.Context) (*protocol.RuntimeInfoResponse, error) {
+	return &protocol.RuntimeInfoResponse{}, nil
+}
+
+func (r *testRuntime) GetActiveVersion() (*version.Version, error) {
+	return nil, nil
+}
+
+func (r *testRuntime) GetCapabilityTEE() (*node.CapabilityTEE, error) {
+	return nil, nil
+}
+
+func (r *testRuntime) Call(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
+	r.Lock()
+	r.calls = append(r.calls, body.Type())
+	r.Unlock()
+
+	if body.RuntimeQueryRequest != nil {
+		select {
+		case <-r.releaseCh:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &protocol.Body{Empty: &protocol.Empty{}}, nil
+}
+
+func (r *testRuntime) getCalls() []string {
+	r.Lock()
+	defer r.Unlock()
+
+	return append([]string{}, r.calls...)
+}
+
+func (r *testRuntime) isStopped() bool {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.stopped
+}
+
+func (r *testRuntime) UpdateCapabilityTEE() {}
+
+func (r *testRuntime) WatchEvents() (<-chan *host.Event, pubsub.ClosableSubscription) {
+	ch := make(chan *host.Event)
+	sub := r.notifier.Subscribe()
+	sub.Unwrap(ch)
+
+	return ch, sub
+}
+
+func (r *testRuntime) Start() {
+	if r.startCh != nil {
+		<-r.startCh
+	}
+	r.notifier.Broadcast(&host.Event{Started: &host.StartedEvent{}})
+}
+
+func (r *testRuntime) Abort(context.Context, bool) error {
+	return nil
+}
+
+func (r *testRuntime) Stop() {
+	r.Lock()
+	r.stopped = true
+	r.Unlock()
+
+	r.notifier.Broadcast(&host.Event{Stopped: &host.StoppedEvent{}})
+}
+
+func TestScaler(t *testing.T) {
+	require := require.New(t)
+
+	primary := newTestRuntime()
+	h, err := newHost(common.Namespace{}, []host.Runtime{primary}, StrategyLeastLoaded)
+	require.NoError(err)
+
+	var provisioned []*testRuntime
+	s := newScaler(h, 1, AutoscaleConfig{
+		MaxInstances:      2,
+		Interval:          time.Hour,
+		ScaleUpQueueDepth: 2,
+	}, func() (host.Runtime, error) {
+		rt := newTestRuntime()
+		provisioned = append(provisioned, rt)
+		return rt, nil
+	})
+	h.scaler = s
+
+	h.Start()
+	defer h.Stop()
+
+	waitAvailable := func(count int) {
+		require.Eventually(func() bool {
+			return s.loadStats().available == count
+		}, 2*time.Second, 10*time.Millisecond)
+	}
+	waitAvailable(1)
+
+	// Propagate a request which should be replayed to new instances.
+	_, err = h.Call(context.Background(), &protocol.Body{
+		RuntimeConsensusSyncRequest: &protocol.RuntimeConsensusSyncRequest{},
+	})
+	require.NoError(err)
+
+	// Generate load on the primary instance.
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = h.Call(context.Background(), &protocol.Body{
+				RuntimeQueryRequest: &protocol.RuntimeQueryRequest{},
+			})
+		}()
+	}
+	require.Eventually(func() bool {
+		return s.loadStats().inFlight == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// High load should provision an additional instance.
+	s.evaluate()
+	require.Len(provisioned, 1)
+	waitAvailable(2)
+	require.Equal([]string{"RuntimeConsensusSyncRequest"}, provisioned[0].getCalls())
+
+	// Maximum number of instances has been reached.
+	s.evaluate()
+	require.Len(provisioned, 1)
+
+	// Release load.
+	close(primary.releaseCh)
+	wg.Wait()
+
+	// Low load should drain the additional instance after the idle period.
+	s.evaluate()
+	require.Equal(2, s.loadStats().instances)
+	s.evaluate()
+	require.Equal(1, s.loadStats().instances)
+
+	s.reap()
+	require.Len(h.getInstances(), 1)
+	require.True(provisioned[0].isStopped())
+	require.False(primary.isStopped())
+
+	// Minimum number of instances has been reached.
+	s.evaluate()
+	s.evaluate()
+	require.Equal(1, s.loadStats().instances)
+}
+
+func TestScalerUnavailableInstance(t *testing.T) {
+	require := require.New(t)
+
+	primary := newTestRuntime()
+	h, err := newHost(common.Namespace{}, []host.Runtime{primary}, StrategyLeastLoaded)
+	require.NoError(err)
+
+	var provisioned []*testRuntime
+	s := newScaler(h, 1, AutoscaleConfig{
+		MaxInstances:      3,
+		Interval:          time.Hour,
+		ScaleUpQueueDepth: 1,
+	}, func() (host.Runtime, error) {
+		rt := newTestRuntime()
+		provisioned = append(provisioned, rt)
+		return rt, nil
+	})
+	h.scaler = s
+
+	h.Start()
+	defer h.Stop()
+
+	require.Eventually(func() bool {
+		return s.loadStats().available == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Generate load on the primary instance and provision an additional instance.
+	go func() {
+		_, _ = h.Call(context.Background(), &protocol.Body{
+			RuntimeQueryRequest: &protocol.RuntimeQueryRequest{},
+		})
+	}()
+	defer close(primary.releaseCh)
+	require.Eventually(func() bool {
+		return s.loadStats().inFlight == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	s.evaluate()
+	require.Len(provisioned, 1)
+	require.Eventually(func() bool {
+		return s.loadStats().available == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// The additional instance stops and never restarts.
+	provisioned[0].notifier.Broadcast(&host.Event{Stopped: &host.StoppedEvent{}})
+	require.Eventually(func() bool {
+		return s.loadStats().starting == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// The instance should not block scaling decisions forever.
+	s.reap()
+	s.evaluate()
+	require.Len(provisioned, 1, "scaling should wait for a starting instance")
+
+	s.startTimeout = 0
+	s.reap()
+	require.Len(h.getInstances(), 1, "unavailable instance should be removed")
+	require.True(provisioned[0].isStopped())
+
+	s.evaluate()
+	require.Len(provisioned, 2, "scaling should continue after removing the unavailable instance")
+}
+
+func TestScalerSlowStart(t *testing.T) {
+	require := require.New(t)
+
+	primary := newTestRuntime()
+	h, err := newHost(common.Namespace{}, []host.Runtime{primary}, StrategyLeastLoaded)
+	require.NoError(err)
+
+	slow := newTestRuntime()
+	slow.startCh = make(chan struct{})
+	s := newScaler(h, 1, AutoscaleConfig{
+		MaxInstances: 2,
+		Interval:     time.Hour,
+	}, func() (host.Runtime, error) {
+		return slow, nil
+	})
+	h.scaler = s
+
+	h.Start()
+	defer h.Stop()
+
+	require.Eventually(func() bool {
+		return s.loadStats().available == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		s.scaleUp()
+	}()
+
+	// A slow starting instance should not block other users of the load balancer.
+	require.Eventually(func() bool {
+		return s.loadStats().starting == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	_, err = h.Call(context.Background(), &protocol.Body{
+		RuntimeConsensusSyncRequest: &protocol.RuntimeConsensusSyncRequest{},
+	})
+	require.NoError(err)
+
+	close(slow.startCh)
+	<-doneCh
+	require.Eventually(func() bool {
+		return s.loadStats().available == 2
+	}, 2*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,198 @@
+package loadbalance
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+)
+
+// latencyEWMAWeight is the weight given to the most recent latency observation when updating the
+// exponentially weighted moving average of instance call latencies.
+const latencyEWMAWeight = 0.3
+
+// Strategy is the instance selection strategy used by the load balancer.
+type Strategy uint8
+
+const (
+	// StrategyRoundRobin selects healthy instances in a round-robin fashion.
+	StrategyRoundRobin Strategy = iota
+	// StrategyLeastLoaded selects the healthy instance with the least number of in-flight calls.
+	StrategyLeastLoaded
+	// StrategyLatency selects the healthy instance with the lowest expected latency, computed as
+	// the EWMA of its call latencies weighted by the number of its in-flight calls.
+	StrategyLatency
+	// StrategyConsistentHash selects a healthy instance based on the caller's affinity key using
+	// rendezvous hashing, so that requests from the same caller are routed to the same instance
+	// for as long as that instance is healthy.
+	StrategyConsistentHash
+)
+
+// String returns a string representation of the strategy.
+func (s Strategy) String() string {
+	switch s {
+	case StrategyRoundRobin:
+		return "round_robin"
+	case StrategyLeastLoaded:
+		return "least_loaded"
+	case StrategyLatency:
+		return "latency"
+	case StrategyConsistentHash:
+		return "consistent_hash"
+	default:
+		return fmt.Sprintf("[unknown strategy: %d]", s)
+	}
+}
+
+// selector selects the instance that should handle the next request.
+type selector interface {
+	// selectInstance returns the index of the selected healthy instance, or false in case there
+	// are no healthy instances.
+	//
+	// The caller must hold the load balancer lock.
+	selectInstance(key uint64, instances []*instance) (int, bool)
+}
+
+func newSelector(strategy Strategy) (selector, error) {
+	switch strategy {
+	case StrategyRoundRobin:
+		return &roundRobinSelector{}, nil
+	case StrategyLeastLoaded:
+		return &scoreSelector{
+			score: func(inst *instance) float64 {
+				return float64(inst.inFlight)
+			},
+		}, nil
+	case StrategyLatency:
+		return &scoreSelector{
+			score: func(inst *instance) float64 {
+				// Instances without any observations have zero latency and are preferred, so that
+				// they get sampled.
+				return inst.latency * float64(inst.inFlight+1)
+			},
+		}, nil
+	case StrategyConsistentHash:
+		return &consistentHashSelector{}, nil
+	default:
+		return nil, fmt.Errorf("host/loadbalance: unsupported strategy: %s", strategy)
+	}
+}
+
+type roundRobinSelector struct {
+	nextIdx int
+}
+
+func (s *roundRobinSelector) selectInstance(_ uint64, instances []*instance) (int, bool) {
+	for attempt := 0; attempt < len(instances); attempt++ {
+		idx := s.nextIdx % len(instances)
+		s.nextIdx = (idx + 1) % len(instances)
+
+		if instances[idx].available() {
+			return idx, true
+		}
+	}
+	return 0, false
+}
+
+// scoreSelector selects the healthy instance with the lowest score. Ties are broken in a
+// round-robin fashion so that equally scored instances share the load.
+type scoreSelector struct {
+	nextIdx int
+	score   func(inst *instance) float64
+}
+
+func (s *scoreSelector) selectInstance(_ uint64, instances []*instance) (int, bool) {
+	var (
+		bestIdx   int
+		bestScore = math.Inf(1)
+		found     bool
+	)
+	start := s.nextIdx % len(instances)
+	for i := range instances {
+		idx := (start + i) % len(instances)
+		inst := instances[idx]
+		if !inst.available() {
+			continue
+		}
+
+		if score := s.score(inst); !found || score < bestScore {
+			bestIdx = idx
+			bestScore = score
+			found = true
+		}
+	}
+	if !found {
+		return 0, false
+	}
+
+	s.nextIdx = (bestIdx + 1) % len(instances)
+	return bestIdx, true
+}
+
+// consistentHashSelector selects the healthy instance with the highest weight for the given key
+// (rendezvous hashing). When an instance becomes unavailable, only the keys that mapped to it are
+// redistributed among the remaining instances.
+type consistentHashSelector struct{}
+
+func (s *consistentHashSelector) selectInstance(key uint64, instances []*instance) (int, bool) {
+	var (
+		bestIdx    int
+		bestWeight uint64
+		found      bool
+	)
+	for idx, inst := range instances {
+		if !inst.available() {
+			continue
+		}
+
+		if weight := rendezvousWeight(key, inst.id); !found || weight > bestWeight {
+			bestIdx = idx
+			bestWeight = weight
+			found = true
+		}
+	}
+	return bestIdx, found
+}
+
+// rendezvousWeight computes the weight of the given instance for the given key.
+func rendezvousWeight(key uint64, id int) uint64 {
+	return mix64(key ^ mix64(uint64(id)+1))
+}
+
+// mix64 is the SplitMix64 finalizer.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+type affinityKeyCtxKey struct{}
+
+// WithAffinityKey returns a copy of the given context carrying an affinity key that is used by the
+// consistent hashing strategy to route all requests with the same key to the same instance.
+func WithAffinityKey(ctx context.Context, key []byte) context.Context {
+	return context.WithValue(ctx, affinityKeyCtxKey{}, key)
+}
+
+// affinityKey returns the hash of the affinity key for the given request. When the context does
+// not carry an explicit affinity key, one is derived from the request itself.
+func affinityKey(ctx context.Context, body *protocol.Body) uint64 {
+	h := fnv.New64a()
+	switch key, ok := ctx.Value(affinityKeyCtxKey{}).([]byte); {
+	case ok:
+		_, _ = h.Write(key)
+	case body.RuntimeQueryRequest != nil:
+		_, _ = h.Write([]byte(body.RuntimeQueryRequest.Method))
+		_, _ = h.Write(body.RuntimeQueryRequest.Args)
+	case body.RuntimeCheckTxBatchRequest != nil:
+		for _, tx := range body.RuntimeCheckTxBatchRequest.Inputs {
+			_, _ = h.Write(tx)
+		}
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,137 @@
+package loadbalance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestInstances(n int) []*instance {
+	instances := make([]*instance, 0, n)
+	for id := range n {
+		instances = append(instances, &instance{id: id, healthy: true})
+	}
+	return instances
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	require := require.New(t)
+
+	s, err := newSelector(StrategyRoundRobin)
+	require.NoError(err)
+
+	instances := newTestInstances(3)
+	for _, expected := range []int{0, 1, 2, 0, 1} {
+		idx, ok := s.selectInstance(0, instances)
+		require.True(ok)
+		require.Equal(expected, idx)
+	}
+
+	instances[0].healthy = false
+	for _, expected := range []int{2, 1, 2, 1} {
+		idx, ok := s.selectInstance(0, instances)
+		require.True(ok)
+		require.Equal(expected, idx)
+	}
+
+	instances[1].healthy = false
+	instances[2].healthy = false
+	_, ok := s.selectInstance(0, instances)
+	require.False(ok, "selection should fail without healthy instances")
+}
+
+func TestLeastLoadedSelector(t *testing.T) {
+	require := require.New(t)
+
+	s, err := newSelector(StrategyLeastLoaded)
+	require.NoError(err)
+
+	instances := newTestInstances(3)
+	instances[0].inFlight = 5
+	instances[1].inFlight = 2
+	instances[2].inFlight = 3
+
+	idx, ok := s.selectInstance(0, instances)
+	require.True(ok)
+	require.Equal(1, idx)
+
+	instances[1].healthy = false
+	idx, ok = s.selectInstance(0, instances)
+	require.True(ok)
+	require.Equal(2, idx)
+
+	// Ties should be broken in a round-robin fashion.
+	instances = newTestInstances(3)
+	seen := make(map[int]struct{})
+	for range 3 {
+		idx, ok = s.selectInstance(0, instances)
+		require.True(ok)
+		seen[idx] = struct{}{}
+	}
+	require.Len(seen, 3, "equally loaded instances should share the load")
+}
+
+func TestLatencySelector(t *testing.T) {
+	require := require.New(t)
+
+	s, err := newSelector(StrategyLatency)
+	require.NoError(err)
+
+	instances := newTestInstances(3)
+	instances[0].observeLatency(100 * time.Millisecond)
+	instances[1].observeLatency(10 * time.Millisecond)
+	instances[2].observeLatency(50 * time.Millisecond)
+
+	idx, ok := s.selectInstance(0, instances)
+	require.True(ok)
+	require.Equal(1, idx)
+
+	// A busy fast instance should lose to an idle slower one.
+	instances[1].inFlight = 9
+	idx, ok = s.selectInstance(0, instances)
+	require.True(ok)
+	require.Equal(2, idx)
+
+	// Instances without observations should be sampled first.
+	instances = append(instances, &instance{id: 3, healthy: true})
+	idx, ok = s.selectInstance(0, instances)
+	require.True(ok)
+	require.Equal(3, idx)
+}
+
+func TestConsistentHashSelector(t *testing.T) {
+	require := require.New(t)
+
+	s, err := newSelector(StrategyConsistentHash)
+	require.NoError(err)
+
+	const numKeys = 1000
+	instances := newTestInstances(4)
+	assignments := make([]int, numKeys)
+	counts := make([]int, len(instances))
+	for key := range numKeys {
+		idx, ok := s.selectInstance(uint64(key), instances)
+		require.True(ok)
+		assignments[key] = idx
+		counts[idx]++
+
+		// Selection must be stable.
+		again, _ := s.selectInstance(uint64(key), instances)
+		require.Equal(idx, again)
+	}
+	for idx, count := range counts {
+		require.Greater(count, numKeys/len(instances)/2, "instance %d should receive a fair share of keys", idx)
+	}
+
+	// Only keys mapped to the unhealthy instance should be redistributed.
+	instances[2].healthy = false
+	for key := range numKeys {
+		idx, ok := s.selectInstance(uint64(key), instances)
+		require.True(ok)
+		require.NotEqual(2, idx)
+		if assignments[key] != 2 {
+			require.Equal(assignments[key], idx)
+		}
+	}
+}
@@ -201,9 +201,15 @@ func createProvisioner(
 	}
 
 	// Configure optional load balancing.
+	lbCfg, err := createLoadBalancerConfig(config.GlobalConfig.Runtime.LoadBalancer)
+	if err != nil {
+		return nil, err
+	}
 	for tee, rp := range provisioners {
-		numInstances := int(config.GlobalConfig.Runtime.LoadBalancer.NumInstances)
-		provisioners[tee] = hostLoadBalance.NewProvisioner(rp, numInstances)
+		provisioners[tee], err = hostLoadBalance.NewProvisioner(rp, lbCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create load-balancing runtime provisioner: %w", err)
+		}
 	}
 
 	// Create a composite provisioner to provision the individual components.
@@ -211,3 +217,31 @@ func createProvisioner(
 
 	return provisioner, nil
 }
+
+func createLoadBalancerConfig(cfg rtConfig.LoadBalancerConfig) (hostLoadBalance.Config, error) {
+	var strategy hostLoadBalance.Strategy
+	switch cfg.Strategy {
+	case "", rtConfig.LoadBalancerStrategyRoundRobin:
+		strategy = hostLoadBalance.StrategyRoundRobin
+	case rtConfig.LoadBalancerStrategyLeastLoaded:
+		strategy = hostLoadBalance.StrategyLeastLoaded
+	case rtConfig.LoadBalancerStrategyLatency:
+		strategy = hostLoadBalance.StrategyLatency
+	case rtConfig.LoadBalancerStrategyConsistentHash:
+		strategy = hostLoadBalance.StrategyConsistentHash
+	default:
+		return hostLoadBalance.Config{}, fmt.Errorf("unknown load balancer strategy: %s", cfg.Strategy)
+	}
+
+	return hostLoadBalance.Config{
+		NumInstances: int(cfg.NumInstances),
+		Strategy:     strategy,
+		Autoscale: hostLoadBalance.AutoscaleConfig{
+			MaxInstances:      int(cfg.MaxInstances),
+			Interval:          cfg.Autoscale.Interval,
+			ScaleUpQueueDepth: int(cfg.Autoscale.ScaleUpQueueDepth),
+			ScaleUpLatency:    cfg.Autoscale.ScaleUpLatency,
+			ScaleDownIdle:     cfg.Autoscale.ScaleDownIdle,
+		},
+	}, nil
+}
@@ -240,6 +240,9 @@ func (h *sandboxHost) startProcess(ctx context.Context) (err error) {
 	if err != nil {
 		return fmt.Errorf("failed to configure process: %w", err)
 	}
+	if cfg.Resources, err = getResourceLimits(h.rtCfg); err != nil {
+		return fmt.Errorf("failed to configure resource limits: %w", err)
+	}
 	if err = connector.Configure(&h.rtCfg, &cfg); err != nil {
 		return err
 	}
@@ -327,8 +330,8 @@ func (h *sandboxHost) startProcess(ctx context.Context) (err error) {
 	}
 
 	ok = true
-	h.process = p
 	h.Lock()
+	h.process = p
 	h.conn = pc
 	h.capabilityTEE = ev.CapabilityTEE
 	h.rtVersion = rtVersion
@@ -435,18 +438,21 @@ func (h *sandboxHost) manager(ctx context.Context) {
 			h.conn.Close()
 			h.process.Kill()
 			<-h.process.Wait()
-			h.process = nil
+			h.clearResourceMetrics()
 
 			h.Lock()
+			h.process = nil
 			h.conn = nil
 			h.capabilityTEE = nil
 			h.Unlock()
 		}
 
+		// Clean up before notifying subscribers, so that any resources (e.g. volumes) held by the
+		// runtime are released by the time it is reported as stopped.
+		h.cfg.Cleanup(h.rtCfg)
+
 		// Notify subscribers that the runtime has stopped.
 		h.notifier.Broadcast(&host.Event{Stopped: &host.StoppedEvent{}})
-
-		h.cfg.Cleanup(h.rtCfg)
 	}()
 
 	// Subscribe to own events to make sure the cached CapabilityTEE remains up-to-date.
@@ -534,8 +540,9 @@ func (h *sandboxHost) manager(ctx context.Context) {
 			)
 
 			h.conn.Close()
-			h.process = nil
+			h.clearResourceMetrics()
 			h.Lock()
+			h.process = nil
 			h.conn = nil
 			h.capabilityTEE = nil
 			h.rtVersion = nil
@@ -560,6 +567,7 @@ func (h *sandboxHost) manager(ctx context.Context) {
 		case <-watchdogCh:
 			// Check for runtime liveness.
 			h.watchdogPing(ctx)
+			h.updateResourceMetrics()
 		}
 	}
 }
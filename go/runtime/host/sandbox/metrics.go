@@ -0,0 +1,51 @@
+package sandbox
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics"
+)
+
+var (
+	componentMemory = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_runtime_component_memory_bytes",
+			Help: "Memory used by the sandboxed runtime component processes (bytes).",
+		},
+		[]string{"runtime", "component", "version"},
+	)
+	componentCPUTime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_runtime_component_cpu_seconds",
+			Help: "CPU time consumed by the sandboxed runtime component processes since the last restart (seconds).",
+		},
+		[]string{"runtime", "component", "version"},
+	)
+	componentPids = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_runtime_component_pids",
+			Help: "Number of processes and threads of the sandboxed runtime component.",
+		},
+		[]string{"runtime", "component", "version"},
+	)
+	sandboxCollectors = []prometheus.Collector{
+		componentMemory,
+		componentCPUTime,
+		componentPids,
+	}
+
+	metricsOnce sync.Once
+)
+
+// initMetrics registers the metrics collectors if metrics are enabled.
+func initMetrics() {
+	if !metrics.Enabled() {
+		return
+	}
+
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(sandboxCollectors...)
+	})
+}
@@ -193,6 +193,8 @@ func NewBubbleWrap(cfg Config) (Process, error) {
 		Args:   cliArgs,
 		Stdout: cfg.Stdout,
 		Stderr: cfg.Stderr,
+		// Apply resource limits to the whole sandbox.
+		Resources: cfg.Resources,
 		// Pass all the pipe file descriptors.
 		// NOTE: Entry i becomes file descriptor 3+i.
 		extraFiles: fdPipes.pipes,
@@ -0,0 +1,375 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	cgroupMountPoint = "/sys/fs/cgroup"
+
+	// cgroupLeafName is the name of the cgroup into which the node processes are moved in case
+	// controllers cannot be enabled for the node's own cgroup while it contains processes.
+	cgroupLeafName = "oasis-node"
+	// cgroupSandboxPrefix is the name prefix of the cgroups created for sandboxed processes.
+	cgroupSandboxPrefix = "oasis-sandbox-"
+
+	cgroupRemoveRetries  = 10
+	cgroupRemoveInterval = 50 * time.Millisecond
+
+	// cgroupMoveRetries is the number of attempts to move all processes between cgroups, as
+	// processes may be created while they are being moved.
+	cgroupMoveRetries = 10
+)
+
+// cgroupControllers are the controllers enabled for sandbox cgroups.
+var cgroupControllers = []string{"cpu", "memory", "pids"}
+
+// cgroupDelegateXattrs are the extended attributes used by systemd to mark delegated cgroups.
+var cgroupDelegateXattrs = []string{"trusted.delegate", "user.delegate"}
+
+// errCgroupNotDelegated is the error returned when the node's cgroup has not been delegated to it.
+var errCgroupNotDelegated = errors.New("cgroup not delegated")
+
+var (
+	cgroupParentLock sync.Mutex
+	cgroupParent     string
+)
+
+// cgroup is a cgroup v2 in which a sandboxed process is placed.
+type cgroup struct {
+	path string
+	dir  *os.File
+}
+
+// newCgroup creates a new cgroup with the given resource limits. In case no limits are given, the
+// cgroup is only used for resource usage accounting.
+func newCgroup(limits *ResourceLimits) (*cgroup, error) {
+	parent, err := getCgroupParent()
+	if err != nil {
+		return nil, err
+	}
+
+	var rawName [8]byte
+	if _, err := rand.Read(rawName[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate cgroup name: %w", err)
+	}
+	cg := &cgroup{
+		path: filepath.Join(parent, cgroupSandboxPrefix+hex.EncodeToString(rawName[:])),
+	}
+	if err := os.Mkdir(cg.path, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cgroup: %w", err)
+	}
+	if err := cg.setLimits(limits); err != nil {
+		cg.remove()
+		return nil, err
+	}
+
+	dir, err := os.Open(cg.path)
+	if err != nil {
+		cg.remove()
+		return nil, fmt.Errorf("failed to open cgroup: %w", err)
+	}
+	cg.dir = dir
+
+	return cg, nil
+}
+
+func (cg *cgroup) setLimits(limits *ResourceLimits) error {
+	if limits == nil {
+		return nil
+	}
+	if limits.Memory > 0 {
+		if err := cg.write("memory.max", strconv.FormatUint(limits.Memory, 10)); err != nil {
+			return err
+		}
+		// Make sure the memory limit cannot be circumvented by swapping. The control file is not
+		// available when swap accounting is disabled.
+		if err := cg.write("memory.swap.max", "0"); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if limits.CPUWeight > 0 {
+		if err := cg.write("cpu.weight", strconv.FormatUint(limits.CPUWeight, 10)); err != nil {
+			return err
+		}
+	}
+	if limits.Pids > 0 {
+		if err := cg.write("pids.max", strconv.FormatUint(limits.Pids, 10)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configure configures the given command to be started in the cgroup.
+func (cg *cgroup) configure(cmd *exec.Cmd) {
+	attrs := *cmd.SysProcAttr
+	attrs.UseCgroupFD = true
+	attrs.CgroupFD = int(cg.dir.Fd())
+	cmd.SysProcAttr = &attrs
+}
+
+// usage returns the current resource usage of all processes in the cgroup.
+func (cg *cgroup) usage() (*ResourceUsage, error) {
+	memory, err := cg.readUint("memory.current")
+	if err != nil {
+		return nil, err
+	}
+	pids, err := cg.readUint("pids.current")
+	if err != nil {
+		return nil, err
+	}
+	cpuStat, err := os.ReadFile(filepath.Join(cg.path, "cpu.stat"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cgroup cpu.stat: %w", err)
+	}
+	cpuUsage, err := parseCPUUsage(cpuStat)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceUsage{
+		Memory:  memory,
+		CPUTime: cpuUsage,
+		Pids:    pids,
+	}, nil
+}
+
+// remove kills any remaining processes in the cgroup and removes it.
+func (cg *cgroup) remove() {
+	if cg.dir != nil {
+		_ = cg.dir.Close()
+	}
+	// Not available before Linux 5.14, in which case the processes should already be gone.
+	_ = cg.write("cgroup.kill", "1")
+
+	for range cgroupRemoveRetries {
+		err := os.Remove(cg.path)
+		if !errors.Is(err, syscall.EBUSY) {
+			return
+		}
+		time.Sleep(cgroupRemoveInterval)
+	}
+}
+
+func (cg *cgroup) write(name, value string) error {
+	return writeCgroupFile(cg.path, name, value)
+}
+
+func (cg *cgroup) readUint(name string) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(cg.path, name))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cgroup %s: %w", name, err)
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed cgroup %s: %w", name, err)
+	}
+	return value, nil
+}
+
+// getCgroupParent returns the cgroup in which sandbox cgroups are created, preparing it on first
+// use. Failures are not cached so that the setup is retried once the cgroup has been delegated.
+func getCgroupParent() (string, error) {
+	cgroupParentLock.Lock()
+	defer cgroupParentLock.Unlock()
+
+	if cgroupParent != "" {
+		return cgroupParent, nil
+	}
+	parent, err := setupCgroupParent()
+	if err != nil {
+		return "", err
+	}
+	cgroupParent = parent
+	return parent, nil
+}
+
+// setupCgroupParent prepares the current process' cgroup for creating sandbox cgroups by enabling
+// the required controllers for its children.
+//
+// The node's cgroup must be delegated to it (e.g. using the Delegate option of systemd), which is
+// confirmed before making any changes. As controllers cannot be enabled for cgroups containing
+// processes, the node processes are first moved into a leaf cgroup and moved back on failure.
+func setupCgroupParent() (string, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", fmt.Errorf("failed to read process cgroup: %w", err)
+	}
+	path, err := parseCgroupPath(data)
+	if err != nil {
+		return "", err
+	}
+	parent := filepath.Join(cgroupMountPoint, path)
+
+	if err = checkCgroupDelegated(parent); err != nil {
+		return "", err
+	}
+
+	err = enableCgroupControllers(parent)
+	if errors.Is(err, syscall.EBUSY) {
+		err = enableCgroupControllersWithLeaf(parent)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to enable cgroup controllers in '%s': %w", parent, err)
+	}
+	return parent, nil
+}
+
+// checkCgroupDelegated makes sure that the given cgroup has been delegated to the current process,
+// meaning that the current user owns its control files and, in case of root which owns all cgroups,
+// that the cgroup or one of its ancestors is explicitly marked as delegated.
+func checkCgroupDelegated(path string) error {
+	euid := os.Geteuid()
+	for _, name := range []string{"cgroup.subtree_control", "cgroup.procs"} {
+		fn := filepath.Join(path, name)
+
+		var st syscall.Stat_t
+		if err := syscall.Stat(fn, &st); err != nil {
+			return fmt.Errorf("failed to stat cgroup %s: %w", name, err)
+		}
+		if int(st.Uid) != euid || syscall.Access(fn, 0x2 /* W_OK */) != nil {
+			return fmt.Errorf("%w: cgroup '%s' is not owned by the node", errCgroupNotDelegated, path)
+		}
+	}
+	if euid != 0 {
+		return nil
+	}
+
+	for p := path; strings.HasPrefix(p, cgroupMountPoint+"/"); p = filepath.Dir(p) {
+		if hasCgroupDelegateXattr(p) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: cgroup '%s' is not marked as delegated", errCgroupNotDelegated, path)
+}
+
+func hasCgroupDelegateXattr(path string) bool {
+	var buf [8]byte
+	for _, attr := range cgroupDelegateXattrs {
+		n, err := syscall.Getxattr(path, attr, buf[:])
+		if err == nil && string(buf[:n]) == "1" {
+			return true
+		}
+	}
+	return false
+}
+
+// enableCgroupControllersWithLeaf moves all processes of the given cgroup into a leaf cgroup and
+// enables the controllers. In case of failure, the processes are moved back.
+func enableCgroupControllersWithLeaf(path string) error {
+	leaf := filepath.Join(path, cgroupLeafName)
+	if err := os.Mkdir(leaf, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("failed to create leaf cgroup: %w", err)
+	}
+
+	err := moveCgroupProcesses(path, leaf)
+	if err == nil {
+		if err = enableCgroupControllers(path); err == nil {
+			return nil
+		}
+	}
+
+	if mvErr := moveCgroupProcesses(leaf, path); mvErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to restore node cgroup: %w", mvErr))
+	}
+	_ = os.Remove(leaf)
+	return err
+}
+
+// parseCgroupPath returns the cgroup v2 path from the contents of /proc/<pid>/cgroup.
+func parseCgroupPath(data []byte) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if path, ok := strings.CutPrefix(scanner.Text(), "0::"); ok {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("cgroup v2 hierarchy not available")
+}
+
+// parseCPUUsage returns the total CPU time from the contents of a cgroup cpu.stat file.
+func parseCPUUsage(data []byte) (time.Duration, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "usage_usec ")
+		if !ok {
+			continue
+		}
+		usec, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("malformed cgroup cpu usage: %w", err)
+		}
+		return time.Duration(usec) * time.Microsecond, nil
+	}
+	return 0, fmt.Errorf("cgroup cpu usage not available")
+}
+
+func enableCgroupControllers(path string) error {
+	data, err := os.ReadFile(filepath.Join(path, "cgroup.controllers"))
+	if err != nil {
+		return err
+	}
+	available := strings.Fields(string(data))
+
+	ctrls := make([]string, 0, len(cgroupControllers))
+	for _, ctrl := range cgroupControllers {
+		if !slices.Contains(available, ctrl) {
+			return fmt.Errorf("controller '%s' not delegated", ctrl)
+		}
+		ctrls = append(ctrls, "+"+ctrl)
+	}
+	return writeCgroupFile(path, "cgroup.subtree_control", strings.Join(ctrls, " "))
+}
+
+// moveCgroupProcesses moves all processes from one cgroup to another.
+func moveCgroupProcesses(from, to string) error {
+	for range cgroupMoveRetries {
+		data, err := os.ReadFile(filepath.Join(from, "cgroup.procs"))
+		if err != nil {
+			return fmt.Errorf("failed to read cgroup processes: %w", err)
+		}
+		pids := strings.Fields(string(data))
+		if len(pids) == 0 {
+			return nil
+		}
+		for _, pid := range pids {
+			err = writeCgroupFile(to, "cgroup.procs", pid)
+			switch {
+			case err == nil, errors.Is(err, syscall.ESRCH):
+				// Processes may terminate in the meantime.
+			default:
+				return fmt.Errorf("failed to move process %s: %w", pid, err)
+			}
+		}
+	}
+	return fmt.Errorf("failed to move all processes from '%s'", from)
+}
+
+func writeCgroupFile(path, name, value string) error {
+	f, err := os.OpenFile(filepath.Join(path, name), os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(value)
+	return err
+}
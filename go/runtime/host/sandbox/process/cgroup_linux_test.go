@@ -0,0 +1,52 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCgroupParsing(t *testing.T) {
+	require := require.New(t)
+
+	path, err := parseCgroupPath([]byte("0::/system.slice/oasis-node.service\n"))
+	require.NoError(err, "parseCgroupPath")
+	require.Equal("/system.slice/oasis-node.service", path)
+
+	_, err = parseCgroupPath([]byte("12:pids:/user.slice\n1:name=systemd:/user.slice\n"))
+	require.Error(err, "parseCgroupPath should fail without a cgroup v2 hierarchy")
+
+	cpuUsage, err := parseCPUUsage([]byte("usage_usec 1500000\nuser_usec 1000000\nsystem_usec 500000\n"))
+	require.NoError(err, "parseCPUUsage")
+	require.Equal(1500*time.Millisecond, cpuUsage)
+
+	_, err = parseCPUUsage([]byte("user_usec 1000000\n"))
+	require.Error(err, "parseCPUUsage should fail without total usage")
+}
+
+func TestCgroupDelegation(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	err := checkCgroupDelegated(dir)
+	require.Error(err, "checkCgroupDelegated should fail without cgroup control files")
+
+	for _, name := range []string{"cgroup.subtree_control", "cgroup.procs"} {
+		err = os.WriteFile(filepath.Join(dir, name), nil, 0o644)
+		require.NoError(err, "WriteFile")
+	}
+	err = checkCgroupDelegated(dir)
+	switch os.Geteuid() {
+	case 0:
+		// Root owns all cgroups, so delegation must be explicitly marked.
+		require.ErrorIs(err, errCgroupNotDelegated)
+	default:
+		require.NoError(err, "checkCgroupDelegated")
+	}
+}
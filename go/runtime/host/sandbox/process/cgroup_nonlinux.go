@@ -0,0 +1,25 @@
+//go:build !linux
+// +build !linux
+
+package process
+
+import (
+	"errors"
+	"os/exec"
+)
+
+type cgroup struct{}
+
+func newCgroup(*ResourceLimits) (*cgroup, error) {
+	return nil, errors.New("resource limits only implemented for Linux")
+}
+
+func (cg *cgroup) configure(*exec.Cmd) {
+}
+
+func (cg *cgroup) usage() (*ResourceUsage, error) {
+	return nil, ErrResourceUsageUnavailable
+}
+
+func (cg *cgroup) remove() {
+}
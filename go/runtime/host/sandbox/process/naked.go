@@ -15,7 +15,8 @@ import (
 type naked struct {
 	sync.Mutex
 
-	cmd *exec.Cmd
+	cmd    *exec.Cmd
+	cgroup *cgroup
 
 	err    error
 	waitCh chan struct{}
@@ -39,6 +40,14 @@ func (n *naked) Error() (err error) {
 	return
 }
 
+// Implements Process.
+func (n *naked) ResourceUsage() (*ResourceUsage, error) {
+	if n.cgroup == nil {
+		return nil, ErrResourceUsageUnavailable
+	}
+	return n.cgroup.usage()
+}
+
 func (n *naked) wait() error {
 	err := n.cmd.Wait()
 	if err != nil {
@@ -100,16 +109,36 @@ func NewNaked(cfg Config) (Process, error) {
 		}
 	}
 
+	// Place the process into a cgroup for enforcing resource limits. Without limits, the process is
+	// only placed into a cgroup for resource usage accounting when the node's cgroup has been
+	// delegated to it and otherwise runs outside a cgroup.
+	cg, err := newCgroup(cfg.Resources)
+	switch {
+	case err == nil:
+		cg.configure(cmd)
+	case cfg.Resources != nil:
+		return nil, fmt.Errorf("failed to set up resource limits: %w", err)
+	default:
+		cg = nil
+	}
+
 	if err := cmd.Start(); err != nil {
+		if cg != nil {
+			cg.remove()
+		}
 		return nil, err
 	}
 
 	n := &naked{
 		cmd:    cmd,
+		cgroup: cg,
 		waitCh: make(chan struct{}),
 	}
 	go func() {
 		err := n.wait()
+		if cg != nil {
+			cg.remove()
+		}
 
 		n.Lock()
 		n.err = err
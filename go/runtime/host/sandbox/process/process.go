@@ -2,10 +2,16 @@
 package process
 
 import (
+	"errors"
 	"io"
 	"os"
+	"time"
 )
 
+// ErrResourceUsageUnavailable is the error returned when resource usage of a process is not
+// available.
+var ErrResourceUsageUnavailable = errors.New("process: resource usage not available")
+
 // Config contains the sandbox configuration.
 //
 // This is similar to the os/exec.Cmd structure.
@@ -45,9 +51,38 @@ type Config struct {
 	// AllowNetwork specifies whether network access should be allowed.
 	AllowNetwork bool
 
+	// Resources are the optional resource limits of the process. The limits are enforced using
+	// cgroup v2 and are only supported on Linux. Without limits, the process is only placed into a
+	// cgroup for resource usage accounting when the node's cgroup has been delegated to it.
+	Resources *ResourceLimits
+
 	extraFiles []*os.File
 }
 
+// ResourceLimits are the resource limits of a sandboxed process.
+type ResourceLimits struct {
+	// Memory is the maximum amount of memory in bytes. Zero means no limit.
+	Memory uint64
+
+	// CPUWeight is the relative CPU weight in the range 1-10000. Zero means the default weight.
+	CPUWeight uint64
+
+	// Pids is the maximum number of processes and threads. Zero means no limit.
+	Pids uint64
+}
+
+// ResourceUsage is the resource usage of a sandboxed process.
+type ResourceUsage struct {
+	// Memory is the current amount of memory used in bytes.
+	Memory uint64
+
+	// CPUTime is the total CPU time consumed.
+	CPUTime time.Duration
+
+	// Pids is the current number of processes and threads.
+	Pids uint64
+}
+
 // Process is a sandboxed process.
 type Process interface {
 	// GetPID returns the process identifier of the sandbox running the given process.
@@ -63,4 +98,11 @@ type Process interface {
 
 	// Kill causes the sandboxed process to exit immediately.
 	Kill()
+
+	// ResourceUsage returns the current resource usage of the sandboxed process.
+	//
+	// Resource usage is available for processes started with resource limits and, when the node's
+	// cgroup has been delegated to it, for all other processes. Otherwise ErrResourceUsageUnavailable
+	// is returned.
+	ResourceUsage() (*ResourceUsage, error)
 }
@@ -56,6 +56,8 @@ type sandboxProvisioner struct {
 
 // NewProvisioner creates a new runtime provisioner that uses a local process sandbox.
 func NewProvisioner(cfg Config) (host.Provisioner, error) {
+	initMetrics()
+
 	// Use a default Logger if none was provided.
 	if cfg.Logger == nil {
 		cfg.Logger = logging.GetLogger("runtime/host/sandbox")
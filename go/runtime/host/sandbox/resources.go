@@ -0,0 +1,78 @@
+package sandbox
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/bytesize"
+	"github.com/oasisprotocol/oasis-core/go/config"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox/process"
+)
+
+// getResourceLimits returns the resource limits configured for the given runtime component.
+func getResourceLimits(cfg host.Config) (*process.ResourceLimits, error) {
+	compCfg, ok := config.GlobalConfig.Runtime.GetComponent(cfg.ID, cfg.Component.ID())
+	if !ok || compCfg.Resources.IsEmpty() {
+		return nil, nil
+	}
+	resources := compCfg.Resources
+
+	var memory uint64
+	if resources.Memory != "" {
+		if memory = uint64(bytesize.Parse(resources.Memory)); memory == 0 {
+			return nil, fmt.Errorf("malformed memory limit: %s", resources.Memory)
+		}
+	}
+
+	return &process.ResourceLimits{
+		Memory:    memory,
+		CPUWeight: resources.CPUShares,
+		Pids:      resources.Pids,
+	}, nil
+}
+
+// Implements host.ResourceReporter.
+func (h *sandboxHost) GetResourceUsage() (*host.ResourceUsage, error) {
+	h.RLock()
+	p := h.process
+	h.RUnlock()
+
+	if p == nil {
+		return nil, errRuntimeNotReady
+	}
+	usage, err := p.ResourceUsage()
+	if err != nil {
+		return nil, err
+	}
+	return &host.ResourceUsage{
+		Memory:  usage.Memory,
+		CPUTime: usage.CPUTime,
+		Pids:    usage.Pids,
+	}, nil
+}
+
+func (h *sandboxHost) metricsLabels() []string {
+	comp := h.rtCfg.Component
+	return []string{h.id.String(), comp.ID().String(), comp.Version.String()}
+}
+
+// updateResourceMetrics updates the resource usage metrics of the runtime component.
+func (h *sandboxHost) updateResourceMetrics() {
+	usage, err := h.GetResourceUsage()
+	if err != nil {
+		return
+	}
+
+	labels := h.metricsLabels()
+	componentMemory.WithLabelValues(labels...).Set(float64(usage.Memory))
+	componentCPUTime.WithLabelValues(labels...).Set(usage.CPUTime.Seconds())
+	componentPids.WithLabelValues(labels...).Set(float64(usage.Pids))
+}
+
+// clearResourceMetrics removes the resource usage metrics of the runtime component.
+func (h *sandboxHost) clearResourceMetrics() {
+	labels := h.metricsLabels()
+	componentMemory.DeleteLabelValues(labels...)
+	componentCPUTime.DeleteLabelValues(labels...)
+	componentPids.DeleteLabelValues(labels...)
+}
@@ -73,6 +73,9 @@ type QemuConfig struct {
 type QemuExtraConfig struct {
 	// CID is the VSOCK cid to use for this runtime. If zero, the CID is automatically assigned.
 	CID uint32
+
+	// volume is the persistent volume attached to this runtime, if any.
+	volume *volume.Volume
 }
 
 type qemuProvisioner struct {
@@ -153,10 +156,14 @@ func (p *qemuProvisioner) Name() string {
 }
 
 func (p *qemuProvisioner) cleanup(cfg host.Config) {
-	cid := cfg.Extra.(*QemuExtraConfig).CID // Ensured above.
-	if !p.cidPool.Release(cid) {
+	extraCfg := cfg.Extra.(*QemuExtraConfig) // Ensured above.
+	if !p.cidPool.Release(extraCfg.CID) {
 		p.logger.Error("previously allocated CID was already released")
 	}
+	if extraCfg.volume != nil {
+		extraCfg.volume.Detach()
+		extraCfg.volume = nil
+	}
 }
 
 func (p *qemuProvisioner) getSandboxConfig(cfg host.Config, _ sandbox.Connector, _ string) (process.Config, error) {
@@ -164,7 +171,8 @@ func (p *qemuProvisioner) getSandboxConfig(cfg host.Config, _ sandbox.Connector,
 		return process.Config{}, fmt.Errorf("component '%s' is not a TDX component", cfg.Component.ID())
 	}
 
-	cid := cfg.Extra.(*QemuExtraConfig).CID // Ensured above.
+	extraCfg := cfg.Extra.(*QemuExtraConfig) // Ensured above.
+	cid := extraCfg.CID
 	tdxCfg := cfg.Component.TDX
 	resources := tdxCfg.Resources
 	firmware := cfg.Component.ExplodedPath(tdxCfg.Firmware)
@@ -224,6 +232,14 @@ func (p *qemuProvisioner) getSandboxConfig(cfg host.Config, _ sandbox.Connector,
 				if !ok {
 					return process.Config{}, fmt.Errorf("volume for '%s' not attached", tdxCfg.Stage2Image)
 				}
+				// Keep the volume attached for as long as the runtime exists, so that it is not
+				// modified by snapshot or restore operations while in use.
+				if extraCfg.volume == nil {
+					if err := volume.Attach(); err != nil {
+						return process.Config{}, fmt.Errorf("failed to attach volume '%s': %w", volume.ID, err)
+					}
+					extraCfg.volume = volume
+				}
 
 				var err error
 				stage2Image, err = p.createPersistentOverlayImage(stage2Image, stage2Format, volume)
@@ -336,31 +352,40 @@ func (p *qemuProvisioner) createNetworkingConfig(cfg host.Config) ([]string, err
 // createPersistentOverlayImage creates a persistent overlay image for the given backing image and
 // returns the full path to the overlay image. In case the image already exists, it is reused.
 //
+// In case the volume has a quota configured, the virtual size of the backing image must not exceed
+// it. The overlay image may still grow slightly beyond the quota due to qcow2 metadata overhead.
+//
 // The format of the resulting image is always qcow2.
 func (p *qemuProvisioner) createPersistentOverlayImage(image string, format string, volume *volume.Volume) (string, error) {
+	// Query the backing image to determine its virtual size.
+	cmd := exec.Command(
+		defaultQemuImgPath,
+		"info",
+		"--output", "json",
+		image,
+	)
+	var out strings.Builder
+	cmd.Stderr = &out
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to query base image: %s\n%w", out.String(), err)
+	}
+	var info struct {
+		VirtualSize uint64 `json:"virtual-size"`
+	}
+	if err := json.Unmarshal([]byte(out.String()), &info); err != nil {
+		return "", fmt.Errorf("malformed base image metadata: %w", err)
+	}
+
+	// Enforce the volume quota.
+	if volume.Quota > 0 && info.VirtualSize > volume.Quota {
+		return "", fmt.Errorf("base image size (%d) exceeds volume quota (%d)", info.VirtualSize, volume.Quota)
+	}
+
 	switch _, err := os.Stat(volume.Path); {
 	case err == nil:
 		// Image already exists, perform a rebase operation to account for the backing file location
 		// changing (e.g. due to an upgrade).
-		cmd := exec.Command(
-			defaultQemuImgPath,
-			"info",
-			"--output", "json",
-			image,
-		)
-		var out strings.Builder
-		cmd.Stderr = &out
-		cmd.Stdout = &out
-		if err := cmd.Run(); err != nil {
-			return "", fmt.Errorf("failed to query base image: %s\n%w", out.String(), err)
-		}
-		var info struct {
-			VirtualSize int `json:"virtual-size"`
-		}
-		if err := json.Unmarshal([]byte(out.String()), &info); err != nil {
-			return "", fmt.Errorf("malformed base image metadata: %w", err)
-		}
-
 		cmd = exec.Command( //nolint: gosec
 			defaultQemuImgPath,
 			"rebase",
@@ -392,7 +417,7 @@ func (p *qemuProvisioner) createPersistentOverlayImage(image string, format stri
 		}
 	case errors.Is(err, os.ErrNotExist):
 		// Create the persistent overlay image.
-		cmd := exec.Command( //nolint: gosec
+		cmd = exec.Command( //nolint: gosec
 			defaultQemuImgPath,
 			"create",
 			"-f", "qcow2",
@@ -400,7 +425,7 @@ func (p *qemuProvisioner) createPersistentOverlayImage(image string, format stri
 			"-F", format,
 			volume.Path,
 		)
-		var out strings.Builder
+		out.Reset()
 		cmd.Stderr = &out
 		cmd.Stdout = &out
 		if err := cmd.Run(); err != nil {
@@ -94,6 +94,18 @@ func createHistoryFactory() (history.Factory, error) {
 		numKept := config.GlobalConfig.Runtime.Prune.NumKept
 		pruneInterval := max(config.GlobalConfig.Runtime.Prune.Interval, time.Second)
 		pruneFactory = history.NewKeepLastPrunerFactory(numKept, pruneInterval)
+	case history.PrunerStrategyKeepRecent:
+		maxAge := config.GlobalConfig.Runtime.Prune.MaxAge
+		pruneInterval := max(config.GlobalConfig.Runtime.Prune.Interval, time.Second)
+		pruneFactory = history.NewKeepRecentPrunerFactory(maxAge, pruneInterval)
+	case history.PrunerStrategyDiskBudget:
+		budget := uint64(config.ParseSizeInBytes(config.GlobalConfig.Runtime.Prune.DiskBudget))
+		if budget == 0 {
+			return nil, fmt.Errorf("runtime/registry: invalid history pruner disk budget: %s", config.GlobalConfig.Runtime.Prune.DiskBudget)
+		}
+		numKept := config.GlobalConfig.Runtime.Prune.NumKept
+		pruneInterval := max(config.GlobalConfig.Runtime.Prune.Interval, time.Second)
+		pruneFactory = history.NewDiskBudgetPrunerFactory(budget, numKept, pruneInterval)
 	default:
 		return nil, fmt.Errorf("runtime/registry: unknown history pruner strategy: %s", strategy)
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/config"
@@ -12,6 +13,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/composite"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/multi"
+	"github.com/oasisprotocol/oasis-core/go/runtime/volume"
 )
 
 // RuntimeHostNode provides methods for nodes that need to host runtimes.
@@ -23,12 +25,24 @@ type RuntimeHostNode struct {
 	runtime     Runtime
 	provisioner host.Provisioner
 	handler     host.RuntimeHandler
+	volumes     *volume.Manager
 
-	rofls map[component.ID]version.Version
+	// rofls are the latest provisioned versions of ROFL components.
+	rofls map[component.ID]*bundle.ExplodedComponent
+	// rollouts are the in-progress health-gated ROFL component upgrades.
+	rollouts map[component.ID]*componentRollout
+	// failed are the ROFL component versions which failed to upgrade and should not be retried.
+	failed map[component.ID]version.Version
+
+	// active and next are the last known active and next versions of the RONL component.
+	active *version.Version
+	next   *version.Version
+
+	logger *logging.Logger
 }
 
 // NewRuntimeHostNode creates a new runtime host node.
-func NewRuntimeHostNode(runtime Runtime, provisioner host.Provisioner, handler host.RuntimeHandler) (*RuntimeHostNode, error) {
+func NewRuntimeHostNode(runtime Runtime, provisioner host.Provisioner, handler host.RuntimeHandler, volumes *volume.Manager) (*RuntimeHostNode, error) {
 	h := composite.NewHost(runtime.ID())
 
 	return &RuntimeHostNode{
@@ -36,7 +50,11 @@ func NewRuntimeHostNode(runtime Runtime, provisioner host.Provisioner, handler h
 		runtime:     runtime,
 		handler:     handler,
 		provisioner: provisioner,
-		rofls:       make(map[component.ID]version.Version),
+		volumes:     volumes,
+		rofls:       make(map[component.ID]*bundle.ExplodedComponent),
+		rollouts:    make(map[component.ID]*componentRollout),
+		failed:      make(map[component.ID]version.Version),
+		logger:      logging.GetLogger("runtime/registry/host").With("runtime_id", runtime.ID()),
 	}, nil
 }
 
@@ -51,7 +69,28 @@ func (n *RuntimeHostNode) ProvisionHostedRuntimeComponent(comp *bundle.ExplodedC
 	if n.host.HasVersion(comp.ID(), comp.Version) {
 		return nil
 	}
+	if v, ok := n.failed[comp.ID()]; ok && v == comp.Version {
+		// Do not retry versions that have already been rolled back.
+		return nil
+	}
+
+	if err := n.provisionLocked(comp); err != nil {
+		return err
+	}
+
+	if comp.Kind == component.ROFL {
+		if latest, ok := n.rofls[comp.ID()]; !ok || comp.Version.Cmp(latest.Version) >= 0 {
+			n.rofls[comp.ID()] = comp
+			if ok {
+				n.startRolloutLocked(latest, comp)
+			}
+		}
+	}
+
+	return nil
+}
 
+func (n *RuntimeHostNode) provisionLocked(comp *bundle.ExplodedComponent) error {
 	handler, err := n.createMessageHandler(comp)
 	if err != nil {
 		return err
@@ -77,10 +116,6 @@ func (n *RuntimeHostNode) ProvisionHostedRuntimeComponent(comp *bundle.ExplodedC
 		return fmt.Errorf("failed to add runtime component %s version %s to composite: %w", comp.ID(), comp.Version, err)
 	}
 
-	if comp.Kind == component.ROFL && comp.Version.Cmp(n.rofls[comp.ID()]) >= 0 {
-		n.rofls[comp.ID()] = comp.Version
-	}
-
 	return nil
 }
 
@@ -97,6 +132,10 @@ func (n *RuntimeHostNode) RemoveHostedRuntimeComponent(id component.ID) error {
 
 	if id.Kind == component.ROFL {
 		delete(n.rofls, id)
+		delete(n.failed, id)
+		if ro := n.stopRolloutLocked(id); ro != nil {
+			n.removeSnapshotsLocked(ro)
+		}
 	}
 
 	return nil
@@ -141,17 +180,11 @@ func (n *RuntimeHostNode) SetHostedRuntimeVersion(active *version.Version, next
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
-	pruneVersions := func(comp *multi.Aggregate, lowest version.Version) {
-		for _, v := range comp.Versions() {
-			if !v.Less(lowest) {
-				return
-			}
-			_ = comp.RemoveVersion(v)
-		}
-	}
+	n.active = active
+	n.next = next
 
 	for id, comp := range n.host.Components() {
-		switch latest, ok := n.rofls[id]; ok {
+		switch _, ok := n.rofls[id]; ok {
 		case false:
 			// RONL components should honor versioning.
 			comp.SetVersion(active, next)
@@ -160,19 +193,40 @@ func (n *RuntimeHostNode) SetHostedRuntimeVersion(active *version.Version, next
 				pruneVersions(comp, *active)
 			}
 		case true:
-			// ROFL components should start when the RONL component starts and
-			// should upgrade immediately when a new version becomes available.
-			switch {
-			case active == nil && next == nil:
-				comp.SetVersion(nil, nil)
-			case active == nil && next != nil:
-				comp.SetVersion(nil, &latest)
-			default:
-				comp.SetVersion(&latest, nil)
-			}
+			n.setROFLVersionLocked(id, comp)
+		}
+	}
+}
+
+// setROFLVersionLocked updates the running versions of the given ROFL component.
+func (n *RuntimeHostNode) setROFLVersionLocked(id component.ID, comp *multi.Aggregate) {
+	// ROFL components should start when the RONL component starts and should upgrade immediately
+	// when a new version becomes available, unless a guarded upgrade is in progress.
+	latest := n.rofls[id].Version
+	var candidate *version.Version
+	if ro, ok := n.rollouts[id]; ok {
+		latest, candidate = ro.versions()
+	}
+
+	switch {
+	case n.active == nil && n.next == nil:
+		comp.SetVersion(nil, nil)
+	case n.active == nil && n.next != nil:
+		comp.SetVersion(nil, &latest)
+	default:
+		comp.SetVersion(&latest, candidate)
+	}
+
+	pruneVersions(comp, latest)
+}
 
-			pruneVersions(comp, latest)
+// pruneVersions removes all versions of the given component lower than the given version.
+func pruneVersions(comp *multi.Aggregate, lowest version.Version) {
+	for _, v := range comp.Versions() {
+		if !v.Less(lowest) {
+			return
 		}
+		_ = comp.RemoveVersion(v)
 	}
 }
 
@@ -82,6 +82,34 @@ func (rh *roflHostHandler) handleVolumeManagement(rq *enclaverpc.Request) (any,
 			return nil, err
 		}
 		return rh.handleVolumeList(&args)
+	case rofl.MethodVolumeSnapshot:
+		// Snapshot a volume.
+		var args rofl.VolumeSnapshotRequest
+		if err := cbor.Unmarshal(rq.Args, &args); err != nil {
+			return nil, err
+		}
+		return rh.handleVolumeSnapshot(&args)
+	case rofl.MethodVolumeRestore:
+		// Restore a volume from a snapshot.
+		var args rofl.VolumeRestoreRequest
+		if err := cbor.Unmarshal(rq.Args, &args); err != nil {
+			return nil, err
+		}
+		return rh.handleVolumeRestore(&args)
+	case rofl.MethodVolumeSnapshotRemove:
+		// Remove a volume snapshot.
+		var args rofl.VolumeSnapshotRemoveRequest
+		if err := cbor.Unmarshal(rq.Args, &args); err != nil {
+			return nil, err
+		}
+		return rh.handleVolumeSnapshotRemove(&args)
+	case rofl.MethodVolumeClone:
+		// Clone a volume.
+		var args rofl.VolumeCloneRequest
+		if err := cbor.Unmarshal(rq.Args, &args); err != nil {
+			return nil, err
+		}
+		return rh.handleVolumeClone(&args)
 	default:
 		return nil, fmt.Errorf("method not supported")
 	}
@@ -113,11 +141,9 @@ func (rh *roflHostHandler) handleBundleAdd(rq *rofl.BundleAddRequest) (*rofl.Bun
 	// Resolve volumes.
 	volumes := make(map[string]*volume.Volume)
 	for volName, volID := range rq.Volumes {
-		volume, ok := rh.getVolumeManager().Get(volID)
-
-		// Ensure volume exists and has the right labels.
-		if !ok || !volume.HasLabels(rh.getBundleManagementLabels()) {
-			return nil, fmt.Errorf("volume '%s' not found", volID)
+		volume, err := rh.getManagedVolume(volID)
+		if err != nil {
+			return nil, err
 		}
 
 		volumes[volName] = volume
@@ -197,7 +223,7 @@ func (rh *roflHostHandler) handleVolumeAdd(rq *rofl.VolumeAddRequest) (*rofl.Vol
 	labels := maps.Clone(rq.Labels)
 	maps.Copy(labels, rh.getBundleManagementLabels())
 
-	volume, err := rh.getVolumeManager().Create(labels)
+	volume, err := rh.getVolumeManager().Create(labels, volume.WithQuota(rq.Quota))
 	if err != nil {
 		return nil, err
 	}
@@ -237,6 +263,16 @@ func (rh *roflHostHandler) handleVolumeList(rq *rofl.VolumeListRequest) (*rofl.V
 		var vi rofl.VolumeInfo
 		vi.ID = volume.ID
 		vi.Labels = volume.Labels
+		vi.Quota = volume.Quota
+		vi.Usage, _ = volume.Usage()
+
+		snapshots, _ := rh.getVolumeManager().Snapshots(volume.ID)
+		for _, snapshot := range snapshots {
+			vi.Snapshots = append(vi.Snapshots, &rofl.VolumeSnapshotInfo{
+				ID:      snapshot.ID,
+				Created: uint64(snapshot.Created.Unix()),
+			})
+		}
 
 		volumes = append(volumes, &vi)
 	}
@@ -246,6 +282,84 @@ func (rh *roflHostHandler) handleVolumeList(rq *rofl.VolumeListRequest) (*rofl.V
 	}, nil
 }
 
+func (rh *roflHostHandler) handleVolumeSnapshot(rq *rofl.VolumeSnapshotRequest) (*rofl.VolumeSnapshotResponse, error) {
+	if err := rh.ensureComponentPermissions(runtimeConfig.PermissionVolumeAdd); err != nil {
+		return nil, err
+	}
+	if _, err := rh.getManagedVolume(rq.ID); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := rh.getVolumeManager().Snapshot(rq.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rofl.VolumeSnapshotResponse{
+		SnapshotID: snapshot.ID,
+	}, nil
+}
+
+func (rh *roflHostHandler) handleVolumeRestore(rq *rofl.VolumeRestoreRequest) (*rofl.VolumeRestoreResponse, error) {
+	if err := rh.ensureComponentPermissions(runtimeConfig.PermissionVolumeAdd); err != nil {
+		return nil, err
+	}
+	if _, err := rh.getManagedVolume(rq.ID); err != nil {
+		return nil, err
+	}
+
+	if err := rh.getVolumeManager().Restore(rq.ID, rq.SnapshotID); err != nil {
+		return nil, err
+	}
+	return &rofl.VolumeRestoreResponse{}, nil
+}
+
+func (rh *roflHostHandler) handleVolumeSnapshotRemove(rq *rofl.VolumeSnapshotRemoveRequest) (*rofl.VolumeSnapshotRemoveResponse, error) {
+	if err := rh.ensureComponentPermissions(runtimeConfig.PermissionVolumeRemove); err != nil {
+		return nil, err
+	}
+	if _, err := rh.getManagedVolume(rq.ID); err != nil {
+		return nil, err
+	}
+
+	if err := rh.getVolumeManager().RemoveSnapshot(rq.ID, rq.SnapshotID); err != nil {
+		return nil, err
+	}
+	return &rofl.VolumeSnapshotRemoveResponse{}, nil
+}
+
+func (rh *roflHostHandler) handleVolumeClone(rq *rofl.VolumeCloneRequest) (*rofl.VolumeCloneResponse, error) {
+	if err := rh.ensureComponentPermissions(runtimeConfig.PermissionVolumeAdd); err != nil {
+		return nil, err
+	}
+	if _, err := rh.getManagedVolume(rq.ID); err != nil {
+		return nil, err
+	}
+
+	// Determine labels, make sure to override origin as that is used for isolation.
+	labels := maps.Clone(rq.Labels)
+	maps.Copy(labels, rh.getBundleManagementLabels())
+
+	volume, err := rh.getVolumeManager().Clone(rq.ID, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rofl.VolumeCloneResponse{
+		ID: volume.ID,
+	}, nil
+}
+
+// getManagedVolume returns the volume with the given identifier, ensuring that it is managed by
+// the component.
+func (rh *roflHostHandler) getManagedVolume(id string) (*volume.Volume, error) {
+	volume, ok := rh.getVolumeManager().Get(id)
+	if !ok || !volume.HasLabels(rh.getBundleManagementLabels()) {
+		return nil, fmt.Errorf("volume '%s' not found", id)
+	}
+	return volume, nil
+}
+
 // ensureComponentPermissions ensures that the component has all of the specified permissions.
 func (rh *roflHostHandler) ensureComponentPermissions(perms ...runtimeConfig.ComponentPermission) error {
 	compCfg, ok := config.GlobalConfig.Runtime.GetComponent(rh.parent.runtime.ID(), rh.id)
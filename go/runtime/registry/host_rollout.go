@@ -0,0 +1,404 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/config"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+	runtimeConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/multi"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+)
+
+// rolloutCheckInterval is the interval between component health checks during an upgrade.
+var rolloutCheckInterval = 10 * time.Second
+
+const (
+	// rolloutCheckTimeout is the time limit for a single component health check.
+	rolloutCheckTimeout = 5 * time.Second
+	// rolloutMaxFailures is the number of consecutive failed health checks after which a new
+	// component version is considered unhealthy during the rollback window.
+	rolloutMaxFailures = 3
+)
+
+// componentRollout is an in-progress guarded ROFL component upgrade.
+type componentRollout struct {
+	// previous is the last known-good component version.
+	previous *bundle.ExplodedComponent
+	// candidate is the component version being rolled out.
+	candidate *bundle.ExplodedComponent
+	// promoted is true after the candidate has become healthy and replaced the previous version.
+	promoted bool
+	// exclusive is true when the previous version is stopped before the candidate is started, as
+	// both versions would otherwise use the same persistent volumes.
+	exclusive bool
+	// snapshots are the identifiers of the volume snapshots taken before the candidate was
+	// started, keyed by volume identifier.
+	snapshots map[string]string
+
+	cancel context.CancelFunc
+}
+
+// versions returns the active and next component versions that should be running.
+func (ro *componentRollout) versions() (version.Version, *version.Version) {
+	if ro.promoted || ro.exclusive {
+		return ro.candidate.Version, nil
+	}
+	return ro.previous.Version, &ro.candidate.Version
+}
+
+// startRolloutLocked starts a guarded upgrade of a ROFL component from the previous to the given
+// version in case one is needed. Any existing upgrade of the same component is stopped.
+//
+// Components with persistent volumes are upgraded exclusively, also in health-gated mode: the
+// previous version is stopped, the volumes are snapshotted and only then the new version is
+// started. This way the two versions never use the same volumes concurrently and the volumes can
+// be restored in case the new version fails to become healthy or fails within the rollback window.
+func (n *RuntimeHostNode) startRolloutLocked(previous *bundle.ExplodedComponent, comp *bundle.ExplodedComponent) {
+	id := comp.ID()
+	if previous.Version == comp.Version {
+		return
+	}
+
+	// When superseding an upgrade that has not yet switched over, the version that is still
+	// running remains the last known-good one and so do the volume snapshots taken for it.
+	var snapshots map[string]string
+	if ro := n.stopRolloutLocked(id); ro != nil {
+		switch ro.promoted {
+		case false:
+			previous = ro.previous
+			snapshots = ro.snapshots
+		case true:
+			n.removeSnapshotsLocked(ro)
+		}
+	}
+
+	compCfg, _ := config.GlobalConfig.Runtime.GetComponent(n.runtime.ID(), id)
+	exclusive := len(comp.Volumes) > 0
+	if compCfg.Upgrade.Mode != runtimeConfig.ComponentUpgradeModeHealthGated && !exclusive {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ro := &componentRollout{
+		previous:  previous,
+		candidate: comp,
+		exclusive: exclusive,
+		snapshots: snapshots,
+		cancel:    cancel,
+	}
+	n.rollouts[id] = ro
+
+	if exclusive {
+		if err := n.prepareVolumesLocked(ro); err != nil {
+			logger := n.logger.With(
+				"component_id", id,
+				"version", comp.Version,
+				"previous_version", previous.Version,
+			)
+			logger.Error("failed to snapshot component volumes, reverting",
+				"err", err,
+			)
+			delete(n.rollouts, id)
+			cancel()
+			n.rollbackLocked(ro, logger)
+			return
+		}
+	}
+
+	go n.runRollout(ctx, ro, compCfg.Upgrade)
+}
+
+// stopRolloutLocked stops and returns the in-progress upgrade of the given component, if any.
+func (n *RuntimeHostNode) stopRolloutLocked(id component.ID) *componentRollout {
+	ro, ok := n.rollouts[id]
+	if !ok {
+		return nil
+	}
+	ro.cancel()
+	delete(n.rollouts, id)
+	return ro
+}
+
+// prepareVolumesLocked stops all running versions of the component and snapshots the volumes of
+// the candidate version unless a restore point already exists. In the latter case, the volumes
+// are restored to it instead, discarding any changes made by a superseded candidate.
+func (n *RuntimeHostNode) prepareVolumesLocked(ro *componentRollout) error {
+	// Volumes cannot be snapshotted or restored while attached to a running component.
+	if comp, ok := n.host.Component(ro.candidate.ID()); ok {
+		comp.SetVersion(nil, nil)
+	}
+
+	if ro.snapshots != nil {
+		return n.restoreVolumesLocked(ro)
+	}
+
+	ro.snapshots = make(map[string]string)
+	for _, vol := range ro.candidate.Volumes {
+		if _, ok := ro.snapshots[vol.ID]; ok {
+			continue
+		}
+		snapshot, err := n.volumes.Snapshot(vol.ID)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot volume '%s': %w", vol.ID, err)
+		}
+		ro.snapshots[vol.ID] = snapshot.ID
+	}
+	return nil
+}
+
+// restoreVolumesLocked restores all volumes snapshotted for the given upgrade.
+func (n *RuntimeHostNode) restoreVolumesLocked(ro *componentRollout) error {
+	for volID, snapshotID := range ro.snapshots {
+		if err := n.volumes.Restore(volID, snapshotID); err != nil {
+			return fmt.Errorf("failed to restore volume '%s': %w", volID, err)
+		}
+	}
+	return nil
+}
+
+// removeSnapshotsLocked removes all volume snapshots taken for the given upgrade.
+func (n *RuntimeHostNode) removeSnapshotsLocked(ro *componentRollout) {
+	for volID, snapshotID := range ro.snapshots {
+		if err := n.volumes.RemoveSnapshot(volID, snapshotID); err != nil {
+			n.logger.Warn("failed to remove volume snapshot",
+				"err", err,
+				"volume_id", volID,
+				"snapshot_id", snapshotID,
+			)
+		}
+	}
+	ro.snapshots = nil
+}
+
+func (n *RuntimeHostNode) runRollout(ctx context.Context, ro *componentRollout, cfg runtimeConfig.ComponentUpgradeConfig) {
+	id := ro.candidate.ID()
+	logger := n.logger.With(
+		"component_id", id,
+		"version", ro.candidate.Version,
+		"previous_version", ro.previous.Version,
+	)
+
+	logger.Info("starting guarded component upgrade",
+		"mode", cfg.Mode,
+	)
+
+	// Wait for the new version to become healthy while the previous version keeps running.
+	if err := n.waitComponentReady(ctx, id, ro.candidate.Version, cfg); err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Error("new component version failed to become healthy, reverting",
+			"err", err,
+		)
+		n.rollback(ro, logger)
+		return
+	}
+
+	if !n.promote(ro) {
+		return
+	}
+	logger.Info("new component version is healthy, switched over")
+
+	// Revert to the previous version if the new version fails within the rollback window.
+	if cfg.Mode == runtimeConfig.ComponentUpgradeModeHealthGated {
+		if err := n.monitorComponent(ctx, id, ro.candidate.Version, cfg); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Error("new component version failed within the rollback window, reverting",
+				"err", err,
+			)
+			n.rollback(ro, logger)
+			return
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.rollouts[id] != ro {
+		return
+	}
+	delete(n.rollouts, id)
+	n.removeSnapshotsLocked(ro)
+
+	logger.Info("component upgrade completed")
+}
+
+// waitComponentReady waits for the given component version to become healthy.
+//
+// The readiness timeout starts once the component version has been started.
+func (n *RuntimeHostNode) waitComponentReady(ctx context.Context, id component.ID, v version.Version, cfg runtimeConfig.ComponentUpgradeConfig) error {
+	ticker := time.NewTicker(rolloutCheckInterval)
+	defer ticker.Stop()
+
+	var deadline time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		err := n.checkComponentHealth(ctx, id, v, cfg.ReadinessMethod)
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, multi.ErrNoSuchVersion):
+			// Component version has not been started yet.
+			continue
+		case deadline.IsZero():
+			deadline = time.Now().Add(cfg.GetReadinessTimeout())
+		case time.Now().After(deadline):
+			return err
+		}
+	}
+}
+
+// monitorComponent monitors the health of the given component version for the duration of the
+// rollback window and returns an error in case it becomes unhealthy.
+func (n *RuntimeHostNode) monitorComponent(ctx context.Context, id component.ID, v version.Version, cfg runtimeConfig.ComponentUpgradeConfig) error {
+	ticker := time.NewTicker(rolloutCheckInterval)
+	defer ticker.Stop()
+
+	window := time.NewTimer(cfg.GetRollbackWindow())
+	defer window.Stop()
+
+	var failures int
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-window.C:
+			return nil
+		case <-ticker.C:
+		}
+
+		err := n.checkComponentHealth(ctx, id, v, cfg.ReadinessMethod)
+		switch {
+		case err == nil:
+			failures = 0
+		case errors.Is(err, multi.ErrNoSuchVersion):
+			// Component is not running, e.g. because the RONL component has been stopped.
+		default:
+			failures++
+			if failures >= rolloutMaxFailures {
+				return err
+			}
+		}
+	}
+}
+
+// checkComponentHealth checks whether the given component version has been initialized, responds
+// to pings and, if configured, successfully handles the readiness local RPC method.
+func (n *RuntimeHostNode) checkComponentHealth(ctx context.Context, id component.ID, v version.Version, method string) error {
+	comp, ok := n.host.Component(id)
+	if !ok {
+		return fmt.Errorf("component not found")
+	}
+	rt, err := comp.Version(v)
+	if err != nil {
+		return err
+	}
+
+	// The runtime is not ready until it has been initialized and attested.
+	if _, err = rt.GetCapabilityTEE(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rolloutCheckTimeout)
+	defer cancel()
+
+	if _, err = rt.Call(ctx, &protocol.Body{RuntimePingRequest: &protocol.Empty{}}); err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+
+	if method == "" {
+		return nil
+	}
+	if err = host.NewRichRuntime(rt).LocalRPC(ctx, method, nil, nil); err != nil {
+		return fmt.Errorf("readiness query failed: %w", err)
+	}
+	return nil
+}
+
+// promote switches over to the new component version.
+func (n *RuntimeHostNode) promote(ro *componentRollout) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	id := ro.candidate.ID()
+	if n.rollouts[id] != ro {
+		return false
+	}
+	ro.promoted = true
+
+	if comp, ok := n.host.Component(id); ok {
+		n.setROFLVersionLocked(id, comp)
+	}
+	return true
+}
+
+// rollback reverts to the previous component version and prevents the failed version from being
+// provisioned again.
+func (n *RuntimeHostNode) rollback(ro *componentRollout, logger *logging.Logger) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	id := ro.candidate.ID()
+	if n.rollouts[id] != ro {
+		return
+	}
+	delete(n.rollouts, id)
+
+	n.rollbackLocked(ro, logger)
+}
+
+func (n *RuntimeHostNode) rollbackLocked(ro *componentRollout, logger *logging.Logger) {
+	id := ro.candidate.ID()
+
+	// The previous version has been removed after switching over, provision it again.
+	if !n.host.HasVersion(id, ro.previous.Version) {
+		if err := n.provisionLocked(ro.previous); err != nil {
+			logger.Error("failed to provision previous component version, keeping new version",
+				"err", err,
+			)
+			n.removeSnapshotsLocked(ro)
+			if comp, ok := n.host.Component(id); ok {
+				n.setROFLVersionLocked(id, comp)
+			}
+			return
+		}
+	}
+
+	n.failed[id] = ro.candidate.Version
+	n.rofls[id] = ro.previous
+
+	comp, ok := n.host.Component(id)
+	if !ok {
+		return
+	}
+
+	// Discard any changes the new version made to the volumes. The new version must be stopped
+	// first as volumes cannot be restored while attached to a running component.
+	if ro.snapshots != nil {
+		comp.SetVersion(nil, nil)
+		if err := n.restoreVolumesLocked(ro); err != nil {
+			logger.Error("failed to restore component volumes",
+				"err", err,
+			)
+		}
+		n.removeSnapshotsLocked(ro)
+	}
+
+	n.setROFLVersionLocked(id, comp)
+	_ = comp.RemoveVersion(ro.candidate.Version)
+}
@@ -0,0 +1,445 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/config"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+	runtimeConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/volume"
+)
+
+var (
+	testROFLID = component.ID{Kind: component.ROFL, Name: "test-rofl"}
+
+	testRONLVersion = version.Version{Major: 1}
+	testV1          = version.Version{Major: 1}
+	testV2          = version.Version{Major: 2}
+	testV3          = version.Version{Major: 3}
+)
+
+type testRuntime struct {
+	Runtime
+
+	id common.Namespace
+}
+
+func (r *testRuntime) ID() common.Namespace {
+	return r.id
+}
+
+type testHandler struct{}
+
+func (h *testHandler) Handle(context.Context, *protocol.Body) (*protocol.Body, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (h *testHandler) NewSubHandler(component.ID) (host.RuntimeHandler, error) {
+	return h, nil
+}
+
+func (h *testHandler) AttachRuntime(component.ID, host.Runtime) error {
+	return nil
+}
+
+// testProvisioner provisions fake component runtimes which are run by the real aggregates of the
+// runtime host node, with the health of each ROFL component version controlled by the test.
+type testProvisioner struct {
+	mu        sync.Mutex
+	unhealthy map[version.Version]bool
+	running   map[version.Version]bool
+	// sharedVolumes is the number of times two ROFL component versions ran concurrently while
+	// using persistent volumes.
+	sharedVolumes int
+}
+
+func newTestProvisioner() *testProvisioner {
+	return &testProvisioner{
+		unhealthy: make(map[version.Version]bool),
+		running:   make(map[version.Version]bool),
+	}
+}
+
+func (p *testProvisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
+	return &testComponentRuntime{
+		id:       cfg.ID,
+		comp:     cfg.Component,
+		prov:     p,
+		notifier: pubsub.NewBroker(false),
+	}, nil
+}
+
+func (p *testProvisioner) Name() string {
+	return "test"
+}
+
+func (p *testProvisioner) setHealthy(v version.Version, healthy bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.unhealthy[v] = !healthy
+}
+
+func (p *testProvisioner) isHealthy(comp *bundle.ExplodedComponent) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return comp.Kind != component.ROFL || !p.unhealthy[comp.Version]
+}
+
+func (p *testProvisioner) setRunning(comp *bundle.ExplodedComponent, running bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if comp.Kind != component.ROFL {
+		return
+	}
+	if running && len(comp.Volumes) > 0 && len(p.running) > 0 {
+		p.sharedVolumes++
+	}
+	switch running {
+	case true:
+		p.running[comp.Version] = true
+	case false:
+		delete(p.running, comp.Version)
+	}
+}
+
+func (p *testProvisioner) numSharedVolumes() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.sharedVolumes
+}
+
+type testComponentRuntime struct {
+	id       common.Namespace
+	comp     *bundle.ExplodedComponent
+	prov     *testProvisioner
+	notifier *pubsub.Broker
+}
+
+func (r *testComponentRuntime) ID() common.Namespace {
+	return r.id
+}
+
+func (r *testComponentRuntime) GetActiveVersion() (*version.Version, error) {
+	return &r.comp.Version, nil
+}
+
+func (r *testComponentRuntime) GetInfo(context.Context) (*protocol.RuntimeInfoResponse, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (r *testComponentRuntime) GetCapabilityTEE() (*node.CapabilityTEE, error) {
+	if !r.prov.isHealthy(r.comp) {
+		return nil, fmt.Errorf("not attested")
+	}
+	return nil, nil
+}
+
+func (r *testComponentRuntime) Call(context.Context, *protocol.Body) (*protocol.Body, error) {
+	if !r.prov.isHealthy(r.comp) {
+		return nil, fmt.Errorf("not responding")
+	}
+	return &protocol.Body{Empty: &protocol.Empty{}}, nil
+}
+
+func (r *testComponentRuntime) UpdateCapabilityTEE() {
+}
+
+func (r *testComponentRuntime) WatchEvents() (<-chan *host.Event, pubsub.ClosableSubscription) {
+	ch := make(chan *host.Event)
+	sub := r.notifier.Subscribe()
+	sub.Unwrap(ch)
+
+	return ch, sub
+}
+
+func (r *testComponentRuntime) Start() {
+	for _, vol := range r.comp.Volumes {
+		_ = vol.Attach()
+	}
+	r.prov.setRunning(r.comp, true)
+	r.notifier.Broadcast(&host.Event{Started: &host.StartedEvent{Version: r.comp.Version}})
+}
+
+func (r *testComponentRuntime) Abort(context.Context, bool) error {
+	return nil
+}
+
+func (r *testComponentRuntime) Stop() {
+	r.prov.setRunning(r.comp, false)
+	for _, vol := range r.comp.Volumes {
+		vol.Detach()
+	}
+	r.notifier.Broadcast(&host.Event{Stopped: &host.StoppedEvent{}})
+}
+
+type testHostNode struct {
+	*RuntimeHostNode
+
+	prov    *testProvisioner
+	volumes *volume.Manager
+}
+
+func newTestHostNode(t *testing.T, upgrade runtimeConfig.ComponentUpgradeConfig) *testHostNode {
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("host rollout test"), 0)
+
+	prevRuntimes := config.GlobalConfig.Runtime.Runtimes
+	prevInterval := rolloutCheckInterval
+	t.Cleanup(func() {
+		config.GlobalConfig.Runtime.Runtimes = prevRuntimes
+		rolloutCheckInterval = prevInterval
+	})
+	config.GlobalConfig.Runtime.Runtimes = []runtimeConfig.RuntimeConfig{
+		{
+			ID: runtimeID,
+			Components: []runtimeConfig.ComponentConfig{
+				{
+					ID:      testROFLID,
+					Upgrade: upgrade,
+				},
+			},
+		},
+	}
+	rolloutCheckInterval = 10 * time.Millisecond
+
+	volumes, err := volume.NewManager(t.TempDir())
+	require.NoError(t, err, "volume.NewManager")
+
+	prov := newTestProvisioner()
+	rhn, err := NewRuntimeHostNode(&testRuntime{id: runtimeID}, prov, &testHandler{}, volumes)
+	require.NoError(t, err, "NewRuntimeHostNode")
+
+	n := &testHostNode{
+		RuntimeHostNode: rhn,
+		prov:            prov,
+		volumes:         volumes,
+	}
+	n.provision(t, &bundle.ExplodedComponent{
+		Component: &bundle.Component{
+			Kind:    component.RONL,
+			Version: testRONLVersion,
+		},
+	})
+
+	hrt := n.GetHostedRuntime()
+	hrt.Start()
+	t.Cleanup(hrt.Stop)
+	n.SetHostedRuntimeVersion(&testRONLVersion, nil)
+
+	return n
+}
+
+// provision provisions the given component and updates the running versions the same way as the
+// runtime workers do.
+func (n *testHostNode) provision(t *testing.T, comp *bundle.ExplodedComponent) {
+	err := n.ProvisionHostedRuntimeComponent(comp)
+	require.NoError(t, err, "ProvisionHostedRuntimeComponent")
+	n.SetHostedRuntimeVersion(&testRONLVersion, nil)
+}
+
+func (n *testHostNode) activeVersion() *version.Version {
+	comp, ok := n.GetHostedRuntime().Component(testROFLID)
+	if !ok {
+		return nil
+	}
+	v, err := comp.GetActiveVersion()
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+func (n *testHostNode) isRunning(v version.Version) bool {
+	comp, ok := n.GetHostedRuntime().Component(testROFLID)
+	if !ok {
+		return false
+	}
+	_, err := comp.Version(v)
+	return err == nil
+}
+
+func (n *testHostNode) rollout() (*componentRollout, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ro, ok := n.rollouts[testROFLID]
+	return ro, ok
+}
+
+func (n *testHostNode) isPromoted() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ro, ok := n.rollouts[testROFLID]
+	return ok && ro.promoted
+}
+
+func newTestROFL(v version.Version, volumes map[string]*volume.Volume) *bundle.ExplodedComponent {
+	return &bundle.ExplodedComponent{
+		Component: &bundle.Component{
+			Kind:    component.ROFL,
+			Name:    testROFLID.Name,
+			Version: v,
+		},
+		Volumes: volumes,
+	}
+}
+
+func TestRolloutPromote(t *testing.T) {
+	require := require.New(t)
+
+	n := newTestHostNode(t, runtimeConfig.ComponentUpgradeConfig{
+		Mode:             runtimeConfig.ComponentUpgradeModeHealthGated,
+		ReadinessTimeout: time.Minute,
+		RollbackWindow:   100 * time.Millisecond,
+	})
+	n.provision(t, newTestROFL(testV1, nil))
+	require.Equal(&testV1, n.activeVersion())
+
+	// The new version should be started alongside the previous one until it becomes healthy.
+	n.prov.setHealthy(testV2, false)
+	n.provision(t, newTestROFL(testV2, nil))
+	require.Eventually(func() bool {
+		return n.isRunning(testV2)
+	}, 10*time.Second, 10*time.Millisecond, "new version should be started")
+	require.Equal(&testV1, n.activeVersion(), "previous version should remain active")
+
+	n.prov.setHealthy(testV2, true)
+	require.Eventually(func() bool {
+		_, ok := n.rollout()
+		return !ok
+	}, 10*time.Second, 10*time.Millisecond, "upgrade should complete")
+	require.Equal(&testV2, n.activeVersion(), "new version should be active")
+	require.False(n.GetHostedRuntime().HasVersion(testROFLID, testV1), "previous version should be removed")
+}
+
+func TestRolloutRollbackBeforeSwitchOver(t *testing.T) {
+	require := require.New(t)
+
+	n := newTestHostNode(t, runtimeConfig.ComponentUpgradeConfig{
+		Mode:             runtimeConfig.ComponentUpgradeModeHealthGated,
+		ReadinessTimeout: 100 * time.Millisecond,
+	})
+	n.provision(t, newTestROFL(testV1, nil))
+
+	n.prov.setHealthy(testV2, false)
+	n.provision(t, newTestROFL(testV2, nil))
+	require.Eventually(func() bool {
+		_, ok := n.rollout()
+		return !ok
+	}, 10*time.Second, 10*time.Millisecond, "upgrade should be reverted")
+	require.Equal(&testV1, n.activeVersion(), "previous version should remain active")
+	require.False(n.GetHostedRuntime().HasVersion(testROFLID, testV2), "new version should be removed")
+
+	// Failed versions should not be provisioned again.
+	n.provision(t, newTestROFL(testV2, nil))
+	require.False(n.GetHostedRuntime().HasVersion(testROFLID, testV2), "failed version should not be provisioned")
+}
+
+func TestRolloutRollbackAfterSwitchOver(t *testing.T) {
+	require := require.New(t)
+
+	n := newTestHostNode(t, runtimeConfig.ComponentUpgradeConfig{
+		Mode:             runtimeConfig.ComponentUpgradeModeHealthGated,
+		ReadinessTimeout: time.Minute,
+		RollbackWindow:   time.Minute,
+	})
+	vol, err := n.volumes.Create(nil)
+	require.NoError(err, "Create")
+	err = os.WriteFile(vol.Path, []byte("version 1"), 0o600)
+	require.NoError(err, "WriteFile")
+	volumes := map[string]*volume.Volume{"data": vol}
+
+	n.provision(t, newTestROFL(testV1, volumes))
+	n.provision(t, newTestROFL(testV2, volumes))
+	require.Eventually(n.isPromoted, 10*time.Second, 10*time.Millisecond, "new version should be promoted")
+	require.Equal(&testV2, n.activeVersion(), "new version should be active")
+	snapshots, err := n.volumes.Snapshots(vol.ID)
+	require.NoError(err, "Snapshots")
+	require.Len(snapshots, 1, "volume should be snapshotted before the upgrade")
+
+	// Failing within the rollback window should revert both the version and the volume.
+	err = os.WriteFile(vol.Path, []byte("version 2"), 0o600)
+	require.NoError(err, "WriteFile")
+	n.prov.setHealthy(testV2, false)
+	require.Eventually(func() bool {
+		_, ok := n.rollout()
+		return !ok
+	}, 10*time.Second, 10*time.Millisecond, "upgrade should be reverted")
+	require.Equal(&testV1, n.activeVersion(), "previous version should be active")
+	require.False(n.GetHostedRuntime().HasVersion(testROFLID, testV2), "new version should be removed")
+
+	data, err := os.ReadFile(vol.Path)
+	require.NoError(err, "ReadFile")
+	require.Equal("version 1", string(data), "volume should be restored")
+	snapshots, err = n.volumes.Snapshots(vol.ID)
+	require.NoError(err, "Snapshots")
+	require.Empty(snapshots, "snapshots should be removed after the upgrade")
+	require.Zero(n.prov.numSharedVolumes(), "versions should never share volumes")
+}
+
+func TestRolloutSupersede(t *testing.T) {
+	require := require.New(t)
+
+	n := newTestHostNode(t, runtimeConfig.ComponentUpgradeConfig{
+		Mode:             runtimeConfig.ComponentUpgradeModeHealthGated,
+		ReadinessTimeout: time.Minute,
+		RollbackWindow:   100 * time.Millisecond,
+	})
+	vol, err := n.volumes.Create(nil)
+	require.NoError(err, "Create")
+	err = os.WriteFile(vol.Path, []byte("version 1"), 0o600)
+	require.NoError(err, "WriteFile")
+	volumes := map[string]*volume.Volume{"data": vol}
+
+	n.provision(t, newTestROFL(testV1, volumes))
+
+	n.prov.setHealthy(testV2, false)
+	n.provision(t, newTestROFL(testV2, volumes))
+	require.Eventually(func() bool {
+		return n.isRunning(testV2)
+	}, 10*time.Second, 10*time.Millisecond, "new version should be started")
+	err = os.WriteFile(vol.Path, []byte("version 2"), 0o600)
+	require.NoError(err, "WriteFile")
+
+	// A newer version should supersede the in-progress upgrade, with the version that was running
+	// before it remaining the last known-good one.
+	n.provision(t, newTestROFL(testV3, volumes))
+	ro, ok := n.rollout()
+	require.True(ok, "upgrade should be in progress")
+	require.Equal(testV1, ro.previous.Version)
+	require.Equal(testV3, ro.candidate.Version)
+
+	data, err := os.ReadFile(vol.Path)
+	require.NoError(err, "ReadFile")
+	require.Equal("version 1", string(data), "changes of the superseded version should be discarded")
+
+	require.Eventually(func() bool {
+		_, ok := n.rollout()
+		return !ok
+	}, 10*time.Second, 10*time.Millisecond, "upgrade should complete")
+	require.Equal(&testV3, n.activeVersion(), "newest version should be active")
+	require.False(n.GetHostedRuntime().HasVersion(testROFLID, testV2), "superseded version should be removed")
+
+	snapshots, err := n.volumes.Snapshots(vol.ID)
+	require.NoError(err, "Snapshots")
+	require.Empty(snapshots, "snapshots should be removed after the upgrade")
+	require.Zero(n.prov.numSharedVolumes(), "versions should never share volumes")
+}
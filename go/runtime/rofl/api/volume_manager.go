@@ -10,6 +10,14 @@ const (
 	MethodVolumeRemove = "VolumeRemove"
 	// MethodVolumeList is the name of the VolumeList method.
 	MethodVolumeList = "VolumeList"
+	// MethodVolumeSnapshot is the name of the VolumeSnapshot method.
+	MethodVolumeSnapshot = "VolumeSnapshot"
+	// MethodVolumeRestore is the name of the VolumeRestore method.
+	MethodVolumeRestore = "VolumeRestore"
+	// MethodVolumeSnapshotRemove is the name of the VolumeSnapshotRemove method.
+	MethodVolumeSnapshotRemove = "VolumeSnapshotRemove"
+	// MethodVolumeClone is the name of the VolumeClone method.
+	MethodVolumeClone = "VolumeClone"
 )
 
 // VolumeAddRequest is a request to add a volume.
@@ -18,6 +26,8 @@ const (
 type VolumeAddRequest struct {
 	// Labels are the labels to tag the volume with so it can later be found.
 	Labels map[string]string `json:"labels"`
+	// Quota is the maximum size of the volume in bytes. Zero means no limit.
+	Quota uint64 `json:"quota,omitempty"`
 }
 
 // VolumeAddResponse is a response from the VolumeAdd method.
@@ -56,4 +66,77 @@ type VolumeInfo struct {
 	ID string `json:"id"`
 	// Labels is a set of labels assigned to this volume.
 	Labels map[string]string `json:"labels,omitempty"`
+	// Quota is the maximum size of the volume in bytes. Zero means no limit.
+	Quota uint64 `json:"quota,omitempty"`
+	// Usage is the amount of disk space used by the volume and its snapshots in bytes.
+	Usage uint64 `json:"usage,omitempty"`
+	// Snapshots are the snapshots of the volume in creation order.
+	Snapshots []*VolumeSnapshotInfo `json:"snapshots,omitempty"`
+}
+
+// VolumeSnapshotInfo is the volume snapshot information.
+type VolumeSnapshotInfo struct {
+	// ID is the snapshot identifier, unique within the volume.
+	ID string `json:"id"`
+	// Created is the UNIX timestamp of when the snapshot was created.
+	Created uint64 `json:"created"`
+}
+
+// VolumeSnapshotRequest is a request to snapshot a volume.
+//
+// Snapshots count towards the volume quota. Volumes attached to running components cannot be
+// snapshotted, restored or cloned.
+//
+// The `PermissionVolumeAdd` permission is required to call this method.
+type VolumeSnapshotRequest struct {
+	// ID is the unique volume identifier.
+	ID string `json:"id"`
+}
+
+// VolumeSnapshotResponse is a response from the VolumeSnapshot method.
+type VolumeSnapshotResponse struct {
+	// SnapshotID is the identifier of the created snapshot.
+	SnapshotID string `json:"snapshot_id"`
+}
+
+// VolumeRestoreRequest is a request to restore a volume from a snapshot.
+//
+// The `PermissionVolumeAdd` permission is required to call this method.
+type VolumeRestoreRequest struct {
+	// ID is the unique volume identifier.
+	ID string `json:"id"`
+	// SnapshotID is the identifier of the snapshot to restore.
+	SnapshotID string `json:"snapshot_id"`
+}
+
+// VolumeRestoreResponse is a response from the VolumeRestore method.
+type VolumeRestoreResponse struct{}
+
+// VolumeSnapshotRemoveRequest is a request to remove a volume snapshot.
+//
+// The `PermissionVolumeRemove` permission is required to call this method.
+type VolumeSnapshotRemoveRequest struct {
+	// ID is the unique volume identifier.
+	ID string `json:"id"`
+	// SnapshotID is the identifier of the snapshot to remove.
+	SnapshotID string `json:"snapshot_id"`
+}
+
+// VolumeSnapshotRemoveResponse is a response from the VolumeSnapshotRemove method.
+type VolumeSnapshotRemoveResponse struct{}
+
+// VolumeCloneRequest is a request to clone a volume.
+//
+// The `PermissionVolumeAdd` permission is required to call this method.
+type VolumeCloneRequest struct {
+	// ID is the unique identifier of the volume to clone.
+	ID string `json:"id"`
+	// Labels are the labels to tag the new volume with so it can later be found.
+	Labels map[string]string `json:"labels"`
+}
+
+// VolumeCloneResponse is a response from the VolumeClone method.
+type VolumeCloneResponse struct {
+	// ID is the unique identifier of the new volume.
+	ID string `json:"id"`
 }
@@ -62,6 +62,17 @@ func (cq *checkTxQueue) pop() []*PendingCheckTransaction {
 	return batch
 }
 
+func (cq *checkTxQueue) peekAll() []*PendingCheckTransaction {
+	cq.l.Lock()
+	defer cq.l.Unlock()
+
+	pcts := make([]*PendingCheckTransaction, 0, cq.txs.Len())
+	for i := 0; i < cq.txs.Len(); i++ {
+		pcts = append(pcts, cq.txs.At(i))
+	}
+	return pcts
+}
+
 func (cq *checkTxQueue) size() int {
 	cq.l.Lock()
 	defer cq.l.Unlock()
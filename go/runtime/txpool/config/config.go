@@ -1,7 +1,10 @@
 // Package config implements the txpool configuration options.
 package config
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Config is the runtime transaction pool configuration structure.
 type Config struct {
@@ -15,4 +18,60 @@ type Config struct {
 	RecheckInterval uint64 `yaml:"recheck_interval"`
 	// Republish interval.
 	RepublishInterval time.Duration
+	// Minimum priority increase (in percent) required for a transaction to replace a pending
+	// transaction from the same sender.
+	MinPriorityBumpPercent uint64 `yaml:"min_priority_bump_percent"`
+	// Interval at which local and main queue transactions are journaled to disk so that they can be
+	// restored after a restart. Zero disables the journal.
+	JournalInterval time.Duration `yaml:"journal_interval"`
+	// Maximum age of journaled transactions that are restored after a restart. Zero means that
+	// journaled transactions never expire.
+	JournalMaxAge time.Duration `yaml:"journal_max_age"`
+	// Per-sender quota of new transactions admitted into the main queue, including replacements of
+	// the sender's queued transaction. Senders are identified by the sender metadata reported by
+	// the runtime when checking transactions, so the quota has no effect for runtimes which do not
+	// report senders.
+	SenderRateLimit RateLimit `yaml:"sender_rate_limit,omitempty"`
+	// Per-peer rate limit of transactions received via gossip and of transactions requested via
+	// the transaction sync protocol. Gossiped transactions are attributed to the originator of the
+	// message and are only dropped locally so that relaying peers are not penalized, while
+	// transaction sync requests are attributed to the requesting peer.
+	PeerRateLimit RateLimit `yaml:"peer_rate_limit,omitempty"`
+}
+
+// RateLimit is a token bucket rate limit configuration.
+type RateLimit struct {
+	// Rate is the number of transactions per second that are allowed on average. Zero disables
+	// the rate limit.
+	Rate float64 `yaml:"rate"`
+	// Burst is the maximum number of transactions that are allowed at once.
+	Burst uint64 `yaml:"burst"`
+}
+
+// Enabled returns true iff the rate limit is enabled.
+func (rl *RateLimit) Enabled() bool {
+	return rl.Rate > 0
+}
+
+// Validate validates the rate limit configuration.
+func (rl *RateLimit) Validate() error {
+	switch {
+	case rl.Rate < 0:
+		return fmt.Errorf("rate must be non-negative")
+	case rl.Enabled() && rl.Burst == 0:
+		return fmt.Errorf("burst must be at least 1")
+	default:
+		return nil
+	}
+}
+
+// Validate validates the configuration settings.
+func (c *Config) Validate() error {
+	if err := c.SenderRateLimit.Validate(); err != nil {
+		return fmt.Errorf("sender_rate_limit: %w", err)
+	}
+	if err := c.PeerRateLimit.Validate(); err != nil {
+		return fmt.Errorf("peer_rate_limit: %w", err)
+	}
+	return nil
 }
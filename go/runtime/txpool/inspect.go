@@ -0,0 +1,185 @@
+package txpool
+
+import (
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+)
+
+// eventBufferSize is the number of transaction pool events buffered for each subscriber. In case
+// a subscriber is not able to keep up, the oldest events are dropped.
+const eventBufferSize = 1024
+
+// QueueKind is the kind of the transaction pool queue a transaction is in.
+type QueueKind string
+
+const (
+	// QueueCheck is the queue of transactions pending (re)checks.
+	QueueCheck QueueKind = "check"
+	// QueueLocal is the queue of transactions obtained from local clients.
+	QueueLocal QueueKind = "local"
+	// QueueMain is the main priority queue of checked transactions.
+	QueueMain QueueKind = "main"
+	// QueueRIM is the queue of transactions from roothash incoming messages.
+	QueueRIM QueueKind = "rim"
+)
+
+// Entry is a transaction pool entry together with its queuing metadata.
+type Entry struct {
+	// Hash is the transaction hash.
+	Hash hash.Hash
+	// Size is the size of the raw transaction in bytes.
+	Size int
+	// Queue is the queue the transaction is in.
+	Queue QueueKind
+	// Priority is the transaction priority as specified by the runtime. It is only available for
+	// transactions in the main queue.
+	Priority uint64
+	// Sender is the transaction sender as specified by the runtime. It is only available for
+	// transactions in the main queue. If the runtime does not specify senders, the transaction
+	// hash is used instead.
+	Sender []byte
+	// SenderSeq is the per-sender sequence number as specified by the runtime. It is only
+	// available for transactions in the main queue.
+	SenderSeq uint64
+	// FirstSeen is the time the transaction was first seen by this node. It is zero for
+	// transactions which did not go through local submission (e.g. incoming messages).
+	FirstSeen time.Time
+	// RecheckCount is the number of times the transaction has been rechecked.
+	RecheckCount uint64
+}
+
+func newEntry(tx *TxQueueMeta, queue QueueKind) *Entry {
+	return &Entry{
+		Hash:         tx.Hash(),
+		Size:         tx.Size(),
+		Queue:        queue,
+		FirstSeen:    tx.FirstSeen(),
+		RecheckCount: tx.RecheckCount(),
+	}
+}
+
+// EventKind is the kind of a transaction pool event.
+type EventKind string
+
+const (
+	// EventAdmitted is emitted when a checked transaction is admitted into a schedulable queue.
+	EventAdmitted EventKind = "admitted"
+	// EventEvicted is emitted when a transaction is removed from the pool before or after being
+	// scheduled.
+	EventEvicted EventKind = "evicted"
+	// EventRejected is emitted when a new transaction is not admitted into the pool.
+	EventRejected EventKind = "rejected"
+)
+
+// Event is a transaction pool event.
+type Event struct {
+	// Kind is the kind of the event.
+	Kind EventKind
+	// Hash is the transaction hash.
+	Hash hash.Hash
+	// Queue is the queue the transaction has been admitted into or removed from. It is empty in
+	// case the transaction never made it into a queue.
+	Queue QueueKind
+	// Reason is a human readable description of why the transaction was evicted or rejected.
+	Reason string
+}
+
+// Reasons used in transaction pool events.
+const (
+	reasonDuplicate       = "duplicate transaction"
+	reasonCheckFailed     = "check failed"
+	reasonRecheckFailed   = "recheck failed"
+	reasonReplaced        = "replaced by a higher priority transaction from the same sender"
+	reasonQueueFull       = "evicted by a higher priority transaction as the queue is full"
+	reasonBlockRejected   = "rejected during block processing"
+	reasonIncludedInBlock = "included in a block"
+)
+
+func (t *txPool) GetEntries() []*Entry {
+	t.drainLock.Lock()
+	defer t.drainLock.Unlock()
+
+	var entries []*Entry
+	for _, pct := range t.checkTxQueue.peekAll() {
+		entries = append(entries, newEntry(pct.TxQueueMeta, QueueCheck))
+	}
+	for _, tx := range t.rimQueue.PeekAll() {
+		entries = append(entries, newEntry(tx, QueueRIM))
+	}
+	for _, tx := range t.localQueue.PeekAll() {
+		entries = append(entries, newEntry(tx, QueueLocal))
+	}
+	for _, tx := range t.mainQueue.inner.getAll() {
+		entry := newEntry(&tx.TxQueueMeta, QueueMain)
+		entry.Priority = tx.Priority()
+		entry.Sender = []byte(tx.Sender())
+		entry.SenderSeq = tx.SenderSeq()
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (t *txPool) WatchEvents() (<-chan *Event, pubsub.ClosableSubscription) {
+	sub := t.eventNotifier.SubscribeBuffered(eventBufferSize)
+	ch := make(chan *Event)
+	sub.Unwrap(ch)
+	return ch, sub
+}
+
+// queueKind returns the kind of the given destination queue.
+func (t *txPool) queueKind(q RecheckableTransactionStore) QueueKind {
+	switch q {
+	case t.localQueue:
+		return QueueLocal
+	case t.mainQueue:
+		return QueueMain
+	default:
+		return ""
+	}
+}
+
+func (t *txPool) emitEvent(kind EventKind, h hash.Hash, queue QueueKind, reason string) {
+	t.eventNotifier.Broadcast(&Event{
+		Kind:   kind,
+		Hash:   h,
+		Queue:  queue,
+		Reason: reason,
+	})
+}
+
+// emitCheckFailure emits an event for a transaction that failed checks or could not be queued.
+// New transactions are reported as rejected, while rechecked transactions are reported as evicted
+// from the queue they were in.
+func (t *txPool) emitCheckFailure(pct *PendingCheckTransaction, reason string) {
+	if pct.dstQueue == nil {
+		// Transactions that are only being checked are never admitted into the pool.
+		return
+	}
+	if pct.flags.isRecheck() {
+		t.emitEvent(EventEvicted, pct.Hash(), t.queueKind(pct.dstQueue), reason)
+		return
+	}
+	t.emitEvent(EventRejected, pct.Hash(), "", reason)
+}
+
+// removeTxs removes the given transactions from all queues, emitting eviction events for any
+// transactions that were present.
+func (t *txPool) removeTxs(hashes []hash.Hash, reason string) {
+	for _, h := range hashes {
+		switch {
+		case t.localQueue.GetTxByHash(h) != nil:
+			t.emitEvent(EventEvicted, h, QueueLocal, reason)
+		case t.mainQueue.GetTxByHash(h) != nil:
+			t.emitEvent(EventEvicted, h, QueueMain, reason)
+		}
+	}
+
+	for _, q := range t.usableSources {
+		q.HandleTxsUsed(hashes)
+	}
+
+	mainQueueSize.With(t.getMetricLabels()).Set(float64(t.mainQueue.inner.size()))
+	localQueueSize.With(t.getMetricLabels()).Set(float64(t.localQueue.size()))
+}
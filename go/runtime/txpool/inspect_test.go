@@ -0,0 +1,130 @@
+package txpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/message"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+)
+
+func newTestTxQueueMeta(raw string) *TxQueueMeta {
+	return &TxQueueMeta{
+		raw:       []byte(raw),
+		hash:      hash.NewFromBytes([]byte(raw)),
+		firstSeen: time.Now(),
+	}
+}
+
+func TestInspect(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Config{
+		MaxPoolSize:          2,
+		MaxLastSeenCacheSize: 100,
+		MaxCheckTxBatchSize:  10,
+	}
+	tp := New(common.Namespace{}, cfg, nil, nil, nil, "").(*txPool)
+
+	evCh, evSub := tp.WatchEvents()
+	defer evSub.Close()
+	recvEvent := func() *Event {
+		select {
+		case ev := <-evCh:
+			return ev
+		case <-time.After(time.Second):
+			require.FailNow("timed out waiting for event")
+			return nil
+		}
+	}
+
+	// Populate the queues.
+	localTx := newTestTxQueueMeta("local tx")
+	err := tp.localQueue.OfferChecked(localTx, nil)
+	require.NoError(err, "OfferChecked")
+	mainTx := newTestTxQueueMeta("main tx")
+	mainTx.recheckCount = 2
+	err = tp.mainQueue.OfferChecked(mainTx, &protocol.CheckTxMetadata{
+		Priority:  42,
+		Sender:    []byte("sender"),
+		SenderSeq: 7,
+	})
+	require.NoError(err, "OfferChecked")
+	pendingTx := newTestTxQueueMeta("pending tx")
+	err = tp.addToCheckQueue(&PendingCheckTransaction{
+		TxQueueMeta: pendingTx,
+		dstQueue:    tp.mainQueue,
+	})
+	require.NoError(err, "addToCheckQueue")
+	tp.ProcessIncomingMessages([]*message.IncomingMessage{{Data: []byte("rim tx")}})
+
+	entries := tp.GetEntries()
+	require.Len(entries, 4, "all transactions should be returned")
+	byQueue := make(map[QueueKind]*Entry)
+	for _, entry := range entries {
+		byQueue[entry.Queue] = entry
+	}
+	require.Equal(pendingTx.Hash(), byQueue[QueueCheck].Hash)
+	require.Equal(hash.NewFromBytes([]byte("rim tx")), byQueue[QueueRIM].Hash)
+	require.True(byQueue[QueueRIM].FirstSeen.IsZero())
+	require.Equal(localTx.Hash(), byQueue[QueueLocal].Hash)
+	require.Equal(localTx.FirstSeen(), byQueue[QueueLocal].FirstSeen)
+	require.Equal(&Entry{
+		Hash:         mainTx.Hash(),
+		Size:         mainTx.Size(),
+		Queue:        QueueMain,
+		Priority:     42,
+		Sender:       []byte("sender"),
+		SenderSeq:    7,
+		FirstSeen:    mainTx.FirstSeen(),
+		RecheckCount: 2,
+	}, byQueue[QueueMain], "main queue entries should include check metadata")
+
+	// Rejections should be reported.
+	err = tp.addToCheckQueue(&PendingCheckTransaction{
+		TxQueueMeta: newTestTxQueueMeta("another pending tx"),
+		dstQueue:    tp.mainQueue,
+	})
+	require.NoError(err, "addToCheckQueue")
+	rejectedTx := newTestTxQueueMeta("rejected tx")
+	err = tp.addToCheckQueue(&PendingCheckTransaction{
+		TxQueueMeta: rejectedTx,
+		dstQueue:    tp.mainQueue,
+	})
+	require.Error(err, "addToCheckQueue should fail when the check queue is full")
+	ev := recvEvent()
+	require.Equal(&Event{Kind: EventRejected, Hash: rejectedTx.Hash(), Reason: err.Error()}, ev)
+
+	_ = tp.seenCache.Put(rejectedTx.Hash(), time.Now())
+	err = tp.SubmitTxNoWait(rejectedTx.Raw(), &TransactionMeta{})
+	require.Error(err, "duplicate transactions should be rejected")
+	ev = recvEvent()
+	require.Equal(&Event{Kind: EventRejected, Hash: rejectedTx.Hash(), Reason: reasonDuplicate}, ev)
+
+	// Removals should be reported as evictions.
+	tp.HandleTxsUsed([]hash.Hash{localTx.Hash(), hash.NewFromBytes([]byte("unknown tx"))})
+	ev = recvEvent()
+	require.Equal(&Event{Kind: EventEvicted, Hash: localTx.Hash(), Queue: QueueLocal, Reason: reasonIncludedInBlock}, ev)
+
+	tp.RejectTxs([]hash.Hash{mainTx.Hash()})
+	ev = recvEvent()
+	require.Equal(&Event{Kind: EventEvicted, Hash: mainTx.Hash(), Queue: QueueMain, Reason: reasonBlockRejected}, ev)
+
+	entries = tp.GetEntries()
+	require.Len(entries, 3, "removed transactions should no longer be returned")
+	for _, entry := range entries {
+		require.NotEqual(QueueLocal, entry.Queue)
+		require.NotEqual(QueueMain, entry.Queue)
+	}
+
+	select {
+	case ev = <-evCh:
+		require.FailNow("unexpected event", "event: %+v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,232 @@
+package txpool
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+const (
+	// journalFilename is the name of the transaction pool journal file.
+	journalFilename = "txpool.journal"
+
+	// journalVersion is the transaction pool journal format version.
+	journalVersion = 1
+)
+
+// journalEntry is a journaled transaction.
+type journalEntry struct {
+	// Raw is the raw transaction data.
+	Raw []byte `json:"raw"`
+	// Local is a flag indicating that the transaction was obtained from a local client.
+	Local bool `json:"local,omitempty"`
+	// FirstSeen is the UNIX timestamp when the transaction was first seen.
+	FirstSeen int64 `json:"first_seen"`
+}
+
+// journalData is the serialized transaction pool journal.
+type journalData struct {
+	cbor.Versioned
+
+	// Entries are the journaled transactions.
+	Entries []*journalEntry `json:"entries"`
+}
+
+// journal persists transactions so that they survive node restarts.
+type journal struct {
+	l sync.Mutex
+
+	path string
+}
+
+// load loads the journaled transactions. In case the journal does not exist, no transactions are
+// returned.
+func (j *journal) load() ([]*journalEntry, error) {
+	j.l.Lock()
+	defer j.l.Unlock()
+
+	raw, err := os.ReadFile(j.path)
+	switch {
+	case err == nil:
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var data journalData
+	if err = cbor.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal journal: %w", err)
+	}
+	if data.V != journalVersion {
+		return nil, fmt.Errorf("unsupported journal version: %d", data.V)
+	}
+	return data.Entries, nil
+}
+
+// save atomically replaces the journal with the given transactions.
+func (j *journal) save(entries []*journalEntry) error {
+	j.l.Lock()
+	defer j.l.Unlock()
+
+	data := journalData{
+		Versioned: cbor.NewVersioned(journalVersion),
+		Entries:   entries,
+	}
+
+	tmpPath := j.path + ".tmp"
+	if err := os.WriteFile(tmpPath, cbor.Marshal(data), 0o600); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return fmt.Errorf("failed to replace journal: %w", err)
+	}
+	return nil
+}
+
+func newJournal(dataDir string) *journal {
+	return &journal{
+		path: filepath.Join(dataDir, journalFilename),
+	}
+}
+
+// journalEntries returns the transactions that should be journaled, local transactions first,
+// followed by other transactions in descending order of priority.
+func (t *txPool) journalEntries() []*journalEntry {
+	var entries []*journalEntry
+	seen := make(map[hash.Hash]struct{})
+	addEntry := func(tx *TxQueueMeta, local bool) {
+		if _, ok := seen[tx.Hash()]; ok {
+			return
+		}
+		seen[tx.Hash()] = struct{}{}
+
+		entries = append(entries, &journalEntry{
+			Raw:       tx.Raw(),
+			Local:     local,
+			FirstSeen: tx.FirstSeen().Unix(),
+		})
+	}
+
+	// Transactions that are currently pending checks (e.g. because of rechecks).
+	var pendingMain []*TxQueueMeta
+	for _, pct := range t.checkTxQueue.peekAll() {
+		switch pct.dstQueue {
+		case t.localQueue:
+			addEntry(pct.TxQueueMeta, true)
+		case t.mainQueue:
+			pendingMain = append(pendingMain, pct.TxQueueMeta)
+		}
+	}
+	for _, tx := range t.localQueue.PeekAll() {
+		addEntry(tx, true)
+	}
+	for _, tx := range t.mainQueue.inner.getPrioritizedBatch(nil, uint32(t.cfg.MaxPoolSize)) {
+		addEntry(&tx.TxQueueMeta, false)
+	}
+	for _, tx := range pendingMain {
+		addEntry(tx, false)
+	}
+
+	return entries
+}
+
+// saveJournal journals all local and main queue transactions.
+func (t *txPool) saveJournal() {
+	if t.journal == nil {
+		return
+	}
+
+	entries := t.journalEntries()
+	if err := t.journal.save(entries); err != nil {
+		t.logger.Error("failed to save transaction journal",
+			"err", err,
+		)
+		return
+	}
+
+	t.logger.Debug("saved transaction journal",
+		"num_txs", len(entries),
+	)
+}
+
+// restoreJournal queues journaled transactions for checks, skipping any expired transactions and
+// respecting the maximum pool size.
+func (t *txPool) restoreJournal() {
+	if t.journal == nil {
+		return
+	}
+
+	entries, err := t.journal.load()
+	if err != nil {
+		t.logger.Error("failed to load transaction journal",
+			"err", err,
+		)
+		return
+	}
+
+	var restored, expired, dropped int
+	now := time.Now()
+	for _, entry := range entries {
+		firstSeen := time.Unix(entry.FirstSeen, 0)
+		if t.cfg.JournalMaxAge > 0 && now.Sub(firstSeen) > t.cfg.JournalMaxAge {
+			expired++
+			continue
+		}
+		if uint64(restored) >= t.cfg.MaxPoolSize {
+			dropped++
+			continue
+		}
+
+		pct := &PendingCheckTransaction{
+			TxQueueMeta: &TxQueueMeta{
+				raw:       entry.Raw,
+				hash:      hash.NewFromBytes(entry.Raw),
+				firstSeen: firstSeen,
+			},
+		}
+		switch entry.Local {
+		case true:
+			pct.dstQueue = t.localQueue
+		case false:
+			pct.dstQueue = t.mainQueue
+		}
+		if err = t.addToCheckQueue(pct); err != nil {
+			dropped++
+			continue
+		}
+		restored++
+	}
+
+	t.logger.Info("restored transactions from journal",
+		"num_txs", restored,
+		"expired_txs", expired,
+		"dropped_txs", dropped,
+	)
+}
+
+func (t *txPool) journalWorker() {
+	if t.journal == nil {
+		return
+	}
+
+	ticker := time.NewTicker(t.cfg.JournalInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		t.saveJournal()
+	}
+}
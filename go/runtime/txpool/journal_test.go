@@ -0,0 +1,117 @@
+package txpool
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+)
+
+func TestJournal(t *testing.T) {
+	require := require.New(t)
+
+	j := newJournal(t.TempDir())
+
+	entries, err := j.load()
+	require.NoError(err, "load should not fail when the journal does not exist")
+	require.Empty(entries)
+
+	expected := []*journalEntry{
+		{Raw: []byte("local tx"), Local: true, FirstSeen: 42},
+		{Raw: []byte("main tx"), FirstSeen: 43},
+	}
+	err = j.save(expected)
+	require.NoError(err, "save")
+
+	entries, err = j.load()
+	require.NoError(err, "load")
+	require.EqualValues(expected, entries)
+}
+
+func TestJournalRestore(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Config{
+		MaxPoolSize:          4,
+		MaxLastSeenCacheSize: 100,
+		MaxCheckTxBatchSize:  10,
+		JournalInterval:      time.Minute,
+		JournalMaxAge:        time.Hour,
+	}
+	dataDir := t.TempDir()
+
+	tp := New(common.Namespace{}, cfg, nil, nil, nil, dataDir).(*txPool)
+
+	// Populate the queues.
+	err := tp.localQueue.OfferChecked(&TxQueueMeta{
+		raw:       []byte("local tx"),
+		hash:      hash.NewFromBytes([]byte("local tx")),
+		firstSeen: time.Now(),
+	}, nil)
+	require.NoError(err, "OfferChecked")
+	for i := uint64(0); i < 3; i++ {
+		raw := []byte(fmt.Sprintf("main tx %d", i))
+		err = tp.mainQueue.OfferChecked(&TxQueueMeta{
+			raw:       raw,
+			hash:      hash.NewFromBytes(raw),
+			firstSeen: time.Now(),
+		}, &protocol.CheckTxMetadata{Priority: i})
+		require.NoError(err, "OfferChecked")
+	}
+	err = tp.checkTxQueue.add(&PendingCheckTransaction{
+		TxQueueMeta: &TxQueueMeta{
+			raw:       []byte("pending tx"),
+			hash:      hash.NewFromBytes([]byte("pending tx")),
+			firstSeen: time.Now(),
+		},
+		dstQueue: tp.mainQueue,
+	})
+	require.NoError(err, "add")
+	err = tp.checkTxQueue.add(&PendingCheckTransaction{
+		TxQueueMeta: &TxQueueMeta{
+			raw:  []byte("discarded tx"),
+			hash: hash.NewFromBytes([]byte("discarded tx")),
+		},
+	})
+	require.NoError(err, "add")
+
+	// Journal transactions.
+	entries := tp.journalEntries()
+	require.Len(entries, 5, "discarded transactions should not be journaled")
+	require.EqualValues("local tx", entries[0].Raw, "local transactions should be first")
+	require.True(entries[0].Local)
+	require.EqualValues("main tx 2", entries[1].Raw, "main queue transactions should be ordered by priority")
+	require.EqualValues("pending tx", entries[4].Raw)
+	tp.saveJournal()
+
+	// Add an expired transaction to the journal.
+	entries = append([]*journalEntry{{
+		Raw:       []byte("expired tx"),
+		FirstSeen: time.Now().Add(-2 * time.Hour).Unix(),
+	}}, entries...)
+	err = tp.journal.save(entries)
+	require.NoError(err, "save")
+
+	// Restore transactions into a new pool.
+	tp = New(common.Namespace{}, cfg, nil, nil, nil, dataDir).(*txPool)
+	tp.restoreJournal()
+
+	pcts := tp.checkTxQueue.peekAll()
+	require.Len(pcts, 4, "restored transactions should respect the maximum pool size")
+	require.Equal(tp.localQueue, pcts[0].dstQueue)
+	require.EqualValues("local tx", pcts[0].Raw(), "expired transactions should not be restored")
+	for _, pct := range pcts[1:] {
+		require.Equal(tp.mainQueue, pct.dstQueue)
+		require.NotEqualValues("expired tx", pct.Raw(), "expired transactions should not be restored")
+	}
+
+	// Journal should be disabled without a data directory.
+	tp = New(common.Namespace{}, cfg, nil, nil, nil, "").(*txPool)
+	require.Nil(tp.journal)
+}
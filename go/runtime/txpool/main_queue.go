@@ -78,9 +78,9 @@ type mainQueue struct {
 	inner *scheduleQueue
 }
 
-func newMainQueue(capacity int) *mainQueue {
+func newMainQueue(capacity int, minBumpPercent uint64) *mainQueue {
 	return &mainQueue{
-		inner: newScheduleQueue(capacity),
+		inner: newScheduleQueue(capacity, minBumpPercent),
 	}
 }
 
@@ -134,6 +134,13 @@ func (mq *mainQueue) TakeAll() []*TxQueueMeta {
 }
 
 func (mq *mainQueue) OfferChecked(tx *TxQueueMeta, meta *protocol.CheckTxMetadata) error {
+	_, _, err := mq.offerChecked(tx, meta)
+	return err
+}
+
+// offerChecked adds a checked transaction and returns the transaction that it replaced and the
+// transaction that was evicted to make room for it (if any).
+func (mq *mainQueue) offerChecked(tx *TxQueueMeta, meta *protocol.CheckTxMetadata) (*MainQueueTransaction, *MainQueueTransaction, error) {
 	txMeta := newTransaction(*tx)
 	txMeta.setChecked(meta)
 
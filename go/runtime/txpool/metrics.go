@@ -49,6 +49,27 @@ var (
 		},
 		[]string{"runtime"},
 	)
+	replacedTransactions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_txpool_replaced_transactions",
+			Help: "Number of transactions replaced by a higher priority transaction from the same sender.",
+		},
+		[]string{"runtime"},
+	)
+	rejectedReplacementTransactions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_txpool_rejected_replacement_transactions",
+			Help: "Number of rejected replacement transactions (insufficient priority bump).",
+		},
+		[]string{"runtime"},
+	)
+	droppedTransactions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_txpool_dropped_transactions",
+			Help: "Number of transactions dropped due to per-sender or per-peer rate limits.",
+		},
+		[]string{"runtime", "reason"},
+	)
 	txpoolCollectors = []prometheus.Collector{
 		pendingCheckSize,
 		mainQueueSize,
@@ -56,6 +77,9 @@ var (
 		rimQueueSize,
 		rejectedTransactions,
 		acceptedTransactions,
+		replacedTransactions,
+		rejectedReplacementTransactions,
+		droppedTransactions,
 	}
 
 	metricsOnce sync.Once
@@ -67,6 +91,13 @@ func (t *txPool) getMetricLabels() prometheus.Labels {
 	}
 }
 
+func (t *txPool) getDroppedMetricLabels(reason string) prometheus.Labels {
+	return prometheus.Labels{
+		"runtime": t.runtimeID.String(),
+		"reason":  reason,
+	}
+}
+
 func initMetrics() {
 	metricsOnce.Do(func() {
 		prometheus.MustRegister(txpoolCollectors...)
@@ -16,6 +16,8 @@ type TxQueueMeta struct {
 	// receiving from txSync) leave this in its default value. Transactions from those sources, however, only move
 	// through a limited area in the tx pool.
 	firstSeen time.Time
+	// recheckCount is the number of times the transaction has been rechecked.
+	recheckCount uint64
 }
 
 // Raw returns the raw transaction data.
@@ -38,6 +40,11 @@ func (t *TxQueueMeta) FirstSeen() time.Time {
 	return t.firstSeen
 }
 
+// RecheckCount returns the number of times the transaction has been rechecked.
+func (t *TxQueueMeta) RecheckCount() uint64 {
+	return t.recheckCount
+}
+
 // UsableTransactionSource is a place to retrieve txs that are "good enough." "Good enough" variously means CheckTx'd,
 // came from roothash incoming message, or came from our own node.
 type UsableTransactionSource interface {
@@ -0,0 +1,93 @@
+package txpool
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cache/lru"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+)
+
+var (
+	// ErrSenderRateLimited is the error returned when a sender exceeds its transaction quota.
+	ErrSenderRateLimited = errors.New("txpool: sender rate limit exceeded")
+	// ErrPeerRateLimited is the error returned when a peer exceeds its transaction rate limit.
+	ErrPeerRateLimited = errors.New("txpool: peer rate limit exceeded")
+)
+
+// Reasons used in dropped transaction metrics.
+const (
+	dropReasonSenderRateLimit = "sender_rate_limit"
+	dropReasonPeerRateLimit   = "peer_rate_limit"
+)
+
+// rateLimiterMaxKeys is the maximum number of keys (senders or peers) tracked by a rate limiter.
+// When exceeded, the least recently used keys are forgotten which resets their quotas.
+const rateLimiterMaxKeys = 65536
+
+// tokenBucket is the per-key rate limiter state.
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// rateLimiter is a keyed token bucket rate limiter.
+type rateLimiter struct {
+	l sync.Mutex
+
+	rate  float64
+	burst float64
+
+	buckets *lru.Cache
+
+	// nowFn returns the current time and can be overridden in tests.
+	nowFn func() time.Time
+}
+
+// allow returns true iff n tokens are available for the given key, consuming them if so.
+//
+// A nil rate limiter allows everything.
+func (rl *rateLimiter) allow(key string, n int) bool {
+	if rl == nil {
+		return true
+	}
+
+	rl.l.Lock()
+	defer rl.l.Unlock()
+
+	now := rl.nowFn()
+	var b *tokenBucket
+	if v, ok := rl.buckets.Get(key); ok {
+		b = v.(*tokenBucket)
+		b.tokens = min(rl.burst, b.tokens+now.Sub(b.updated).Seconds()*rl.rate)
+		b.updated = now
+	} else {
+		b = &tokenBucket{
+			tokens:  rl.burst,
+			updated: now,
+		}
+		// Put cannot fail as the capacity is not in bytes.
+		_ = rl.buckets.Put(key, b)
+	}
+
+	if b.tokens < float64(n) {
+		return false
+	}
+	b.tokens -= float64(n)
+	return true
+}
+
+// newRateLimiter creates a new rate limiter. In case the rate limit is disabled, nil is returned.
+func newRateLimiter(cfg config.RateLimit) *rateLimiter {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	return &rateLimiter{
+		rate:    cfg.Rate,
+		burst:   float64(cfg.Burst),
+		buckets: lru.New(lru.Capacity(rateLimiterMaxKeys, false)),
+		nowFn:   time.Now,
+	}
+}
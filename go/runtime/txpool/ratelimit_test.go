@@ -0,0 +1,76 @@
+package txpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+)
+
+func TestRateLimiter(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(newRateLimiter(config.RateLimit{}), "disabled rate limiter should be nil")
+	var disabled *rateLimiter
+	require.True(disabled.allow("peer", 1000), "disabled rate limiter should allow everything")
+
+	rl := newRateLimiter(config.RateLimit{Rate: 2, Burst: 4})
+	now := time.Now()
+	rl.nowFn = func() time.Time { return now }
+
+	// The full burst should be available initially.
+	require.True(rl.allow("a", 3))
+	require.True(rl.allow("a", 1))
+	require.False(rl.allow("a", 1), "quota should be exhausted")
+
+	// Other keys should have separate quotas.
+	require.True(rl.allow("b", 4))
+	require.False(rl.allow("b", 1), "quota should be exhausted")
+
+	// Tokens should be replenished over time.
+	now = now.Add(500 * time.Millisecond)
+	require.True(rl.allow("a", 1))
+	require.False(rl.allow("a", 1), "quota should be exhausted")
+
+	// Tokens should not be replenished over the burst.
+	now = now.Add(time.Hour)
+	require.False(rl.allow("a", 5), "requests over the burst should never be allowed")
+	require.True(rl.allow("a", 4))
+}
+
+func TestRateLimits(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Config{
+		MaxPoolSize:          10,
+		MaxLastSeenCacheSize: 100,
+		MaxCheckTxBatchSize:  10,
+		SenderRateLimit:      config.RateLimit{Rate: 0.001, Burst: 1},
+		PeerRateLimit:        config.RateLimit{Rate: 0.001, Burst: 2},
+	}
+	tp := New(common.Namespace{}, cfg, nil, nil, nil, "").(*txPool)
+
+	// Per-peer rate limits.
+	require.True(tp.AllowPeerTxs("peer", 2))
+	require.False(tp.AllowPeerTxs("peer", 1), "peer should be rate limited")
+	require.True(tp.AllowPeerTxs("another peer", 1))
+
+	// Per-sender quotas.
+	newPct := func(raw string, flags txCheckFlags) *PendingCheckTransaction {
+		return &PendingCheckTransaction{
+			TxQueueMeta: newTestTxQueueMeta(raw),
+			flags:       flags,
+			dstQueue:    tp.mainQueue,
+		}
+	}
+	meta := &protocol.CheckTxMetadata{Sender: []byte("sender")}
+	require.True(tp.allowSender(newPct("tx 1", 0), meta))
+	require.False(tp.allowSender(newPct("tx 2", 0), meta), "sender should be rate limited")
+	require.True(tp.allowSender(newPct("tx 1", txCheckRecheck), meta), "rechecks should not be rate limited")
+	require.True(tp.allowSender(newPct("tx 3", 0), &protocol.CheckTxMetadata{}), "unknown senders should not be rate limited")
+	require.True(tp.allowSender(newPct("tx 4", 0), nil), "unknown senders should not be rate limited")
+}
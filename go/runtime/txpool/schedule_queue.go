@@ -2,6 +2,7 @@ package txpool
 
 import (
 	"errors"
+	"math/bits"
 	"sync"
 
 	"github.com/google/btree"
@@ -35,6 +36,25 @@ func priorityLessFunc(tx, tx2 *MainQueueTransaction) bool {
 	return tx.FirstSeen().After(tx2.FirstSeen())
 }
 
+// isSufficientPriorityBump returns true iff the new priority is higher than the old priority by
+// at least the given percentage.
+func isSufficientPriorityBump(oldPriority, newPriority, bumpPercent uint64) bool {
+	if newPriority <= oldPriority {
+		return false
+	}
+
+	// Compare newPriority * 100 >= oldPriority * (100 + bumpPercent) using 128-bit arithmetic.
+	hiNew, loNew := bits.Mul64(newPriority, 100)
+	hiOld, loOld := bits.Mul64(oldPriority, 100)
+	hiBump, loBump := bits.Mul64(oldPriority, bumpPercent)
+	loOld, carry := bits.Add64(loOld, loBump, 0)
+	hiOld, carry = bits.Add64(hiOld, hiBump, carry)
+	if carry != 0 {
+		return false
+	}
+	return hiNew > hiOld || (hiNew == hiOld && loNew >= loOld)
+}
+
 type scheduleQueue struct {
 	l sync.Mutex
 
@@ -42,18 +62,27 @@ type scheduleQueue struct {
 	bySender   map[string]*MainQueueTransaction
 	byPriority *btree.BTreeG[*MainQueueTransaction]
 
-	capacity int
+	capacity       int
+	minBumpPercent uint64
 }
 
-func (sq *scheduleQueue) add(tx *MainQueueTransaction) error {
+// add adds a transaction to the queue. In case the transaction replaced an existing transaction
+// from the same sender which was still valid, the replaced transaction is returned. In case a
+// lower priority transaction had to be evicted to make room, the evicted transaction is returned.
+func (sq *scheduleQueue) add(tx *MainQueueTransaction) (*MainQueueTransaction, *MainQueueTransaction, error) {
 	sq.l.Lock()
 	defer sq.l.Unlock()
 
-	// If a transaction from the same sender already exists, we accept a new transaction only if it
-	// has a higher priority or if the old transaction is no longer valid based on sequence numbers.
+	// If a transaction from the same sender already exists, we accept a new transaction only if its
+	// priority is sufficiently higher or if the old transaction is no longer valid based on sequence
+	// numbers.
+	var replaced *MainQueueTransaction
 	if etx, exists := sq.bySender[tx.sender]; exists {
-		if etx.senderSeq >= tx.senderStateSeq && tx.priority <= etx.priority {
-			return ErrReplacementTxPriorityTooLow
+		if etx.senderSeq >= tx.senderStateSeq {
+			if !isSufficientPriorityBump(etx.priority, tx.priority, sq.minBumpPercent) {
+				return nil, nil, ErrReplacementTxPriorityTooLow
+			}
+			replaced = etx
 		}
 
 		// Remove any existing transaction.
@@ -61,20 +90,22 @@ func (sq *scheduleQueue) add(tx *MainQueueTransaction) error {
 	}
 
 	// If the queue is full, we accept a new transaction only if it has a higher priority.
+	var evicted *MainQueueTransaction
 	if len(sq.all) >= sq.capacity {
 		// Attempt eviction.
 		etx, _ := sq.byPriority.Min()
 		if tx.priority <= etx.priority {
-			return ErrQueueFull
+			return nil, nil, ErrQueueFull
 		}
 		sq.removeLocked(etx)
+		evicted = etx
 	}
 
 	sq.all[tx.Hash()] = tx
 	sq.bySender[tx.sender] = tx
 	sq.byPriority.ReplaceOrInsert(tx)
 
-	return nil
+	return replaced, evicted, nil
 }
 
 func (sq *scheduleQueue) removeLocked(tx *MainQueueTransaction) {
@@ -176,11 +207,12 @@ func (sq *scheduleQueue) clear() {
 	sq.byPriority.Clear(true)
 }
 
-func newScheduleQueue(capacity int) *scheduleQueue {
+func newScheduleQueue(capacity int, minBumpPercent uint64) *scheduleQueue {
 	return &scheduleQueue{
-		all:        make(map[hash.Hash]*MainQueueTransaction),
-		bySender:   make(map[string]*MainQueueTransaction),
-		byPriority: btree.NewG[*MainQueueTransaction](2, priorityLessFunc),
-		capacity:   capacity,
+		all:            make(map[hash.Hash]*MainQueueTransaction),
+		bySender:       make(map[string]*MainQueueTransaction),
+		byPriority:     btree.NewG[*MainQueueTransaction](2, priorityLessFunc),
+		capacity:       capacity,
+		minBumpPercent: minBumpPercent,
 	}
 }
@@ -2,6 +2,7 @@ package txpool
 
 import (
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -26,25 +27,25 @@ func newTestTransaction(data []byte, priority uint64) *MainQueueTransaction {
 func TestScheduleQueueBasic(t *testing.T) {
 	require := require.New(t)
 
-	queue := newScheduleQueue(51)
+	queue := newScheduleQueue(51, 0)
 
 	tx := newTestTransaction([]byte("hello world"), 0)
 
-	err := queue.add(tx)
+	_, _, err := queue.add(tx)
 	require.NoError(err, "Add")
 
-	err = queue.add(tx)
+	_, _, err = queue.add(tx)
 	require.Error(err, "Add error on duplicates")
 
 	// Add some more calls.
 	for i := 0; i < 50; i++ {
-		err = queue.add(
+		_, _, err = queue.add(
 			newTestTransaction([]byte(fmt.Sprintf("call %d", i)), 0),
 		)
 		require.NoError(err, "Add")
 	}
 
-	err = queue.add(newTestTransaction([]byte("another call"), 0))
+	_, _, err = queue.add(newTestTransaction([]byte("another call"), 0))
 	require.Error(err, "Add error on queue full")
 
 	require.EqualValues(51, queue.size(), "Size")
@@ -68,7 +69,7 @@ func TestScheduleQueueBasic(t *testing.T) {
 func TestScheduleQueueRemoveTxBatch(t *testing.T) {
 	require := require.New(t)
 
-	queue := newScheduleQueue(51)
+	queue := newScheduleQueue(51, 0)
 	queue.remove([]hash.Hash{})
 
 	for _, tx := range []*MainQueueTransaction{
@@ -77,7 +78,8 @@ func TestScheduleQueueRemoveTxBatch(t *testing.T) {
 		newTestTransaction([]byte("two"), 0),
 		newTestTransaction([]byte("three"), 0),
 	} {
-		require.NoError(queue.add(tx), "Add")
+		_, _, err := queue.add(tx)
+		require.NoError(err, "Add")
 	}
 	require.EqualValues(4, queue.size(), "Size")
 
@@ -99,7 +101,7 @@ func TestScheduleQueueRemoveTxBatch(t *testing.T) {
 func TestScheduleQueuePriority(t *testing.T) {
 	require := require.New(t)
 
-	queue := newScheduleQueue(3)
+	queue := newScheduleQueue(3, 0)
 
 	txs := []*MainQueueTransaction{
 		newTestTransaction(
@@ -116,7 +118,8 @@ func TestScheduleQueuePriority(t *testing.T) {
 		),
 	}
 	for _, tx := range txs {
-		require.NoError(queue.add(tx), "Add")
+		_, _, err := queue.add(tx)
+		require.NoError(err, "Add")
 	}
 
 	batch := queue.getPrioritizedBatch(nil, 2)
@@ -151,8 +154,9 @@ func TestScheduleQueuePriority(t *testing.T) {
 		[]byte("hello world 6"),
 		6,
 	)
-	err := queue.add(highTx)
+	_, evicted, err := queue.add(highTx)
 	require.NoError(err, "higher priority transaction should still get queued")
+	require.Equal(txs[1], evicted, "lowest priority transaction should be evicted")
 
 	batch = queue.getPrioritizedBatch(nil, 3)
 	require.Len(batch, 3, "three transactions should be returned")
@@ -171,7 +175,7 @@ func TestScheduleQueuePriority(t *testing.T) {
 		[]byte("hello world 3"),
 		3,
 	)
-	err = queue.add(lowTx)
+	_, _, err = queue.add(lowTx)
 	require.Error(err, "lower priority transaction should not get queued")
 	require.Equal(ErrQueueFull, err)
 }
@@ -184,12 +188,12 @@ func TestScheduleQueueSender(t *testing.T) {
 		sender2 = "sender2"
 	)
 
-	queue := newScheduleQueue(10)
+	queue := newScheduleQueue(10, 0)
 
 	tx := newTestTransaction([]byte("hello world s1 p0"), 0)
 	tx.sender = sender1
 
-	err := queue.add(tx)
+	_, _, err := queue.add(tx)
 	require.NoError(err, "Add")
 
 	tx = newTestTransaction([]byte("hello world s2 p0"), 0)
@@ -198,7 +202,7 @@ func TestScheduleQueueSender(t *testing.T) {
 	tx = newTestTransaction([]byte("hello worldd s1 p0"), 0)
 	tx.sender = sender1
 
-	err = queue.add(tx)
+	_, _, err = queue.add(tx)
 	require.Error(err, "Add")
 	require.Equal(ErrReplacementTxPriorityTooLow, err)
 	require.Equal(1, queue.size())
@@ -206,10 +210,80 @@ func TestScheduleQueueSender(t *testing.T) {
 	tx = newTestTransaction([]byte("hello world 2"), 10)
 	tx.sender = sender1
 
-	err = queue.add(tx)
+	_, _, err = queue.add(tx)
 	require.NoError(err, "Add")
 	require.Equal(1, queue.size())
 
 	queue.remove([]hash.Hash{tx.Hash()})
 	require.Equal(0, queue.size())
 }
+
+func TestScheduleQueueReplaceByFee(t *testing.T) {
+	require := require.New(t)
+
+	const sender = "sender"
+
+	newSenderTx := func(data string, priority, seq, stateSeq uint64) *MainQueueTransaction {
+		tx := newTestTransaction([]byte(data), priority)
+		tx.sender = sender
+		tx.senderSeq = seq
+		tx.senderStateSeq = stateSeq
+		return tx
+	}
+
+	queue := newScheduleQueue(10, 10)
+
+	tx1 := newSenderTx("tx1", 100, 5, 5)
+	replaced, _, err := queue.add(tx1)
+	require.NoError(err, "Add")
+	require.Nil(replaced)
+
+	// Replacement with an insufficient priority bump should be rejected.
+	_, _, err = queue.add(newSenderTx("tx2", 109, 5, 5))
+	require.ErrorIs(err, ErrReplacementTxPriorityTooLow)
+	require.Equal(1, queue.size())
+
+	// Replacement with a sufficient priority bump should evict the existing transaction.
+	tx3 := newSenderTx("tx3", 110, 5, 5)
+	replaced, _, err = queue.add(tx3)
+	require.NoError(err, "Add")
+	require.Equal(tx1, replaced)
+	require.Equal(1, queue.size())
+	txs, missing := queue.getKnownBatch([]hash.Hash{tx1.Hash(), tx3.Hash()})
+	require.Nil(txs[0], "replaced transaction should be removed")
+	require.Equal(tx3, txs[1])
+	require.Len(missing, 1)
+
+	// Transactions that are no longer valid can be replaced regardless of priority.
+	tx4 := newSenderTx("tx4", 1, 6, 6)
+	replaced, _, err = queue.add(tx4)
+	require.NoError(err, "Add")
+	require.Nil(replaced, "stale transactions should not be reported as replaced")
+	require.Equal(1, queue.size())
+}
+
+func TestIsSufficientPriorityBump(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		oldPriority uint64
+		newPriority uint64
+		bumpPercent uint64
+		sufficient  bool
+	}{
+		{0, 0, 0, false},
+		{0, 1, 0, true},
+		{10, 10, 0, false},
+		{10, 11, 0, true},
+		{100, 109, 10, false},
+		{100, 110, 10, true},
+		{5, 6, 10, true},
+		{math.MaxUint64 - 1, math.MaxUint64, 0, true},
+		{math.MaxUint64 - 1, math.MaxUint64, 10, false},
+		{math.MaxUint64, math.MaxUint64, math.MaxUint64, false},
+		{1, math.MaxUint64, math.MaxUint64, true},
+	} {
+		require.Equal(tc.sufficient, isSufficientPriorityBump(tc.oldPriority, tc.newPriority, tc.bumpPercent),
+			"old: %d new: %d bump: %d", tc.oldPriority, tc.newPriority, tc.bumpPercent)
+	}
+}
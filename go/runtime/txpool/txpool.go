@@ -132,6 +132,21 @@ type TransactionPool interface {
 
 	// GetTxs returns all transactions currently queued in the transaction pool.
 	GetTxs() []*TxQueueMeta
+
+	// AllowPeerTxs checks whether the given number of transactions received from or requested by
+	// the given remote peer is within the per-peer rate limit and consumes the peer's quota if so.
+	AllowPeerTxs(peer string, n int) bool
+
+	// GetEntries returns all transactions currently in the transaction pool, including the ones
+	// pending checks, together with their queuing metadata.
+	GetEntries() []*Entry
+
+	// WatchEvents subscribes to notifications about transactions being admitted into, evicted
+	// from or rejected by the transaction pool.
+	//
+	// Events are buffered per subscriber and the oldest events are dropped in case the
+	// subscriber is not able to keep up.
+	WatchEvents() (<-chan *Event, pubsub.ClosableSubscription)
 }
 
 // TransactionPublisher is an interface representing a mechanism for publishing transactions.
@@ -152,11 +167,18 @@ type txPool struct {
 	quitCh chan struct{}
 	initCh chan struct{}
 
+	startOnce sync.Once
+	startedCh chan struct{}
+
 	runtimeID   common.Namespace
 	cfg         config.Config
 	runtime     host.RichRuntime
 	txPublisher TransactionPublisher
 	history     history.History
+	journal     *journal
+
+	senderLimiter *rateLimiter
+	peerLimiter   *rateLimiter
 
 	// seenCache maps from transaction hashes to time.Time that specifies when the transaction was
 	// last published.
@@ -167,6 +189,8 @@ type txPool struct {
 	checkTxNotifier *pubsub.Broker
 	recheckTxCh     *channels.RingChannel
 
+	eventNotifier *pubsub.Broker
+
 	drainLock sync.Mutex
 
 	usableSources        []UsableTransactionSource
@@ -188,14 +212,28 @@ type txPool struct {
 }
 
 func (t *txPool) Start() error {
+	t.restoreJournal()
+
 	go t.checkWorker()
 	go t.republishWorker()
 	go t.recheckWorker()
+	go t.journalWorker()
+
+	t.startOnce.Do(func() { close(t.startedCh) })
+
 	return nil
 }
 
 func (t *txPool) Stop() {
 	close(t.stopCh)
+
+	// Only journal transactions when the pool has been started as otherwise any previously
+	// journaled transactions would be lost.
+	select {
+	case <-t.startedCh:
+		t.saveJournal()
+	default:
+	}
 }
 
 func (t *txPool) Quit() <-chan struct{} {
@@ -234,6 +272,7 @@ func (t *txPool) submitTx(rawTx []byte, meta *TransactionMeta, notifyCh chan *pr
 	// Skip recently seen transactions.
 	if _, seen := t.seenCache.Peek(tx.Hash()); seen {
 		t.logger.Debug("ignoring already seen transaction", "tx_hash", tx.Hash())
+		t.emitEvent(EventRejected, tx.Hash(), "", reasonDuplicate)
 		return fmt.Errorf("duplicate transaction")
 	}
 
@@ -264,6 +303,7 @@ func (t *txPool) addToCheckQueue(pct *PendingCheckTransaction) error {
 			"tx_hash", pct.Hash(),
 			"err", err,
 		)
+		t.emitCheckFailure(pct, err.Error())
 		return err
 	}
 
@@ -345,16 +385,11 @@ func (t *txPool) RejectTxs(hashes []hash.Hash) {
 		t.seenCache.Remove(h)
 	}
 
-	t.HandleTxsUsed(hashes)
+	t.removeTxs(hashes, reasonBlockRejected)
 }
 
 func (t *txPool) HandleTxsUsed(hashes []hash.Hash) {
-	for _, q := range t.usableSources {
-		q.HandleTxsUsed(hashes)
-	}
-
-	mainQueueSize.With(t.getMetricLabels()).Set(float64(t.mainQueue.inner.size()))
-	localQueueSize.With(t.getMetricLabels()).Set(float64(t.localQueue.size()))
+	t.removeTxs(hashes, reasonIncludedInBlock)
 }
 
 func (t *txPool) GetKnownBatch(batch []hash.Hash) ([]*TxQueueMeta, map[hash.Hash]int) {
@@ -436,6 +471,18 @@ func (t *txPool) GetTxs() []*TxQueueMeta {
 	return txs
 }
 
+func (t *txPool) AllowPeerTxs(peer string, n int) bool {
+	if !t.peerLimiter.allow(peer, n) {
+		droppedTransactions.With(t.getDroppedMetricLabels(dropReasonPeerRateLimit)).Add(float64(n))
+		t.logger.Debug("peer rate limit exceeded",
+			"peer", peer,
+			"num_txs", n,
+		)
+		return false
+	}
+	return true
+}
+
 func (t *txPool) getCurrentBlockInfo() (*runtime.BlockInfo, time.Time, error) {
 	t.blockInfoLock.Lock()
 	defer t.blockInfoLock.Unlock()
@@ -546,6 +593,11 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 			t.seenCache.Remove(batch[i].Hash())
 
 			// We won't be sending this tx on to its destination queue.
+			reason := reasonCheckFailed
+			if batch[i].flags.isRecheck() {
+				reason = reasonRecheckFailed
+			}
+			t.emitCheckFailure(batch[i], fmt.Sprintf("%s: %s", reason, res.Error))
 			notifySubmitter(i)
 			continue
 		}
@@ -581,7 +633,22 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 
 	// Queue checked transactions for scheduling.
 	for i, pct := range goodPcts {
-		if err = pct.dstQueue.OfferChecked(pct.TxQueueMeta, results[batchIndices[i]].Meta); err != nil {
+		var replaced, evicted *MainQueueTransaction
+		switch pct.dstQueue {
+		case t.mainQueue:
+			meta := results[batchIndices[i]].Meta
+			if !t.allowSender(pct, meta) {
+				err = ErrSenderRateLimited
+				break
+			}
+			replaced, evicted, err = t.mainQueue.offerChecked(pct.TxQueueMeta, meta)
+		default:
+			err = pct.dstQueue.OfferChecked(pct.TxQueueMeta, results[batchIndices[i]].Meta)
+		}
+		if err != nil {
+			if errors.Is(err, ErrReplacementTxPriorityTooLow) {
+				rejectedReplacementTransactions.With(t.getMetricLabels()).Inc()
+			}
 			t.logger.Error("unable to queue transaction for scheduling",
 				"err", err,
 				"tx_hash", pct.Hash(),
@@ -593,6 +660,7 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 				Code:    1,
 				Message: err.Error(),
 			}
+			t.emitCheckFailure(pct, err.Error())
 			notifySubmitter(batchIndices[i])
 			continue
 		}
@@ -600,7 +668,28 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 		// Notify submitter of success.
 		notifySubmitter(batchIndices[i])
 
+		if replaced != nil {
+			replacedTransactions.With(t.getMetricLabels()).Inc()
+			t.logger.Debug("transaction replaced",
+				"tx_hash", pct.Hash(),
+				"replaced_tx_hash", replaced.Hash(),
+				"replaced_priority", replaced.Priority(),
+				"sender_seq", replaced.SenderSeq(),
+			)
+			t.emitEvent(EventEvicted, replaced.Hash(), QueueMain, reasonReplaced)
+		}
+		if evicted != nil {
+			t.logger.Debug("transaction evicted",
+				"tx_hash", evicted.Hash(),
+				"evicted_by_tx_hash", pct.Hash(),
+				"evicted_priority", evicted.Priority(),
+			)
+			t.emitEvent(EventEvicted, evicted.Hash(), QueueMain, reasonQueueFull)
+		}
+
 		if !pct.flags.isRecheck() {
+			t.emitEvent(EventAdmitted, pct.Hash(), t.queueKind(pct.dstQueue), "")
+
 			// Mark new transactions as never having been published. The republish worker will
 			// publish these immediately.
 			publishTime := time.Time{}
@@ -634,6 +723,19 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 	return nil
 }
 
+// allowSender checks whether a transaction is within its sender's quota. Only new transactions
+// with a sender reported by the runtime are subject to the quota.
+func (t *txPool) allowSender(pct *PendingCheckTransaction, meta *protocol.CheckTxMetadata) bool {
+	if pct.flags.isRecheck() || meta == nil || len(meta.Sender) == 0 {
+		return true
+	}
+	if !t.senderLimiter.allow(string(meta.Sender), 1) {
+		droppedTransactions.With(t.getDroppedMetricLabels(dropReasonSenderRateLimit)).Inc()
+		return false
+	}
+	return true
+}
+
 func (t *txPool) ensureInitialized() error {
 	select {
 	case <-t.stopCh:
@@ -835,6 +937,7 @@ func (t *txPool) recheck() {
 	var results []chan *protocol.CheckTxResult
 	for _, q := range t.recheckableStores {
 		for _, tx := range q.TakeAll() {
+			tx.recheckCount++
 			notifyCh := make(chan *protocol.CheckTxResult, 1)
 			pcts = append(pcts, &PendingCheckTransaction{
 				TxQueueMeta: tx,
@@ -875,12 +978,16 @@ func (t *txPool) recheck() {
 }
 
 // New creates a new transaction pool instance.
+//
+// In case dataDir is non-empty and the journal is enabled in the configuration, local and main
+// queue transactions are journaled into the given directory.
 func New(
 	runtimeID common.Namespace,
 	cfg config.Config,
 	runtime host.Runtime,
 	history history.History,
 	txPublisher TransactionPublisher,
+	dataDir string,
 ) TransactionPool {
 	initMetrics()
 
@@ -892,23 +999,33 @@ func New(
 
 	rq := newRimQueue()
 	lq := newLocalQueue()
-	mq := newMainQueue(int(cfg.MaxPoolSize))
+	mq := newMainQueue(int(cfg.MaxPoolSize), cfg.MinPriorityBumpPercent)
+
+	var jrnl *journal
+	if dataDir != "" && cfg.JournalInterval > 0 {
+		jrnl = newJournal(dataDir)
+	}
 
 	return &txPool{
 		logger:               logging.GetLogger("runtime/txpool"),
 		stopCh:               make(chan struct{}),
 		quitCh:               make(chan struct{}),
 		initCh:               make(chan struct{}),
+		startedCh:            make(chan struct{}),
 		runtimeID:            runtimeID,
 		cfg:                  cfg,
 		runtime:              host.NewRichRuntime(runtime),
 		history:              history,
 		txPublisher:          txPublisher,
+		journal:              jrnl,
+		senderLimiter:        newRateLimiter(cfg.SenderRateLimit),
+		peerLimiter:          newRateLimiter(cfg.PeerRateLimit),
 		seenCache:            seenCache,
 		checkTxQueue:         newCheckTxQueue(maxCheckTxQueueSize, int(cfg.MaxCheckTxBatchSize)),
 		checkTxCh:            channels.NewRingChannel(1),
 		checkTxNotifier:      pubsub.NewBroker(false),
 		recheckTxCh:          channels.NewRingChannel(1),
+		eventNotifier:        pubsub.NewBroker(false),
 		usableSources:        []UsableTransactionSource{rq, lq, mq},
 		recheckableStores:    []RecheckableTransactionStore{lq, mq},
 		republishableSources: []RepublishableTransactionSource{lq, mq},
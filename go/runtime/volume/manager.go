@@ -5,11 +5,14 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
@@ -21,8 +24,37 @@ const (
 	descriptorFn = "descriptor.json"
 	// volumeFn is the filename of the volume file.
 	volumeFn = "volume"
+	// snapshotsDir is the name of the directory containing volume snapshots.
+	snapshotsDir = "snapshots"
+
+	// maxSnapshots is the maximum number of snapshots per volume.
+	maxSnapshots = 16
 )
 
+// CreateOptions are options for creating volumes.
+type CreateOptions struct {
+	quota uint64
+}
+
+// NewCreateOptions creates options using default and given values.
+func NewCreateOptions(opts ...CreateOption) *CreateOptions {
+	var o CreateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o
+}
+
+// CreateOption is an option used when creating a volume.
+type CreateOption func(o *CreateOptions)
+
+// WithQuota sets the maximum size of the volume file in bytes.
+func WithQuota(quota uint64) CreateOption {
+	return func(o *CreateOptions) {
+		o.quota = quota
+	}
+}
+
 // Manager is a volume manager.
 type Manager struct {
 	mu       sync.Mutex
@@ -155,9 +187,11 @@ func (m *Manager) loadVolumes() ([]*Volume, error) {
 		)
 
 		volumes = append(volumes, &Volume{
-			ID:     dsc.ID,
-			Path:   filepath.Join(dir, volumeFn),
-			Labels: dsc.Labels,
+			ID:        dsc.ID,
+			Path:      filepath.Join(dir, volumeFn),
+			Labels:    dsc.Labels,
+			Quota:     dsc.Quota,
+			snapshots: dsc.Snapshots,
 		})
 	}
 
@@ -194,18 +228,18 @@ func (m *Manager) registerVolumeLocked(volume *Volume) error {
 }
 
 // Create creates and registers a new volume.
-func (m *Manager) Create(labels map[string]string) (*Volume, error) {
+func (m *Manager) Create(labels map[string]string, opts ...CreateOption) (*Volume, error) {
 	if err := m.ensureInitialized(); err != nil {
 		return nil, err
 	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	return m.createVolumeLocked(labels)
+	return m.createVolumeLocked(labels, opts...)
 }
 
-func (m *Manager) createVolumeLocked(labels map[string]string) (*Volume, error) {
-	volume, err := m.createVolumeDir(labels)
+func (m *Manager) createVolumeLocked(labels map[string]string, opts ...CreateOption) (*Volume, error) {
+	volume, err := m.createVolumeDir(labels, NewCreateOptions(opts...))
 	if err != nil {
 		return nil, err
 	}
@@ -216,31 +250,19 @@ func (m *Manager) createVolumeLocked(labels map[string]string) (*Volume, error)
 	return volume, nil
 }
 
-func (m *Manager) createVolumeDir(labels map[string]string) (*Volume, error) {
+func (m *Manager) createVolumeDir(labels map[string]string, options *CreateOptions) (*Volume, error) {
 	// Generate a 256-bit random byte string and use its hex representation as an ID.
-	var rawID [32]byte
-	if _, err := io.ReadFull(rand.Reader, rawID[:]); err != nil {
+	volumeID, err := generateID(32)
+	if err != nil {
 		return nil, fmt.Errorf("failed to generate volume identifier: %w", err)
 	}
 
-	volumeID := hex.EncodeToString(rawID[:])
 	volumeDir := filepath.Join(m.volumesDir, volumeID)
 	volume := &Volume{
 		ID:     volumeID,
 		Path:   filepath.Join(volumeDir, volumeFn),
 		Labels: labels,
-	}
-
-	// Prepare and write a volume descriptor.
-	dsc := &Descriptor{
-		ID:     volume.ID,
-		Labels: volume.Labels,
-	}
-
-	dscFn := filepath.Join(volumeDir, descriptorFn)
-	b, err := json.Marshal(dsc)
-	if err != nil {
-		return nil, fmt.Errorf("failed to serialize volume descriptor: %w", err)
+		Quota:  options.quota,
 	}
 
 	if _, err = os.Lstat(volumeDir); err == nil {
@@ -249,8 +271,8 @@ func (m *Manager) createVolumeDir(labels map[string]string) (*Volume, error) {
 	if err = common.Mkdir(volumeDir); err != nil {
 		return nil, fmt.Errorf("failed to create volume directory: %w", err)
 	}
-	if err = os.WriteFile(dscFn, b, 0o600); err != nil {
-		return nil, fmt.Errorf("failed to write volume descriptor: %w", err)
+	if err = m.writeDescriptor(volume); err != nil {
+		return nil, err
 	}
 
 	m.logger.Info("volume created",
@@ -282,6 +304,10 @@ func (m *Manager) Remove(labels map[string]string) error {
 }
 
 func (m *Manager) removeVolumeLocked(volume *Volume) error {
+	if volume.isBusy() {
+		return fmt.Errorf("%w: '%s'", ErrInUse, volume.ID)
+	}
+
 	volumeDir := filepath.Join(m.volumesDir, volume.ID)
 	if err := os.RemoveAll(volumeDir); err != nil {
 		return fmt.Errorf("failed to remove volume directory: %w", err)
@@ -346,3 +372,308 @@ func (m *Manager) Volumes(labels map[string]string) []*Volume {
 	}
 	return volumes
 }
+
+// Snapshot creates a point-in-time snapshot of the volume with the specified identifier.
+//
+// Snapshots count towards the volume quota. The volume must not be attached to a running
+// component while the snapshot is being taken.
+func (m *Manager) Snapshot(id string) (*Snapshot, error) {
+	if err := m.ensureInitialized(); err != nil {
+		return nil, err
+	}
+
+	volume, err := m.acquireVolume(id)
+	if err != nil {
+		return nil, err
+	}
+	defer volume.release()
+
+	m.mu.Lock()
+	numSnapshots := len(volume.snapshots)
+	m.mu.Unlock()
+	if numSnapshots >= maxSnapshots {
+		return nil, fmt.Errorf("volume '%s' has too many snapshots", id)
+	}
+	if err = checkSnapshotQuota(volume); err != nil {
+		return nil, err
+	}
+
+	snapshotID, err := generateID(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate snapshot identifier: %w", err)
+	}
+	snapshot := &Snapshot{
+		ID:      snapshotID,
+		Created: time.Now().UTC(),
+	}
+
+	dir := filepath.Join(m.volumesDir, volume.ID, snapshotsDir)
+	if err = common.Mkdir(dir); err != nil {
+		return nil, fmt.Errorf("failed to create snapshots directory: %w", err)
+	}
+
+	// The volume file is copied without holding the manager lock as it may be large.
+	snapshotFn := filepath.Join(dir, snapshot.ID)
+	switch err = copyFile(volume.Path, snapshotFn); {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+		snapshot.Empty = true
+	default:
+		_ = os.Remove(snapshotFn)
+		return nil, fmt.Errorf("failed to copy volume file: %w", err)
+	}
+	// The copy may use more space than the volume file in case the latter is sparse.
+	if err = volume.CheckQuota(); err != nil {
+		_ = os.Remove(snapshotFn)
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	volume.snapshots = append(volume.snapshots, snapshot)
+	if err = m.writeDescriptor(volume); err != nil {
+		volume.snapshots = volume.snapshots[:len(volume.snapshots)-1]
+		_ = os.Remove(snapshotFn)
+		return nil, err
+	}
+
+	m.logger.Info("volume snapshot created",
+		"id", volume.ID,
+		"snapshot_id", snapshot.ID,
+	)
+
+	return snapshot, nil
+}
+
+// Snapshots returns all snapshots of the volume with the specified identifier.
+func (m *Manager) Snapshots(id string) ([]*Snapshot, error) {
+	if err := m.ensureInitialized(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	volume, ok := m.volumes[id]
+	if !ok {
+		return nil, fmt.Errorf("volume '%s' not found", id)
+	}
+	return slices.Clone(volume.snapshots), nil
+}
+
+// Restore rolls back the volume with the specified identifier to the given snapshot.
+//
+// The snapshot is retained so that the volume can be restored to it again. The volume must not
+// be attached to a running component while it is being restored.
+func (m *Manager) Restore(id string, snapshotID string) error {
+	if err := m.ensureInitialized(); err != nil {
+		return err
+	}
+
+	volume, err := m.acquireVolume(id)
+	if err != nil {
+		return err
+	}
+	defer volume.release()
+
+	m.mu.Lock()
+	idx := slices.IndexFunc(volume.snapshots, func(s *Snapshot) bool { return s.ID == snapshotID })
+	var snapshot *Snapshot
+	if idx >= 0 {
+		snapshot = volume.snapshots[idx]
+	}
+	m.mu.Unlock()
+	if snapshot == nil {
+		return fmt.Errorf("snapshot '%s' of volume '%s' not found", snapshotID, id)
+	}
+
+	if snapshot.Empty {
+		if err = os.Remove(volume.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove volume file: %w", err)
+		}
+	} else {
+		// Copy into a temporary file first so that a failed restore leaves the volume intact.
+		snapshotFn := filepath.Join(m.volumesDir, volume.ID, snapshotsDir, snapshot.ID)
+		tmpFn := volume.Path + ".restore"
+		if err = copyFile(snapshotFn, tmpFn); err != nil {
+			_ = os.Remove(tmpFn)
+			return fmt.Errorf("failed to copy snapshot file: %w", err)
+		}
+		if err = os.Rename(tmpFn, volume.Path); err != nil {
+			_ = os.Remove(tmpFn)
+			return fmt.Errorf("failed to replace volume file: %w", err)
+		}
+	}
+
+	m.logger.Info("volume restored from snapshot",
+		"id", volume.ID,
+		"snapshot_id", snapshot.ID,
+	)
+
+	return nil
+}
+
+// RemoveSnapshot removes the given snapshot of the volume with the specified identifier.
+func (m *Manager) RemoveSnapshot(id string, snapshotID string) error {
+	if err := m.ensureInitialized(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	volume, ok := m.volumes[id]
+	if !ok {
+		return fmt.Errorf("volume '%s' not found", id)
+	}
+	idx := slices.IndexFunc(volume.snapshots, func(s *Snapshot) bool { return s.ID == snapshotID })
+	if idx < 0 {
+		return fmt.Errorf("snapshot '%s' of volume '%s' not found", snapshotID, id)
+	}
+	if volume.isBusy() {
+		return fmt.Errorf("%w: '%s'", ErrInUse, id)
+	}
+
+	snapshots := volume.snapshots
+	volume.snapshots = slices.Delete(slices.Clone(snapshots), idx, idx+1)
+	if err := m.writeDescriptor(volume); err != nil {
+		volume.snapshots = snapshots
+		return err
+	}
+
+	snapshotFn := filepath.Join(m.volumesDir, volume.ID, snapshotsDir, snapshotID)
+	if err := os.Remove(snapshotFn); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove snapshot file: %w", err)
+	}
+	return nil
+}
+
+// Clone creates and registers a new volume with the given labels, containing a copy of the volume
+// with the specified identifier. The quota of the source volume is retained, snapshots are not.
+//
+// The source volume must not be attached to a running component while it is being cloned.
+func (m *Manager) Clone(id string, labels map[string]string) (*Volume, error) {
+	if err := m.ensureInitialized(); err != nil {
+		return nil, err
+	}
+
+	src, err := m.acquireVolume(id)
+	if err != nil {
+		return nil, err
+	}
+	defer src.release()
+
+	volume, err := m.createVolumeDir(labels, NewCreateOptions(WithQuota(src.Quota)))
+	if err != nil {
+		return nil, err
+	}
+
+	// The volume file is copied without holding the manager lock as it may be large.
+	switch err = copyFile(src.Path, volume.Path); {
+	case err == nil, errors.Is(err, os.ErrNotExist):
+	default:
+		_ = os.RemoveAll(filepath.Join(m.volumesDir, volume.ID))
+		return nil, fmt.Errorf("failed to copy volume file: %w", err)
+	}
+
+	if err = m.registerVolume(volume); err != nil {
+		return nil, err
+	}
+
+	m.logger.Info("volume cloned",
+		"id", volume.ID,
+		"source_id", src.ID,
+	)
+
+	return volume, nil
+}
+
+// acquireVolume retrieves the volume with the specified identifier and gives the caller exclusive
+// access to its file. The caller must release the volume once done.
+func (m *Manager) acquireVolume(id string) (*Volume, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	volume, ok := m.volumes[id]
+	if !ok {
+		return nil, fmt.Errorf("volume '%s' not found", id)
+	}
+	if err := volume.acquire(); err != nil {
+		return nil, fmt.Errorf("%w: '%s'", err, id)
+	}
+	return volume, nil
+}
+
+func (m *Manager) writeDescriptor(volume *Volume) error {
+	dsc := &Descriptor{
+		ID:        volume.ID,
+		Labels:    volume.Labels,
+		Quota:     volume.Quota,
+		Snapshots: volume.snapshots,
+	}
+
+	b, err := json.Marshal(dsc)
+	if err != nil {
+		return fmt.Errorf("failed to serialize volume descriptor: %w", err)
+	}
+
+	dscFn := filepath.Join(m.volumesDir, volume.ID, descriptorFn)
+	tmpFn := dscFn + ".tmp"
+	if err = os.WriteFile(tmpFn, b, 0o600); err != nil {
+		return fmt.Errorf("failed to write volume descriptor: %w", err)
+	}
+	if err = os.Rename(tmpFn, dscFn); err != nil {
+		return fmt.Errorf("failed to write volume descriptor: %w", err)
+	}
+	return nil
+}
+
+// checkSnapshotQuota returns an error in case a snapshot of the given volume would exceed the
+// volume quota.
+func checkSnapshotQuota(volume *Volume) error {
+	if volume.Quota == 0 {
+		return nil
+	}
+	usage, err := volume.Usage()
+	if err != nil {
+		return err
+	}
+	size, err := fileUsage(volume.Path)
+	if err != nil {
+		return fmt.Errorf("failed to stat volume file: %w", err)
+	}
+	if usage+size > volume.Quota {
+		return fmt.Errorf("%w (usage: %d, snapshot: %d, quota: %d)", ErrQuotaExceeded, usage, size, volume.Quota)
+	}
+	return nil
+}
+
+// generateID generates a random byte string of the given size and returns its hex representation.
+func generateID(size int) (string, error) {
+	rawID := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, rawID); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(rawID), nil
+}
+
+// copyFile copies the source file to the destination file, overwriting it in case it exists.
+func copyFile(src, dst string) error {
+	sf, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer sf.Close()
+
+	df, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer df.Close()
+
+	if _, err = io.Copy(df, sf); err != nil {
+		return err
+	}
+	return df.Sync()
+}
@@ -0,0 +1,171 @@
+package volume
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerSnapshotsAndClones(t *testing.T) {
+	require := require.New(t)
+
+	dataDir := t.TempDir()
+	mgr, err := NewManager(dataDir)
+	require.NoError(err, "NewManager")
+
+	labels := map[string]string{"test": "snapshots"}
+	vol, err := mgr.Create(labels, WithQuota(1<<20))
+	require.NoError(err, "Create")
+	require.EqualValues(1<<20, vol.Quota)
+
+	// Snapshots of volumes without a volume file should be empty.
+	emptySnap, err := mgr.Snapshot(vol.ID)
+	require.NoError(err, "Snapshot")
+	require.True(emptySnap.Empty)
+
+	err = os.WriteFile(vol.Path, []byte("version 1"), 0o600)
+	require.NoError(err, "WriteFile")
+	snap, err := mgr.Snapshot(vol.ID)
+	require.NoError(err, "Snapshot")
+	require.False(snap.Empty)
+
+	// Restoring a snapshot should roll back the volume file.
+	err = os.WriteFile(vol.Path, []byte("version 2"), 0o600)
+	require.NoError(err, "WriteFile")
+	err = mgr.Restore(vol.ID, snap.ID)
+	require.NoError(err, "Restore")
+	data, err := os.ReadFile(vol.Path)
+	require.NoError(err, "ReadFile")
+	require.Equal("version 1", string(data))
+
+	err = mgr.Restore(vol.ID, "nonexistent")
+	require.Error(err, "Restore should fail for unknown snapshots")
+
+	// Clones should copy the volume file and quota, but not snapshots.
+	cloneLabels := map[string]string{"test": "clone"}
+	clone, err := mgr.Clone(vol.ID, cloneLabels)
+	require.NoError(err, "Clone")
+	require.NotEqual(vol.ID, clone.ID)
+	require.Equal(cloneLabels, clone.Labels)
+	require.EqualValues(vol.Quota, clone.Quota)
+	data, err = os.ReadFile(clone.Path)
+	require.NoError(err, "ReadFile")
+	require.Equal("version 1", string(data))
+	cloneSnaps, err := mgr.Snapshots(clone.ID)
+	require.NoError(err, "Snapshots")
+	require.Empty(cloneSnaps)
+
+	// Volumes, quotas and snapshots should persist across restarts.
+	mgr, err = NewManager(dataDir)
+	require.NoError(err, "NewManager")
+	loaded, ok := mgr.Get(vol.ID)
+	require.True(ok, "volume should be loaded")
+	require.EqualValues(vol.Quota, loaded.Quota)
+	snaps, err := mgr.Snapshots(vol.ID)
+	require.NoError(err, "Snapshots")
+	require.Len(snaps, 2)
+	require.Equal(emptySnap.ID, snaps[0].ID)
+	require.Equal(snap.ID, snaps[1].ID)
+
+	// Restoring an empty snapshot should remove the volume file.
+	err = mgr.Restore(vol.ID, emptySnap.ID)
+	require.NoError(err, "Restore")
+	_, err = os.Stat(loaded.Path)
+	require.ErrorIs(err, os.ErrNotExist)
+
+	err = mgr.RemoveSnapshot(vol.ID, snap.ID)
+	require.NoError(err, "RemoveSnapshot")
+	snaps, err = mgr.Snapshots(vol.ID)
+	require.NoError(err, "Snapshots")
+	require.Len(snaps, 1)
+}
+
+func TestVolumeQuota(t *testing.T) {
+	require := require.New(t)
+
+	mgr, err := NewManager(t.TempDir())
+	require.NoError(err, "NewManager")
+
+	vol, err := mgr.Create(nil, WithQuota(4096))
+	require.NoError(err, "Create")
+	require.NoError(vol.CheckQuota(), "missing volume file should be within quota")
+
+	err = os.WriteFile(vol.Path, make([]byte, 1024), 0o600)
+	require.NoError(err, "WriteFile")
+	require.NoError(vol.CheckQuota(), "volume file should be within quota")
+
+	err = os.WriteFile(vol.Path, make([]byte, 64*1024), 0o600)
+	require.NoError(err, "WriteFile")
+	require.ErrorIs(vol.CheckQuota(), ErrQuotaExceeded)
+
+	unlimited, err := mgr.Create(nil)
+	require.NoError(err, "Create")
+	err = os.WriteFile(unlimited.Path, make([]byte, 64*1024), 0o600)
+	require.NoError(err, "WriteFile")
+	require.NoError(unlimited.CheckQuota(), "volumes without a quota should not be limited")
+}
+
+func TestManagerAttachedVolumes(t *testing.T) {
+	require := require.New(t)
+
+	mgr, err := NewManager(t.TempDir())
+	require.NoError(err, "NewManager")
+
+	vol, err := mgr.Create(nil)
+	require.NoError(err, "Create")
+	err = os.WriteFile(vol.Path, []byte("version 1"), 0o600)
+	require.NoError(err, "WriteFile")
+	snap, err := mgr.Snapshot(vol.ID)
+	require.NoError(err, "Snapshot")
+
+	// Volumes attached to running components should not be snapshotted, restored or cloned.
+	err = vol.Attach()
+	require.NoError(err, "Attach")
+	_, err = mgr.Snapshot(vol.ID)
+	require.ErrorIs(err, ErrInUse)
+	err = mgr.Restore(vol.ID, snap.ID)
+	require.ErrorIs(err, ErrInUse)
+	_, err = mgr.Clone(vol.ID, nil)
+	require.ErrorIs(err, ErrInUse)
+
+	vol.Detach()
+	err = mgr.Restore(vol.ID, snap.ID)
+	require.NoError(err, "Restore should succeed after detaching")
+	_, err = mgr.Clone(vol.ID, nil)
+	require.NoError(err, "Clone should succeed after detaching")
+
+	// Volumes being copied by the manager should not be attached.
+	err = vol.acquire()
+	require.NoError(err, "acquire")
+	require.ErrorIs(vol.Attach(), ErrInUse)
+	err = mgr.RemoveSnapshot(vol.ID, snap.ID)
+	require.ErrorIs(err, ErrInUse)
+	vol.release()
+	require.NoError(vol.Attach(), "Attach")
+}
+
+func TestSnapshotQuota(t *testing.T) {
+	require := require.New(t)
+
+	mgr, err := NewManager(t.TempDir())
+	require.NoError(err, "NewManager")
+
+	vol, err := mgr.Create(nil, WithQuota(128*1024))
+	require.NoError(err, "Create")
+	err = os.WriteFile(vol.Path, make([]byte, 48*1024), 0o600)
+	require.NoError(err, "WriteFile")
+
+	// Snapshots should count towards the volume quota.
+	_, err = mgr.Snapshot(vol.ID)
+	require.NoError(err, "Snapshot")
+	usage, err := vol.Usage()
+	require.NoError(err, "Usage")
+	require.GreaterOrEqual(usage, uint64(96*1024))
+
+	_, err = mgr.Snapshot(vol.ID)
+	require.ErrorIs(err, ErrQuotaExceeded)
+	snaps, err := mgr.Snapshots(vol.ID)
+	require.NoError(err, "Snapshots")
+	require.Len(snaps, 1, "failed snapshots should not be retained")
+}
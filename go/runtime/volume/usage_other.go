@@ -0,0 +1,18 @@
+//go:build !windows
+// +build !windows
+
+package volume
+
+import (
+	"os"
+	"syscall"
+)
+
+// diskUsage returns the number of bytes allocated on disk for the given file, which may be less
+// than its size in case the file is sparse.
+func diskUsage(fi os.FileInfo) uint64 {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Blocks) * 512
+	}
+	return uint64(fi.Size())
+}
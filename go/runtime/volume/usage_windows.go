@@ -0,0 +1,11 @@
+//go:build windows
+// +build windows
+
+package volume
+
+import "os"
+
+// diskUsage returns the number of bytes allocated on disk for the given file.
+func diskUsage(fi os.FileInfo) uint64 {
+	return uint64(fi.Size())
+}
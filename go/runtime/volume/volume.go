@@ -1,5 +1,23 @@
 package volume
 
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQuotaExceeded is the error returned when a volume exceeds its quota.
+	ErrQuotaExceeded = errors.New("volume: quota exceeded")
+
+	// ErrInUse is the error returned when an operation cannot be performed because the volume is
+	// attached to a running component or another operation on the volume is in progress.
+	ErrInUse = errors.New("volume: in use")
+)
+
 // Volume is a persistent volume.
 type Volume struct {
 	// ID is the unique volume identifier.
@@ -8,6 +26,70 @@ type Volume struct {
 	Path string
 	// Labels are the labels assigned to the volume.
 	Labels map[string]string
+	// Quota is the maximum size of the volume file in bytes. Zero means no limit.
+	Quota uint64
+
+	// snapshots are the snapshots of the volume in creation order, protected by the manager.
+	snapshots []*Snapshot
+
+	mu sync.Mutex
+	// attached is the number of running components using the volume.
+	attached int
+	// busy is true while the volume file is being copied or replaced by the manager.
+	busy bool
+}
+
+// Attach marks the volume as being used by a running component. While attached, the volume
+// cannot be snapshotted, restored or cloned.
+//
+// Each successful call must be paired with a call to Detach once the component has stopped.
+func (v *Volume) Attach() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.busy {
+		return ErrInUse
+	}
+	v.attached++
+	return nil
+}
+
+// Detach marks the volume as no longer being used by a component.
+func (v *Volume) Detach() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.attached > 0 {
+		v.attached--
+	}
+}
+
+// acquire gives the caller exclusive access to the volume file.
+func (v *Volume) acquire() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.busy || v.attached > 0 {
+		return ErrInUse
+	}
+	v.busy = true
+	return nil
+}
+
+// release releases exclusive access to the volume file.
+func (v *Volume) release() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.busy = false
+}
+
+// isBusy returns true iff exclusive access to the volume file is currently held.
+func (v *Volume) isBusy() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.busy
 }
 
 // HasLabels returns true iff the volume has all of the given labels set.
@@ -20,10 +102,80 @@ func (v *Volume) HasLabels(labels map[string]string) bool {
 	return true
 }
 
+// Usage returns the amount of disk space used by the volume file and its snapshots in bytes.
+func (v *Volume) Usage() (uint64, error) {
+	usage, err := fileUsage(v.Path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat volume file: %w", err)
+	}
+
+	dir := filepath.Join(filepath.Dir(v.Path), snapshotsDir)
+	entries, err := os.ReadDir(dir)
+	switch {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+		return usage, nil
+	default:
+		return 0, fmt.Errorf("failed to read snapshots directory: %w", err)
+	}
+	for _, entry := range entries {
+		snapshotUsage, err := fileUsage(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return 0, fmt.Errorf("failed to stat snapshot file: %w", err)
+		}
+		usage += snapshotUsage
+	}
+	return usage, nil
+}
+
+// CheckQuota returns an error in case the volume file together with its snapshots exceeds the
+// volume quota.
+func (v *Volume) CheckQuota() error {
+	if v.Quota == 0 {
+		return nil
+	}
+	usage, err := v.Usage()
+	if err != nil {
+		return err
+	}
+	if usage > v.Quota {
+		return fmt.Errorf("%w (usage: %d, quota: %d)", ErrQuotaExceeded, usage, v.Quota)
+	}
+	return nil
+}
+
+// Snapshot is a point-in-time copy of a volume.
+type Snapshot struct {
+	// ID is the snapshot identifier, unique within the volume.
+	ID string `json:"id"`
+	// Created is the time when the snapshot was created.
+	Created time.Time `json:"created"`
+	// Empty is true iff the volume file did not exist when the snapshot was created.
+	Empty bool `json:"empty,omitempty"`
+}
+
 // Descriptor is a serializable volume descriptor.
 type Descriptor struct {
 	// ID is the unique volume identifier.
 	ID string `json:"id"`
 	// Labels are the labels assigned to the volume.
 	Labels map[string]string `json:"labels"`
+	// Quota is the maximum size of the volume file in bytes. Zero means no limit.
+	Quota uint64 `json:"quota,omitempty"`
+	// Snapshots are the snapshots of the volume in creation order.
+	Snapshots []*Snapshot `json:"snapshots,omitempty"`
+}
+
+// fileUsage returns the amount of disk space used by the given file in bytes or zero in case the
+// file does not exist.
+func fileUsage(fn string) (uint64, error) {
+	fi, err := os.Stat(fn)
+	switch {
+	case err == nil:
+		return diskUsage(fi), nil
+	case errors.Is(err, os.ErrNotExist):
+		return 0, nil
+	default:
+		return 0, err
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
@@ -980,13 +981,48 @@ const (
 	// HookKindWithdraw is the hook kind invoked during withdrawals. It may either allow or reject
 	// the given withdrawal based on custom logic.
 	HookKindWithdraw HookKind = 1
+	// HookKindIncomingTransfer is the hook kind invoked on the destination account during
+	// transfers from other accounts. It may either allow or reject the given transfer based on custom logic.
+	HookKindIncomingTransfer HookKind = 2
+	// HookKindAddEscrow is the hook kind invoked on the escrow account when another account is
+	// delegating stake to it. It may either allow or reject the given delegation based on custom logic.
+	HookKindAddEscrow HookKind = 3
+	// HookKindReclaimEscrow is the hook kind invoked on the escrow account when another account is
+	// reclaiming stake from it. It may either allow or reject the given reclaim based on custom logic.
+	HookKindReclaimEscrow HookKind = 4
+	// HookKindAllowanceChange is the hook kind invoked on the beneficiary account when its
+	// allowance is being changed. It may either allow or reject the given change based on custom
+	// logic.
+	HookKindAllowanceChange HookKind = 5
 )
 
+// HookKinds are all valid account hook kinds.
+var HookKinds = []HookKind{
+	HookKindWithdraw,
+	HookKindIncomingTransfer,
+	HookKindAddEscrow,
+	HookKindReclaimEscrow,
+	HookKindAllowanceChange,
+}
+
+// IsValid returns true iff the account hook kind is valid.
+func (hk HookKind) IsValid() bool {
+	return slices.Contains(HookKinds, hk)
+}
+
 // String returns a string representation of an account hook kind.
 func (hk HookKind) String() string {
 	switch hk {
 	case HookKindWithdraw:
 		return "withdraw"
+	case HookKindIncomingTransfer:
+		return "incoming_transfer"
+	case HookKindAddEscrow:
+		return "add_escrow"
+	case HookKindReclaimEscrow:
+		return "reclaim_escrow"
+	case HookKindAllowanceChange:
+		return "allowance_change"
 	default:
 		return "[invalid]"
 	}
@@ -1257,6 +1293,10 @@ type ConsensusParameters struct { // nolint: maligned
 	// MaxAllowances is the maximum number of allowances an account can have. Zero means disabled.
 	MaxAllowances uint32 `json:"max_allowances,omitempty"`
 
+	// EnabledHookKinds are the account hook kinds that are dispatched in addition to the withdraw
+	// hook which is always enabled. Hooks of kinds that are not enabled are ignored.
+	EnabledHookKinds []HookKind `json:"enabled_hook_kinds,omitempty"`
+
 	// FeeSplitWeightPropose is the proportion of block fee portions that go to the proposer.
 	FeeSplitWeightPropose quantity.Quantity `json:"fee_split_weight_propose"`
 	// FeeSplitWeightVote is the proportion of block fee portions that go to the validator that votes.
@@ -1276,6 +1316,14 @@ type ConsensusParameters struct { // nolint: maligned
 	DebugBypassStake bool `json:"debug_bypass_stake,omitempty"`
 }
 
+// IsHookKindEnabled returns true iff hooks of the given kind should be dispatched.
+func (p *ConsensusParameters) IsHookKindEnabled(kind HookKind) bool {
+	if kind == HookKindWithdraw {
+		return true
+	}
+	return slices.Contains(p.EnabledHookKinds, kind)
+}
+
 // ConsensusParameterChanges are allowed staking consensus parameter changes.
 type ConsensusParameterChanges struct {
 	// DebondingInterval is the new debonding interval.
@@ -1307,6 +1355,9 @@ type ConsensusParameterChanges struct {
 	// MaxAllowances is the new maximum number of allowances.
 	MaxAllowances *uint32 `json:"max_allowances,omitempty"`
 
+	// EnabledHookKinds are the new enabled account hook kinds.
+	EnabledHookKinds *[]HookKind `json:"enabled_hook_kinds,omitempty"`
+
 	// FeeSplitWeightPropose is the new propose fee split weight.
 	FeeSplitWeightPropose *quantity.Quantity `json:"fee_split_weight_propose"`
 	// FeeSplitWeightVote is the new vote fee split weight.
@@ -1355,6 +1406,9 @@ func (c *ConsensusParameterChanges) Apply(params *ConsensusParameters) error {
 	if c.MaxAllowances != nil {
 		params.MaxAllowances = *c.MaxAllowances
 	}
+	if c.EnabledHookKinds != nil {
+		params.EnabledHookKinds = *c.EnabledHookKinds
+	}
 	if c.FeeSplitWeightPropose != nil {
 		params.FeeSplitWeightPropose = *c.FeeSplitWeightPropose
 	}
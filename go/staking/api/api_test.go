@@ -135,6 +135,43 @@ func TestConsensusParameters(t *testing.T) {
 		},
 	}
 	require.Error(r9.SanityCheck(), "reward schedule step scale should not be greater than the reward amount denominator")
+
+	// Enabled hook kinds.
+	h1 := ConsensusParameters{
+		Thresholds:         validThresholds,
+		FeeSplitWeightVote: mustInitQuantity(t, 1),
+		EnabledHookKinds:   []HookKind{HookKindIncomingTransfer, HookKindAllowanceChange},
+	}
+	require.NoError(h1.SanityCheck(), "valid enabled hook kinds should be valid")
+	require.True(h1.IsHookKindEnabled(HookKindWithdraw), "withdraw hook should always be enabled")
+	require.True(h1.IsHookKindEnabled(HookKindIncomingTransfer))
+	require.False(h1.IsHookKindEnabled(HookKindAddEscrow))
+
+	h2 := ConsensusParameters{
+		Thresholds:         validThresholds,
+		FeeSplitWeightVote: mustInitQuantity(t, 1),
+		EnabledHookKinds:   []HookKind{HookKindAddEscrow, HookKindAddEscrow},
+	}
+	require.Error(h2.SanityCheck(), "duplicate enabled hook kinds should be invalid")
+
+	h3 := ConsensusParameters{
+		Thresholds:         validThresholds,
+		FeeSplitWeightVote: mustInitQuantity(t, 1),
+		EnabledHookKinds:   []HookKind{HookKind(42)},
+	}
+	require.Error(h3.SanityCheck(), "invalid enabled hook kinds should be invalid")
+}
+
+func TestHookKind(t *testing.T) {
+	require := require.New(t)
+
+	for _, kind := range HookKinds {
+		require.True(kind.IsValid())
+		require.NotEqual("[invalid]", kind.String())
+	}
+	require.False(HookKind(0).IsValid())
+	require.False(HookKind(42).IsValid())
+	require.Equal("[invalid]", HookKind(42).String())
 }
 
 func TestThresholdKind(t *testing.T) {
@@ -49,6 +49,18 @@ func (p *ConsensusParameters) SanityCheck() error {
 		return fmt.Errorf("minimum commission %v/%v over unity", p.CommissionScheduleRules, CommissionRateDenominator)
 	}
 
+	// Enabled hook kinds must be valid and unique.
+	hookKinds := make(map[HookKind]struct{})
+	for _, kind := range p.EnabledHookKinds {
+		if !kind.IsValid() {
+			return fmt.Errorf("invalid enabled hook kind: %d", kind)
+		}
+		if _, ok := hookKinds[kind]; ok {
+			return fmt.Errorf("duplicate enabled hook kind: %s", kind)
+		}
+		hookKinds[kind] = struct{}{}
+	}
+
 	// Reward schedule steps must be sequential.
 	var prevUntil beacon.EpochTime
 	for _, step := range p.RewardSchedule {
@@ -80,6 +92,7 @@ func (c *ConsensusParameterChanges) SanityCheck() error {
 		c.DisableDelegation == nil &&
 		c.AllowEscrowMessages == nil &&
 		c.MaxAllowances == nil &&
+		c.EnabledHookKinds == nil &&
 		c.FeeSplitWeightPropose == nil &&
 		c.FeeSplitWeightVote == nil &&
 		c.FeeSplitWeightNextPropose == nil &&
@@ -144,6 +157,15 @@ func SanityCheckAccount(
 		}
 	}
 
+	for kind, dst := range acct.General.Hooks {
+		if !kind.IsValid() {
+			return fmt.Errorf("staking: sanity check failed: account %s has invalid hook kind %d", addr, kind)
+		}
+		if dst.Module == "" {
+			return fmt.Errorf("staking: sanity check failed: account %s hook %s has empty destination module", addr, kind)
+		}
+	}
+
 	return nil
 }
 
@@ -120,6 +120,9 @@ type LogEntry = writelog.LogEntry
 // WriteLogIterator iterates over write log entries.
 type WriteLogIterator = writelog.Iterator
 
+// Usage is a breakdown of the storage space used by a node database.
+type Usage = nodedb.Usage
+
 // RootType is a storage root type.
 type RootType = mkvsNode.RootType
 
@@ -168,6 +171,12 @@ type GetPrefixesRequest = syncer.GetPrefixesRequest
 // IterateRequest is a request for the SyncIterate operation.
 type IterateRequest = syncer.IterateRequest
 
+// GetMultiRequest is a request for the SyncGetMulti operation.
+type GetMultiRequest = syncer.GetMultiRequest
+
+// GetRangeRequest is a request for the SyncGetRange operation.
+type GetRangeRequest = syncer.GetRangeRequest
+
 // ProofResponse is a response for requests that produce proofs.
 type ProofResponse = syncer.ProofResponse
 
@@ -50,6 +50,26 @@ var (
 			return r.Tree.Root.Namespace, nil
 		}).
 		WithAccessControl(cmnGrpc.AccessControlAlways)
+	// MethodSyncGetMulti is the SyncGetMulti method.
+	MethodSyncGetMulti = ServiceName.NewMethod("SyncGetMulti", GetMultiRequest{}).
+				WithNamespaceExtractor(func(_ context.Context, req any) (common.Namespace, error) {
+			r, ok := req.(*GetMultiRequest)
+			if !ok {
+				return common.Namespace{}, errInvalidRequestType
+			}
+			return r.Tree.Root.Namespace, nil
+		}).
+		WithAccessControl(cmnGrpc.AccessControlAlways)
+	// MethodSyncGetRange is the SyncGetRange method.
+	MethodSyncGetRange = ServiceName.NewMethod("SyncGetRange", GetRangeRequest{}).
+				WithNamespaceExtractor(func(_ context.Context, req any) (common.Namespace, error) {
+			r, ok := req.(*GetRangeRequest)
+			if !ok {
+				return common.Namespace{}, errInvalidRequestType
+			}
+			return r.Tree.Root.Namespace, nil
+		}).
+		WithAccessControl(cmnGrpc.AccessControlAlways)
 
 	// MethodGetDiff is the GetDiff method.
 	MethodGetDiff = ServiceName.NewMethod("GetDiff", GetDiffRequest{})
@@ -77,6 +97,14 @@ var (
 				MethodName: MethodSyncIterate.ShortName(),
 				Handler:    handlerSyncIterate,
 			},
+			{
+				MethodName: MethodSyncGetMulti.ShortName(),
+				Handler:    handlerSyncGetMulti,
+			},
+			{
+				MethodName: MethodSyncGetRange.ShortName(),
+				Handler:    handlerSyncGetRange,
+			},
 			{
 				MethodName: MethodGetCheckpoints.ShortName(),
 				Handler:    handlerGetCheckpoints,
@@ -166,6 +194,55 @@ func handlerSyncIterate(
 	return interceptor(ctx, &req, info, handler)
 }
 
+func handlerSyncGetMulti(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var req GetMultiRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).SyncGetMulti(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MethodSyncGetMulti.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).SyncGetMulti(ctx, req.(*GetMultiRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerSyncGetRange(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var req GetRangeRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).SyncGetRange(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MethodSyncGetRange.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).SyncGetRange(ctx, req.(*GetRangeRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
 func handlerGetCheckpoints(
 	srv any,
 	ctx context.Context,
@@ -316,6 +393,22 @@ func (c *Client) SyncIterate(ctx context.Context, request *IterateRequest) (*Pro
 	return &rsp, nil
 }
 
+func (c *Client) SyncGetMulti(ctx context.Context, request *GetMultiRequest) (*ProofResponse, error) {
+	var rsp ProofResponse
+	if err := c.conn.Invoke(ctx, MethodSyncGetMulti.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *Client) SyncGetRange(ctx context.Context, request *GetRangeRequest) (*ProofResponse, error) {
+	var rsp ProofResponse
+	if err := c.conn.Invoke(ctx, MethodSyncGetRange.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) GetCheckpoints(ctx context.Context, request *checkpoint.GetCheckpointsRequest) ([]*checkpoint.Metadata, error) {
 	var rsp []*checkpoint.Metadata
 	if err := c.conn.Invoke(ctx, MethodGetCheckpoints.FullName(), request, &rsp); err != nil {
@@ -52,6 +52,8 @@ var (
 	labelSyncGet         = prometheus.Labels{"call": "sync_get"}
 	labelSyncGetPrefixes = prometheus.Labels{"call": "sync_get_prefixes"}
 	labelSyncIterate     = prometheus.Labels{"call": "sync_iterate"}
+	labelSyncGetMulti    = prometheus.Labels{"call": "sync_get_multi"}
+	labelSyncGetRange    = prometheus.Labels{"call": "sync_get_range"}
 	labelGetDiff         = prometheus.Labels{"call": "get_diff"}
 
 	metricsOnce sync.Once
@@ -113,6 +115,32 @@ func (w *metricsWrapper) SyncIterate(ctx context.Context, request *IterateReques
 	return res, err
 }
 
+func (w *metricsWrapper) SyncGetMulti(ctx context.Context, request *GetMultiRequest) (*ProofResponse, error) {
+	start := time.Now()
+	res, err := w.Backend.SyncGetMulti(ctx, request)
+	storageLatency.With(labelSyncGetMulti).Observe(time.Since(start).Seconds())
+	if err != nil {
+		storageFailures.With(labelSyncGetMulti).Inc()
+		return nil, err
+	}
+
+	storageCalls.With(labelSyncGetMulti).Inc()
+	return res, err
+}
+
+func (w *metricsWrapper) SyncGetRange(ctx context.Context, request *GetRangeRequest) (*ProofResponse, error) {
+	start := time.Now()
+	res, err := w.Backend.SyncGetRange(ctx, request)
+	storageLatency.With(labelSyncGetRange).Observe(time.Since(start).Seconds())
+	if err != nil {
+		storageFailures.With(labelSyncGetRange).Inc()
+		return nil, err
+	}
+
+	storageCalls.With(labelSyncGetRange).Inc()
+	return res, err
+}
+
 type localMetricsWrapper struct {
 	metricsWrapper
 }
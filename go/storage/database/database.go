@@ -126,6 +126,26 @@ func (ba *databaseBackend) SyncIterate(ctx context.Context, request *api.Iterate
 	return tree.SyncIterate(ctx, request)
 }
 
+func (ba *databaseBackend) SyncGetMulti(ctx context.Context, request *api.GetMultiRequest) (*api.ProofResponse, error) {
+	tree, err := ba.rootCache.GetTree(request.Tree.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer tree.Close()
+
+	return tree.SyncGetMulti(ctx, request)
+}
+
+func (ba *databaseBackend) SyncGetRange(ctx context.Context, request *api.GetRangeRequest) (*api.ProofResponse, error) {
+	tree, err := ba.rootCache.GetTree(request.Tree.Root)
+	if err != nil {
+		return nil, err
+	}
+	defer tree.Close()
+
+	return tree.SyncGetRange(ctx, request)
+}
+
 func (ba *databaseBackend) GetDiff(ctx context.Context, request *api.GetDiffRequest) (api.WriteLogIterator, error) {
 	return ba.ndb.GetWriteLog(ctx, request.StartRoot, request.EndRoot)
 }
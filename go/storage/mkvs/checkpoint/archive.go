@@ -0,0 +1,315 @@
+package checkpoint
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+const (
+	// ArchiveFormatVersion is the current state archive format version.
+	ArchiveFormatVersion uint16 = 1
+
+	archiveManifestName = "manifest.json"
+)
+
+// ArchiveManifest is the manifest describing the contents of a state archive.
+type ArchiveManifest struct {
+	// FormatVersion is the archive format version.
+	FormatVersion uint16 `json:"format_version"`
+	// Namespace is the namespace of the archived state.
+	Namespace common.Namespace `json:"namespace"`
+	// Version is the version (round) of the archived roots.
+	Version uint64 `json:"version"`
+	// Created is the time when the archive was created.
+	Created time.Time `json:"created"`
+	// Roots are the archived roots, in the order in which they are stored in the archive.
+	Roots []*ArchiveRoot `json:"roots"`
+}
+
+// ArchiveRoot describes a single root stored in a state archive.
+type ArchiveRoot struct {
+	// Checkpoint is the metadata of the checkpoint the root is stored as.
+	Checkpoint *Metadata `json:"checkpoint"`
+	// NumKeys is the number of keys in the root.
+	NumKeys uint64 `json:"num_keys"`
+	// ContentDigest is the digest of all key/value pairs in the root in iteration order. Unlike
+	// the root hash it does not depend on the tree structure, so it is used to double-check that
+	// all of the data was restored.
+	ContentDigest hash.Hash `json:"content_digest"`
+}
+
+func archiveChunkName(rootIdx, chunkIdx int) string {
+	return fmt.Sprintf("roots/%d/chunks/%d", rootIdx, chunkIdx)
+}
+
+// contentDigest iterates over all of the key/value pairs in the given root and returns their
+// number and digest.
+func contentDigest(ctx context.Context, ndb db.NodeDB, root node.Root) (uint64, hash.Hash, error) {
+	tree := mkvs.NewWithRoot(nil, ndb, root)
+	defer tree.Close()
+
+	it := tree.NewIterator(ctx)
+	defer it.Close()
+
+	var numKeys uint64
+	hb := hash.NewBuilder()
+	for it.Rewind(); it.Valid(); it.Next() {
+		if ctx.Err() != nil {
+			return 0, hash.Hash{}, ctx.Err()
+		}
+
+		for _, b := range [][]byte{it.Key(), it.Value()} {
+			_, _ = hb.Write(binary.BigEndian.AppendUint64(nil, uint64(len(b))))
+			_, _ = hb.Write(b)
+		}
+		numKeys++
+	}
+	if it.Err() != nil {
+		return 0, hash.Hash{}, fmt.Errorf("archive: failed to iterate over root %s: %w", root, it.Err())
+	}
+	return numKeys, hb.Build(), nil
+}
+
+// WriteArchive creates checkpoints for the given roots using the passed creator and writes them
+// into a self-describing tar archive.
+//
+// All roots must be finalized, share the same namespace and version and are stored in the
+// order given. The checkpoints are left in the creator so it is up to the caller to dispose of
+// them.
+func WriteArchive(
+	ctx context.Context,
+	w io.Writer,
+	ndb db.NodeDB,
+	creator Creator,
+	version uint16,
+	roots []node.Root,
+	chunkSize uint64,
+) (*ArchiveManifest, error) {
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("archive: no roots to archive")
+	}
+
+	manifest := &ArchiveManifest{
+		FormatVersion: ArchiveFormatVersion,
+		Namespace:     roots[0].Namespace,
+		Version:       roots[0].Version,
+		Created:       time.Now().UTC(),
+	}
+	for _, root := range roots {
+		if !root.Namespace.Equal(&manifest.Namespace) || root.Version != manifest.Version {
+			return nil, fmt.Errorf("archive: all roots must have the same namespace and version")
+		}
+
+		cp, err := creator.CreateCheckpoint(ctx, version, root, chunkSize)
+		if err != nil {
+			return nil, fmt.Errorf("archive: failed to create checkpoint for root %s: %w", root, err)
+		}
+		numKeys, digest, err := contentDigest(ctx, ndb, root)
+		if err != nil {
+			return nil, err
+		}
+
+		manifest.Roots = append(manifest.Roots, &ArchiveRoot{
+			Checkpoint:    cp,
+			NumKeys:       numKeys,
+			ContentDigest: digest,
+		})
+	}
+
+	tw := tar.NewWriter(w)
+	writeEntry := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Size:     int64(len(data)),
+			Mode:     0o600,
+			ModTime:  manifest.Created,
+		}); err != nil {
+			return fmt.Errorf("archive: failed to write header for '%s': %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("archive: failed to write '%s': %w", name, err)
+		}
+		return nil
+	}
+
+	// The manifest always comes first so that archives can be restored in a streaming fashion.
+	rawManifest, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("archive: failed to marshal manifest: %w", err)
+	}
+	if err = writeEntry(archiveManifestName, rawManifest); err != nil {
+		return nil, err
+	}
+
+	for rootIdx, ar := range manifest.Roots {
+		for chunkIdx := range ar.Checkpoint.Chunks {
+			cm, err := ar.Checkpoint.GetChunkMetadata(uint64(chunkIdx))
+			if err != nil {
+				return nil, err
+			}
+
+			// Chunks need to be buffered as the tar header must include the size.
+			var buf bytes.Buffer
+			if err = creator.GetCheckpointChunk(ctx, cm, &buf); err != nil {
+				return nil, fmt.Errorf("archive: failed to get chunk %d of root %s: %w", chunkIdx, cm.Root, err)
+			}
+			if err = writeEntry(archiveChunkName(rootIdx, chunkIdx), buf.Bytes()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return nil, fmt.Errorf("archive: failed to close archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// ReadArchiveManifest reads the manifest of the state archive and validates it.
+//
+// The passed reader is left positioned at the first chunk of the archive.
+func ReadArchiveManifest(tr *tar.Reader) (*ArchiveManifest, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read manifest: %w", ErrArchiveInvalid, err)
+	}
+	if hdr.Name != archiveManifestName {
+		return nil, fmt.Errorf("%w: expected manifest, got '%s'", ErrArchiveInvalid, hdr.Name)
+	}
+
+	var manifest ArchiveManifest
+	if err = json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("%w: malformed manifest: %w", ErrArchiveInvalid, err)
+	}
+
+	if manifest.FormatVersion != ArchiveFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version: %d", ErrArchiveInvalid, manifest.FormatVersion)
+	}
+	if len(manifest.Roots) == 0 {
+		return nil, fmt.Errorf("%w: no roots", ErrArchiveInvalid)
+	}
+	seenTypes := make(map[node.RootType]struct{})
+	for _, ar := range manifest.Roots {
+		if ar == nil || ar.Checkpoint == nil {
+			return nil, fmt.Errorf("%w: missing checkpoint metadata", ErrArchiveInvalid)
+		}
+
+		cp := ar.Checkpoint
+		switch {
+		case !IsVersionSupported(cp.Version):
+			return nil, fmt.Errorf("%w: unsupported checkpoint version: %d", ErrArchiveInvalid, cp.Version)
+		case cp.IsDelta():
+			return nil, fmt.Errorf("%w: delta checkpoints are not supported", ErrArchiveInvalid)
+		case !cp.Root.Namespace.Equal(&manifest.Namespace) || cp.Root.Version != manifest.Version:
+			return nil, fmt.Errorf("%w: root %s does not match manifest", ErrArchiveInvalid, cp.Root)
+		}
+		if _, ok := seenTypes[cp.Root.Type]; ok {
+			return nil, fmt.Errorf("%w: duplicate root type: %s", ErrArchiveInvalid, cp.Root.Type)
+		}
+		seenTypes[cp.Root.Type] = struct{}{}
+	}
+	return &manifest, nil
+}
+
+// RestoreArchive restores all roots contained in the given state archive into the node database
+// and finalizes their version.
+//
+// Chunks are verified against the root hashes recorded in the manifest as they are restored. In
+// addition, the key/value content of each restored root is verified against the manifest before
+// the version is finalized. If anything fails, the partially restored state is discarded.
+func RestoreArchive(
+	ctx context.Context,
+	r io.Reader,
+	ndb db.NodeDB,
+	restorer Restorer,
+) (manifest *ArchiveManifest, err error) {
+	tr := tar.NewReader(r)
+	if manifest, err = ReadArchiveManifest(tr); err != nil {
+		return nil, err
+	}
+
+	// Restored roots are finalized so they can only be imported on top of earlier versions.
+	if latest, ok := ndb.GetLatestVersion(); ok && manifest.Version <= latest {
+		return nil, fmt.Errorf("archive: version %d is not newer than the latest version %d", manifest.Version, latest)
+	}
+
+	var roots []node.Root
+	for _, ar := range manifest.Roots {
+		roots = append(roots, ar.Checkpoint.Root)
+	}
+
+	if err = ndb.StartMultipartInsert(manifest.Version); err != nil {
+		return nil, fmt.Errorf("archive: failed to start multipart insert: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = restorer.AbortRestore(ctx)
+		}
+		// On success this only clears the insertion log.
+		if abortErr := ndb.AbortMultipartInsert(); abortErr != nil && err == nil {
+			err = fmt.Errorf("archive: failed to abort multipart insert: %w", abortErr)
+		}
+	}()
+
+	for rootIdx, ar := range manifest.Roots {
+		if err = restorer.StartRestore(ctx, ar.Checkpoint); err != nil {
+			return nil, fmt.Errorf("archive: failed to start restore of root %s: %w", ar.Checkpoint.Root, err)
+		}
+
+		var done bool
+		for chunkIdx := range ar.Checkpoint.Chunks {
+			var hdr *tar.Header
+			if hdr, err = tr.Next(); err != nil {
+				return nil, fmt.Errorf("%w: failed to read chunk: %w", ErrArchiveInvalid, err)
+			}
+			if expected := archiveChunkName(rootIdx, chunkIdx); hdr.Name != expected {
+				return nil, fmt.Errorf("%w: expected '%s', got '%s'", ErrArchiveInvalid, expected, hdr.Name)
+			}
+
+			if done, err = restorer.RestoreChunk(ctx, uint64(chunkIdx), tr); err != nil {
+				return nil, fmt.Errorf("archive: failed to restore chunk %d of root %s: %w", chunkIdx, ar.Checkpoint.Root, err)
+			}
+		}
+		if !done {
+			return nil, fmt.Errorf("%w: incomplete checkpoint for root %s", ErrArchiveInvalid, ar.Checkpoint.Root)
+		}
+
+		var (
+			numKeys uint64
+			digest  hash.Hash
+		)
+		if numKeys, digest, err = contentDigest(ctx, ndb, ar.Checkpoint.Root); err != nil {
+			return nil, err
+		}
+		if numKeys != ar.NumKeys || !digest.Equal(&ar.ContentDigest) {
+			return nil, fmt.Errorf("archive: content of root %s does not match manifest (keys: %d expected: %d)",
+				ar.Checkpoint.Root,
+				numKeys,
+				ar.NumKeys,
+			)
+		}
+	}
+
+	if _, err = tr.Next(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: unexpected trailing data", ErrArchiveInvalid)
+	}
+
+	if err = ndb.Finalize(roots); err != nil {
+		return nil, fmt.Errorf("archive: failed to finalize version %d: %w", manifest.Version, err)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,143 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db"
+	dbApi "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	dbTesting "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/testing"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func TestArchive(t *testing.T) {
+	dbTesting.TestMultipleBackends(t, db.Backends, testArchive)
+}
+
+func testArchive(t *testing.T, factory dbApi.Factory) {
+	dir, err := os.MkdirTemp("", "mkvs.checkpoint.archive")
+	require.NoError(t, err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := factory.New(&dbApi.Config{
+		DB:        filepath.Join(dir, "db"),
+		Namespace: testNs,
+	})
+	require.NoError(t, err, "New")
+	defer ndb.Close()
+
+	// Create a version with both a state and an I/O root.
+	ctx := context.Background()
+	const version = 42
+	var roots []node.Root
+	for _, rootType := range []node.RootType{node.RootTypeState, node.RootTypeIO} {
+		tree := mkvs.New(nil, ndb, rootType)
+		for i := 0; i < 1000; i++ {
+			err = tree.Insert(ctx, []byte(rootType.String()+strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+			require.NoError(t, err, "Insert")
+		}
+		var rootHash hash.Hash
+		_, rootHash, err = tree.Commit(ctx, testNs, version)
+		require.NoError(t, err, "Commit")
+		tree.Close()
+
+		roots = append(roots, node.Root{
+			Namespace: testNs,
+			Version:   version,
+			Type:      rootType,
+			Hash:      rootHash,
+		})
+	}
+	err = ndb.Finalize(roots)
+	require.NoError(t, err, "Finalize")
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(t, err, "NewFileCreator")
+
+	var archive bytes.Buffer
+	manifest, err := WriteArchive(ctx, &archive, ndb, fc, VersionZstd, roots, 16*1024)
+	require.NoError(t, err, "WriteArchive")
+	require.EqualValues(t, version, manifest.Version, "manifest version should be correct")
+	require.Len(t, manifest.Roots, 2, "manifest should contain all roots")
+	for i, ar := range manifest.Roots {
+		require.EqualValues(t, roots[i], ar.Checkpoint.Root, "manifest roots should be correct")
+		require.EqualValues(t, 1000, ar.NumKeys, "manifest key count should be correct")
+		require.Greater(t, len(ar.Checkpoint.Chunks), 1, "there should be multiple chunks")
+	}
+	raw := archive.Bytes()
+
+	// Archives should be portable between node database backends.
+	for _, dstFactory := range db.Backends {
+		t.Run("RestoreInto/"+dstFactory.Name(), func(t *testing.T) {
+			require := require.New(t)
+
+			ndb2, err := dstFactory.New(&dbApi.Config{
+				DB:        filepath.Join(dir, "restore-"+dstFactory.Name()),
+				Namespace: testNs,
+			})
+			require.NoError(err, "New")
+			defer ndb2.Close()
+			rs, err := NewRestorer(ndb2)
+			require.NoError(err, "NewRestorer")
+
+			restored, err := RestoreArchive(ctx, bytes.NewReader(raw), ndb2, rs)
+			require.NoError(err, "RestoreArchive")
+			require.EqualValues(manifest.Roots[0].ContentDigest, restored.Roots[0].ContentDigest)
+
+			latest, ok := ndb2.GetLatestVersion()
+			require.True(ok, "GetLatestVersion")
+			require.EqualValues(version, latest, "restored version should be finalized")
+			for _, root := range roots {
+				require.True(ndb2.HasRoot(root), "restored root should exist")
+
+				tree := mkvs.NewWithRoot(nil, ndb2, root)
+				value, err := tree.Get(ctx, []byte(root.Type.String()+"42"))
+				require.NoError(err, "Get")
+				require.EqualValues([]byte("42"), value, "restored value should be correct")
+				tree.Close()
+			}
+
+			// Restoring the same version again should fail.
+			_, err = RestoreArchive(ctx, bytes.NewReader(raw), ndb2, rs)
+			require.Error(err, "RestoreArchive should fail for existing roots")
+		})
+	}
+
+	t.Run("Corrupted", func(t *testing.T) {
+		require := require.New(t)
+
+		ndb2, err := factory.New(&dbApi.Config{
+			DB:        filepath.Join(dir, "restore-corrupted"),
+			Namespace: testNs,
+		})
+		require.NoError(err, "New")
+		defer ndb2.Close()
+		rs, err := NewRestorer(ndb2)
+		require.NoError(err, "NewRestorer")
+
+		// Truncated archives should be rejected without finalizing anything.
+		_, err = RestoreArchive(ctx, bytes.NewReader(raw[:len(raw)/2]), ndb2, rs)
+		require.Error(err, "RestoreArchive should fail for truncated archives")
+		_, ok := ndb2.GetLatestVersion()
+		require.False(ok, "nothing should be finalized")
+
+		// Archives with a modified root hash should fail proof verification.
+		corrupted := bytes.Replace(raw, []byte(roots[0].Hash.String()), []byte(roots[1].Hash.String()), 1)
+		_, err = RestoreArchive(ctx, bytes.NewReader(corrupted), ndb2, rs)
+		require.Error(err, "RestoreArchive should fail for corrupted archives")
+		_, ok = ndb2.GetLatestVersion()
+		require.False(ok, "nothing should be finalized")
+
+		// A subsequent restore of a valid archive should succeed.
+		_, err = RestoreArchive(ctx, bytes.NewReader(raw), ndb2, rs)
+		require.NoError(err, "RestoreArchive")
+	})
+}
@@ -13,6 +13,22 @@ import (
 
 const moduleName = "storage/mkvs/checkpoint"
 
+const (
+	// VersionSnappy is the checkpoint version where chunks are compressed using snappy.
+	VersionSnappy uint16 = 1
+	// VersionZstd is the checkpoint version where chunks are compressed using zstd and are
+	// addressed by their digest.
+	VersionZstd uint16 = 2
+)
+
+// SupportedVersions are the supported checkpoint versions in order of preference.
+var SupportedVersions = []uint16{VersionZstd, VersionSnappy}
+
+// IsVersionSupported returns true iff the given checkpoint version is supported.
+func IsVersionSupported(version uint16) bool {
+	return version == VersionSnappy || version == VersionZstd
+}
+
 var (
 	// ErrCheckpointNotFound is the error when a checkpoint is not found.
 	ErrCheckpointNotFound = errors.New(moduleName, 1, "checkpoint: not found")
@@ -35,6 +51,12 @@ var (
 
 	// ErrChunkCorrupted is the error when a chunk is corrupted.
 	ErrChunkCorrupted = errors.New(moduleName, 7, "chunk: corrupted chunk")
+
+	// ErrBaseRootNotFound is the error when the base root of a delta checkpoint is not available.
+	ErrBaseRootNotFound = errors.New(moduleName, 8, "checkpoint: base root not found")
+
+	// ErrArchiveInvalid is the error when a state archive is malformed.
+	ErrArchiveInvalid = errors.New(moduleName, 9, "archive: invalid state archive")
 )
 
 // ChunkProvider is a chunk provider.
@@ -54,19 +76,27 @@ type GetCheckpointsRequest struct {
 	// RootVersion specifies an optional root version to limit the request to. If specified, only
 	// checkpoints for roots with the specific version will be considered.
 	RootVersion *uint64 `json:"root_version,omitempty"`
+
+	// IncludeDeltas specifies whether delta checkpoints should be included in the response.
+	IncludeDeltas bool `json:"include_deltas,omitempty"`
 }
 
 // Creator is a checkpoint creator.
 type Creator interface {
 	ChunkProvider
 
-	// CreateCheckpoint creates a new checkpoint at the given root.
-	CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64) (*Metadata, error)
+	// CreateCheckpoint creates a new checkpoint of the given version at the given root.
+	CreateCheckpoint(ctx context.Context, version uint16, root node.Root, chunkSize uint64) (*Metadata, error)
+
+	// CreateDeltaCheckpoint creates a new delta checkpoint of the given version at the given root,
+	// containing only the nodes that are not already present in the given base root.
+	CreateDeltaCheckpoint(ctx context.Context, version uint16, baseRoot, root node.Root, chunkSize uint64) (*Metadata, error)
 
 	// GetCheckpoint retrieves checkpoint metadata for a specific checkpoint.
 	GetCheckpoint(ctx context.Context, version uint16, root node.Root) (*Metadata, error)
 
-	// DeleteCheckpoint deletes a specific checkpoint.
+	// DeleteCheckpoint deletes a specific checkpoint, together with any delta checkpoints at
+	// the same root.
 	DeleteCheckpoint(ctx context.Context, version uint16, root node.Root) error
 }
 
@@ -74,6 +104,8 @@ type Creator interface {
 type Restorer interface {
 	// StartRestore starts a checkpoint restoration process.
 	//
+	// In case of a delta checkpoint, the base root must already exist in the underlying database.
+	//
 	// Multipart management in the underlying database is the responsibility of the caller.
 	StartRestore(ctx context.Context, checkpoint *Metadata) error
 
@@ -124,6 +156,9 @@ type ChunkMetadata struct {
 	Root    node.Root `json:"root"`
 	Index   uint64    `json:"index"`
 	Digest  hash.Hash `json:"digest"`
+
+	// BaseRoot is the base root in case the chunk belongs to a delta checkpoint.
+	BaseRoot *node.Root `json:"base_root,omitempty"`
 }
 
 // Metadata is checkpoint metadata.
@@ -131,6 +166,15 @@ type Metadata struct {
 	Version uint16      `json:"version"`
 	Root    node.Root   `json:"root"`
 	Chunks  []hash.Hash `json:"chunks"`
+
+	// BaseRoot is the root that a delta checkpoint should be applied on top of. If not set, this
+	// is a full checkpoint.
+	BaseRoot *node.Root `json:"base_root,omitempty"`
+}
+
+// IsDelta returns true iff this is a delta checkpoint.
+func (m *Metadata) IsDelta() bool {
+	return m.BaseRoot != nil
 }
 
 // EncodedHash returns the encoded cryptographic hash of the checkpoint metadata.
@@ -145,9 +189,10 @@ func (m Metadata) GetChunkMetadata(idx uint64) (*ChunkMetadata, error) {
 	}
 
 	return &ChunkMetadata{
-		Version: m.Version,
-		Root:    m.Root,
-		Index:   idx,
-		Digest:  m.Chunks[int(idx)],
+		Version:  m.Version,
+		Root:     m.Root,
+		Index:    idx,
+		Digest:   m.Chunks[int(idx)],
+		BaseRoot: m.BaseRoot,
 	}, nil
 }
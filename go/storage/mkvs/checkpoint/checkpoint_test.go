@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -73,7 +74,7 @@ func testFileCheckpointCreator(t *testing.T, factory dbApi.Factory) {
 	require.Error(err, "GetCheckpoint should fail with non-existent checkpoint")
 
 	// Create a checkpoint and check that it has been created correctly.
-	cp, err := fc.CreateCheckpoint(ctx, root, 16*1024)
+	cp, err := fc.CreateCheckpoint(ctx, VersionSnappy, root, 16*1024)
 	require.NoError(err, "CreateCheckpoint")
 	require.EqualValues(1, cp.Version, "version should be correct")
 	require.EqualValues(root, cp.Root, "checkpoint root should be correct")
@@ -101,7 +102,7 @@ func testFileCheckpointCreator(t *testing.T, factory dbApi.Factory) {
 	require.Equal(cp, gcp)
 
 	// Try re-creating the same checkpoint again and make sure we get the same metadata.
-	existingCp, err := fc.CreateCheckpoint(ctx, root, 16*1024)
+	existingCp, err := fc.CreateCheckpoint(ctx, VersionSnappy, root, 16*1024)
 	require.NoError(err, "CreateCheckpoint on an existing root should work")
 	require.Equal(cp, existingCp, "created checkpoint should be correct")
 
@@ -260,7 +261,7 @@ func testFileCheckpointCreator(t *testing.T, factory dbApi.Factory) {
 	// Create a checkpoint with unknown root.
 	invalidRoot := root
 	invalidRoot.Hash.FromBytes([]byte("mkvs checkpoint test invalid root"))
-	_, err = fc.CreateCheckpoint(ctx, invalidRoot, 16*1024)
+	_, err = fc.CreateCheckpoint(ctx, VersionSnappy, invalidRoot, 16*1024)
 	require.Error(err, "CreateCheckpoint should fail for invalid root")
 }
 
@@ -307,7 +308,7 @@ func testOversizedChunks(t *testing.T, factory dbApi.Factory) {
 	require.NoError(err, "NewFileCreator")
 
 	// Create a checkpoint and check that it has been created correctly.
-	cp, err := fc.CreateCheckpoint(ctx, root, 128)
+	cp, err := fc.CreateCheckpoint(ctx, VersionSnappy, root, 128)
 	require.NoError(err, "CreateCheckpoint")
 	require.EqualValues(1, cp.Version, "version should be correct")
 	require.EqualValues(root, cp.Root, "checkpoint root should be correct")
@@ -410,7 +411,7 @@ func testPruneGapAfterCheckpointRestore(t *testing.T, factory dbApi.Factory) {
 	require.NoError(err, "NewFileCreator")
 
 	// Create a checkpoint and check that it has been created correctly.
-	cp, err := fc.CreateCheckpoint(ctx, root, 16*1024)
+	cp, err := fc.CreateCheckpoint(ctx, VersionSnappy, root, 16*1024)
 	require.NoError(err, "CreateCheckpoint")
 
 	// Restore checkpoints in the second database.
@@ -491,3 +492,474 @@ func testPruneGapAfterCheckpointRestore(t *testing.T, factory dbApi.Factory) {
 	err = ndb2.Prune(checkpointRootVersion)
 	require.NoError(err, "Prune(%d)", checkpointRootVersion)
 }
+
+func TestDeltaCheckpoint(t *testing.T) {
+	dbTesting.TestMultipleBackends(t, db.Backends, testDeltaCheckpoint)
+}
+
+func testDeltaCheckpoint(t *testing.T, factory dbApi.Factory) {
+	require := require.New(t)
+
+	dir, err := os.MkdirTemp("", "mkvs.checkpoint")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	// Create two databases, the first will contain everything while the second one will only
+	// contain versions up to the base version.
+	ndb1, err := factory.New(&dbApi.Config{
+		DB:        filepath.Join(dir, "db1"),
+		Namespace: testNs,
+	})
+	require.NoError(err, "New")
+
+	ndb2, err := factory.New(&dbApi.Config{
+		DB:        filepath.Join(dir, "db2"),
+		Namespace: testNs,
+	})
+	require.NoError(err, "New")
+
+	ctx := context.Background()
+	root := node.Root{
+		Namespace: testNs,
+		Version:   0,
+		Type:      node.RootTypeState,
+	}
+	root.Hash.Empty()
+
+	const (
+		numVersions       = 10
+		numKeysPerVersion = 200
+		baseVersion       = 6
+	)
+
+	var baseRoot node.Root
+	for v := uint64(0); v < numVersions; v++ {
+		trees := []mkvs.Tree{mkvs.NewWithRoot(nil, ndb1, root)}
+		if v <= baseVersion {
+			trees = append(trees, mkvs.NewWithRoot(nil, ndb2, root))
+		}
+
+		var rootHash hash.Hash
+		for idx, tree := range trees {
+			for i := 0; i < numKeysPerVersion; i++ {
+				err = tree.Insert(ctx, []byte(fmt.Sprintf("key %d %d", v, i)), []byte(strconv.Itoa(i)))
+				require.NoError(err, "Insert")
+			}
+			if v > 0 {
+				// Remove and update some of the keys from the previous version.
+				for i := 0; i < numKeysPerVersion; i += 10 {
+					err = tree.Remove(ctx, []byte(fmt.Sprintf("key %d %d", v-1, i)))
+					require.NoError(err, "Remove")
+					err = tree.Insert(ctx, []byte(fmt.Sprintf("key %d %d", v-1, i+1)), []byte("updated"))
+					require.NoError(err, "Insert")
+				}
+			}
+
+			var h hash.Hash
+			_, h, err = tree.Commit(ctx, testNs, v)
+			require.NoError(err, "Commit")
+			if idx > 0 {
+				require.EqualValues(rootHash, h, "root hashes should be equal")
+			}
+			rootHash = h
+		}
+
+		root.Version = v
+		root.Hash = rootHash
+		for _, ndb := range ([]dbApi.NodeDB{ndb1, ndb2})[:len(trees)] {
+			err = ndb.Finalize([]node.Root{root})
+			require.NoError(err, "Finalize")
+		}
+		for _, tree := range trees {
+			tree.Close()
+		}
+
+		if v == baseVersion {
+			baseRoot = root
+		}
+	}
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb1)
+	require.NoError(err, "NewFileCreator")
+
+	// Create a delta checkpoint and make sure it is smaller than the full one.
+	const chunkSize = 4 * 1024
+	fullCp, err := fc.CreateCheckpoint(ctx, VersionSnappy, root, chunkSize)
+	require.NoError(err, "CreateCheckpoint")
+	cp, err := fc.CreateDeltaCheckpoint(ctx, VersionSnappy, baseRoot, root, chunkSize)
+	require.NoError(err, "CreateDeltaCheckpoint")
+	require.True(cp.IsDelta())
+	require.EqualValues(baseRoot, *cp.BaseRoot)
+	require.Less(len(cp.Chunks), len(fullCp.Chunks), "delta checkpoint should be smaller")
+
+	_, err = fc.CreateDeltaCheckpoint(ctx, VersionSnappy, root, baseRoot, chunkSize)
+	require.Error(err, "CreateDeltaCheckpoint should fail with base root after root")
+
+	cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: VersionSnappy, Namespace: testNs})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(cps, 1, "delta checkpoints should not be included by default")
+	cps, err = fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: VersionSnappy, Namespace: testNs, IncludeDeltas: true})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(cps, 2, "delta checkpoints should be included when requested")
+
+	rs, err := NewRestorer(ndb2)
+	require.NoError(err, "NewRestorer")
+
+	// Restoring a delta checkpoint without the base root should fail.
+	bogusCp := *cp
+	bogusCp.BaseRoot = &node.Root{
+		Namespace: testNs,
+		Version:   baseVersion + 1,
+		Type:      node.RootTypeState,
+		Hash:      root.Hash,
+	}
+	err = rs.StartRestore(ctx, &bogusCp)
+	require.ErrorIs(err, ErrBaseRootNotFound)
+
+	// Restore the delta checkpoint in reverse chunk order.
+	err = ndb2.StartMultipartInsert(cp.Root.Version)
+	require.NoError(err, "StartMultipartInsert")
+	err = rs.StartRestore(ctx, cp)
+	require.NoError(err, "StartRestore")
+	for i := len(cp.Chunks) - 1; i >= 0; i-- {
+		var cm *ChunkMetadata
+		cm, err = cp.GetChunkMetadata(uint64(i))
+		require.NoError(err, "GetChunkMetadata")
+
+		var buf bytes.Buffer
+		err = fc.GetCheckpointChunk(ctx, cm, &buf)
+		require.NoError(err, "GetChunk")
+
+		var done bool
+		done, err = rs.RestoreChunk(ctx, uint64(i), &buf)
+		require.NoError(err, "RestoreChunk")
+		require.Equal(i == 0, done, "restore should be done after the last chunk")
+	}
+	err = ndb2.Finalize([]node.Root{root})
+	require.NoError(err, "Finalize")
+
+	checkState := func() {
+		tree1 := mkvs.NewWithRoot(nil, ndb1, root)
+		defer tree1.Close()
+		tree2 := mkvs.NewWithRoot(nil, ndb2, root)
+		defer tree2.Close()
+
+		it1 := tree1.NewIterator(ctx)
+		defer it1.Close()
+		it2 := tree2.NewIterator(ctx)
+		defer it2.Close()
+
+		var numKeys int
+		it2.Rewind()
+		for it1.Rewind(); it1.Valid(); it1.Next() {
+			require.True(it2.Valid(), "restored tree should contain all keys")
+			require.EqualValues(it1.Key(), it2.Key())
+			require.EqualValues(it1.Value(), it2.Value())
+			it2.Next()
+			numKeys++
+		}
+		require.NoError(it1.Err(), "iterator")
+		require.NoError(it2.Err(), "iterator")
+		require.False(it2.Valid(), "restored tree should not contain extra keys")
+		require.NotZero(numKeys)
+	}
+	checkState()
+
+	// Prune all previous versions and make sure the restored state is still available.
+	for v := uint64(0); v < root.Version; v++ {
+		err = ndb2.Prune(v)
+		require.NoError(err, "Prune(%d)", v)
+	}
+	checkState()
+}
+
+func TestDeltaCheckpointChain(t *testing.T) {
+	dbTesting.TestMultipleBackends(t, db.Backends, testDeltaCheckpointChain)
+}
+
+func testDeltaCheckpointChain(t *testing.T, factory dbApi.Factory) {
+	require := require.New(t)
+
+	dir, err := os.MkdirTemp("", "mkvs.checkpoint")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb1, err := factory.New(&dbApi.Config{
+		DB:        filepath.Join(dir, "db1"),
+		Namespace: testNs,
+	})
+	require.NoError(err, "New")
+
+	ndb2, err := factory.New(&dbApi.Config{
+		DB:        filepath.Join(dir, "db2"),
+		Namespace: testNs,
+	})
+	require.NoError(err, "New")
+
+	ctx := context.Background()
+	root := node.Root{
+		Namespace: testNs,
+		Version:   0,
+		Type:      node.RootTypeState,
+	}
+	root.Hash.Empty()
+
+	const (
+		numVersions       = 10
+		numKeysPerVersion = 200
+		interval          = 3
+	)
+
+	// Generate some versions in the first database and remember the checkpointed roots.
+	var roots []node.Root
+	for v := uint64(0); v < numVersions; v++ {
+		tree := mkvs.NewWithRoot(nil, ndb1, root)
+		for i := 0; i < numKeysPerVersion; i++ {
+			err = tree.Insert(ctx, []byte(fmt.Sprintf("key %d %d", v, i)), []byte(strconv.Itoa(i)))
+			require.NoError(err, "Insert")
+		}
+		if v > 0 {
+			for i := 0; i < numKeysPerVersion; i += 10 {
+				err = tree.Remove(ctx, []byte(fmt.Sprintf("key %d %d", v-1, i)))
+				require.NoError(err, "Remove")
+			}
+		}
+		_, root.Hash, err = tree.Commit(ctx, testNs, v)
+		require.NoError(err, "Commit")
+		tree.Close()
+
+		root.Version = v
+		err = ndb1.Finalize([]node.Root{root})
+		require.NoError(err, "Finalize")
+
+		if v > 0 && v%interval == 0 {
+			roots = append(roots, root)
+		}
+	}
+	require.Len(roots, 3)
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb1)
+	require.NoError(err, "NewFileCreator")
+
+	// Create a chain consisting of a full checkpoint followed by delta checkpoints.
+	const chunkSize = 4 * 1024
+	fullCp, err := fc.CreateCheckpoint(ctx, VersionSnappy, roots[0], chunkSize)
+	require.NoError(err, "CreateCheckpoint")
+	chain := []*Metadata{fullCp}
+	for i := 1; i < len(roots); i++ {
+		var cp *Metadata
+		cp, err = fc.CreateDeltaCheckpoint(ctx, VersionSnappy, roots[i-1], roots[i], chunkSize)
+		require.NoError(err, "CreateDeltaCheckpoint")
+		chain = append(chain, cp)
+	}
+
+	rs, err := NewRestorer(ndb2)
+	require.NoError(err, "NewRestorer")
+
+	// Restore the chain in order, finalizing each intermediate root.
+	for _, cp := range chain {
+		err = ndb2.StartMultipartInsert(cp.Root.Version)
+		require.NoError(err, "StartMultipartInsert")
+		err = rs.StartRestore(ctx, cp)
+		require.NoError(err, "StartRestore")
+		for i := range cp.Chunks {
+			var cm *ChunkMetadata
+			cm, err = cp.GetChunkMetadata(uint64(i))
+			require.NoError(err, "GetChunkMetadata")
+
+			var buf bytes.Buffer
+			err = fc.GetCheckpointChunk(ctx, cm, &buf)
+			require.NoError(err, "GetChunk")
+
+			var done bool
+			done, err = rs.RestoreChunk(ctx, uint64(i), &buf)
+			require.NoError(err, "RestoreChunk")
+			require.Equal(i == len(cp.Chunks)-1, done, "restore should be done after the last chunk")
+		}
+		err = ndb2.Finalize([]node.Root{cp.Root})
+		require.NoError(err, "Finalize")
+		require.True(ndb2.HasRoot(cp.Root), "restored root should be available")
+	}
+
+	checkState := func() {
+		tree1 := mkvs.NewWithRoot(nil, ndb1, root)
+		defer tree1.Close()
+		tree2 := mkvs.NewWithRoot(nil, ndb2, root)
+		defer tree2.Close()
+
+		it1 := tree1.NewIterator(ctx)
+		defer it1.Close()
+		it2 := tree2.NewIterator(ctx)
+		defer it2.Close()
+
+		it2.Rewind()
+		for it1.Rewind(); it1.Valid(); it1.Next() {
+			require.True(it2.Valid(), "restored tree should contain all keys")
+			require.EqualValues(it1.Key(), it2.Key())
+			require.EqualValues(it1.Value(), it2.Value())
+			it2.Next()
+		}
+		require.NoError(it1.Err(), "iterator")
+		require.NoError(it2.Err(), "iterator")
+		require.False(it2.Valid(), "restored tree should not contain extra keys")
+	}
+	checkState()
+
+	// Prune all previous versions and make sure the restored state is still available.
+	for v := roots[0].Version; v < root.Version; v++ {
+		err = ndb2.Prune(v)
+		require.NoError(err, "Prune(%d)", v)
+	}
+	checkState()
+}
+
+func TestContentAddressedCheckpoint(t *testing.T) {
+	dbTesting.TestMultipleBackends(t, db.Backends, testContentAddressedCheckpoint)
+}
+
+func testContentAddressedCheckpoint(t *testing.T, factory dbApi.Factory) {
+	require := require.New(t)
+
+	dir, err := os.MkdirTemp("", "mkvs.checkpoint")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := factory.New(&dbApi.Config{
+		DB:        filepath.Join(dir, "db"),
+		Namespace: testNs,
+	})
+	require.NoError(err, "New")
+
+	// Create two versions with identical roots.
+	ctx := context.Background()
+	var roots []node.Root
+	root := node.Root{
+		Namespace: testNs,
+		Type:      node.RootTypeState,
+	}
+	root.Hash.Empty()
+	for v := uint64(0); v < 2; v++ {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		if v == 0 {
+			for i := 0; i < 1000; i++ {
+				err = tree.Insert(ctx, []byte(strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+				require.NoError(err, "Insert")
+			}
+		}
+
+		var rootHash hash.Hash
+		_, rootHash, err = tree.Commit(ctx, testNs, v)
+		require.NoError(err, "Commit")
+		tree.Close()
+
+		root.Version = v
+		root.Hash = rootHash
+		err = ndb.Finalize([]node.Root{root})
+		require.NoError(err, "Finalize")
+		roots = append(roots, root)
+	}
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+
+	// Zstd-compressed chunks should be smaller than snappy-compressed ones.
+	snappyCp, err := fc.CreateCheckpoint(ctx, VersionSnappy, roots[0], 16*1024)
+	require.NoError(err, "CreateCheckpoint")
+	var cps []*Metadata
+	for _, root := range roots {
+		var cp *Metadata
+		cp, err = fc.CreateCheckpoint(ctx, VersionZstd, root, 16*1024)
+		require.NoError(err, "CreateCheckpoint")
+		require.EqualValues(VersionZstd, cp.Version, "version should be correct")
+		cps = append(cps, cp)
+	}
+	require.EqualValues(cps[0].Chunks, cps[1].Chunks, "identical roots should have identical chunks")
+
+	var snappySize, zstdSize int
+	for i := range snappyCp.Chunks {
+		var buf bytes.Buffer
+		cm, _ := snappyCp.GetChunkMetadata(uint64(i))
+		err = fc.GetCheckpointChunk(ctx, cm, &buf)
+		require.NoError(err, "GetCheckpointChunk")
+		snappySize += buf.Len()
+	}
+	for i := range cps[0].Chunks {
+		var buf bytes.Buffer
+		cm, _ := cps[0].GetChunkMetadata(uint64(i))
+		err = fc.GetCheckpointChunk(ctx, cm, &buf)
+		require.NoError(err, "GetCheckpointChunk")
+		zstdSize += buf.Len()
+	}
+	require.Less(zstdSize, snappySize, "zstd-compressed chunks should be smaller")
+
+	// Checkpoints should only be reported for the requested version.
+	zstdCps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: VersionZstd})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(zstdCps, 2)
+	snappyCps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: VersionSnappy})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(snappyCps, 1)
+
+	// Identical chunks should only be stored once.
+	chunkStoreDir := filepath.Join(dir, "checkpoints", "v2", "chunks")
+	entries, err := os.ReadDir(chunkStoreDir)
+	require.NoError(err, "ReadDir")
+	require.Len(entries, len(cps[0].Chunks), "identical chunks should be deduplicated")
+
+	// Chunks should only be served for checkpoints that contain them.
+	cm, err := cps[0].GetChunkMetadata(0)
+	require.NoError(err, "GetChunkMetadata")
+	invalidChunk := *cm
+	invalidChunk.Index = 1
+	err = fc.GetCheckpointChunk(ctx, &invalidChunk, io.Discard)
+	require.ErrorIs(err, ErrChunkNotFound)
+
+	// Restore the checkpoint into a fresh database.
+	ndb2, err := factory.New(&dbApi.Config{
+		DB:        filepath.Join(dir, "db2"),
+		Namespace: testNs,
+	})
+	require.NoError(err, "New")
+	rs, err := NewRestorer(ndb2)
+	require.NoError(err, "NewRestorer")
+
+	cp := cps[1]
+	err = ndb2.StartMultipartInsert(cp.Root.Version)
+	require.NoError(err, "StartMultipartInsert")
+	err = rs.StartRestore(ctx, cp)
+	require.NoError(err, "StartRestore")
+	for i := range cp.Chunks {
+		cm, err = cp.GetChunkMetadata(uint64(i))
+		require.NoError(err, "GetChunkMetadata")
+
+		var buf bytes.Buffer
+		err = fc.GetCheckpointChunk(ctx, cm, &buf)
+		require.NoError(err, "GetCheckpointChunk")
+		_, err = rs.RestoreChunk(ctx, uint64(i), &buf)
+		require.NoError(err, "RestoreChunk")
+	}
+	err = ndb2.Finalize([]node.Root{cp.Root})
+	require.NoError(err, "Finalize")
+
+	tree := mkvs.NewWithRoot(nil, ndb2, cp.Root)
+	defer tree.Close()
+	for i := 0; i < 1000; i++ {
+		var value []byte
+		value, err = tree.Get(ctx, []byte(strconv.Itoa(i)))
+		require.NoError(err, "Get(%d)", i)
+		require.Equal([]byte(strconv.Itoa(i)), value)
+	}
+
+	// Chunks should only be removed once no longer referenced.
+	err = fc.DeleteCheckpoint(ctx, VersionZstd, roots[0])
+	require.NoError(err, "DeleteCheckpoint")
+	entries, err = os.ReadDir(chunkStoreDir)
+	require.NoError(err, "ReadDir")
+	require.Len(entries, len(cps[0].Chunks), "referenced chunks should not be removed")
+
+	err = fc.DeleteCheckpoint(ctx, VersionZstd, roots[1])
+	require.NoError(err, "DeleteCheckpoint")
+	entries, err = os.ReadDir(chunkStoreDir)
+	require.NoError(err, "ReadDir")
+	require.Empty(entries, "unreferenced chunks should be removed")
+}
@@ -39,6 +39,9 @@ type CheckpointerConfig struct {
 	// RootsPerVersion is the number of roots per version.
 	RootsPerVersion int
 
+	// Version is the version of the created checkpoints. If not specified, VersionSnappy is used.
+	Version uint16
+
 	// Parameters are the checkpoint creation parameters.
 	Parameters *CreationParameters
 	// GetParameters can be used instead of specifying Parameters to dynamically fetch the current
@@ -50,6 +53,10 @@ type CheckpointerConfig struct {
 	//
 	// This must return exactly RootsPerVersion roots.
 	GetRoots func(context.Context, uint64) ([]node.Root, error)
+
+	// CreateDeltas specifies whether delta checkpoints against the previous checkpoint interval
+	// should be created in addition to full checkpoints.
+	CreateDeltas bool
 }
 
 // CreationParameters are the checkpoint creation parameters used by the checkpointer.
@@ -135,16 +142,18 @@ func (c *checkpointer) Pause(pause bool) {
 	c.pausedCh <- pause
 }
 
+func (c *checkpointer) getRoots(ctx context.Context, version uint64) ([]node.Root, error) {
+	if c.cfg.GetRoots == nil {
+		return c.ndb.GetRootsForVersion(version)
+	}
+	return c.cfg.GetRoots(ctx, version)
+}
+
 func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *CreationParameters) (err error) {
 	// Notify watchers about the checkpoint we are about to make.
 	c.cpNotifier.Broadcast(version)
 
-	var roots []node.Root
-	if c.cfg.GetRoots == nil {
-		roots, err = c.ndb.GetRootsForVersion(version)
-	} else {
-		roots, err = c.cfg.GetRoots(ctx, version)
-	}
+	roots, err := c.getRoots(ctx, version)
 	if err != nil {
 		return fmt.Errorf("checkpointer: failed to get storage roots: %w", err)
 	}
@@ -162,7 +171,7 @@ func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *C
 
 		// If there is an error, make sure to remove any created checkpoints.
 		for _, root := range roots {
-			_ = c.creator.DeleteCheckpoint(ctx, checkpointVersion, root)
+			_ = c.creator.DeleteCheckpoint(ctx, c.cfg.Version, root)
 		}
 	}()
 
@@ -177,7 +186,7 @@ func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *C
 			"chunk_size", params.ChunkSize,
 		)
 
-		_, err = c.creator.CreateCheckpoint(ctx, root, params.ChunkSize)
+		_, err = c.creator.CreateCheckpoint(ctx, c.cfg.Version, root, params.ChunkSize)
 		if err != nil {
 			c.logger.Error("failed to create checkpoint",
 				"root", root,
@@ -186,13 +195,67 @@ func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *C
 			return fmt.Errorf("checkpointer: failed to create checkpoint: %w", err)
 		}
 	}
+
+	if c.cfg.CreateDeltas {
+		c.checkpointDeltas(ctx, version, roots, params)
+	}
 	return nil
 }
 
+// checkpointDeltas creates delta checkpoints for the given roots against the roots of the same
+// type from the previous checkpoint interval. Failure to create a delta checkpoint is not fatal
+// as full checkpoints are always available.
+func (c *checkpointer) checkpointDeltas(ctx context.Context, version uint64, roots []node.Root, params *CreationParameters) {
+	if params.Interval == 0 || version < params.Interval {
+		return
+	}
+	baseVersion := version - params.Interval
+	if baseVersion < params.InitialVersion || baseVersion < c.ndb.GetEarliestVersion() {
+		return
+	}
+
+	baseRoots, err := c.getRoots(ctx, baseVersion)
+	if err != nil {
+		c.logger.Warn("failed to get base roots for delta checkpoint",
+			"version", version,
+			"base_version", baseVersion,
+			"err", err,
+		)
+		return
+	}
+
+	for _, root := range roots {
+		// Roots without child roots (e.g. IO roots) are not carried over between versions.
+		if db.PolicyForRoot(root).NoChildRoots {
+			continue
+		}
+
+		for _, baseRoot := range baseRoots {
+			if baseRoot.Type != root.Type {
+				continue
+			}
+
+			c.logger.Info("creating new delta checkpoint",
+				"root", root,
+				"base_root", baseRoot,
+				"chunk_size", params.ChunkSize,
+			)
+
+			if _, err = c.creator.CreateDeltaCheckpoint(ctx, c.cfg.Version, baseRoot, root, params.ChunkSize); err != nil {
+				c.logger.Warn("failed to create delta checkpoint",
+					"root", root,
+					"base_root", baseRoot,
+					"err", err,
+				)
+			}
+		}
+	}
+}
+
 func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, params *CreationParameters) error {
 	// Get a list of all current checkpoints.
 	cps, err := c.creator.GetCheckpoints(ctx, &GetCheckpointsRequest{
-		Version:   checkpointVersion,
+		Version:   c.cfg.Version,
 		Namespace: c.cfg.Namespace,
 	})
 	if err != nil {
@@ -266,7 +329,7 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 
 		for _, version := range cpVersions[:len(cpVersions)-int(params.NumKept)] {
 			for _, root := range cpsByVersion[version] {
-				if err = c.creator.DeleteCheckpoint(ctx, checkpointVersion, root); err != nil {
+				if err = c.creator.DeleteCheckpoint(ctx, c.cfg.Version, root); err != nil {
 					c.logger.Warn("failed to garbage collect checkpoint",
 						"root", root,
 						"err", err,
@@ -277,6 +340,39 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 		}
 	}
 
+	// Garbage collect checkpoints of other versions (e.g., in case the configured checkpoint
+	// version has changed), again making sure that genesis checkpoint is excluded.
+	for _, version := range SupportedVersions {
+		if version == c.cfg.Version {
+			continue
+		}
+
+		var stale []*Metadata
+		stale, err = c.creator.GetCheckpoints(ctx, &GetCheckpointsRequest{
+			Version:   version,
+			Namespace: c.cfg.Namespace,
+		})
+		if err != nil {
+			c.logger.Warn("failed to get checkpoints of other versions",
+				"version", version,
+				"err", err,
+			)
+			continue
+		}
+		for _, cp := range stale {
+			if cp.Root.Version == params.InitialVersion {
+				continue
+			}
+			if err = c.creator.DeleteCheckpoint(ctx, version, cp.Root); err != nil {
+				c.logger.Warn("failed to garbage collect checkpoint",
+					"root", cp.Root,
+					"version", version,
+					"err", err,
+				)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -388,6 +484,13 @@ func NewCheckpointer(
 	creator Creator,
 	cfg CheckpointerConfig,
 ) (Checkpointer, error) {
+	if cfg.Version == 0 {
+		cfg.Version = VersionSnappy
+	}
+	if !IsVersionSupported(cfg.Version) {
+		return nil, fmt.Errorf("checkpointer: unsupported checkpoint version: %d", cfg.Version)
+	}
+
 	c := &checkpointer{
 		cfg:        cfg,
 		ndb:        ndb,
@@ -88,6 +88,7 @@ func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, inte
 			}
 			return ndb.GetRootsForVersion(version)
 		},
+		CreateDeltas: true,
 	})
 	require.NoError(err, "NewCheckpointer")
 
@@ -122,7 +123,7 @@ func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, inte
 		// Make sure that there are always the correct number of checkpoints.
 		if round > earliestVersion+(testNumKept+1)*interval {
 			cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
-				Version:   checkpointVersion,
+				Version:   VersionSnappy,
 				Namespace: testNs,
 			})
 			require.NoError(err, "GetCheckpoints")
@@ -138,6 +139,24 @@ func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, inte
 		}
 	}
 
+	// Make sure that delta checkpoints against the previous interval were created.
+	cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
+		Version:       VersionSnappy,
+		Namespace:     testNs,
+		IncludeDeltas: true,
+	})
+	require.NoError(err, "GetCheckpoints")
+
+	var numDeltas int
+	for _, cpm := range cps {
+		if !cpm.IsDelta() {
+			continue
+		}
+		require.Equal(cpm.Root.Version-interval, cpm.BaseRoot.Version, "delta checkpoint base should be correct")
+		numDeltas++
+	}
+	require.NotZero(numDeltas, "delta checkpoints should have been created")
+
 	// Force a checkpoint at a version outside the regular interval.
 	if interval > 1 {
 		cpVersion := round - interval + 1
@@ -151,7 +170,7 @@ func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, inte
 
 		// Make sure that the correct checkpoint was created.
 		cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
-			Version:   checkpointVersion,
+			Version:   VersionSnappy,
 			Namespace: testNs,
 		})
 		require.NoError(err, "GetCheckpoints")
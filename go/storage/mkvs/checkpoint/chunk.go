@@ -7,6 +7,7 @@ import (
 	"io"
 
 	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
@@ -16,8 +17,52 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 )
 
+// maxDecompressorMemory is the maximum amount of memory that the chunk decompressor may use.
+const maxDecompressorMemory = 64 * 1024 * 1024
+
+type nopCloser struct {
+	io.Reader
+}
+
+func (nopCloser) Close() {}
+
+// newChunkWriter returns a writer that encodes chunk data according to the given checkpoint
+// version. The writer must be closed to flush any buffered data.
+func newChunkWriter(version uint16, w io.Writer) (io.WriteCloser, error) {
+	switch version {
+	case VersionSnappy:
+		return snappy.NewBufferedWriter(w), nil
+	case VersionZstd:
+		return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	default:
+		return nil, fmt.Errorf("chunk: unsupported checkpoint version: %d", version)
+	}
+}
+
+// newChunkReader returns a reader that decodes chunk data according to the given checkpoint
+// version. The reader must be closed after use.
+func newChunkReader(version uint16, r io.Reader) (interface {
+	io.Reader
+	Close()
+}, error,
+) {
+	switch version {
+	case VersionSnappy:
+		return nopCloser{snappy.NewReader(r)}, nil
+	case VersionZstd:
+		// Decode synchronously so that the decoder does not read ahead in the background.
+		return zstd.NewReader(r,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxMemory(maxDecompressorMemory),
+		)
+	default:
+		return nil, fmt.Errorf("chunk: unsupported checkpoint version: %d", version)
+	}
+}
+
 func createChunk(
 	ctx context.Context,
+	version uint16,
 	tree mkvs.Tree,
 	root node.Root,
 	offset node.Key,
@@ -59,28 +104,71 @@ func createChunk(
 	it.Next()
 	nextOffset = it.Key()
 
+	chunkHash, err = writeChunk(version, proof, w)
+	return
+}
+
+// writeChunk writes the given proof as a chunk of the given checkpoint version and returns the
+// chunk digest.
+func writeChunk(version uint16, proof *syncer.Proof, w io.Writer) (hash.Hash, error) {
 	hb := hash.NewBuilder()
-	sw := snappy.NewBufferedWriter(io.MultiWriter(w, hb))
+	sw, err := newChunkWriter(version, io.MultiWriter(w, hb))
+	if err != nil {
+		return hash.Hash{}, err
+	}
 	enc := cbor.NewEncoder(sw)
 	for _, entry := range proof.Entries {
-		if err = enc.Encode(entry); err != nil {
-			err = fmt.Errorf("chunk: failed to encode chunk part: %w", err)
-			return
+		if err := enc.Encode(entry); err != nil {
+			return hash.Hash{}, fmt.Errorf("chunk: failed to encode chunk part: %w", err)
 		}
 	}
-	if err = sw.Close(); err != nil {
-		err = fmt.Errorf("chunk: failed to close chunk: %w", err)
-		return
+	if err := sw.Close(); err != nil {
+		return hash.Hash{}, fmt.Errorf("chunk: failed to close chunk: %w", err)
 	}
 
-	chunkHash = hb.Build()
-	return
+	return hb.Build(), nil
 }
 
 func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io.Reader) error {
+	ptr, err := readChunk(ctx, chunk, r)
+	if err != nil {
+		return err
+	}
+
+	// Import chunk into the node database.
+	emptyRoot := node.Root{
+		Namespace: chunk.Root.Namespace,
+		Version:   chunk.Root.Version,
+		Type:      chunk.Root.Type,
+	}
+	emptyRoot.Hash.Empty()
+
+	batch, err := ndb.NewBatch(emptyRoot, chunk.Root.Version, true)
+	if err != nil {
+		return fmt.Errorf("chunk: failed to create batch: %w", err)
+	}
+	defer batch.Reset()
+
+	if err = doRestoreChunk(ctx, batch, ptr, nil); err != nil {
+		return fmt.Errorf("chunk: node import failed: %w", err)
+	}
+	if err = batch.Commit(chunk.Root); err != nil {
+		return fmt.Errorf("chunk: node import failed: %w", err)
+	}
+
+	return nil
+}
+
+// readChunk reads the given chunk, verifies its integrity and returns the in-memory subtree
+// represented by the chunk's proof.
+func readChunk(ctx context.Context, chunk *ChunkMetadata, r io.Reader) (*node.Pointer, error) {
 	hb := hash.NewBuilder()
 	tr := io.TeeReader(r, hb)
-	sr := snappy.NewReader(tr)
+	sr, err := newChunkReader(chunk.Version, tr)
+	if err != nil {
+		return nil, err
+	}
+	defer sr.Close()
 	dec := cbor.NewDecoder(sr)
 
 	// Reconstruct the proof.
@@ -89,7 +177,7 @@ func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io
 	p.V = checkpointProofsVersion
 	for {
 		if ctx.Err() != nil {
-			return ctx.Err()
+			return nil, ctx.Err()
 		}
 
 		var entry []byte
@@ -112,7 +200,7 @@ func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io
 	// Verify overall chunk integrity.
 	chunkHash := hb.Build()
 	if !chunk.Digest.Equal(&chunkHash) {
-		return fmt.Errorf("%w: digest incorrect (expected: %s got: %s)",
+		return nil, fmt.Errorf("%w: digest incorrect (expected: %s got: %s)",
 			ErrChunkCorrupted,
 			chunk.Digest,
 			chunkHash,
@@ -121,38 +209,16 @@ func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io
 
 	// Treat decode errors after integrity verification as proof verification failures.
 	if decodeErr != nil {
-		return fmt.Errorf("%w: %s", ErrChunkProofVerificationFailed, decodeErr.Error())
+		return nil, fmt.Errorf("%w: %s", ErrChunkProofVerificationFailed, decodeErr.Error())
 	}
 
 	// Verify the proof.
 	var pv syncer.ProofVerifier
 	ptr, err := pv.VerifyProof(ctx, chunk.Root.Hash, &p)
 	if err != nil {
-		return fmt.Errorf("%w: %s", ErrChunkProofVerificationFailed, err.Error())
-	}
-
-	// Import chunk into the node database.
-	emptyRoot := node.Root{
-		Namespace: chunk.Root.Namespace,
-		Version:   chunk.Root.Version,
-		Type:      chunk.Root.Type,
-	}
-	emptyRoot.Hash.Empty()
-
-	batch, err := ndb.NewBatch(emptyRoot, chunk.Root.Version, true)
-	if err != nil {
-		return fmt.Errorf("chunk: failed to create batch: %w", err)
-	}
-	defer batch.Reset()
-
-	if err = doRestoreChunk(ctx, batch, ptr, nil); err != nil {
-		return fmt.Errorf("chunk: node import failed: %w", err)
+		return nil, fmt.Errorf("%w: %s", ErrChunkProofVerificationFailed, err.Error())
 	}
-	if err = batch.Commit(chunk.Root); err != nil {
-		return fmt.Errorf("chunk: node import failed: %w", err)
-	}
-
-	return nil
+	return ptr, nil
 }
 
 func doRestoreChunk(
@@ -0,0 +1,464 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+)
+
+// Delta checkpoints contain only the nodes of the target root that are not present at the same
+// position in the base root. Each delta chunk is a proof for the target root where all subtrees
+// that are either unchanged or are contained in other chunks are only included by hash.
+//
+// Subtrees are matched by their position in the tree instead of only by their hash so that node
+// databases which can only resolve nodes by following pointers from a root are supported.
+
+// deltaCursor is a position in the base tree.
+type deltaCursor struct {
+	// ptr is the pointer to the base tree node.
+	ptr *node.Pointer
+	// path is the key prefix leading to the node's incoming edge.
+	path node.Key
+	// depth is the bit depth at which the node's incoming edge starts.
+	depth node.Depth
+}
+
+// matches returns true iff the given target tree pointer at the given depth points to the same
+// subtree as the cursor.
+func (c *deltaCursor) matches(ptr *node.Pointer, depth node.Depth) bool {
+	if c == nil || ptr == nil || c.depth != depth {
+		return false
+	}
+	return c.ptr.Hash.Equal(&ptr.Hash)
+}
+
+// nodeEnd returns the key prefix and bit depth at which the given node ends.
+func nodeEnd(n node.Node, path node.Key, depth node.Depth) (node.Key, node.Depth) {
+	switch n := n.(type) {
+	case *node.InternalNode:
+		return path.Merge(depth, n.Label, n.LabelBitLength), depth + n.LabelBitLength
+	case *node.LeafNode:
+		return n.Key, n.Key.BitLength()
+	default:
+		return path, depth
+	}
+}
+
+// deltaBase is the base tree of a delta checkpoint.
+type deltaBase struct {
+	ndb  db.NodeDB
+	root node.Root
+
+	// shared is true iff the node database allows the restored root to reference base nodes
+	// instead of storing its own copies.
+	shared bool
+}
+
+// newDeltaBase creates a new delta checkpoint base tree.
+func newDeltaBase(ndb db.NodeDB, root node.Root) *deltaBase {
+	sharer, ok := ndb.(db.NodeSharer)
+	return &deltaBase{
+		ndb:    ndb,
+		root:   root,
+		shared: ok && sharer.SharesNodes(),
+	}
+}
+
+// rootCursor returns the cursor pointing to the base tree root.
+func (b *deltaBase) rootCursor() *deltaCursor {
+	if b.root.Hash.IsEmpty() {
+		return nil
+	}
+	return &deltaCursor{
+		ptr: &node.Pointer{
+			Clean: true,
+			Hash:  b.root.Hash,
+		},
+	}
+}
+
+func (b *deltaBase) resolve(ptr *node.Pointer) (node.Node, error) {
+	if ptr.Node != nil {
+		return ptr.Node, nil
+	}
+	n, err := b.ndb.GetNode(b.root, ptr)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to get base node: %w", err)
+	}
+	return n, nil
+}
+
+// childCursors returns the base tree cursors corresponding to the left and right children of
+// the given target tree internal node which ends at the given key prefix and depth.
+func (b *deltaBase) childCursors(c *deltaCursor, path node.Key, depth node.Depth) (*deltaCursor, *deltaCursor, error) {
+	// Find the base node that ends at or below the target node and is on the same path.
+	for c != nil {
+		n, err := b.resolve(c.ptr)
+		if err != nil {
+			return nil, nil, err
+		}
+		endPath, endDepth := nodeEnd(n, c.path, c.depth)
+		if endPath.CommonPrefixLen(endDepth, path, depth) < min(endDepth, depth) {
+			// Paths diverge, nothing to match against.
+			return nil, nil, nil
+		}
+
+		in, ok := n.(*node.InternalNode)
+		switch {
+		case !ok:
+			return nil, nil, nil
+		case endDepth == depth:
+			// Both nodes end at the same position, so their children can be matched.
+			var left, right *deltaCursor
+			if in.Left != nil {
+				left = &deltaCursor{ptr: in.Left, path: path, depth: depth}
+			}
+			if in.Right != nil {
+				right = &deltaCursor{ptr: in.Right, path: path, depth: depth}
+			}
+			return left, right, nil
+		case endDepth > depth:
+			// Base node continues below the target node, it can only match one of the children.
+			if endPath.GetBit(depth) {
+				return nil, c, nil
+			}
+			return c, nil, nil
+		}
+
+		// Base node ends before the target node, continue with its child on the same path.
+		next := in.Left
+		if path.GetBit(endDepth) {
+			next = in.Right
+		}
+		if next == nil {
+			return nil, nil, nil
+		}
+		c = &deltaCursor{ptr: next, path: endPath, depth: endDepth}
+	}
+	return nil, nil, nil
+}
+
+// importSubtree imports the base subtree at the given base pointer into the given batch.
+//
+// In case the node database supports sharing nodes, only the subtree root is stored and its
+// children reference the existing base nodes. Otherwise the whole subtree is copied.
+func (b *deltaBase) importSubtree(ctx context.Context, batch db.Batch, basePtr, ptr, parent *node.Pointer) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	bn, err := b.resolve(basePtr)
+	if err != nil {
+		return err
+	}
+
+	// Make sure to always use fresh pointers as the base pointers may contain database-specific
+	// metadata which refers to the base root. Referenced children must keep the metadata as it
+	// is needed to resolve them.
+	freshPtr := func(p *node.Pointer) *node.Pointer {
+		if p == nil {
+			return nil
+		}
+		return &node.Pointer{Clean: true, Hash: p.Hash}
+	}
+	childPtr := freshPtr
+	if b.shared {
+		childPtr = func(p *node.Pointer) *node.Pointer {
+			if p == nil {
+				return nil
+			}
+			return &node.Pointer{Clean: true, Hash: p.Hash, DBInternal: p.DBInternal}
+		}
+	}
+
+	switch n := bn.(type) {
+	case *node.InternalNode:
+		in := &node.InternalNode{
+			Clean:          true,
+			Hash:           n.Hash,
+			Label:          n.Label,
+			LabelBitLength: n.LabelBitLength,
+			LeafNode:       freshPtr(n.LeafNode),
+			Left:           childPtr(n.Left),
+			Right:          childPtr(n.Right),
+		}
+		if n.LeafNode != nil {
+			var leaf node.Node
+			if leaf, err = b.resolve(n.LeafNode); err != nil {
+				return err
+			}
+			in.LeafNode.Node = leaf.ExtractUnchecked()
+		}
+		ptr.Node = in
+
+		if err = batch.VisitDirtyNode(ptr, parent); err != nil {
+			return err
+		}
+		if err = doRestoreChunk(ctx, batch, in.LeafNode, ptr); err != nil {
+			return err
+		}
+		for _, child := range []struct {
+			base, ptr *node.Pointer
+		}{
+			{n.Left, in.Left},
+			{n.Right, in.Right},
+		} {
+			switch {
+			case child.base == nil:
+			case b.shared:
+				err = batch.VisitCleanNode(child.ptr, ptr)
+			default:
+				err = b.importSubtree(ctx, batch, child.base, child.ptr, ptr)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err = batch.PutNode(ptr); err != nil {
+			return err
+		}
+
+		// Children have been stored, so there is no need to keep them in memory.
+		if in.Left != nil {
+			in.Left.Node = nil
+		}
+		if in.Right != nil {
+			in.Right.Node = nil
+		}
+	case *node.LeafNode:
+		ptr.Node = n.ExtractUnchecked()
+
+		if err = batch.VisitDirtyNode(ptr, parent); err != nil {
+			return err
+		}
+		if err = batch.PutNode(ptr); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("checkpoint: unexpected base node type %T", bn)
+	}
+	return nil
+}
+
+// deltaChunker splits the nodes which differ between the base and the target root into chunks.
+type deltaChunker struct {
+	ndb       db.NodeDB
+	root      node.Root
+	base      *deltaBase
+	chunkSize uint64
+	emit      func(*syncer.Proof) error
+
+	pb        *syncer.ProofBuilder
+	ancestors []node.Node
+}
+
+func (dc *deltaChunker) include(ctx context.Context, n node.Node) error {
+	if dc.pb == nil {
+		// Each chunk must be a proof for the root, so include the path leading to the node.
+		dc.pb = syncer.NewProofBuilderV0(dc.root.Hash, dc.root.Hash)
+		for _, an := range dc.ancestors {
+			dc.pb.Include(an)
+		}
+	}
+	dc.pb.Include(n)
+
+	if dc.pb.Size() < dc.chunkSize {
+		return nil
+	}
+	return dc.flush(ctx)
+}
+
+func (dc *deltaChunker) flush(ctx context.Context) error {
+	if dc.pb == nil {
+		return nil
+	}
+
+	proof, err := dc.pb.Build(ctx)
+	if err != nil {
+		return fmt.Errorf("chunk: failed to build proof: %w", err)
+	}
+	dc.pb = nil
+
+	return dc.emit(proof)
+}
+
+func (dc *deltaChunker) visit(ctx context.Context, ptr *node.Pointer, path node.Key, depth node.Depth, bc *deltaCursor) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if ptr == nil || bc.matches(ptr, depth) {
+		// Unchanged subtrees are only included by hash.
+		return nil
+	}
+
+	n, err := dc.ndb.GetNode(dc.root, ptr)
+	if err != nil {
+		return fmt.Errorf("chunk: failed to get node: %w", err)
+	}
+	if err = dc.include(ctx, n); err != nil {
+		return err
+	}
+
+	in, ok := n.(*node.InternalNode)
+	if !ok {
+		return nil
+	}
+	endPath, endDepth := nodeEnd(in, path, depth)
+	left, right, err := dc.base.childCursors(bc, endPath, endDepth)
+	if err != nil {
+		return err
+	}
+
+	dc.ancestors = append(dc.ancestors, n)
+	defer func() {
+		dc.ancestors = dc.ancestors[:len(dc.ancestors)-1]
+	}()
+
+	if err = dc.visit(ctx, in.Left, endPath, endDepth, left); err != nil {
+		return err
+	}
+	return dc.visit(ctx, in.Right, endPath, endDepth, right)
+}
+
+// createDeltaChunks generates delta checkpoint chunks containing all nodes of the given root
+// that are not present in the given base root and stores them using the passed function.
+func createDeltaChunks(
+	ctx context.Context,
+	ndb db.NodeDB,
+	baseRoot node.Root,
+	root node.Root,
+	chunkSize uint64,
+	storeChunk func(index int, proof *syncer.Proof) (hash.Hash, error),
+) ([]hash.Hash, error) {
+	var chunks []hash.Hash
+	emit := func(proof *syncer.Proof) error {
+		chunkHash, err := storeChunk(len(chunks), proof)
+		if err != nil {
+			return fmt.Errorf("checkpoint: failed to create chunk %d: %w", len(chunks), err)
+		}
+
+		chunks = append(chunks, chunkHash)
+		return nil
+	}
+
+	dc := &deltaChunker{
+		ndb:       ndb,
+		root:      root,
+		base:      newDeltaBase(ndb, baseRoot),
+		chunkSize: chunkSize,
+		emit:      emit,
+	}
+	rootPtr := &node.Pointer{Clean: true, Hash: root.Hash}
+	if err := dc.visit(ctx, rootPtr, nil, 0, dc.base.rootCursor()); err != nil {
+		return nil, err
+	}
+	if err := dc.flush(ctx); err != nil {
+		return nil, err
+	}
+
+	// In case nothing has changed, emit a single chunk referencing the base root.
+	if len(chunks) == 0 {
+		dc.pb = syncer.NewProofBuilderV0(root.Hash, root.Hash)
+		if err := dc.flush(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return chunks, nil
+}
+
+func restoreDeltaChunk(
+	ctx context.Context,
+	ndb db.NodeDB,
+	chunk *ChunkMetadata,
+	r io.Reader,
+	claimBase func(hash.Hash) bool,
+) error {
+	ptr, err := readChunk(ctx, chunk, r)
+	if err != nil {
+		return err
+	}
+
+	// Import chunk into the node database.
+	emptyRoot := node.Root{
+		Namespace: chunk.Root.Namespace,
+		Version:   chunk.Root.Version,
+		Type:      chunk.Root.Type,
+	}
+	emptyRoot.Hash.Empty()
+
+	batch, err := ndb.NewBatch(emptyRoot, chunk.Root.Version, true)
+	if err != nil {
+		return fmt.Errorf("chunk: failed to create batch: %w", err)
+	}
+	defer batch.Reset()
+
+	base := newDeltaBase(ndb, *chunk.BaseRoot)
+	if err = doRestoreDeltaChunk(ctx, batch, base, claimBase, ptr, nil, nil, 0, base.rootCursor()); err != nil {
+		return fmt.Errorf("chunk: node import failed: %w", err)
+	}
+	if err = batch.Commit(chunk.Root); err != nil {
+		return fmt.Errorf("chunk: node import failed: %w", err)
+	}
+
+	return nil
+}
+
+func doRestoreDeltaChunk(
+	ctx context.Context,
+	batch db.Batch,
+	base *deltaBase,
+	claimBase func(hash.Hash) bool,
+	ptr *node.Pointer,
+	parent *node.Pointer,
+	path node.Key,
+	depth node.Depth,
+	bc *deltaCursor,
+) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if ptr == nil {
+		return nil
+	}
+
+	switch n := ptr.Node.(type) {
+	case nil:
+		// Subtrees that are unchanged are imported from the base root, but only once as they may
+		// be referenced by multiple chunks. All other subtrees are restored by other chunks.
+		if bc.matches(ptr, depth) && claimBase(ptr.Hash) {
+			return base.importSubtree(ctx, batch, bc.ptr, ptr, parent)
+		}
+		return batch.VisitDirtyNode(ptr, parent)
+	case *node.InternalNode:
+		if err := batch.VisitDirtyNode(ptr, parent); err != nil {
+			return err
+		}
+
+		// Commit internal leaf (considered to be on the same depth as the internal node).
+		if err := doRestoreChunk(ctx, batch, n.LeafNode, ptr); err != nil {
+			return err
+		}
+
+		endPath, endDepth := nodeEnd(n, path, depth)
+		left, right, err := base.childCursors(bc, endPath, endDepth)
+		if err != nil {
+			return err
+		}
+		if err = doRestoreDeltaChunk(ctx, batch, base, claimBase, n.Left, ptr, endPath, endDepth, left); err != nil {
+			return err
+		}
+		if err = doRestoreDeltaChunk(ctx, batch, base, claimBase, n.Right, ptr, endPath, endDepth, right); err != nil {
+			return err
+		}
+
+		// Store the node.
+		return batch.PutNode(ptr)
+	default:
+		return doRestoreChunk(ctx, batch, ptr, parent)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
@@ -14,39 +15,144 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 )
 
 const (
 	chunksDir              = "chunks"
+	deltasDir              = "deltas"
 	checkpointMetadataFile = "meta"
-	checkpointVersion      = 1
 
-	// Versions 1 of checkpoint chunks use proofs version 0. Consider bumping
+	// chunkStoreTmpPattern is the pattern of temporary files in the content-addressed chunk store.
+	chunkStoreTmpPattern = ".tmp-*"
+
+	// All versions of checkpoint chunks use proofs version 0. Consider bumping
 	// this to latest version when introducing new checkpoint versions.
 	checkpointProofsVersion = 0
 )
 
+// chunkStoreFunc stores a chunk written by the given function and returns its digest.
+type chunkStoreFunc func(index int, write func(w io.Writer) (hash.Hash, error)) (hash.Hash, error)
+
 type fileCreator struct {
 	dataDir string
 	ndb     db.NodeDB
+
+	// storeLock serializes garbage collection of the content-addressed chunk store with
+	// checkpoint creation, so that chunks are never removed while being referenced.
+	storeLock sync.RWMutex
 }
 
-func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64) (meta *Metadata, err error) {
-	tree := mkvs.NewWithRoot(nil, fc.ndb, root)
-	defer tree.Close()
+// versionedDataDir returns the directory containing all checkpoints of the given version.
+//
+// Version 1 checkpoints are stored directly in the data directory for backwards compatibility.
+func (fc *fileCreator) versionedDataDir(version uint16) string {
+	if version == VersionSnappy {
+		return fc.dataDir
+	}
+	return filepath.Join(fc.dataDir, "v"+strconv.FormatUint(uint64(version), 10))
+}
 
-	// Create checkpoint directory.
-	checkpointDir := filepath.Join(
-		fc.dataDir,
+// chunkStoreDir returns the directory of the content-addressed chunk store of the given version.
+func (fc *fileCreator) chunkStoreDir(version uint16) string {
+	return filepath.Join(fc.versionedDataDir(version), chunksDir)
+}
+
+// isContentAddressed returns true iff chunks of the given version are stored in the
+// content-addressed chunk store instead of alongside the checkpoint metadata.
+func isContentAddressed(version uint16) bool {
+	return version != VersionSnappy
+}
+
+// checkpointDir returns the directory of the checkpoint for the given root. In case a base root
+// is given, the directory of the corresponding delta checkpoint is returned instead.
+func (fc *fileCreator) checkpointDir(version uint16, root node.Root, baseRoot *node.Root) string {
+	dir := filepath.Join(
+		fc.versionedDataDir(version),
 		strconv.FormatUint(root.Version, 10),
 		root.Hash.String(),
 	)
+	if baseRoot == nil {
+		return dir
+	}
+	return filepath.Join(
+		dir,
+		deltasDir,
+		strconv.FormatUint(baseRoot.Version, 10),
+		baseRoot.Hash.String(),
+	)
+}
+
+// storeChunk stores a chunk written by the given function and returns its digest.
+func (fc *fileCreator) storeChunk(
+	version uint16,
+	checkpointDir string,
+	index int,
+	write func(w io.Writer) (hash.Hash, error),
+) (hash.Hash, error) {
+	if !isContentAddressed(version) {
+		dataFilename := filepath.Join(checkpointDir, chunksDir, strconv.Itoa(index))
+		f, err := os.Create(dataFilename)
+		if err != nil {
+			return hash.Hash{}, fmt.Errorf("checkpoint: failed to create chunk file for chunk %d: %w", index, err)
+		}
+		defer f.Close()
+
+		return write(f)
+	}
+
+	storeDir := fc.chunkStoreDir(version)
+	f, err := os.CreateTemp(storeDir, chunkStoreTmpPattern)
+	if err != nil {
+		return hash.Hash{}, fmt.Errorf("checkpoint: failed to create chunk file for chunk %d: %w", index, err)
+	}
+	defer func() {
+		// Make sure to remove the temporary file in case it has not been moved.
+		_ = os.Remove(f.Name())
+	}()
+
+	chunkHash, err := write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	// Identical chunks only need to be stored once.
+	chunkFilename := filepath.Join(storeDir, chunkHash.String())
+	if _, err = os.Stat(chunkFilename); err == nil {
+		return chunkHash, nil
+	}
+	if err = os.Rename(f.Name(), chunkFilename); err != nil {
+		return hash.Hash{}, fmt.Errorf("checkpoint: failed to store chunk %d: %w", index, err)
+	}
+	return chunkHash, nil
+}
+
+// createCheckpoint creates a checkpoint in the given directory, using the given function to
+// generate the chunks.
+func (fc *fileCreator) createCheckpoint(
+	meta *Metadata,
+	checkpointDir string,
+	createChunks func(storeChunk chunkStoreFunc) ([]hash.Hash, error),
+) (_ *Metadata, err error) {
+	if !IsVersionSupported(meta.Version) {
+		return nil, fmt.Errorf("checkpoint: unsupported checkpoint version: %d", meta.Version)
+	}
+
+	// Prevent chunk garbage collection while the checkpoint is being created.
+	fc.storeLock.RLock()
+	defer fc.storeLock.RUnlock()
+
+	// Create checkpoint directory.
 	if err = common.Mkdir(checkpointDir); err != nil {
 		return nil, fmt.Errorf("checkpoint: failed to create checkpoint directory: %w", err)
 	}
 	defer func() {
 		if err != nil {
 			// In case we have failed to create a checkpoint, make sure to clean up after ourselves.
+			// Any unreferenced chunks will be removed during the next garbage collection.
 			_ = os.RemoveAll(checkpointDir)
 		}
 	}()
@@ -63,66 +169,101 @@ func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chu
 
 	// Create chunks directory.
 	chunksDir := filepath.Join(checkpointDir, chunksDir)
+	if isContentAddressed(meta.Version) {
+		chunksDir = fc.chunkStoreDir(meta.Version)
+	}
 	if err = common.Mkdir(chunksDir); err != nil {
 		return nil, fmt.Errorf("checkpoint: failed to create chunk directory: %w", err)
 	}
 
 	// Create chunks until we are done.
-	var chunks []hash.Hash
-	var nextOffset node.Key
-	for chunkIndex := 0; ; chunkIndex++ {
-		dataFilename := filepath.Join(chunksDir, strconv.Itoa(chunkIndex))
-
-		// Generate chunk.
-		var f *os.File
-		if f, err = os.Create(dataFilename); err != nil {
-			return nil, fmt.Errorf("checkpoint: failed to create chunk file for chunk %d: %w", chunkIndex, err)
-		}
-
-		var chunkHash hash.Hash
-		chunkHash, nextOffset, err = createChunk(ctx, tree, root, nextOffset, chunkSize, f)
-		f.Close()
-		if err != nil {
-			return nil, fmt.Errorf("checkpoint: failed to create chunk %d: %w", chunkIndex, err)
-		}
-
-		chunks = append(chunks, chunkHash)
-
-		// Check if we are finished.
-		if nextOffset == nil {
-			break
-		}
+	storeChunk := func(index int, write func(w io.Writer) (hash.Hash, error)) (hash.Hash, error) {
+		return fc.storeChunk(meta.Version, checkpointDir, index, write)
 	}
-
-	// Generate and write checkpoint metadata.
-	meta = &Metadata{
-		Version: checkpointVersion,
-		Root:    root,
-		Chunks:  chunks,
+	if meta.Chunks, err = createChunks(storeChunk); err != nil {
+		return nil, err
 	}
 
+	// Write checkpoint metadata.
 	if err = os.WriteFile(filepath.Join(checkpointDir, checkpointMetadataFile), cbor.Marshal(meta), 0o600); err != nil {
 		return nil, fmt.Errorf("checkpoint: failed to create checkpoint metadata: %w", err)
 	}
 	return meta, nil
 }
 
-func (fc *fileCreator) GetCheckpoints(_ context.Context, request *GetCheckpointsRequest) ([]*Metadata, error) {
-	// Currently we only support a single version so we report no checkpoints for other versions.
-	if request.Version != checkpointVersion {
-		return []*Metadata{}, nil
+func (fc *fileCreator) CreateCheckpoint(ctx context.Context, version uint16, root node.Root, chunkSize uint64) (*Metadata, error) {
+	tree := mkvs.NewWithRoot(nil, fc.ndb, root)
+	defer tree.Close()
+
+	meta := &Metadata{
+		Version: version,
+		Root:    root,
+	}
+	createChunks := func(storeChunk chunkStoreFunc) ([]hash.Hash, error) {
+		var chunks []hash.Hash
+		var nextOffset node.Key
+		for chunkIndex := 0; ; chunkIndex++ {
+			// Generate chunk.
+			chunkHash, err := storeChunk(chunkIndex, func(w io.Writer) (chunkHash hash.Hash, err error) {
+				chunkHash, nextOffset, err = createChunk(ctx, version, tree, root, nextOffset, chunkSize, w)
+				return
+			})
+			if err != nil {
+				return nil, fmt.Errorf("checkpoint: failed to create chunk %d: %w", chunkIndex, err)
+			}
+
+			chunks = append(chunks, chunkHash)
+
+			// Check if we are finished.
+			if nextOffset == nil {
+				return chunks, nil
+			}
+		}
 	}
 
-	// Apply optional root version filter.
-	versionGlob := "*"
-	if request.RootVersion != nil {
-		versionGlob = strconv.FormatUint(*request.RootVersion, 10)
+	return fc.createCheckpoint(meta, fc.checkpointDir(version, root, nil), createChunks)
+}
+
+func (fc *fileCreator) CreateDeltaCheckpoint(ctx context.Context, version uint16, baseRoot, root node.Root, chunkSize uint64) (*Metadata, error) {
+	if !baseRoot.Namespace.Equal(&root.Namespace) || baseRoot.Type != root.Type {
+		return nil, fmt.Errorf("checkpoint: base root %s is incompatible with root %s", baseRoot, root)
+	}
+	if baseRoot.Version >= root.Version {
+		return nil, fmt.Errorf("checkpoint: base root version (%d) must be lower than root version (%d)",
+			baseRoot.Version, root.Version,
+		)
 	}
 
-	matches, err := filepath.Glob(filepath.Join(fc.dataDir, versionGlob, "*", checkpointMetadataFile))
+	meta := &Metadata{
+		Version:  version,
+		Root:     root,
+		BaseRoot: &baseRoot,
+	}
+	createChunks := func(storeChunk chunkStoreFunc) ([]hash.Hash, error) {
+		// Create chunks containing only the changes against the base root.
+		return createDeltaChunks(ctx, fc.ndb, baseRoot, root, chunkSize, func(index int, proof *syncer.Proof) (hash.Hash, error) {
+			return storeChunk(index, func(w io.Writer) (hash.Hash, error) {
+				return writeChunk(version, proof, w)
+			})
+		})
+	}
+
+	return fc.createCheckpoint(meta, fc.checkpointDir(version, root, &baseRoot), createChunks)
+}
+
+func (fc *fileCreator) getCheckpoints(version uint16, versionGlob string, includeDeltas bool) ([]*Metadata, error) {
+	matches, err := filepath.Glob(filepath.Join(fc.versionedDataDir(version), versionGlob, "*", checkpointMetadataFile))
 	if err != nil {
 		return nil, fmt.Errorf("checkpoint: failed to enumerate checkpoints: %w", err)
 	}
+	if includeDeltas {
+		var deltas []string
+		deltas, err = filepath.Glob(filepath.Join(fc.versionedDataDir(version), versionGlob, "*", deltasDir, "*", "*", checkpointMetadataFile))
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint: failed to enumerate delta checkpoints: %w", err)
+		}
+		matches = append(matches, deltas...)
+	}
 
 	var cps []*Metadata
 	for _, m := range matches {
@@ -141,18 +282,27 @@ func (fc *fileCreator) GetCheckpoints(_ context.Context, request *GetCheckpoints
 	return cps, nil
 }
 
+func (fc *fileCreator) GetCheckpoints(_ context.Context, request *GetCheckpointsRequest) ([]*Metadata, error) {
+	// We report no checkpoints for unsupported versions.
+	if !IsVersionSupported(request.Version) {
+		return []*Metadata{}, nil
+	}
+
+	// Apply optional root version filter.
+	versionGlob := "*"
+	if request.RootVersion != nil {
+		versionGlob = strconv.FormatUint(*request.RootVersion, 10)
+	}
+
+	return fc.getCheckpoints(request.Version, versionGlob, request.IncludeDeltas)
+}
+
 func (fc *fileCreator) GetCheckpoint(_ context.Context, version uint16, root node.Root) (*Metadata, error) {
-	// Currently we only support a single version.
-	if version != checkpointVersion {
+	if !IsVersionSupported(version) {
 		return nil, ErrCheckpointNotFound
 	}
 
-	checkpointFilename := filepath.Join(
-		fc.dataDir,
-		strconv.FormatUint(root.Version, 10),
-		root.Hash.String(),
-		checkpointMetadataFile,
-	)
+	checkpointFilename := filepath.Join(fc.checkpointDir(version, root, nil), checkpointMetadataFile)
 	data, err := os.ReadFile(checkpointFilename)
 	if err != nil {
 		return nil, ErrCheckpointNotFound
@@ -166,13 +316,12 @@ func (fc *fileCreator) GetCheckpoint(_ context.Context, version uint16, root nod
 }
 
 func (fc *fileCreator) DeleteCheckpoint(_ context.Context, version uint16, root node.Root) error {
-	// Currently we only support a single version.
-	if version != checkpointVersion {
+	if !IsVersionSupported(version) {
 		return ErrCheckpointNotFound
 	}
 
-	versionDir := filepath.Join(fc.dataDir, strconv.FormatUint(root.Version, 10))
-	checkpointDir := filepath.Join(versionDir, root.Hash.String())
+	versionDir := filepath.Join(fc.versionedDataDir(version), strconv.FormatUint(root.Version, 10))
+	checkpointDir := fc.checkpointDir(version, root, nil)
 	checkpointFilename := filepath.Join(checkpointDir, checkpointMetadataFile)
 	if err := os.Remove(checkpointFilename); err != nil {
 		return ErrCheckpointNotFound
@@ -201,22 +350,76 @@ func (fc *fileCreator) DeleteCheckpoint(_ context.Context, version uint16, root
 		return fmt.Errorf("checkpoint: failed to read directory: %w", err)
 	}
 
+	if isContentAddressed(version) {
+		return fc.collectChunks(version)
+	}
+	return nil
+}
+
+// collectChunks removes all chunks from the content-addressed chunk store of the given version
+// that are no longer referenced by any checkpoint.
+func (fc *fileCreator) collectChunks(version uint16) error {
+	fc.storeLock.Lock()
+	defer fc.storeLock.Unlock()
+
+	cps, err := fc.getCheckpoints(version, "*", true)
+	if err != nil {
+		return err
+	}
+	referenced := make(map[string]struct{})
+	for _, cp := range cps {
+		for _, chunkHash := range cp.Chunks {
+			referenced[chunkHash.String()] = struct{}{}
+		}
+	}
+
+	entries, err := os.ReadDir(fc.chunkStoreDir(version))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("checkpoint: failed to read chunk store: %w", err)
+	}
+	for _, entry := range entries {
+		if _, ok := referenced[entry.Name()]; ok {
+			continue
+		}
+		if err = os.Remove(filepath.Join(fc.chunkStoreDir(version), entry.Name())); err != nil {
+			return fmt.Errorf("checkpoint: failed to remove unreferenced chunk: %w", err)
+		}
+	}
 	return nil
 }
 
 func (fc *fileCreator) GetCheckpointChunk(_ context.Context, chunk *ChunkMetadata, w io.Writer) error {
-	// Currently we only support a single version.
-	if chunk.Version != checkpointVersion {
+	if !IsVersionSupported(chunk.Version) {
 		return ErrChunkNotFound
 	}
 
-	chunkFilename := filepath.Join(
-		fc.dataDir,
-		strconv.FormatUint(chunk.Root.Version, 10),
-		chunk.Root.Hash.String(),
-		chunksDir,
-		strconv.FormatUint(chunk.Index, 10),
-	)
+	var chunkFilename string
+	switch isContentAddressed(chunk.Version) {
+	case true:
+		// Make sure that the chunk is part of the given checkpoint.
+		data, err := os.ReadFile(filepath.Join(fc.checkpointDir(chunk.Version, chunk.Root, chunk.BaseRoot), checkpointMetadataFile))
+		if err != nil {
+			return ErrChunkNotFound
+		}
+		var cp Metadata
+		if err = cbor.Unmarshal(data, &cp); err != nil {
+			return fmt.Errorf("checkpoint: corrupted checkpoint metadata: %w", err)
+		}
+		if chunk.Index >= uint64(len(cp.Chunks)) || !cp.Chunks[chunk.Index].Equal(&chunk.Digest) {
+			return ErrChunkNotFound
+		}
+
+		chunkFilename = filepath.Join(fc.chunkStoreDir(chunk.Version), chunk.Digest.String())
+	case false:
+		chunkFilename = filepath.Join(
+			fc.checkpointDir(chunk.Version, chunk.Root, chunk.BaseRoot),
+			chunksDir,
+			strconv.FormatUint(chunk.Index, 10),
+		)
+	}
 
 	f, err := os.Open(chunkFilename)
 	if err != nil {
@@ -231,6 +434,9 @@ func (fc *fileCreator) GetCheckpointChunk(_ context.Context, chunk *ChunkMetadat
 }
 
 // NewFileCreator creates a new checkpoint creator that writes created chunks into the filesystem.
+//
+// Chunks of checkpoints with versions other than VersionSnappy are stored in a content-addressed
+// chunk store so that identical chunks are only stored once.
 func NewFileCreator(dataDir string, ndb db.NodeDB) (Creator, error) {
 	return &fileCreator{
 		dataDir: dataDir,
@@ -3,9 +3,11 @@ package checkpoint
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"sync"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 )
 
@@ -20,6 +22,9 @@ type restorer struct {
 	currentCheckpoint *Metadata
 	// pendingChunks is a set of pending chunks.
 	pendingChunks map[uint64]bool
+	// importedBase is a set of base root subtrees that have already been imported in case a delta
+	// checkpoint is being restored.
+	importedBase map[hash.Hash]struct{}
 }
 
 // Implements Restorer.
@@ -31,11 +36,25 @@ func (rs *restorer) StartRestore(_ context.Context, checkpoint *Metadata) error
 		return ErrRestoreAlreadyInProgress
 	}
 
+	if !IsVersionSupported(checkpoint.Version) {
+		return fmt.Errorf("checkpoint: unsupported checkpoint version: %d", checkpoint.Version)
+	}
+	if checkpoint.IsDelta() {
+		base := checkpoint.BaseRoot
+		if !base.Namespace.Equal(&checkpoint.Root.Namespace) || base.Type != checkpoint.Root.Type {
+			return fmt.Errorf("checkpoint: base root %s is incompatible with root %s", base, checkpoint.Root)
+		}
+		if !rs.ndb.HasRoot(*base) {
+			return ErrBaseRootNotFound
+		}
+	}
+
 	rs.currentCheckpoint = checkpoint
 	rs.pendingChunks = make(map[uint64]bool)
 	for idx := range checkpoint.Chunks {
 		rs.pendingChunks[uint64(idx)] = true
 	}
+	rs.importedBase = make(map[hash.Hash]struct{})
 
 	return nil
 }
@@ -46,6 +65,7 @@ func (rs *restorer) AbortRestore(context.Context) error {
 
 	rs.pendingChunks = nil
 	rs.currentCheckpoint = nil
+	rs.importedBase = nil
 
 	return nil
 }
@@ -83,7 +103,12 @@ func (rs *restorer) RestoreChunk(ctx context.Context, idx uint64, r io.Reader) (
 		return false, err
 	}
 
-	err = restoreChunk(ctx, rs.ndb, chunk, r)
+	switch chunk.BaseRoot {
+	case nil:
+		err = restoreChunk(ctx, rs.ndb, chunk, r)
+	default:
+		err = rs.restoreDeltaChunk(ctx, chunk, r)
+	}
 	switch {
 	case err == nil:
 	case errors.Is(err, ErrChunkProofVerificationFailed):
@@ -105,12 +130,44 @@ func (rs *restorer) RestoreChunk(ctx context.Context, idx uint64, r io.Reader) (
 	if len(rs.pendingChunks) == 0 {
 		rs.pendingChunks = nil
 		rs.currentCheckpoint = nil
+		rs.importedBase = nil
 		return true, nil
 	}
 
 	return false, nil
 }
 
+func (rs *restorer) restoreDeltaChunk(ctx context.Context, chunk *ChunkMetadata, r io.Reader) error {
+	// Make sure that each base subtree is only imported by a single chunk. In case the chunk fails
+	// to be restored, its claims are released so that the chunk can be retried.
+	var claimed []hash.Hash
+	claim := func(h hash.Hash) bool {
+		rs.Lock()
+		defer rs.Unlock()
+
+		if rs.importedBase == nil {
+			return false
+		}
+		if _, ok := rs.importedBase[h]; ok {
+			return false
+		}
+		rs.importedBase[h] = struct{}{}
+		claimed = append(claimed, h)
+		return true
+	}
+
+	err := restoreDeltaChunk(ctx, rs.ndb, chunk, r, claim)
+	if err != nil {
+		rs.Lock()
+		defer rs.Unlock()
+
+		for _, h := range claimed {
+			delete(rs.importedBase, h)
+		}
+	}
+	return err
+}
+
 // NewRestorer creates a new checkpoint restorer.
 func NewRestorer(ndb db.NodeDB) (Restorer, error) {
 	return &restorer{ndb: ndb}, nil
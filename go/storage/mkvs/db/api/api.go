@@ -181,6 +181,17 @@ type NodeDB interface {
 	// Size returns the size of the database in bytes.
 	Size() (int64, error)
 
+	// Usage returns a breakdown of the storage space used by the database.
+	//
+	// The breakdown is expensive to compute, so it may be cached for a while.
+	Usage(ctx context.Context) (*Usage, error)
+
+	// Compact garbage collects the value log until no more space that has been left behind by
+	// pruning can be reclaimed. It is safe to call while the database is in use as the LSM tree
+	// is only compacted by the background compactions. It may take a long time on large
+	// databases.
+	Compact(ctx context.Context) error
+
 	// Sync syncs the database to disk. This is useful if the NoFsync option is used to explicitly
 	// perform a sync.
 	Sync() error
@@ -189,6 +200,59 @@ type NodeDB interface {
 	Close()
 }
 
+// NodeSharer is an optional interface implemented by node databases in which a root can reference
+// nodes of any earlier root without storing its own copies, even if the roots are not derived
+// from each other. Referenced nodes must remain available after the earlier root is pruned.
+type NodeSharer interface {
+	// SharesNodes returns true iff nodes can be referenced by unrelated roots.
+	SharesNodes() bool
+}
+
+// Usage is a breakdown of the storage space used by a node database.
+type Usage struct {
+	// LSMSize is the on-disk size of the LSM tree in bytes.
+	LSMSize int64 `json:"lsm_size"`
+	// ValueLogSize is the on-disk size of the value log in bytes.
+	ValueLogSize int64 `json:"value_log_size"`
+
+	// Breakdown is the estimated size of live data per root type.
+	Breakdown *UsageBreakdown `json:"breakdown,omitempty"`
+}
+
+// UsageBreakdown is the estimated size of live data per root type.
+type UsageBreakdown struct {
+	// State is the estimated size of live data belonging to state roots.
+	State RootUsage `json:"state"`
+	// IO is the estimated size of live data belonging to I/O roots.
+	IO RootUsage `json:"io"`
+	// Other is the estimated size of live data that does not belong to any root, e.g. metadata.
+	Other uint64 `json:"other"`
+	// UnattributedNodes is the estimated size of tree nodes that cannot be attributed to a root
+	// type as the backend shares nodes between roots of different types. In this case, the size
+	// of tree nodes per root type only includes the root indices.
+	UnattributedNodes uint64 `json:"unattributed_nodes,omitempty"`
+}
+
+// ForRootType returns the usage for the given root type or nil if the root type is unknown.
+func (u *UsageBreakdown) ForRootType(rootType node.RootType) *RootUsage {
+	switch rootType {
+	case node.RootTypeState:
+		return &u.State
+	case node.RootTypeIO:
+		return &u.IO
+	default:
+		return nil
+	}
+}
+
+// RootUsage is the estimated size of live data belonging to roots of a given type.
+type RootUsage struct {
+	// Nodes is the estimated size of tree nodes in bytes.
+	Nodes uint64 `json:"nodes"`
+	// WriteLogs is the estimated size of write logs in bytes.
+	WriteLogs uint64 `json:"write_logs"`
+}
+
 // Batch is a NodeDB-specific batch implementation.
 type Batch interface {
 	// PutNode persists a node in the NodeDB.
@@ -292,6 +356,14 @@ func (d *nopNodeDB) Size() (int64, error) {
 	return 0, nil
 }
 
+func (d *nopNodeDB) Usage(context.Context) (*Usage, error) {
+	return &Usage{}, nil
+}
+
+func (d *nopNodeDB) Compact(context.Context) error {
+	return nil
+}
+
 func (d *nopNodeDB) Sync() error {
 	return nil
 }
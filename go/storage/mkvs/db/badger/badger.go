@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/dgraph-io/badger/v4"
 
@@ -123,6 +124,11 @@ type badgerNodeDB struct { // nolint: maligned
 	metaUpdateLock sync.Mutex
 	meta           metadata
 
+	// usageLock protects the cached usage breakdown.
+	usageLock      sync.Mutex
+	usageCache     *api.UsageBreakdown
+	usageCacheTime time.Time
+
 	closeOnce sync.Once
 }
 
@@ -703,7 +703,7 @@ func TestBadgerV3InitialFill(t *testing.T) {
 
 	fc, err := checkpoint.NewFileCreator(dir, ndb)
 	require.NoError(t, err, "NewFileCreator")
-	ckMeta, err := fc.CreateCheckpoint(ctx, node.Root{
+	ckMeta, err := fc.CreateCheckpoint(ctx, checkpoint.VersionSnappy, node.Root{
 		Namespace: testNs,
 		Version:   2,
 		Hash:      newRootHash,
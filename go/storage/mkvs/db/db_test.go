@@ -3,12 +3,16 @@ package db
 import (
 	"context"
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	dbTesting "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/testing"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 )
@@ -85,3 +89,86 @@ func TestHashedWriteLog(t *testing.T) {
 	}
 	require.Equal(t, i, len(wl))
 }
+
+func TestUsageAndCompact(t *testing.T) {
+	dbTesting.TestMultipleBackends(t, Backends, testUsageAndCompact)
+}
+
+func testUsageAndCompact(t *testing.T, factory api.Factory) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir, err := os.MkdirTemp("", "mkvs.db.usage")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ns := common.NewTestNamespaceFromSeed([]byte("mkvs db usage test ns"), 0)
+	ndb, err := factory.New(&api.Config{
+		DB:        dir,
+		Namespace: ns,
+		NoFsync:   true,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	var stateRoot node.Root
+	stateRoot.Empty()
+	stateRoot.Namespace = ns
+	stateRoot.Type = node.RootTypeState
+	const numVersions = 5
+	for version := uint64(0); version < numVersions; version++ {
+		stateTree := mkvs.NewWithRoot(nil, ndb, stateRoot)
+		ioTree := mkvs.New(nil, ndb, node.RootTypeIO)
+		for i := 0; i < 100; i++ {
+			key := []byte(fmt.Sprintf("key %d", i))
+			value := []byte(fmt.Sprintf("value %d %d", version, i))
+			require.NoError(stateTree.Insert(ctx, key, value), "Insert")
+			require.NoError(ioTree.Insert(ctx, key, value), "Insert")
+		}
+
+		_, stateHash, err := stateTree.Commit(ctx, ns, version)
+		require.NoError(err, "Commit")
+		_, ioHash, err := ioTree.Commit(ctx, ns, version)
+		require.NoError(err, "Commit")
+		stateTree.Close()
+		ioTree.Close()
+
+		stateRoot.Version = version
+		stateRoot.Hash = stateHash
+		ioRoot := node.Root{Namespace: ns, Version: version, Type: node.RootTypeIO, Hash: ioHash}
+		require.NoError(ndb.Finalize([]node.Root{stateRoot, ioRoot}), "Finalize")
+	}
+
+	usage, err := ndb.Usage(ctx)
+	require.NoError(err, "Usage")
+	require.NotNil(usage.Breakdown, "breakdown should be available")
+	require.NotZero(usage.Breakdown.State.WriteLogs, "state write logs should be accounted for")
+	require.NotZero(usage.Breakdown.IO.WriteLogs, "I/O write logs should be accounted for")
+	require.NotZero(usage.Breakdown.Other, "metadata should be accounted for")
+	require.NotZero(usage.Breakdown.State.Nodes, "state nodes should be accounted for")
+	require.NotZero(usage.Breakdown.IO.Nodes, "I/O nodes should be accounted for")
+	switch factory.Name() {
+	case "pathbadger":
+		require.Zero(usage.Breakdown.UnattributedNodes, "all nodes should be attributed to a root type")
+	default:
+		require.NotZero(usage.Breakdown.UnattributedNodes, "shared nodes should be accounted for")
+	}
+
+	// Prune all but the latest version and compact.
+	for version := uint64(0); version < numVersions-1; version++ {
+		require.NoError(ndb.Prune(version), "Prune")
+	}
+	err = ndb.Compact(ctx)
+	require.NoError(err, "Compact")
+
+	pruned, err := ndb.Usage(ctx)
+	require.NoError(err, "Usage")
+	require.Less(pruned.Breakdown.State.WriteLogs, usage.Breakdown.State.WriteLogs, "pruned write logs should not be accounted for")
+
+	// The latest version should still be available after compaction.
+	tree := mkvs.NewWithRoot(nil, ndb, stateRoot)
+	defer tree.Close()
+	value, err := tree.Get(ctx, []byte("key 42"))
+	require.NoError(err, "Get")
+	require.EqualValues(fmt.Sprintf("value %d 42", numVersions-1), string(value), "value should be correct")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/dgraph-io/badger/v4"
 
@@ -81,6 +82,11 @@ type badgerNodeDB struct { // nolint: maligned
 	metaUpdateLock sync.Mutex
 	meta           metadata
 
+	// usageLock protects the cached usage breakdown.
+	usageLock      sync.Mutex
+	usageCache     *api.UsageBreakdown
+	usageCacheTime time.Time
+
 	closeOnce sync.Once
 }
 
@@ -726,6 +732,14 @@ func (d *badgerNodeDB) NewBatch(oldRoot node.Root, version uint64, chunk bool) (
 	}, nil
 }
 
+// Implements api.NodeSharer.
+//
+// Finalized nodes are not tied to the version in which they were created and are only removed
+// once they are replaced in a later finalized version.
+func (d *badgerNodeDB) SharesNodes() bool {
+	return true
+}
+
 // Implements api.NodeDB.
 func (d *badgerNodeDB) Size() (int64, error) {
 	lsm, vlog := d.db.Size()
@@ -0,0 +1,110 @@
+package pathbadger
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// usageCacheTTL is the time for which the usage breakdown is cached.
+const usageCacheTTL = 10 * time.Minute
+
+// usageClass returns the root type the given key belongs to and whether it is a write log key.
+// The root type is invalid for keys that do not belong to any root (e.g. metadata).
+func usageClass(key []byte) (node.RootType, bool) {
+	var (
+		version  uint64
+		th       api.TypedHash
+		rootType uint8
+	)
+	switch {
+	case writeLogKeyFmt.Decode(key, &version, &th):
+		return th.Type(), true
+	case rootUpdatedNodesKeyFmt.Decode(key, &version, &th), rootNodeKeyFmt.Decode(key, &version, &th):
+		return th.Type(), false
+	case finalizedNodeKeyFmt.Decode(key, &rootType), multipartRestoreNodeLogKeyFmt.Decode(key, &rootType):
+		return node.RootType(rootType), false
+	case pendingNodeKeyFmt.Decode(key, &version, &rootType):
+		return node.RootType(rootType), false
+	default:
+		return node.RootTypeInvalid, false
+	}
+}
+
+// Implements api.NodeDB.
+func (d *badgerNodeDB) Usage(ctx context.Context) (*api.Usage, error) {
+	var usage api.Usage
+	usage.LSMSize, usage.ValueLogSize = d.db.Size()
+
+	breakdown, err := d.usageBreakdown(ctx)
+	if err != nil {
+		return nil, err
+	}
+	usage.Breakdown = breakdown
+	return &usage, nil
+}
+
+// usageBreakdown returns the per-root-type usage breakdown. As computing it requires a scan over
+// all keys, the result is cached for usageCacheTTL.
+func (d *badgerNodeDB) usageBreakdown(ctx context.Context) (*api.UsageBreakdown, error) {
+	d.usageLock.Lock()
+	defer d.usageLock.Unlock()
+
+	if d.usageCache != nil && time.Since(d.usageCacheTime) < usageCacheTTL {
+		breakdown := *d.usageCache
+		return &breakdown, nil
+	}
+
+	tx := d.db.NewTransactionAt(math.MaxUint64, false)
+	defer tx.Discard()
+	it := tx.NewIterator(badger.IteratorOptions{})
+	defer it.Close()
+
+	var breakdown api.UsageBreakdown
+	for it.Rewind(); it.Valid(); it.Next() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		item := it.Item()
+		size := uint64(item.EstimatedSize())
+		rootType, isWriteLog := usageClass(item.Key())
+		ru := breakdown.ForRootType(rootType)
+		switch {
+		case ru == nil:
+			breakdown.Other += size
+		case isWriteLog:
+			ru.WriteLogs += size
+		default:
+			ru.Nodes += size
+		}
+	}
+
+	cached := breakdown
+	d.usageCache = &cached
+	d.usageCacheTime = time.Now()
+
+	return &breakdown, nil
+}
+
+// Implements api.NodeDB.
+func (d *badgerNodeDB) Compact(ctx context.Context) error {
+	if d.readOnly {
+		return api.ErrReadOnly
+	}
+	if err := d.gc.Compact(ctx); err != nil {
+		return err
+	}
+
+	// Make sure the reclaimed space is reflected in the next usage query.
+	d.usageLock.Lock()
+	d.usageCache = nil
+	d.usageLock.Unlock()
+
+	return nil
+}
@@ -3,6 +3,7 @@ package mkvs
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
@@ -60,6 +61,62 @@ func (t *tree) SyncIterate(ctx context.Context, request *syncer.IterateRequest)
 	}, nil
 }
 
+// Implements syncer.ReadSyncer.
+func (t *tree) SyncGetRange(ctx context.Context, request *syncer.GetRangeRequest) (*syncer.ProofResponse, error) {
+	t.cache.Lock()
+	defer t.cache.Unlock()
+
+	if t.cache.isClosed() {
+		return nil, ErrClosed
+	}
+	if !request.Tree.Root.Equal(&t.cache.syncRoot) {
+		return nil, syncer.ErrInvalidRoot
+	}
+	if !t.cache.pendingRoot.IsClean() {
+		return nil, syncer.ErrDirtyRoot
+	}
+	if request.Limit == 0 {
+		return nil, fmt.Errorf("mkvs: range limit must be non-zero")
+	}
+	pb, err := syncer.NewProofBuilderForVersion(request.Tree.Root.Hash, request.Tree.Root.Hash, request.ProofVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	it := t.NewIterator(ctx,
+		WithProofBuilder(pb),
+		IteratorPrefetch(request.Limit),
+	)
+	defer it.Close()
+
+	// Iterate until reaching either the limit or the first key past the end of the range. In the
+	// latter case the proof will include the first key past the end which proves that there are
+	// no other keys in the range. When the limit is reached, the iterator is not advanced any
+	// further so that the proof only covers the included entries.
+	it.Seek(request.StartKey)
+	for count := uint16(0); it.Valid(); {
+		if request.EndKey != nil && it.Key().Compare(request.EndKey) >= 0 {
+			break
+		}
+		if count++; count >= request.Limit {
+			break
+		}
+		it.Next()
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+
+	proof, err := it.GetProof()
+	if err != nil {
+		return nil, err
+	}
+
+	return &syncer.ProofResponse{
+		Proof: *proof,
+	}, nil
+}
+
 func (t *tree) newFetcherSyncIterate(key node.Key, prefetch uint16) readSyncFetcher {
 	return func(ctx context.Context, ptr *node.Pointer, rs syncer.ReadSyncer) (*syncer.Proof, error) {
 		rsp, err := rs.SyncIterate(ctx, &syncer.IterateRequest{
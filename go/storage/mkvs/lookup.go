@@ -72,6 +72,50 @@ func (t *tree) SyncGet(ctx context.Context, request *syncer.GetRequest) (*syncer
 	}, nil
 }
 
+// Implements syncer.ReadSyncer.
+func (t *tree) SyncGetMulti(ctx context.Context, request *syncer.GetMultiRequest) (*syncer.ProofResponse, error) {
+	t.cache.Lock()
+	defer t.cache.Unlock()
+
+	if t.cache.isClosed() {
+		return nil, ErrClosed
+	}
+	if !request.Tree.Root.Equal(&t.cache.syncRoot) {
+		return nil, syncer.ErrInvalidRoot
+	}
+	if !t.cache.pendingRoot.IsClean() {
+		return nil, syncer.ErrDirtyRoot
+	}
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Always anchor the proof at the root as the keys may be in different subtrees.
+	pb, err := syncer.NewProofBuilderForVersion(request.Tree.Root.Hash, request.Tree.Root.Hash, request.ProofVersion)
+	if err != nil {
+		return nil, err
+	}
+	opts := doGetOptions{
+		proofBuilder: pb,
+	}
+	for _, key := range request.Keys {
+		// Remember where the path from root to target node ends (will end).
+		t.cache.markPosition()
+
+		if _, err = t.doGet(ctx, t.cache.pendingRoot, 0, key, opts, false); err != nil {
+			return nil, err
+		}
+	}
+	proof, err := pb.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &syncer.ProofResponse{
+		Proof: *proof,
+	}, nil
+}
+
 func (t *tree) newFetcherSyncGet(key node.Key, includeSiblings bool) readSyncFetcher {
 	return func(ctx context.Context, ptr *node.Pointer, rs syncer.ReadSyncer) (*syncer.Proof, error) {
 		rsp, err := rs.SyncGet(ctx, &syncer.GetRequest{
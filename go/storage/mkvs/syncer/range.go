@@ -0,0 +1,244 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+// ErrIncompleteProof is the error returned when a proof does not contain enough nodes to prove
+// the requested keys.
+var ErrIncompleteProof = errors.New("verifier: incomplete proof")
+
+// VerifiedRange is the result of verifying a range proof.
+type VerifiedRange struct {
+	// Entries are all of the key/value pairs in the proven range, in key order.
+	Entries writelog.WriteLog
+	// NextKey is the key at which the proven range ends in case the proof does not cover the
+	// whole requested range (e.g., because the number of entries was limited). A subsequent
+	// request should start at this key. If nil, the proof covers the whole requested range.
+	NextKey []byte
+}
+
+// VerifyGetMulti verifies a proof for the given keys as returned by SyncGetMulti and returns the
+// value for each key. Values of keys that the proof shows are not present are nil.
+func (pv *ProofVerifier) VerifyGetMulti(ctx context.Context, root hash.Hash, proof *Proof, keys [][]byte) ([][]byte, error) {
+	rootPtr, err := pv.VerifyProof(ctx, root, proof)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		value, err := lookupVerified(rootPtr, 0, node.Key{}, key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %X", err, key)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// VerifyRange verifies a proof for the range [startKey, endKey) as returned by SyncGetRange and
+// returns all of the key/value pairs in the part of the range covered by the proof. A nil endKey
+// denotes an unbounded range.
+//
+// The returned entries are guaranteed to be complete, i.e. there are no other keys under the
+// given root between startKey and the returned NextKey (or endKey if the whole range is covered).
+func (pv *ProofVerifier) VerifyRange(ctx context.Context, root hash.Hash, proof *Proof, startKey, endKey []byte) (*VerifiedRange, error) {
+	if endKey != nil && node.Key(startKey).Compare(endKey) >= 0 {
+		return nil, fmt.Errorf("verifier: empty range")
+	}
+
+	rootPtr, err := pv.VerifyProof(ctx, root, proof)
+	if err != nil {
+		return nil, err
+	}
+
+	w := rangeWalker{
+		ctx:   ctx,
+		start: startKey,
+		end:   endKey,
+	}
+	if err = w.walk(rootPtr, 0, node.Key{}, 0, false); err != nil {
+		return nil, err
+	}
+	if w.nextKey != nil && w.nextKey.Equal(startKey) && len(w.entries) == 0 {
+		return nil, fmt.Errorf("%w: range not covered", ErrIncompleteProof)
+	}
+
+	return &VerifiedRange{
+		Entries: w.entries,
+		NextKey: w.nextKey,
+	}, nil
+}
+
+// isUnresolved returns true iff the pointer refers to a non-empty subtree which is not included
+// in the proof.
+func isUnresolved(ptr *node.Pointer) bool {
+	return ptr != nil && ptr.Node == nil && !ptr.Hash.IsEmpty()
+}
+
+// isEmpty returns true iff the pointer refers to an empty subtree.
+func isEmpty(ptr *node.Pointer) bool {
+	return ptr == nil || (ptr.Node == nil && ptr.Hash.IsEmpty())
+}
+
+func lookupVerified(ptr *node.Pointer, bitDepth node.Depth, path, key node.Key) ([]byte, error) {
+	switch {
+	case isEmpty(ptr):
+		return nil, nil
+	case isUnresolved(ptr):
+		return nil, ErrIncompleteProof
+	}
+
+	switch n := ptr.Node.(type) {
+	case *node.InternalNode:
+		bitLength := bitDepth + n.LabelBitLength
+		newPath := path.Merge(bitDepth, n.Label, n.LabelBitLength)
+
+		// The key is not stored in this subtree unless it shares the whole path.
+		if key.BitLength() < bitLength || key.CommonPrefixLen(key.BitLength(), newPath, bitLength) < bitLength {
+			return nil, nil
+		}
+		if key.BitLength() == bitLength {
+			return lookupVerified(n.LeafNode, bitLength, newPath, key)
+		}
+		if key.GetBit(bitLength) {
+			return lookupVerified(n.Right, bitLength, newPath.AppendBit(bitLength, true), key)
+		}
+		return lookupVerified(n.Left, bitLength, newPath.AppendBit(bitLength, false), key)
+	case *node.LeafNode:
+		if n.Key.Equal(key) {
+			return n.Value, nil
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("verifier: unexpected node type: %T", n)
+	}
+}
+
+// prefixBelow returns true iff all keys with the given bit prefix are smaller than key.
+func prefixBelow(prefix node.Key, prefixLen node.Depth, key node.Key) bool {
+	cpl := prefix.CommonPrefixLen(prefixLen, key, key.BitLength())
+	if cpl == prefixLen || cpl == key.BitLength() {
+		return false
+	}
+	return !prefix.GetBit(cpl)
+}
+
+// prefixAtOrAbove returns true iff all keys with the given bit prefix are larger than or equal
+// to key.
+func prefixAtOrAbove(prefix node.Key, prefixLen node.Depth, key node.Key) bool {
+	cpl := prefix.CommonPrefixLen(prefixLen, key, key.BitLength())
+	switch cpl {
+	case prefixLen:
+		return minKeyWithPrefix(prefix, prefixLen).Compare(key) >= 0
+	case key.BitLength():
+		return true
+	default:
+		return prefix.GetBit(cpl)
+	}
+}
+
+// minKeyWithPrefix returns the smallest key with the given bit prefix.
+func minKeyWithPrefix(prefix node.Key, prefixLen node.Depth) node.Key {
+	minKey, _ := prefix.Split(prefixLen, prefix.BitLength())
+	return minKey
+}
+
+type rangeWalker struct {
+	ctx   context.Context
+	start node.Key
+	end   node.Key
+
+	entries writelog.WriteLog
+	nextKey node.Key
+	done    bool
+}
+
+func (w *rangeWalker) beforeStart(key node.Key) bool {
+	return key.Compare(w.start) < 0
+}
+
+func (w *rangeWalker) atOrAfterEnd(key node.Key) bool {
+	return w.end != nil && key.Compare(w.end) >= 0
+}
+
+// walk visits the given subtree in key order. The path is the known bit prefix of all keys in the
+// subtree and prefixLen is its length in bits. Leaf pointers of internal nodes have a key which
+// is exactly equal to the path.
+func (w *rangeWalker) walk(ptr *node.Pointer, bitDepth node.Depth, path node.Key, prefixLen node.Depth, leaf bool) error {
+	if w.done || isEmpty(ptr) {
+		return nil
+	}
+	if w.ctx.Err() != nil {
+		return w.ctx.Err()
+	}
+
+	if isUnresolved(ptr) {
+		// The subtree is not part of the proof, so the proof only covers the range if the subtree
+		// is entirely outside of it.
+		if leaf {
+			key := minKeyWithPrefix(path, prefixLen)
+			switch {
+			case w.beforeStart(key):
+			case w.atOrAfterEnd(key):
+				w.done = true
+			default:
+				w.nextKey = key
+				w.done = true
+			}
+			return nil
+		}
+
+		switch {
+		case prefixBelow(path, prefixLen, w.start):
+		case w.end != nil && prefixAtOrAbove(path, prefixLen, w.end):
+			w.done = true
+		default:
+			w.nextKey = minKeyWithPrefix(path, prefixLen)
+			if w.beforeStart(w.nextKey) {
+				w.nextKey = w.start
+			}
+			w.done = true
+		}
+		return nil
+	}
+
+	switch n := ptr.Node.(type) {
+	case *node.InternalNode:
+		bitLength := bitDepth + n.LabelBitLength
+		newPath := path.Merge(bitDepth, n.Label, n.LabelBitLength)
+
+		// Skip subtrees which are entirely before the start of the range.
+		if prefixBelow(newPath, bitLength, w.start) {
+			return nil
+		}
+
+		// Keys in the leaf node sort before keys in the left subtree which sort before keys in
+		// the right subtree.
+		if err := w.walk(n.LeafNode, bitLength, newPath, bitLength, true); err != nil {
+			return err
+		}
+		if err := w.walk(n.Left, bitLength, newPath.AppendBit(bitLength, false), bitLength+1, false); err != nil {
+			return err
+		}
+		return w.walk(n.Right, bitLength, newPath.AppendBit(bitLength, true), bitLength+1, false)
+	case *node.LeafNode:
+		switch {
+		case w.beforeStart(n.Key):
+		case w.atOrAfterEnd(n.Key):
+			w.done = true
+		default:
+			w.entries = append(w.entries, writelog.LogEntry{Key: n.Key, Value: n.Value})
+		}
+		return nil
+	default:
+		return fmt.Errorf("verifier: unexpected node type: %T", n)
+	}
+}
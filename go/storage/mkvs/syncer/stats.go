@@ -7,6 +7,8 @@ type StatsCollector struct {
 	SyncGetCount         int
 	SyncGetPrefixesCount int
 	SyncIterateCount     int
+	SyncGetMultiCount    int
+	SyncGetRangeCount    int
 
 	rs ReadSyncer
 }
@@ -32,3 +34,13 @@ func (c *StatsCollector) SyncIterate(ctx context.Context, request *IterateReques
 	c.SyncIterateCount++
 	return c.rs.SyncIterate(ctx, request)
 }
+
+func (c *StatsCollector) SyncGetMulti(ctx context.Context, request *GetMultiRequest) (*ProofResponse, error) {
+	c.SyncGetMultiCount++
+	return c.rs.SyncGetMulti(ctx, request)
+}
+
+func (c *StatsCollector) SyncGetRange(ctx context.Context, request *GetRangeRequest) (*ProofResponse, error) {
+	c.SyncGetRangeCount++
+	return c.rs.SyncGetRange(ctx, request)
+}
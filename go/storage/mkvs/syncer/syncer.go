@@ -20,8 +20,13 @@ var (
 	ErrUnsupported = errors.New("mkvs: method not supported")
 	// ErrUnsupportedProofVersion is the error returned when a ReadSyncer requests an unsuported proof version.
 	ErrUnsupportedProofVersion = errors.New("mkvs: unsupported proof version")
+	// ErrTooManyKeys is the error returned when a ReadSyncer request contains too many keys.
+	ErrTooManyKeys = errors.New("mkvs: too many keys")
 )
 
+// MaxGetMultiKeys is the maximum number of keys in a single SyncGetMulti request.
+const MaxGetMultiKeys = 1024
+
 // TreeID identifies a specific tree and a position within that tree.
 type TreeID struct {
 	// Root is the Merkle tree root.
@@ -64,6 +69,39 @@ type IterateRequest struct {
 	ProofVersion uint16 `json:"proof_version,omitempty"`
 }
 
+// GetMultiRequest is a request for the SyncGetMulti operation.
+type GetMultiRequest struct {
+	Tree TreeID   `json:"tree"`
+	Keys [][]byte `json:"keys"`
+
+	// ProofVersion specifies the proof version to use. If not specified,
+	// the default (0) version is used for backwards compatibility.
+	ProofVersion uint16 `json:"proof_version,omitempty"`
+}
+
+// Validate performs basic validation of the request.
+func (r *GetMultiRequest) Validate() error {
+	if len(r.Keys) > MaxGetMultiKeys {
+		return ErrTooManyKeys
+	}
+	return nil
+}
+
+// GetRangeRequest is a request for the SyncGetRange operation.
+type GetRangeRequest struct {
+	Tree TreeID `json:"tree"`
+	// StartKey is the first key of the range (inclusive).
+	StartKey []byte `json:"start_key"`
+	// EndKey is the end of the range (exclusive). If not specified, the range is unbounded.
+	EndKey []byte `json:"end_key,omitempty"`
+	// Limit is the maximum number of entries to include in the proof.
+	Limit uint16 `json:"limit"`
+
+	// ProofVersion specifies the proof version to use. If not specified,
+	// the default (0) version is used for backwards compatibility.
+	ProofVersion uint16 `json:"proof_version,omitempty"`
+}
+
 // ProofResponse is a response for requests that produce proofs.
 type ProofResponse struct {
 	Proof Proof `json:"proof"`
@@ -82,6 +120,15 @@ type ReadSyncer interface {
 	// SyncIterate seeks to a given key and then fetches the specified
 	// number of following items based on key iteration order.
 	SyncIterate(ctx context.Context, request *IterateRequest) (*ProofResponse, error)
+
+	// SyncGetMulti fetches the given keys and returns a single proof of
+	// either their values or their absence.
+	SyncGetMulti(ctx context.Context, request *GetMultiRequest) (*ProofResponse, error)
+
+	// SyncGetRange fetches all keys in the given range, up to the given
+	// limit, and returns a proof which can be used to verify that the range
+	// is complete.
+	SyncGetRange(ctx context.Context, request *GetRangeRequest) (*ProofResponse, error)
 }
 
 // nopReadSyncer is a no-op read syncer.
@@ -101,3 +148,11 @@ func (r *nopReadSyncer) SyncGetPrefixes(context.Context, *GetPrefixesRequest) (*
 func (r *nopReadSyncer) SyncIterate(context.Context, *IterateRequest) (*ProofResponse, error) {
 	return nil, ErrUnsupported
 }
+
+func (r *nopReadSyncer) SyncGetMulti(context.Context, *GetMultiRequest) (*ProofResponse, error) {
+	return nil, ErrUnsupported
+}
+
+func (r *nopReadSyncer) SyncGetRange(context.Context, *GetRangeRequest) (*ProofResponse, error) {
+	return nil, ErrUnsupported
+}
@@ -1,8 +1,10 @@
 package mkvs
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -540,3 +542,151 @@ func TestTreeProofs(t *testing.T) {
 		}
 	}
 }
+
+func TestSyncGetMulti(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	keys, values := generateKeyValuePairsEx("", 200)
+	var ns common.Namespace
+
+	tree := New(nil, nil, node.RootTypeState).(*tree)
+	for i, key := range keys {
+		err := tree.Insert(ctx, key, values[i])
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, ns, 0)
+	require.NoError(err, "Commit")
+	treeID := syncer.TreeID{
+		Root:     node.Root{Namespace: ns, Version: 0, Hash: rootHash, Type: node.RootTypeState},
+		Position: rootHash,
+	}
+
+	// Include existing keys, keys which are prefixes of existing keys and missing keys.
+	requestKeys := [][]byte{keys[1], keys[10], keys[100], keys[199], []byte("key"), []byte("key 1000"), []byte("zzz")}
+	expectedValues := [][]byte{values[1], values[10], values[100], values[199], nil, nil, nil}
+
+	var verifier syncer.ProofVerifier
+	for _, proofVersion := range []uint16{0, 1} {
+		rsp, err := tree.SyncGetMulti(ctx, &syncer.GetMultiRequest{
+			Tree:         treeID,
+			Keys:         requestKeys,
+			ProofVersion: proofVersion,
+		})
+		require.NoError(err, "SyncGetMulti")
+
+		got, err := verifier.VerifyGetMulti(ctx, rootHash, &rsp.Proof, requestKeys)
+		require.NoError(err, "VerifyGetMulti")
+		require.EqualValues(expectedValues, got, "VerifyGetMulti should return correct values")
+
+		// Keys not covered by the proof should not verify.
+		_, err = verifier.VerifyGetMulti(ctx, rootHash, &rsp.Proof, [][]byte{keys[50]})
+		require.ErrorIs(err, syncer.ErrIncompleteProof, "VerifyGetMulti should fail for keys not in proof")
+	}
+
+	// Requests with too many keys should be rejected.
+	_, err = tree.SyncGetMulti(ctx, &syncer.GetMultiRequest{
+		Tree: treeID,
+		Keys: make([][]byte, syncer.MaxGetMultiKeys+1),
+	})
+	require.ErrorIs(err, syncer.ErrTooManyKeys, "SyncGetMulti should fail with too many keys")
+}
+
+func TestSyncGetRange(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	keys, values := generateKeyValuePairsEx("", 200)
+	var ns common.Namespace
+
+	tree := New(nil, nil, node.RootTypeState).(*tree)
+	for i, key := range keys {
+		err := tree.Insert(ctx, key, values[i])
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, ns, 0)
+	require.NoError(err, "Commit")
+	treeID := syncer.TreeID{
+		Root:     node.Root{Namespace: ns, Version: 0, Hash: rootHash, Type: node.RootTypeState},
+		Position: rootHash,
+	}
+
+	var sorted writelog.WriteLog
+	for i := range keys {
+		sorted = append(sorted, writelog.LogEntry{Key: keys[i], Value: values[i]})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i].Key, sorted[j].Key) < 0 })
+	expectedRange := func(start, end []byte) writelog.WriteLog {
+		var wl writelog.WriteLog
+		for _, entry := range sorted {
+			if bytes.Compare(entry.Key, start) >= 0 && (end == nil || bytes.Compare(entry.Key, end) < 0) {
+				wl = append(wl, entry)
+			}
+		}
+		return wl
+	}
+
+	var verifier syncer.ProofVerifier
+	for _, proofVersion := range []uint16{0, 1} {
+		for _, tc := range []struct {
+			start []byte
+			end   []byte
+			limit uint16
+		}{
+			{nil, nil, 1000},
+			{nil, nil, 7},
+			{[]byte("key 1"), []byte("key 2"), 1000},
+			{[]byte("key 1"), []byte("key 2"), 10},
+			{[]byte("key 15"), []byte("key 150"), 1000},
+			{[]byte("key 150"), []byte("key 150\x00"), 1000},
+			{[]byte("key 5x"), []byte("key 6"), 3},
+			{[]byte("aaa"), []byte("bbb"), 1000},
+			{[]byte("zzz"), nil, 1000},
+		} {
+			// Fetch the whole range, continuing from where the previous proof ends.
+			var got writelog.WriteLog
+			start := tc.start
+			for {
+				rsp, err := tree.SyncGetRange(ctx, &syncer.GetRangeRequest{
+					Tree:         treeID,
+					StartKey:     start,
+					EndKey:       tc.end,
+					Limit:        tc.limit,
+					ProofVersion: proofVersion,
+				})
+				require.NoError(err, "SyncGetRange")
+
+				vr, err := verifier.VerifyRange(ctx, rootHash, &rsp.Proof, start, tc.end)
+				require.NoError(err, "VerifyRange")
+				require.LessOrEqual(len(vr.Entries), int(tc.limit), "number of entries should be limited")
+				if vr.NextKey == nil {
+					require.EqualValues(expectedRange(start, tc.end), vr.Entries, "entries should be complete")
+					got = append(got, vr.Entries...)
+					break
+				}
+				require.EqualValues(expectedRange(start, vr.NextKey), vr.Entries, "entries should be complete")
+				require.True(bytes.Compare(vr.NextKey, start) > 0, "next key should make progress")
+				got = append(got, vr.Entries...)
+				start = vr.NextKey
+			}
+			require.EqualValues(expectedRange(tc.start, tc.end), got, "range should be complete")
+		}
+
+		// A proof for a smaller range must not prove completeness of a larger range.
+		rsp, err := tree.SyncGetRange(ctx, &syncer.GetRangeRequest{
+			Tree:         treeID,
+			StartKey:     []byte("key 1"),
+			EndKey:       []byte("key 2"),
+			Limit:        1000,
+			ProofVersion: proofVersion,
+		})
+		require.NoError(err, "SyncGetRange")
+		vr, err := verifier.VerifyRange(ctx, rootHash, &rsp.Proof, []byte("key 1"), []byte("key 3"))
+		require.NoError(err, "VerifyRange")
+		require.NotNil(vr.NextKey, "proof should not cover the larger range")
+		require.EqualValues(expectedRange([]byte("key 1"), vr.NextKey), vr.Entries, "entries should be complete")
+
+		_, err = verifier.VerifyRange(ctx, rootHash, &rsp.Proof, []byte("key 5"), []byte("key 6"))
+		require.ErrorIs(err, syncer.ErrIncompleteProof, "proof should not cover a disjoint range")
+	}
+}
@@ -130,6 +130,42 @@ func (s *dummySerialSyncer) SyncIterate(ctx context.Context, request *syncer.Ite
 	return &rs, nil
 }
 
+func (s *dummySerialSyncer) SyncGetMulti(ctx context.Context, request *syncer.GetMultiRequest) (*syncer.ProofResponse, error) {
+	raw := cbor.Marshal(request)
+	var rq syncer.GetMultiRequest
+	if err := cbor.Unmarshal(raw, &rq); err != nil {
+		return nil, err
+	}
+	rsp, err := s.backing.SyncGetMulti(ctx, &rq)
+	if err != nil {
+		return nil, err
+	}
+	raw = cbor.Marshal(rsp)
+	var rs syncer.ProofResponse
+	if err := cbor.Unmarshal(raw, &rs); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+func (s *dummySerialSyncer) SyncGetRange(ctx context.Context, request *syncer.GetRangeRequest) (*syncer.ProofResponse, error) {
+	raw := cbor.Marshal(request)
+	var rq syncer.GetRangeRequest
+	if err := cbor.Unmarshal(raw, &rq); err != nil {
+		return nil, err
+	}
+	rsp, err := s.backing.SyncGetRange(ctx, &rq)
+	if err != nil {
+		return nil, err
+	}
+	raw = cbor.Marshal(rsp)
+	var rs syncer.ProofResponse
+	if err := cbor.Unmarshal(raw, &rs); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
 func testBasic(t *testing.T, ndb db.NodeDB, _ NodeDBFactory) {
 	ctx := context.Background()
 	tree := New(nil, ndb, node.RootTypeState)
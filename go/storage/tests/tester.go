@@ -16,6 +16,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 )
 
 var testValues = [][]byte{
@@ -153,6 +154,41 @@ func testBasic(t *testing.T, localBackend api.LocalBackend, storage api.Backend,
 		require.EqualValues(t, len(wl), idx, "iterator should visit all items")
 	})
 
+	// Test multi-key fetches.
+	t.Run("SyncGetMulti", func(t *testing.T) {
+		var keys [][]byte
+		for _, entry := range wl {
+			keys = append(keys, entry.Key)
+		}
+		rsp, err := storage.SyncGetMulti(ctx, &api.GetMultiRequest{
+			Tree: api.TreeID{Root: newRoot, Position: newRoot.Hash},
+			Keys: keys,
+		})
+		require.NoError(t, err, "SyncGetMulti")
+
+		var pv syncer.ProofVerifier
+		values, err := pv.VerifyGetMulti(ctx, newRoot.Hash, &rsp.Proof, keys)
+		require.NoError(t, err, "VerifyGetMulti")
+		for i, entry := range wl {
+			require.EqualValues(t, entry.Value, values[i])
+		}
+	})
+
+	// Test range fetches.
+	t.Run("SyncGetRange", func(t *testing.T) {
+		rsp, err := storage.SyncGetRange(ctx, &api.GetRangeRequest{
+			Tree:  api.TreeID{Root: newRoot, Position: newRoot.Hash},
+			Limit: uint16(len(wl)),
+		})
+		require.NoError(t, err, "SyncGetRange")
+
+		var pv syncer.ProofVerifier
+		vr, err := pv.VerifyRange(ctx, newRoot.Hash, &rsp.Proof, nil, nil)
+		require.NoError(t, err, "VerifyRange")
+		require.Nil(t, vr.NextKey, "range proof should cover all items")
+		require.Len(t, vr.Entries, len(wl), "range proof should include all items")
+	})
+
 	// Get the write log, it should be the same as what we stuffed in.
 	root := api.Root{
 		Namespace: namespace,
@@ -184,7 +220,7 @@ func testBasic(t *testing.T, localBackend api.LocalBackend, storage api.Backend,
 	// Test checkpoints.
 	t.Run("Checkpoints", func(t *testing.T) {
 		// Create a new checkpoint with the local backend.
-		cp, err := localBackend.Checkpointer().CreateCheckpoint(ctx, newRoot, 16*1024)
+		cp, err := localBackend.Checkpointer().CreateCheckpoint(ctx, checkpoint.VersionSnappy, newRoot, 16*1024)
 		require.NoError(t, err, "CreateCheckpoint")
 
 		cps, err := storage.GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{Version: 1, Namespace: namespace})
@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"slices"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
@@ -27,6 +28,12 @@ type PendingAction struct {
 	AuthorizedBy []staking.Address `json:"authorized_by"`
 	// Action is the pending action itself.
 	Action Action `json:"action"`
+	// Expiry is the epoch at which the action expires in case it has not been executed before.
+	// Zero means that the action never expires.
+	Expiry beacon.EpochTime `json:"expiry,omitempty"`
+	// ExecuteAt is the epoch at which the (sufficiently authorized) time-locked action will be
+	// executed. Zero means that the action has not yet been sufficiently authorized.
+	ExecuteAt beacon.EpochTime `json:"execute_at,omitempty"`
 }
 
 // ContainsAuthorizationFrom returns true iff the given address is among the action authorizers.
@@ -34,6 +41,22 @@ func (pa *PendingAction) ContainsAuthorizationFrom(addr staking.Address) bool {
 	return slices.Contains(pa.AuthorizedBy, addr)
 }
 
+// IsExpired returns true iff the action has expired at the given epoch.
+func (pa *PendingAction) IsExpired(epoch beacon.EpochTime) bool {
+	return pa.Expiry != 0 && epoch >= pa.Expiry
+}
+
+// IsScheduled returns true iff the action has been sufficiently authorized and is waiting for its
+// execution delay to pass. Scheduled actions no longer occupy the vault nonce.
+func (pa *PendingAction) IsScheduled() bool {
+	return pa.ExecuteAt != 0
+}
+
+// IsExecutable returns true iff the time-locked action can be executed at the given epoch.
+func (pa *PendingAction) IsExecutable(epoch beacon.EpochTime) bool {
+	return pa.IsScheduled() && !pa.IsExpired(epoch) && epoch >= pa.ExecuteAt
+}
+
 // Action is a vault action.
 type Action struct {
 	// Suspend is the suspend action.
@@ -46,6 +69,10 @@ type Action struct {
 	UpdateWithdrawPolicy *ActionUpdateWithdrawPolicy `json:"update_withdraw_policy,omitempty"`
 	// UpdateAuthority is the authority update action.
 	UpdateAuthority *ActionUpdateAuthority `json:"update_authority,omitempty"`
+	// UpdateSpendingPolicy is the spending policy update action.
+	UpdateSpendingPolicy *ActionUpdateSpendingPolicy `json:"update_spending_policy,omitempty"`
+	// UpdateAccountHooks is the account hooks update action.
+	UpdateAccountHooks *ActionUpdateAccountHooks `json:"update_account_hooks,omitempty"`
 }
 
 // Validate validates the given action.
@@ -57,6 +84,8 @@ func (a *Action) Validate(params *ConsensusParameters) error {
 		a.ExecuteMessage != nil,
 		a.UpdateWithdrawPolicy != nil,
 		a.UpdateAuthority != nil,
+		a.UpdateSpendingPolicy != nil,
+		a.UpdateAccountHooks != nil,
 	) {
 		return fmt.Errorf("exactly one action must be set")
 	}
@@ -70,6 +99,10 @@ func (a *Action) Validate(params *ConsensusParameters) error {
 		err = a.UpdateWithdrawPolicy.Validate()
 	case a.UpdateAuthority != nil:
 		err = a.UpdateAuthority.Validate(params)
+	case a.UpdateSpendingPolicy != nil:
+		err = a.UpdateSpendingPolicy.Validate()
+	case a.UpdateAccountHooks != nil:
+		err = a.UpdateAccountHooks.Validate()
 	}
 	return err
 }
@@ -92,11 +125,35 @@ func (a *Action) Authorities(vault *Vault) []*Authority {
 		return a.UpdateWithdrawPolicy.Authorities(vault)
 	case a.UpdateAuthority != nil:
 		return a.UpdateAuthority.Authorities(vault)
+	case a.UpdateSpendingPolicy != nil:
+		return a.UpdateSpendingPolicy.Authorities(vault)
+	case a.UpdateAccountHooks != nil:
+		return a.UpdateAccountHooks.Authorities(vault)
 	default:
 		return nil
 	}
 }
 
+// ExecutionDelay returns the shortest execution delay among the authorities of the given vault
+// that are satisfied by the given authorizations. In case no authority is satisfied, false is
+// returned.
+func (a *Action) ExecutionDelay(vault *Vault, authorizedBy []staking.Address) (beacon.EpochTime, bool) {
+	var (
+		delay beacon.EpochTime
+		ok    bool
+	)
+	for _, auth := range a.Authorities(vault) {
+		if !auth.Verify(authorizedBy) {
+			continue
+		}
+		if !ok || auth.ExecutionDelay < delay {
+			delay = auth.ExecutionDelay
+		}
+		ok = true
+	}
+	return delay, ok
+}
+
 // IsAuthorized returns true iff the given address is authorized to execute this action.
 func (a *Action) IsAuthorized(vault *Vault, addr staking.Address) bool {
 	for _, auth := range a.Authorities(vault) {
@@ -127,6 +184,14 @@ func (a Action) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
 		fmt.Fprintf(w, "%sUpdate authority:\n", prefix)
 		a.UpdateAuthority.PrettyPrint(ctx, prefix+"  ", w)
 	}
+	if a.UpdateSpendingPolicy != nil {
+		fmt.Fprintf(w, "%sUpdate spending policy:\n", prefix)
+		a.UpdateSpendingPolicy.PrettyPrint(ctx, prefix+"  ", w)
+	}
+	if a.UpdateAccountHooks != nil {
+		fmt.Fprintf(w, "%sUpdate account hooks:\n", prefix)
+		a.UpdateAccountHooks.PrettyPrint(ctx, prefix+"  ", w)
+	}
 }
 
 // PrettyType returns a representation of Action that can be used for pretty printing.
@@ -358,3 +423,105 @@ func (au ActionUpdateAuthority) PrettyPrint(ctx context.Context, prefix string,
 func (au ActionUpdateAuthority) PrettyType() (any, error) {
 	return au, nil
 }
+
+// ActionUpdateSpendingPolicy is the action to update the vault spending policy.
+type ActionUpdateSpendingPolicy struct {
+	// Policy is the new spending policy. If the field is nil the spending policy is removed.
+	Policy *SpendingPolicy `json:"policy,omitempty"`
+}
+
+// Validate validates the given action.
+func (au *ActionUpdateSpendingPolicy) Validate() error {
+	if au.Policy == nil {
+		return nil
+	}
+	if err := au.Policy.Validate(); err != nil {
+		return fmt.Errorf("malformed spending policy: %w", err)
+	}
+	return nil
+}
+
+// Authorities returns the authorities of the given vault that can authorize this action.
+func (au *ActionUpdateSpendingPolicy) Authorities(vault *Vault) []*Authority {
+	return []*Authority{
+		&vault.AdminAuthority,
+	}
+}
+
+// Apply applies the spending policy update to the given vault.
+func (au *ActionUpdateSpendingPolicy) Apply(vault *Vault) {
+	vault.SpendingPolicy = au.Policy
+}
+
+// PrettyPrint writes a pretty-printed representation of ActionUpdateSpendingPolicy to the given
+// writer.
+func (au ActionUpdateSpendingPolicy) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	if au.Policy == nil {
+		fmt.Fprintf(w, "%sRemove spending policy\n", prefix)
+		return
+	}
+	fmt.Fprintf(w, "%sNew spending policy:\n", prefix)
+	au.Policy.PrettyPrint(ctx, prefix+"  ", w)
+}
+
+// PrettyType returns a representation of ActionUpdateSpendingPolicy that can be used for pretty
+// printing.
+func (au ActionUpdateSpendingPolicy) PrettyType() (any, error) {
+	return au, nil
+}
+
+// ActionUpdateAccountHooks is the action to enable or disable account hooks of the vault account.
+//
+// The withdraw hook is always enabled for vault accounts and cannot be updated.
+type ActionUpdateAccountHooks struct {
+	// Enable are the account hook kinds that should be enabled.
+	Enable []staking.HookKind `json:"enable,omitempty"`
+	// Disable are the account hook kinds that should be disabled.
+	Disable []staking.HookKind `json:"disable,omitempty"`
+}
+
+// Validate validates the given action.
+func (au *ActionUpdateAccountHooks) Validate() error {
+	if len(au.Enable) == 0 && len(au.Disable) == 0 {
+		return fmt.Errorf("no account hooks to update")
+	}
+
+	kinds := make(map[staking.HookKind]struct{})
+	for _, kind := range slices.Concat(au.Enable, au.Disable) {
+		if !kind.IsValid() {
+			return fmt.Errorf("invalid account hook kind: %d", kind)
+		}
+		if kind == staking.HookKindWithdraw {
+			return fmt.Errorf("withdraw account hook cannot be updated")
+		}
+		if _, ok := kinds[kind]; ok {
+			return fmt.Errorf("duplicate account hook kind: %s", kind)
+		}
+		kinds[kind] = struct{}{}
+	}
+	return nil
+}
+
+// Authorities returns the authorities of the given vault that can authorize this action.
+func (au *ActionUpdateAccountHooks) Authorities(vault *Vault) []*Authority {
+	return []*Authority{
+		&vault.AdminAuthority,
+	}
+}
+
+// PrettyPrint writes a pretty-printed representation of ActionUpdateAccountHooks to the given
+// writer.
+func (au ActionUpdateAccountHooks) PrettyPrint(_ context.Context, prefix string, w io.Writer) {
+	for _, kind := range au.Enable {
+		fmt.Fprintf(w, "%sEnable: %s\n", prefix, kind)
+	}
+	for _, kind := range au.Disable {
+		fmt.Fprintf(w, "%sDisable: %s\n", prefix, kind)
+	}
+}
+
+// PrettyType returns a representation of ActionUpdateAccountHooks that can be used for pretty
+// printing.
+func (au ActionUpdateAccountHooks) PrettyType() (any, error) {
+	return au, nil
+}
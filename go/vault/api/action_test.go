@@ -120,6 +120,39 @@ func TestActionUpdateWithdrawPolicy(t *testing.T) {
 	)
 }
 
+func TestActionUpdateAccountHooks(t *testing.T) {
+	require := require.New(t)
+
+	vault := createTestVault()
+	action := Action{
+		UpdateAccountHooks: &ActionUpdateAccountHooks{
+			Enable:  []staking.HookKind{staking.HookKindIncomingTransfer},
+			Disable: []staking.HookKind{staking.HookKindAddEscrow},
+		},
+	}
+	require.NoError(action.Validate(&ConsensusParameters{}), "Validate should succeed on valid action")
+	require.EqualValues(
+		action.Authorities(vault),
+		[]*Authority{&vault.AdminAuthority},
+		"update account hooks should require admin authority",
+	)
+
+	for _, tc := range []struct {
+		msg    string
+		action ActionUpdateAccountHooks
+	}{
+		{"empty update", ActionUpdateAccountHooks{}},
+		{"invalid hook kind", ActionUpdateAccountHooks{Enable: []staking.HookKind{42}}},
+		{"withdraw hook", ActionUpdateAccountHooks{Disable: []staking.HookKind{staking.HookKindWithdraw}}},
+		{"duplicate hook kind", ActionUpdateAccountHooks{
+			Enable:  []staking.HookKind{staking.HookKindAddEscrow},
+			Disable: []staking.HookKind{staking.HookKindAddEscrow},
+		}},
+	} {
+		require.Error(tc.action.Validate(), "Validate should fail on %s", tc.msg)
+	}
+}
+
 func TestActionUpdateAuthority(t *testing.T) {
 	require := require.New(t)
 
@@ -159,3 +192,55 @@ func TestActionUpdateAuthority(t *testing.T) {
 	action.UpdateAuthority.Apply(newVault)
 	require.EqualValues(newVault.SuspendAuthority, *action.UpdateAuthority.SuspendAuthority)
 }
+
+func TestActionExecutionDelay(t *testing.T) {
+	require := require.New(t)
+
+	vault := createTestVault()
+	vault.AdminAuthority.ExecutionDelay = 10
+	vault.SuspendAuthority.ExecutionDelay = 2
+
+	action := Action{
+		Suspend: &ActionSuspend{},
+	}
+	_, ok := action.ExecutionDelay(vault, []staking.Address{testAddrA})
+	require.False(ok, "action should not be executable without satisfied authorities")
+
+	delay, ok := action.ExecutionDelay(vault, []staking.Address{testAddrA, testAddrB})
+	require.True(ok, "action should be executable with satisfied authorities")
+	require.EqualValues(2, delay, "shortest execution delay should be used")
+
+	delay, ok = action.ExecutionDelay(vault, []staking.Address{testAddrC})
+	require.True(ok)
+	require.EqualValues(2, delay)
+
+	action = Action{
+		ExecuteMessage: &ActionExecuteMessage{
+			Method: "foo",
+		},
+	}
+	_, ok = action.ExecutionDelay(vault, []staking.Address{testAddrC})
+	require.False(ok, "suspend authority should not satisfy admin actions")
+	delay, ok = action.ExecutionDelay(vault, []staking.Address{testAddrA, testAddrB})
+	require.True(ok)
+	require.EqualValues(10, delay)
+}
+
+func TestPendingActionTimeLock(t *testing.T) {
+	require := require.New(t)
+
+	pa := PendingAction{}
+	require.False(pa.IsExpired(100), "action without expiry should never expire")
+	require.False(pa.IsScheduled())
+	require.False(pa.IsExecutable(100), "unscheduled action should not be executable")
+
+	pa.Expiry = 10
+	pa.ExecuteAt = 5
+	require.True(pa.IsScheduled())
+	require.False(pa.IsExpired(9))
+	require.True(pa.IsExpired(10))
+	require.False(pa.IsExecutable(4))
+	require.True(pa.IsExecutable(5))
+	require.True(pa.IsExecutable(9))
+	require.False(pa.IsExecutable(10), "expired action should not be executable")
+}
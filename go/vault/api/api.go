@@ -4,6 +4,7 @@ package api
 import (
 	"context"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
@@ -30,6 +31,11 @@ var (
 	ErrNoSuchAction = errors.New(ModuleName, 6, "vault: no such action")
 	// ErrUnsupportedAction is the error returned when an action is not supported.
 	ErrUnsupportedAction = errors.New(ModuleName, 7, "vault: action not supported")
+	// ErrActionExpired is the error returned when an action has expired.
+	ErrActionExpired = errors.New(ModuleName, 8, "vault: action expired")
+	// ErrSpendingPolicyViolated is the error returned when a transfer violates the vault's
+	// spending policy.
+	ErrSpendingPolicyViolated = errors.New(ModuleName, 9, "vault: spending policy violated")
 )
 
 // Backend is a vault implementation.
@@ -43,6 +49,9 @@ type Backend interface {
 	// AddressState returns the state information for the given source address.
 	AddressState(ctx context.Context, query *AddressQuery) (*AddressState, error)
 
+	// SpendingState returns the spending accounting state for the given vault.
+	SpendingState(ctx context.Context, query *VaultQuery) (*SpendingState, error)
+
 	// PendingActions returns the list of pending actions for the given vault.
 	PendingActions(ctx context.Context, query *VaultQuery) ([]*PendingAction, error)
 
@@ -88,6 +97,8 @@ type Genesis struct {
 	States map[staking.Address]map[staking.Address]*AddressState `json:"states,omitempty"`
 	// PendingActions are the per-vault pending actions.
 	PendingActions map[staking.Address][]*PendingAction `json:"pending_actions,omitempty"`
+	// SpendingStates are the per-vault spending states.
+	SpendingStates map[staking.Address]*SpendingState `json:"spending_states,omitempty"`
 }
 
 // ConsensusParameters are the vault consensus parameters.
@@ -99,6 +110,10 @@ type ConsensusParameters struct {
 	// authority.
 	MaxAuthorityAddresses uint8 `json:"max_authority_addresses,omitempty"`
 
+	// MaxExecutionDelay is the maximum execution delay (in epochs) that can be configured for each
+	// authority.
+	MaxExecutionDelay beacon.EpochTime `json:"max_execution_delay,omitempty"`
+
 	// GasCosts are the vault transaction gas costs.
 	GasCosts transaction.Costs `json:"gas_costs,omitempty"`
 }
@@ -107,6 +122,7 @@ type ConsensusParameters struct {
 var DefaultConsensusParameters = ConsensusParameters{
 	Enabled:               true,
 	MaxAuthorityAddresses: 32,
+	MaxExecutionDelay:     168,
 	GasCosts:              DefaultGasCosts,
 }
 
@@ -116,6 +132,10 @@ type ConsensusParameterChanges struct {
 	// authority.
 	MaxAuthorityAddresses *uint8 `json:"max_authority_addresses,omitempty"`
 
+	// MaxExecutionDelay is the new maximum execution delay (in epochs) that can be configured for
+	// each authority.
+	MaxExecutionDelay *beacon.EpochTime `json:"max_execution_delay,omitempty"`
+
 	// GasCosts are the new gas costs.
 	GasCosts transaction.Costs `json:"gas_costs,omitempty"`
 }
@@ -125,6 +145,9 @@ func (c *ConsensusParameterChanges) Apply(params *ConsensusParameters) error {
 	if c.MaxAuthorityAddresses != nil {
 		params.MaxAuthorityAddresses = *c.MaxAuthorityAddresses
 	}
+	if c.MaxExecutionDelay != nil {
+		params.MaxExecutionDelay = *c.MaxExecutionDelay
+	}
 	if c.GasCosts != nil {
 		params.GasCosts = c.GasCosts
 	}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
@@ -12,10 +14,17 @@ type Event struct {
 
 	ActionSubmitted  *ActionSubmittedEvent  `json:"action_submitted,omitempty"`
 	ActionCanceled   *ActionCanceledEvent   `json:"action_canceled,omitempty"`
+	ActionScheduled  *ActionScheduledEvent  `json:"action_scheduled,omitempty"`
 	ActionExecuted   *ActionExecutedEvent   `json:"action_executed,omitempty"`
+	ActionExpired    *ActionExpiredEvent    `json:"action_expired,omitempty"`
 	StateChanged     *StateChangedEvent     `json:"state_changed,omitempty"`
 	PolicyUpdated    *PolicyUpdatedEvent    `json:"policy_updated"`
 	AuthorityUpdated *AuthorityUpdatedEvent `json:"authority_updated"`
+
+	SpendingPolicyUpdated  *SpendingPolicyUpdatedEvent  `json:"spending_policy_updated,omitempty"`
+	SpendingPolicyViolated *SpendingPolicyViolatedEvent `json:"spending_policy_violated,omitempty"`
+
+	AccountHooksUpdated *AccountHooksUpdatedEvent `json:"account_hooks_updated,omitempty"`
 }
 
 // ActionSubmittedEvent is the event emitted when a new vault action is submitted.
@@ -46,6 +55,22 @@ func (e *ActionCanceledEvent) EventKind() string {
 	return "action_canceled"
 }
 
+// ActionScheduledEvent is the event emitted when a time-locked vault action has been sufficiently
+// authorized and is scheduled for execution.
+type ActionScheduledEvent struct {
+	// Vault is the vault address.
+	Vault staking.Address `json:"vault"`
+	// Nonce is the action nonce.
+	Nonce uint64 `json:"nonce"`
+	// ExecuteAt is the epoch at which the action will be executed.
+	ExecuteAt beacon.EpochTime `json:"execute_at"`
+}
+
+// EventKind returns a string representation of this event's kind.
+func (e *ActionScheduledEvent) EventKind() string {
+	return "action_scheduled"
+}
+
 // ActionExecutedEvent is the event emitted when a new vault action is executed.
 type ActionExecutedEvent struct {
 	// Vault is the vault address.
@@ -61,6 +86,19 @@ func (e *ActionExecutedEvent) EventKind() string {
 	return "action_executed"
 }
 
+// ActionExpiredEvent is the event emitted when a pending vault action expires and is removed.
+type ActionExpiredEvent struct {
+	// Vault is the vault address.
+	Vault staking.Address `json:"vault"`
+	// Nonce is the action nonce.
+	Nonce uint64 `json:"nonce"`
+}
+
+// EventKind returns a string representation of this event's kind.
+func (e *ActionExpiredEvent) EventKind() string {
+	return "action_expired"
+}
+
 // ActionExecutionResult is the result of executing an action.
 type ActionExecutionResult struct {
 	Module string `json:"module,omitempty"`
@@ -105,3 +143,44 @@ type AuthorityUpdatedEvent struct {
 func (e *AuthorityUpdatedEvent) EventKind() string {
 	return "authority_updated"
 }
+
+// SpendingPolicyUpdatedEvent is the event emitted when a vault spending policy is updated.
+type SpendingPolicyUpdatedEvent struct {
+	// Vault is the vault address.
+	Vault staking.Address `json:"vault"`
+}
+
+// EventKind returns a string representation of this event's kind.
+func (e *SpendingPolicyUpdatedEvent) EventKind() string {
+	return "spending_policy_updated"
+}
+
+// SpendingPolicyViolatedEvent is the event emitted when a message moving tokens out of the vault
+// (e.g. a transfer) executed via a vault action is rejected because it violates the vault spending
+// policy.
+type SpendingPolicyViolatedEvent struct {
+	// Vault is the vault address.
+	Vault staking.Address `json:"vault"`
+	// To is the destination of the tokens. It is empty for burns.
+	To staking.Address `json:"to"`
+	// Amount is the amount of tokens.
+	Amount quantity.Quantity `json:"amount"`
+	// Violation is the kind of the spending policy violation.
+	Violation SpendingViolation `json:"violation"`
+}
+
+// EventKind returns a string representation of this event's kind.
+func (e *SpendingPolicyViolatedEvent) EventKind() string {
+	return "spending_policy_violated"
+}
+
+// AccountHooksUpdatedEvent is the event emitted when the account hooks of a vault are updated.
+type AccountHooksUpdatedEvent struct {
+	// Vault is the vault address.
+	Vault staking.Address `json:"vault"`
+}
+
+// EventKind returns a string representation of this event's kind.
+func (e *AccountHooksUpdatedEvent) EventKind() string {
+	return "account_hooks_updated"
+}
@@ -19,6 +19,8 @@ var (
 	methodVault = serviceName.NewMethod("Vault", VaultQuery{})
 	// methodAddressState is the AddressState method.
 	methodAddressState = serviceName.NewMethod("AddressState", AddressQuery{})
+	// methodSpendingState is the SpendingState method.
+	methodSpendingState = serviceName.NewMethod("SpendingState", VaultQuery{})
 	// methodPendingActions is the PendingActions method.
 	methodPendingActions = serviceName.NewMethod("PendingActions", VaultQuery{})
 	// methodStateToGenesis is the StateToGenesis method.
@@ -48,6 +50,10 @@ var (
 				MethodName: methodAddressState.ShortName(),
 				Handler:    handlerAddressState,
 			},
+			{
+				MethodName: methodSpendingState.ShortName(),
+				Handler:    handlerSpendingState,
+			},
 			{
 				MethodName: methodPendingActions.ShortName(),
 				Handler:    handlerPendingActions,
@@ -144,6 +150,29 @@ func handlerAddressState(
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerSpendingState(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var query VaultQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).SpendingState(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSpendingState.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).SpendingState(ctx, req.(*VaultQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerPendingActions(
 	srv any,
 	ctx context.Context,
@@ -304,6 +333,14 @@ func (c *Client) AddressState(ctx context.Context, request *AddressQuery) (*Addr
 	return &rsp, nil
 }
 
+func (c *Client) SpendingState(ctx context.Context, request *VaultQuery) (*SpendingState, error) {
+	var rsp SpendingState
+	if err := c.conn.Invoke(ctx, methodSpendingState.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) PendingActions(ctx context.Context, request *VaultQuery) ([]*PendingAction, error) {
 	var rsp []*PendingAction
 	if err := c.conn.Invoke(ctx, methodPendingActions.FullName(), request, &rsp); err != nil {
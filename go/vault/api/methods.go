@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
@@ -70,6 +71,11 @@ type AuthorizeAction struct {
 	Nonce uint64 `json:"nonce"`
 	// Action is the action that should be authorized.
 	Action Action `json:"action"`
+	// Expiry is the epoch at which the pending action expires in case it has not been executed
+	// before. Zero means that the action never expires.
+	//
+	// All authorizations of the same pending action must specify the same expiry.
+	Expiry beacon.EpochTime `json:"expiry,omitempty"`
 }
 
 // Validate validates the action authorization call.
@@ -88,6 +94,9 @@ func (a *AuthorizeAction) Validate(params *ConsensusParameters) error {
 func (a AuthorizeAction) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
 	fmt.Fprintf(w, "%sVault:  %s\n", prefix, a.Vault)
 	fmt.Fprintf(w, "%sNonce:  %d\n", prefix, a.Nonce)
+	if a.Expiry != 0 {
+		fmt.Fprintf(w, "%sExpiry: %d\n", prefix, a.Expiry)
+	}
 	fmt.Fprintf(w, "%sAction:\n", prefix)
 	a.Action.PrettyPrint(ctx, prefix+"  ", w)
 }
@@ -3,6 +3,7 @@ package api
 
 import (
 	"fmt"
+	"slices"
 )
 
 // SanityCheck performs a sanity check on the consensus parameters.
@@ -18,7 +19,7 @@ func (p *ConsensusParameters) SanityCheck() error {
 
 // SanityCheck performs a sanity check on the consensus parameter changes.
 func (c *ConsensusParameterChanges) SanityCheck() error {
-	if c.MaxAuthorityAddresses == nil && c.GasCosts == nil {
+	if c.MaxAuthorityAddresses == nil && c.MaxExecutionDelay == nil && c.GasCosts == nil {
 		return fmt.Errorf("consensus parameter changes should not be empty")
 	}
 	return nil
@@ -32,5 +33,18 @@ func (g *Genesis) SanityCheck() error {
 	if err := g.Parameters.SanityCheck(); err != nil {
 		return fmt.Errorf("vault: sanity check failed: %w", err)
 	}
+	for _, vlt := range g.Vaults {
+		if vlt.SpendingPolicy == nil {
+			continue
+		}
+		if err := vlt.SpendingPolicy.Validate(); err != nil {
+			return fmt.Errorf("vault: sanity check failed: vault %s: invalid spending policy: %w", vlt.Address(), err)
+		}
+	}
+	for vaultAddr := range g.SpendingStates {
+		if !slices.ContainsFunc(g.Vaults, func(vlt *Vault) bool { return vlt.Address().Equal(vaultAddr) }) {
+			return fmt.Errorf("vault: sanity check failed: spending state for unknown vault %s", vaultAddr)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,275 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/staking/api/token"
+)
+
+const (
+	// MaxSpendingAllowlistSize is the maximum number of destinations in a spending policy allowlist.
+	MaxSpendingAllowlistSize = 128
+	// MaxSpendingWindow is the maximum length (in epochs) of a spending policy window.
+	MaxSpendingWindow beacon.EpochTime = 1024
+)
+
+var _ prettyprint.PrettyPrinter = (*SpendingPolicy)(nil)
+
+// SpendingViolation is the kind of a spending policy violation.
+type SpendingViolation uint8
+
+const (
+	// SpendingViolationNone means that the spending policy has not been violated.
+	SpendingViolationNone SpendingViolation = 0
+	// SpendingViolationDestination means that the transfer destination is not allowlisted.
+	SpendingViolationDestination SpendingViolation = 1
+	// SpendingViolationTransferLimit means that the transfer exceeds the single-transfer limit.
+	SpendingViolationTransferLimit SpendingViolation = 2
+	// SpendingViolationWindowLimit means that the transfer exceeds the spending window limit.
+	SpendingViolationWindowLimit SpendingViolation = 3
+)
+
+// String returns a string representation of a spending policy violation.
+func (v SpendingViolation) String() string {
+	switch v {
+	case SpendingViolationNone:
+		return "none"
+	case SpendingViolationDestination:
+		return "destination not allowed"
+	case SpendingViolationTransferLimit:
+		return "transfer limit exceeded"
+	case SpendingViolationWindowLimit:
+		return "window limit exceeded"
+	default:
+		return fmt.Sprintf("[unknown spending violation: %d]", v)
+	}
+}
+
+// SpendingPolicy is the vault policy for tokens moved out of the vault account by messages
+// executed via vault actions (transfers, burns, escrow and allowance grants).
+type SpendingPolicy struct {
+	// Allowlist are the only destinations that tokens are allowed to move to. In case the
+	// allowlist is empty, any destination is allowed. Burns are only allowed in case the allowlist
+	// is empty.
+	Allowlist []staking.Address `json:"allowlist,omitempty"`
+
+	// TransferLimit is the maximum amount of a single transfer. Zero means that there is no limit.
+	TransferLimit quantity.Quantity `json:"transfer_limit,omitempty"`
+
+	// WindowLimit is the maximum amount of tokens that may be transferred within any window of
+	// WindowEpochs consecutive epochs. Zero means that there is no limit.
+	WindowLimit quantity.Quantity `json:"window_limit,omitempty"`
+	// WindowEpochs is the length (in epochs) of the rolling spending window. It must be set iff
+	// the window limit is set.
+	WindowEpochs beacon.EpochTime `json:"window_epochs,omitempty"`
+}
+
+// Validate validates the spending policy.
+func (sp *SpendingPolicy) Validate() error {
+	if len(sp.Allowlist) > MaxSpendingAllowlistSize {
+		return fmt.Errorf("too many addresses in allowlist (max: %d got: %d)",
+			MaxSpendingAllowlistSize, len(sp.Allowlist))
+	}
+
+	// Ensure no duplicate or malformed addresses.
+	addressSet := make(map[staking.Address]struct{})
+	for _, addr := range sp.Allowlist {
+		if !addr.IsValid() {
+			return fmt.Errorf("malformed address in allowlist")
+		}
+		if _, ok := addressSet[addr]; ok {
+			return fmt.Errorf("duplicate address in allowlist: %s", addr)
+		}
+		addressSet[addr] = struct{}{}
+	}
+
+	if sp.WindowEpochs > MaxSpendingWindow {
+		return fmt.Errorf("spending window too long (max: %d got: %d)", MaxSpendingWindow, sp.WindowEpochs)
+	}
+	if sp.WindowEpochs == 0 && !sp.WindowLimit.IsZero() {
+		return fmt.Errorf("window limit requires a non-zero spending window")
+	}
+	if sp.WindowEpochs != 0 && sp.WindowLimit.IsZero() {
+		return fmt.Errorf("spending window requires a non-zero window limit")
+	}
+	return nil
+}
+
+// IsAllowed returns true iff transfers to the given destination are allowed.
+func (sp *SpendingPolicy) IsAllowed(to staking.Address) bool {
+	return len(sp.Allowlist) == 0 || slices.Contains(sp.Allowlist, to)
+}
+
+// PrettyPrint writes a pretty-printed representation of SpendingPolicy to the given writer.
+func (sp SpendingPolicy) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sAllowed destinations:", prefix)
+	switch len(sp.Allowlist) {
+	case 0:
+		fmt.Fprintf(w, " any\n")
+	default:
+		fmt.Fprintf(w, "\n")
+		for _, addr := range sp.Allowlist {
+			fmt.Fprintf(w, "%s  - %s\n", prefix, addr)
+		}
+	}
+
+	fmt.Fprintf(w, "%sTransfer limit: ", prefix)
+	switch sp.TransferLimit.IsZero() {
+	case true:
+		fmt.Fprintf(w, "none\n")
+	case false:
+		token.PrettyPrintAmount(ctx, sp.TransferLimit, w)
+		fmt.Fprintf(w, "\n")
+	}
+
+	fmt.Fprintf(w, "%sWindow limit: ", prefix)
+	switch sp.WindowLimit.IsZero() {
+	case true:
+		fmt.Fprintf(w, "none\n")
+	case false:
+		token.PrettyPrintAmount(ctx, sp.WindowLimit, w)
+		fmt.Fprintf(w, " / %d epoch(s)\n", sp.WindowEpochs)
+	}
+}
+
+// PrettyType returns a representation of SpendingPolicy that can be used for pretty printing.
+func (sp SpendingPolicy) PrettyType() (any, error) {
+	return sp, nil
+}
+
+// EpochSpending is the amount transferred by a vault in a given epoch.
+type EpochSpending struct {
+	// Epoch is the epoch.
+	Epoch beacon.EpochTime `json:"epoch"`
+	// Amount is the amount transferred in the epoch.
+	Amount quantity.Quantity `json:"amount"`
+}
+
+// SpendingState is the vault spending accounting state.
+type SpendingState struct {
+	// Epochs are the amounts transferred in recent epochs, ordered by epoch.
+	Epochs []EpochSpending `json:"epochs,omitempty"`
+}
+
+// AuthorizeOutflow performs outflow authorization against the given spending policy. Outflows are
+// authorized as transfers, except that burns are only allowed in case the allowlist is empty. In
+// case the outflow is allowed, the state is also updated to reflect the additional outflow.
+func (ss *SpendingState) AuthorizeOutflow(policy *SpendingPolicy, epoch beacon.EpochTime, outflow *Outflow) SpendingViolation {
+	if outflow.Burn && len(policy.Allowlist) > 0 {
+		return SpendingViolationDestination
+	}
+	return ss.AuthorizeTransfer(policy, epoch, outflow.To, &outflow.Amount)
+}
+
+// AuthorizeTransfer performs transfer authorization against the given spending policy. In case
+// the transfer is allowed, the state is also updated to reflect the additional transfer.
+func (ss *SpendingState) AuthorizeTransfer(
+	policy *SpendingPolicy,
+	epoch beacon.EpochTime,
+	to staking.Address,
+	amount *quantity.Quantity,
+) SpendingViolation {
+	if !policy.IsAllowed(to) {
+		return SpendingViolationDestination
+	}
+	if !policy.TransferLimit.IsZero() && amount.Cmp(&policy.TransferLimit) > 0 {
+		return SpendingViolationTransferLimit
+	}
+	if policy.WindowLimit.IsZero() {
+		return SpendingViolationNone
+	}
+
+	// Drop epochs that are no longer part of the window.
+	var windowStart beacon.EpochTime
+	if epoch >= policy.WindowEpochs {
+		windowStart = epoch - policy.WindowEpochs + 1
+	}
+	epochs := slices.DeleteFunc(slices.Clone(ss.Epochs), func(es EpochSpending) bool {
+		return es.Epoch < windowStart
+	})
+
+	// Compute how much has been transferred in the window.
+	wanted := amount.Clone()
+	for _, es := range epochs {
+		if err := wanted.Add(&es.Amount); err != nil {
+			return SpendingViolationWindowLimit
+		}
+	}
+	if wanted.Cmp(&policy.WindowLimit) > 0 {
+		return SpendingViolationWindowLimit
+	}
+
+	// Update state and authorize transfer.
+	switch n := len(epochs); {
+	case n > 0 && epochs[n-1].Epoch == epoch:
+		spent := epochs[n-1].Amount.Clone()
+		_ = spent.Add(amount)
+		epochs[n-1].Amount = *spent
+	default:
+		epochs = append(epochs, EpochSpending{
+			Epoch:  epoch,
+			Amount: *amount.Clone(),
+		})
+	}
+	ss.Epochs = epochs
+	return SpendingViolationNone
+}
+
+// Outflow is an amount of tokens moved out of the vault account by a message executed on behalf of
+// the vault.
+type Outflow struct {
+	// To is the destination of the tokens. It is empty for burns.
+	To staking.Address
+	// Amount is the amount of tokens.
+	Amount quantity.Quantity
+	// Burn is true iff the tokens are destroyed.
+	Burn bool
+}
+
+// Outflow returns the tokens moved out of the vault account by the message. In case the message
+// does not move any tokens out of the vault account, nil is returned.
+//
+// Allowance grants are treated as outflows to the beneficiary as the beneficiary can withdraw the
+// allowance without any further vault actions.
+func (am *ActionExecuteMessage) Outflow() (*Outflow, error) {
+	switch am.Method {
+	case staking.MethodTransfer:
+		var xfer staking.Transfer
+		if err := cbor.Unmarshal(am.Body, &xfer); err != nil {
+			return nil, err
+		}
+		return &Outflow{To: xfer.To, Amount: xfer.Amount}, nil
+	case staking.MethodBurn:
+		var burn staking.Burn
+		if err := cbor.Unmarshal(am.Body, &burn); err != nil {
+			return nil, err
+		}
+		return &Outflow{Amount: burn.Amount, Burn: true}, nil
+	case staking.MethodAddEscrow:
+		var escrow staking.Escrow
+		if err := cbor.Unmarshal(am.Body, &escrow); err != nil {
+			return nil, err
+		}
+		return &Outflow{To: escrow.Account, Amount: escrow.Amount}, nil
+	case staking.MethodAllow:
+		var allow staking.Allow
+		if err := cbor.Unmarshal(am.Body, &allow); err != nil {
+			return nil, err
+		}
+		if allow.Negative {
+			// Decreasing an allowance does not move any tokens.
+			return nil, nil
+		}
+		return &Outflow{To: allow.Beneficiary, Amount: allow.AmountChange}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func TestSpendingPolicyValidate(t *testing.T) {
+	require := require.New(t)
+
+	sp := SpendingPolicy{}
+	require.NoError(sp.Validate(), "Validate should succeed on empty policy")
+
+	sp = SpendingPolicy{
+		Allowlist: []staking.Address{testAddrA, testAddrA},
+	}
+	require.Error(sp.Validate(), "Validate should fail on duplicate allowlist addresses")
+
+	sp = SpendingPolicy{
+		WindowLimit: *quantity.NewFromUint64(100),
+	}
+	require.Error(sp.Validate(), "Validate should fail on window limit without a window")
+
+	sp = SpendingPolicy{
+		WindowEpochs: 3,
+	}
+	require.Error(sp.Validate(), "Validate should fail on window without a window limit")
+
+	sp = SpendingPolicy{
+		WindowLimit:  *quantity.NewFromUint64(100),
+		WindowEpochs: MaxSpendingWindow + 1,
+	}
+	require.Error(sp.Validate(), "Validate should fail on too long window")
+
+	sp = SpendingPolicy{
+		Allowlist:     []staking.Address{testAddrA, testAddrB},
+		TransferLimit: *quantity.NewFromUint64(50),
+		WindowLimit:   *quantity.NewFromUint64(100),
+		WindowEpochs:  3,
+	}
+	require.NoError(sp.Validate(), "Validate should succeed on valid policy")
+}
+
+func TestAuthorizeTransfer(t *testing.T) {
+	require := require.New(t)
+
+	// Limit transfers to 50 base units per transfer and 100 base units per 3 epochs.
+	policy := SpendingPolicy{
+		Allowlist:     []staking.Address{testAddrA, testAddrB},
+		TransferLimit: *quantity.NewFromUint64(50),
+		WindowLimit:   *quantity.NewFromUint64(100),
+		WindowEpochs:  3,
+	}
+	var ss SpendingState
+
+	for _, tc := range []struct {
+		epoch    beacon.EpochTime
+		to       staking.Address
+		amount   uint64
+		expected SpendingViolation
+	}{
+		{1, testAddrC, 10, SpendingViolationDestination},
+		{1, testAddrA, 60, SpendingViolationTransferLimit},
+		{1, testAddrA, 50, SpendingViolationNone},
+		{2, testAddrB, 40, SpendingViolationNone},
+		{2, testAddrB, 20, SpendingViolationWindowLimit},
+		{3, testAddrA, 10, SpendingViolationNone},
+		{3, testAddrA, 1, SpendingViolationWindowLimit},
+		// Spending in epoch 1 is no longer part of the window.
+		{4, testAddrA, 50, SpendingViolationNone},
+		{4, testAddrA, 1, SpendingViolationWindowLimit},
+		{10, testAddrB, 50, SpendingViolationNone},
+		{10, testAddrB, 50, SpendingViolationNone},
+		{10, testAddrB, 0, SpendingViolationNone},
+		{10, testAddrB, 1, SpendingViolationWindowLimit},
+	} {
+		violation := ss.AuthorizeTransfer(&policy, tc.epoch, tc.to, quantity.NewFromUint64(tc.amount))
+		require.Equal(tc.expected, violation, "AuthorizeTransfer(%d, %s, %d)", tc.epoch, tc.to, tc.amount)
+	}
+	require.Len(ss.Epochs, 1, "epochs outside of the window should be pruned")
+	require.EqualValues(10, ss.Epochs[0].Epoch)
+	require.EqualValues(*quantity.NewFromUint64(100), ss.Epochs[0].Amount)
+
+	// Policy without a window should only enforce the allowlist and the transfer limit.
+	policy.WindowEpochs = 0
+	policy.WindowLimit = *quantity.NewQuantity()
+	policy.Allowlist = nil
+	violation := ss.AuthorizeTransfer(&policy, 10, testAddrC, quantity.NewFromUint64(50))
+	require.Equal(SpendingViolationNone, violation)
+	violation = ss.AuthorizeTransfer(&policy, 10, testAddrC, quantity.NewFromUint64(51))
+	require.Equal(SpendingViolationTransferLimit, violation)
+}
+
+func TestAuthorizeOutflow(t *testing.T) {
+	require := require.New(t)
+
+	var ss SpendingState
+	policy := SpendingPolicy{
+		TransferLimit: *quantity.NewFromUint64(50),
+	}
+	burn := &Outflow{Amount: *quantity.NewFromUint64(10), Burn: true}
+	require.Equal(SpendingViolationNone, ss.AuthorizeOutflow(&policy, 1, burn), "burn without an allowlist should be allowed")
+	require.Empty(ss.Epochs, "spending should not be tracked without a window limit")
+
+	policy.Allowlist = []staking.Address{testAddrA}
+	require.Equal(SpendingViolationDestination, ss.AuthorizeOutflow(&policy, 1, burn), "burn with an allowlist should be rejected")
+}
+
+func TestExecuteMessageOutflow(t *testing.T) {
+	require := require.New(t)
+
+	amount := *quantity.NewFromUint64(42)
+	for _, tc := range []struct {
+		method  transaction.MethodName
+		body    any
+		outflow *Outflow
+	}{
+		{staking.MethodTransfer, &staking.Transfer{To: testAddrA, Amount: amount}, &Outflow{To: testAddrA, Amount: amount}},
+		{staking.MethodBurn, &staking.Burn{Amount: amount}, &Outflow{Amount: amount, Burn: true}},
+		{staking.MethodAddEscrow, &staking.Escrow{Account: testAddrA, Amount: amount}, &Outflow{To: testAddrA, Amount: amount}},
+		{staking.MethodAllow, &staking.Allow{Beneficiary: testAddrA, AmountChange: amount}, &Outflow{To: testAddrA, Amount: amount}},
+		{staking.MethodAllow, &staking.Allow{Beneficiary: testAddrA, Negative: true, AmountChange: amount}, nil},
+		{staking.MethodReclaimEscrow, &staking.ReclaimEscrow{Account: testAddrA, Shares: amount}, nil},
+	} {
+		msg := ActionExecuteMessage{
+			Method: tc.method,
+			Body:   cbor.Marshal(tc.body),
+		}
+		outflow, err := msg.Outflow()
+		require.NoError(err, "Outflow(%s)", tc.method)
+		require.EqualValues(tc.outflow, outflow, "Outflow(%s)", tc.method)
+	}
+
+	msg := ActionExecuteMessage{
+		Method: staking.MethodTransfer,
+		Body:   []byte("malformed"),
+	}
+	_, err := msg.Outflow()
+	require.Error(err, "Outflow should fail on malformed body")
+}
+
+func TestGenesisSanityCheckSpendingPolicy(t *testing.T) {
+	require := require.New(t)
+
+	vlt := &Vault{
+		Creator:        testAddrA,
+		SpendingPolicy: &SpendingPolicy{WindowEpochs: 3},
+	}
+	g := &Genesis{
+		Vaults: []*Vault{vlt},
+	}
+	require.Error(g.SanityCheck(), "SanityCheck should fail on invalid spending policy")
+
+	vlt.SpendingPolicy.WindowLimit = *quantity.NewFromUint64(100)
+	require.NoError(g.SanityCheck(), "SanityCheck should succeed on valid spending policy")
+
+	g.SpendingStates = map[staking.Address]*SpendingState{
+		testAddrB: {},
+	}
+	require.Error(g.SanityCheck(), "SanityCheck should fail on spending state for unknown vault")
+
+	g.SpendingStates = map[staking.Address]*SpendingState{
+		vlt.Address(): {},
+	}
+	require.NoError(g.SanityCheck(), "SanityCheck should succeed on spending state for known vault")
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"slices"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
@@ -35,6 +36,10 @@ type Vault struct {
 	AdminAuthority Authority `json:"admin_authority"`
 	// SuspendAuthority specifies the vault's suspend authority.
 	SuspendAuthority Authority `json:"suspend_authority"`
+
+	// SpendingPolicy is the policy for tokens moved out of the vault via vault actions. In case it
+	// is not set, outgoing tokens are not restricted.
+	SpendingPolicy *SpendingPolicy `json:"spending_policy,omitempty"`
 }
 
 // NewVaultAddress returns the address for the vault.
@@ -76,6 +81,9 @@ type Authority struct {
 	Addresses []staking.Address `json:"addresses"`
 	// Threshold is the minimum number of addresses that must authorize an action.
 	Threshold uint8 `json:"threshold"`
+	// ExecutionDelay is the number of epochs that must pass after an action has been authorized
+	// by this authority before it is executed. Zero means that the action is executed immediately.
+	ExecutionDelay beacon.EpochTime `json:"execution_delay,omitempty"`
 }
 
 // Validate validates the authority configuration.
@@ -93,6 +101,10 @@ func (a *Authority) Validate(params *ConsensusParameters) error {
 		return fmt.Errorf("too many addresses in authority (max: %d got: %d)",
 			params.MaxAuthorityAddresses, len(a.Addresses))
 	}
+	if a.ExecutionDelay > params.MaxExecutionDelay {
+		return fmt.Errorf("execution delay too long (max: %d got: %d)",
+			params.MaxExecutionDelay, a.ExecutionDelay)
+	}
 
 	// Ensure no duplicate addresses.
 	addressSet := make(map[staking.Address]struct{})
@@ -139,6 +151,9 @@ func (a Authority) PrettyPrint(_ context.Context, prefix string, w io.Writer) {
 		fmt.Fprintf(w, "%s  - %s\n", prefix, addr)
 	}
 	fmt.Fprintf(w, "%sThreshold: %d\n", prefix, a.Threshold)
+	if a.ExecutionDelay > 0 {
+		fmt.Fprintf(w, "%sExecution delay: %d epoch(s)\n", prefix, a.ExecutionDelay)
+	}
 }
 
 // PrettyType returns a representation of Authority that can be used for pretty printing.
@@ -47,6 +47,13 @@ func TestAuthority(t *testing.T) {
 	err = auth.Validate(&DefaultConsensusParameters)
 	require.NoError(err, "Validate should succeed on valid authority configuration")
 
+	auth.ExecutionDelay = DefaultConsensusParameters.MaxExecutionDelay + 1
+	err = auth.Validate(&DefaultConsensusParameters)
+	require.Error(err, "Validate should fail on execution delay that exceeds the maximum")
+	auth.ExecutionDelay = DefaultConsensusParameters.MaxExecutionDelay
+	err = auth.Validate(&DefaultConsensusParameters)
+	require.NoError(err, "Validate should succeed on maximum execution delay")
+
 	ok := auth.Verify([]staking.Address{testAddrA})
 	require.False(ok, "Verify(testAddrA)")
 	ok = auth.Verify([]staking.Address{testAddrA, testAddrB})
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/loadbalance"
+)
+
+// withCallerAffinity returns a copy of the given context carrying an affinity key identifying the
+// caller, so that load-balanced queries of the same caller are routed to the same runtime instance.
+//
+// An explicit session key passed by the caller takes precedence. Otherwise, the caller is identified
+// by its TLS client certificate or its remote address. Callers connected over the local UNIX socket
+// cannot be told apart, in which case the context is returned unchanged.
+func withCallerAffinity(ctx context.Context) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(api.AffinityKeyMetadataKey); len(keys) > 0 && keys[0] != "" {
+			return loadbalance.WithAffinityKey(ctx, []byte(keys[0]))
+		}
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ctx
+	}
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		return loadbalance.WithAffinityKey(ctx, tlsInfo.State.PeerCertificates[0].Raw)
+	}
+	if p.Addr.Network() == "unix" {
+		return ctx
+	}
+	return loadbalance.WithAffinityKey(ctx, []byte(p.Addr.String()))
+}
@@ -116,6 +116,45 @@ func (s *service) WatchBlocks(_ context.Context, runtimeID common.Namespace) (<-
 	return rt.History().WatchBlocks()
 }
 
+// Implements api.RuntimeClient.
+func (s *service) WatchStateDiffs(ctx context.Context, request *api.WatchStateDiffsRequest) (<-chan *api.StateDiff, pubsub.ClosableSubscription, error) {
+	rt, err := s.w.commonWorker.RuntimeRegistry.GetRuntime(request.RuntimeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	lsb, ok := rt.Storage().(storage.LocalBackend)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: no local storage", api.ErrStateDiffUnavailable)
+	}
+
+	genesisBlk, err := s.GetGenesisBlock(ctx, request.RuntimeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w := &stateDiffWatcher{
+		history:      rt.History(),
+		backend:      lsb,
+		genesisRound: genesisBlk.Header.Round,
+		nextRound:    max(request.StartRound, genesisBlk.Header.Round),
+		logger:       s.w.logger.With("runtime_id", request.RuntimeID),
+	}
+	if request.Cursor != nil {
+		if err = w.resume(ctx, request.Cursor); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err = w.checkAvailable(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+	ch := make(chan *api.StateDiff)
+	go w.run(ctx, ch)
+
+	return ch, sub, nil
+}
+
 // Implements api.RuntimeClient.
 func (s *service) GetGenesisBlock(ctx context.Context, runtimeID common.Namespace) (*block.Block, error) {
 	return s.w.commonWorker.Consensus.RootHash().GetGenesisBlock(ctx, &roothash.RuntimeRequest{
@@ -261,6 +300,70 @@ func (s *service) GetUnconfirmedTransactions(_ context.Context, runtimeID common
 	return out, nil
 }
 
+// Implements api.RuntimeClient.
+func (s *service) GetTransactionPoolEntries(_ context.Context, runtimeID common.Namespace) ([]*api.TransactionPoolEntry, error) {
+	rt := s.w.commonWorker.GetRuntime(runtimeID)
+	if rt == nil {
+		return nil, api.ErrNotFound
+	}
+
+	entries := rt.TxPool.GetEntries()
+	out := make([]*api.TransactionPoolEntry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, &api.TransactionPoolEntry{
+			Hash:         entry.Hash,
+			Size:         uint64(entry.Size),
+			Queue:        string(entry.Queue),
+			Priority:     entry.Priority,
+			Sender:       entry.Sender,
+			SenderSeq:    entry.SenderSeq,
+			FirstSeen:    entry.FirstSeen,
+			RecheckCount: entry.RecheckCount,
+		})
+	}
+	return out, nil
+}
+
+// Implements api.RuntimeClient.
+func (s *service) WatchTransactionPoolEvents(ctx context.Context, runtimeID common.Namespace) (<-chan *api.TransactionPoolEvent, pubsub.ClosableSubscription, error) {
+	rt := s.w.commonWorker.GetRuntime(runtimeID)
+	if rt == nil {
+		return nil, nil, api.ErrNotFound
+	}
+
+	evCh, evSub := rt.TxPool.WatchEvents()
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+	ch := make(chan *api.TransactionPoolEvent)
+	go func() {
+		defer close(ch)
+		defer evSub.Close()
+
+		for {
+			select {
+			case ev, ok := <-evCh:
+				if !ok {
+					return
+				}
+
+				select {
+				case ch <- &api.TransactionPoolEvent{
+					Kind:   string(ev.Kind),
+					Hash:   ev.Hash,
+					Queue:  string(ev.Queue),
+					Reason: ev.Reason,
+				}:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 // Implements api.RuntimeClient.
 func (s *service) GetEvents(ctx context.Context, request *api.GetEventsRequest) ([]*api.Event, error) {
 	rt, err := s.w.commonWorker.RuntimeRegistry.GetRuntime(request.RuntimeID)
@@ -299,6 +402,7 @@ func (s *service) Query(ctx context.Context, request *api.QueryRequest) (*api.Qu
 		return nil, api.ErrNoHostedRuntime
 	}
 
+	ctx = withCallerAffinity(ctx)
 	data, err := rt.Query(ctx, request.Round, request.Method, request.Args, request.Component)
 	if err != nil {
 		return nil, err
@@ -341,3 +445,21 @@ func (sr *storageRouter) SyncIterate(ctx context.Context, request *syncer.Iterat
 	}
 	return rt.Storage().SyncIterate(ctx, request)
 }
+
+// Implements syncer.ReadSyncer.
+func (sr *storageRouter) SyncGetMulti(ctx context.Context, request *syncer.GetMultiRequest) (*syncer.ProofResponse, error) {
+	rt, err := sr.r.GetRuntime(request.Tree.Root.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return rt.Storage().SyncGetMulti(ctx, request)
+}
+
+// Implements syncer.ReadSyncer.
+func (sr *storageRouter) SyncGetRange(ctx context.Context, request *syncer.GetRangeRequest) (*syncer.ProofResponse, error) {
+	rt, err := sr.r.GetRuntime(request.Tree.Root.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return rt.Storage().SyncGetRange(ctx, request)
+}
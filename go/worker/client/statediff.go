@@ -0,0 +1,311 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/history"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+)
+
+// computeStateDiff computes the state diff between the state roots of the given blocks, including
+// the old and new values of all changed keys. The previous block may be nil in which case the
+// diff is computed against the empty root.
+func computeStateDiff(ctx context.Context, backend storage.LocalBackend, prevBlk, blk *block.Block) (*api.StateDiff, error) {
+	newRoot := storage.Root{
+		Namespace: blk.Header.Namespace,
+		Version:   blk.Header.Round,
+		Type:      storage.RootTypeState,
+		Hash:      blk.Header.StateRoot,
+	}
+	oldRoot := storage.Root{
+		Namespace: blk.Header.Namespace,
+		Version:   blk.Header.Round,
+		Type:      storage.RootTypeState,
+	}
+	oldRoot.Hash.Empty()
+	if prevBlk != nil {
+		oldRoot.Version = prevBlk.Header.Round
+		oldRoot.Hash = prevBlk.Header.StateRoot
+	}
+
+	sd := &api.StateDiff{
+		Round:             blk.Header.Round,
+		PreviousStateRoot: oldRoot.Hash,
+		StateRoot:         newRoot.Hash,
+		Cursor: api.StateDiffCursor{
+			Round:     blk.Header.Round,
+			StateRoot: newRoot.Hash,
+		},
+	}
+	if oldRoot.Hash.Equal(&newRoot.Hash) {
+		return sd, nil
+	}
+
+	oldTree := mkvs.NewWithRoot(nil, backend.NodeDB(), oldRoot)
+	defer oldTree.Close()
+
+	it, err := backend.GetDiff(ctx, &storage.GetDiffRequest{StartRoot: oldRoot, EndRoot: newRoot})
+	switch {
+	case err == nil:
+		for {
+			more, err := it.Next()
+			if err != nil {
+				return nil, fmt.Errorf("failed to iterate over write log: %w", err)
+			}
+			if !more {
+				break
+			}
+			entry, err := it.Value()
+			if err != nil {
+				return nil, fmt.Errorf("failed to iterate over write log: %w", err)
+			}
+
+			oldValue, err := oldTree.Get(ctx, entry.Key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get previous value: %w", err)
+			}
+			if bytes.Equal(oldValue, entry.Value) {
+				continue
+			}
+			sd.Entries = append(sd.Entries, &api.StateDiffEntry{
+				Key:      entry.Key,
+				OldValue: oldValue,
+				NewValue: entry.Value,
+			})
+		}
+		sort.Slice(sd.Entries, func(i, j int) bool {
+			return bytes.Compare(sd.Entries[i].Key, sd.Entries[j].Key) < 0
+		})
+	case errors.Is(err, storage.ErrWriteLogNotFound):
+		// Write logs are not available for all rounds (e.g. for rounds restored from
+		// checkpoints), so fall back to comparing both trees.
+		newTree := mkvs.NewWithRoot(nil, backend.NodeDB(), newRoot)
+		defer newTree.Close()
+
+		if sd.Entries, err = compareTrees(ctx, oldTree, newTree); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("failed to get write log: %w", err)
+	}
+
+	return sd, nil
+}
+
+// compareTrees returns all keys that differ between the given trees in key order.
+func compareTrees(ctx context.Context, oldTree, newTree mkvs.Tree) ([]*api.StateDiffEntry, error) {
+	oldIt := oldTree.NewIterator(ctx)
+	defer oldIt.Close()
+	newIt := newTree.NewIterator(ctx)
+	defer newIt.Close()
+
+	var entries []*api.StateDiffEntry
+	oldIt.Rewind()
+	newIt.Rewind()
+	for oldIt.Valid() || newIt.Valid() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		var cmp int
+		switch {
+		case !newIt.Valid():
+			cmp = -1
+		case !oldIt.Valid():
+			cmp = 1
+		default:
+			cmp = bytes.Compare(oldIt.Key(), newIt.Key())
+		}
+
+		switch {
+		case cmp < 0:
+			entries = append(entries, &api.StateDiffEntry{
+				Key:      oldIt.Key(),
+				OldValue: oldIt.Value(),
+			})
+			oldIt.Next()
+		case cmp > 0:
+			entries = append(entries, &api.StateDiffEntry{
+				Key:      newIt.Key(),
+				NewValue: newIt.Value(),
+			})
+			newIt.Next()
+		default:
+			if !bytes.Equal(oldIt.Value(), newIt.Value()) {
+				entries = append(entries, &api.StateDiffEntry{
+					Key:      newIt.Key(),
+					OldValue: oldIt.Value(),
+					NewValue: newIt.Value(),
+				})
+			}
+			oldIt.Next()
+			newIt.Next()
+		}
+	}
+	if err := oldIt.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over previous state: %w", err)
+	}
+	if err := newIt.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over state: %w", err)
+	}
+	return entries, nil
+}
+
+// stateDiffWatcher streams per-round state diffs backed by the runtime history and local storage.
+type stateDiffWatcher struct {
+	history      history.History
+	backend      storage.LocalBackend
+	genesisRound uint64
+
+	nextRound uint64
+	prevBlk   *block.Block
+
+	logger *logging.Logger
+}
+
+// resume validates the given cursor against the local history and positions the watcher at the
+// round following it.
+func (w *stateDiffWatcher) resume(ctx context.Context, cursor *api.StateDiffCursor) error {
+	blk, err := w.history.GetCommittedBlock(ctx, cursor.Round)
+	switch {
+	case err == nil:
+	case errors.Is(err, roothash.ErrNotFound):
+		// Report cursors pointing at pruned rounds as such.
+		w.nextRound = cursor.Round + 1
+		if err = w.checkAvailable(ctx); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: unknown round %d", api.ErrInvalidCursor, cursor.Round)
+	default:
+		return err
+	}
+	if !blk.Header.StateRoot.Equal(&cursor.StateRoot) {
+		return fmt.Errorf("%w: state root mismatch at round %d", api.ErrInvalidCursor, cursor.Round)
+	}
+
+	w.nextRound = cursor.Round + 1
+	w.prevBlk = blk
+	return nil
+}
+
+// checkAvailable makes sure that the state needed to compute the first diff is still available.
+func (w *stateDiffWatcher) checkAvailable(ctx context.Context) error {
+	if w.nextRound == w.genesisRound {
+		return nil
+	}
+	prevRound := w.nextRound - 1
+
+	if earliest := w.backend.NodeDB().GetEarliestVersion(); prevRound < earliest {
+		return fmt.Errorf("%w: state for round %d has been pruned (earliest: %d)", api.ErrStateDiffUnavailable, prevRound, earliest)
+	}
+	earliestBlk, err := w.history.GetEarliestBlock(ctx)
+	switch {
+	case err == nil:
+		if prevRound < earliestBlk.Header.Round {
+			return fmt.Errorf("%w: block for round %d has been pruned (earliest: %d)", api.ErrStateDiffUnavailable, prevRound, earliestBlk.Header.Round)
+		}
+	case errors.Is(err, roothash.ErrNotFound):
+	default:
+		return err
+	}
+	return nil
+}
+
+// diff computes the state diff for the next round.
+func (w *stateDiffWatcher) diff(ctx context.Context) (*api.StateDiff, error) {
+	blk, err := w.history.GetBlock(ctx, w.nextRound)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block for round %d: %w", w.nextRound, err)
+	}
+
+	if w.prevBlk == nil && w.nextRound != w.genesisRound {
+		if w.prevBlk, err = w.history.GetBlock(ctx, w.nextRound-1); err != nil {
+			return nil, fmt.Errorf("failed to get block for round %d: %w", w.nextRound-1, err)
+		}
+	}
+
+	sd, err := computeStateDiff(ctx, w.backend, w.prevBlk, blk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute state diff for round %d: %w", w.nextRound, err)
+	}
+	w.prevBlk = blk
+	w.nextRound++
+	return sd, nil
+}
+
+// catchUp emits state diffs for all rounds up to and including the given round.
+func (w *stateDiffWatcher) catchUp(ctx context.Context, round uint64, ch chan<- *api.StateDiff) error {
+	for w.nextRound <= round {
+		sd, err := w.diff(ctx)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case ch <- sd:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (w *stateDiffWatcher) streamFailed(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		// The subscriber went away.
+		return
+	}
+	w.logger.Error("failed to stream state diffs",
+		"err", err,
+		"round", w.nextRound,
+	)
+}
+
+func (w *stateDiffWatcher) run(ctx context.Context, ch chan<- *api.StateDiff) {
+	defer close(ch)
+
+	// Subscribe before catching up so no rounds can be missed.
+	blkCh, blkSub, err := w.history.WatchBlocks()
+	if err != nil {
+		w.logger.Error("failed to watch blocks", "err", err)
+		return
+	}
+	defer blkSub.Close()
+
+	blk, err := w.history.GetBlock(ctx, roothash.RoundLatest)
+	switch {
+	case err == nil:
+		if err = w.catchUp(ctx, blk.Header.Round, ch); err != nil {
+			w.streamFailed(ctx, err)
+			return
+		}
+	case errors.Is(err, roothash.ErrNotFound):
+	default:
+		w.logger.Error("failed to get latest block", "err", err)
+		return
+	}
+
+	for {
+		select {
+		case annBlk, ok := <-blkCh:
+			if !ok {
+				return
+			}
+			if err = w.catchUp(ctx, annBlk.Block.Header.Round, ch); err != nil {
+				w.streamFailed(ctx, err)
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
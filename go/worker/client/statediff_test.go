@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/history"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/database"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+)
+
+const recvTimeout = 2 * time.Second
+
+var testNs = common.NewTestNamespaceFromSeed([]byte("client state diff test ns"), 0)
+
+func TestStateDiffs(t *testing.T) {
+	for _, backend := range []string{database.BackendNameBadgerDB, database.BackendNamePathBadger} {
+		t.Run(backend, func(t *testing.T) {
+			testStateDiffs(t, backend)
+		})
+	}
+}
+
+// commitRound applies the given updates on top of the previous round and returns the new block.
+func commitRound(ctx context.Context, t *testing.T, backend storage.LocalBackend, prevBlk *block.Block, updates map[string][]byte) *block.Block {
+	root := storage.Root{
+		Namespace: testNs,
+		Type:      storage.RootTypeState,
+	}
+	root.Hash.Empty()
+	round := uint64(0)
+	if prevBlk != nil {
+		root.Version = prevBlk.Header.Round
+		root.Hash = prevBlk.Header.StateRoot
+		round = prevBlk.Header.Round + 1
+	}
+
+	tree := mkvs.NewWithRoot(nil, backend.NodeDB(), root)
+	defer tree.Close()
+	for key, value := range updates {
+		var err error
+		switch value {
+		case nil:
+			err = tree.Remove(ctx, []byte(key))
+		default:
+			err = tree.Insert(ctx, []byte(key), value)
+		}
+		require.NoError(t, err, "Insert/Remove")
+	}
+	_, stateRoot, err := tree.Commit(ctx, testNs, round)
+	require.NoError(t, err, "Commit")
+
+	root.Version = round
+	root.Hash = stateRoot
+	err = backend.NodeDB().Finalize([]storage.Root{root})
+	require.NoError(t, err, "Finalize")
+
+	blk := block.NewGenesisBlock(testNs, 0)
+	blk.Header.Round = round
+	blk.Header.StateRoot = stateRoot
+	return blk
+}
+
+func testStateDiffs(t *testing.T, backendName string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "oasis-client-statediff-test")
+	require.NoError(t, err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	backend, err := database.New(&storage.Config{
+		Backend:      backendName,
+		DB:           filepath.Join(dir, "storage"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+		NoFsync:      true,
+	})
+	require.NoError(t, err, "database.New")
+	defer backend.Cleanup()
+
+	hist, err := history.New(testNs, dir, history.NewNonePrunerFactory(), false)
+	require.NoError(t, err, "history.New")
+	defer hist.Close()
+	err = hist.SetInitialized()
+	require.NoError(t, err, "SetInitialized")
+
+	// Round updates, nil values denote removals.
+	updates := []map[string][]byte{
+		{"a": []byte("a0"), "b": []byte("b0"), "c": []byte("c0")},
+		{"a": []byte("a1"), "d": []byte("d1")},
+		{"b": nil, "c": []byte("c0")},
+		{},
+		{"a": nil, "e": []byte("e4")},
+		{"b": []byte("b5")},
+	}
+	expected := [][]*api.StateDiffEntry{
+		{
+			{Key: []byte("a"), NewValue: []byte("a0")},
+			{Key: []byte("b"), NewValue: []byte("b0")},
+			{Key: []byte("c"), NewValue: []byte("c0")},
+		},
+		{
+			{Key: []byte("a"), OldValue: []byte("a0"), NewValue: []byte("a1")},
+			{Key: []byte("d"), NewValue: []byte("d1")},
+		},
+		{
+			{Key: []byte("b"), OldValue: []byte("b0")},
+		},
+		nil,
+		{
+			{Key: []byte("a"), OldValue: []byte("a1")},
+			{Key: []byte("e"), NewValue: []byte("e4")},
+		},
+		{
+			{Key: []byte("b"), NewValue: []byte("b5")},
+		},
+	}
+
+	var (
+		blks    []*block.Block
+		prevBlk *block.Block
+	)
+	commit := func(round int) {
+		blk := commitRound(ctx, t, backend, prevBlk, updates[round])
+		err := hist.Commit([]*roothash.AnnotatedBlock{{Height: int64(round + 1), Block: blk}})
+		require.NoError(t, err, "history.Commit")
+		blks = append(blks, blk)
+		prevBlk = blk
+	}
+	checkDiff := func(sd *api.StateDiff, round int) {
+		require.EqualValues(t, round, sd.Round, "diff round should be correct")
+		require.EqualValues(t, blks[round].Header.StateRoot, sd.StateRoot, "state root should be correct")
+		require.EqualValues(t, api.StateDiffCursor{Round: uint64(round), StateRoot: sd.StateRoot}, sd.Cursor, "cursor should be correct")
+		require.EqualValues(t, expected[round], sd.Entries, fmt.Sprintf("entries for round %d should be correct", round))
+	}
+	recv := func(ch <-chan *api.StateDiff) *api.StateDiff {
+		select {
+		case sd, ok := <-ch:
+			require.True(t, ok, "channel should not be closed")
+			return sd
+		case <-time.After(recvTimeout):
+			require.FailNow(t, "timed out waiting for state diff")
+			return nil
+		}
+	}
+	newWatcher := func() *stateDiffWatcher {
+		return &stateDiffWatcher{
+			history: hist,
+			backend: backend,
+			logger:  logging.GetLogger("client/statediff/test"),
+		}
+	}
+
+	// Commit some rounds before the watcher starts.
+	for round := 0; round < 3; round++ {
+		commit(round)
+	}
+
+	w := newWatcher()
+	err = w.checkAvailable(ctx)
+	require.NoError(t, err, "checkAvailable")
+	ch := make(chan *api.StateDiff)
+	wCtx, wCancel := context.WithCancel(ctx)
+	go w.run(wCtx, ch)
+
+	var cursor api.StateDiffCursor
+	for round := 0; round < 3; round++ {
+		sd := recv(ch)
+		checkDiff(sd, round)
+		cursor = sd.Cursor
+	}
+
+	// New rounds should be streamed as they are committed.
+	commit(3)
+	checkDiff(recv(ch), 3)
+	wCancel()
+
+	// Resuming from a cursor should continue with the next round.
+	commit(4)
+	commit(5)
+	w = newWatcher()
+	err = w.resume(ctx, &cursor)
+	require.NoError(t, err, "resume")
+	ch = make(chan *api.StateDiff)
+	go w.run(ctx, ch)
+	for round := 3; round < 6; round++ {
+		checkDiff(recv(ch), round)
+	}
+
+	// Cursors not matching the history should be rejected.
+	err = newWatcher().resume(ctx, &api.StateDiffCursor{Round: 2, StateRoot: hash.NewFromBytes([]byte("bad"))})
+	require.ErrorIs(t, err, api.ErrInvalidCursor, "resume should fail for mismatched state root")
+	err = newWatcher().resume(ctx, &api.StateDiffCursor{Round: 10})
+	require.ErrorIs(t, err, api.ErrInvalidCursor, "resume should fail for unknown rounds")
+
+	// Comparing trees should give the same result as using write logs.
+	for round := 1; round < 6; round++ {
+		oldTree := mkvs.NewWithRoot(nil, backend.NodeDB(), stateRoot(blks[round-1]))
+		newTree := mkvs.NewWithRoot(nil, backend.NodeDB(), stateRoot(blks[round]))
+		entries, err := compareTrees(ctx, oldTree, newTree)
+		require.NoError(t, err, "compareTrees")
+		require.EqualValues(t, expected[round], entries, fmt.Sprintf("entries for round %d should be correct", round))
+		oldTree.Close()
+		newTree.Close()
+	}
+}
+
+func stateRoot(blk *block.Block) storage.Root {
+	return storage.Root{
+		Namespace: blk.Header.Namespace,
+		Version:   blk.Header.Round,
+		Type:      storage.RootTypeState,
+		Hash:      blk.Header.StateRoot,
+	}
+}
@@ -30,6 +30,16 @@ func (s *statelessStorage) SyncIterate(ctx context.Context, request *storage.Ite
 	return rsp, err
 }
 
+func (s *statelessStorage) SyncGetMulti(ctx context.Context, request *storage.GetMultiRequest) (*storage.ProofResponse, error) {
+	rsp, _, err := s.rpc.GetMulti(ctx, request)
+	return rsp, err
+}
+
+func (s *statelessStorage) SyncGetRange(ctx context.Context, request *storage.GetRangeRequest) (*storage.ProofResponse, error) {
+	rsp, _, err := s.rpc.GetRange(ctx, request)
+	return rsp, err
+}
+
 func (s *statelessStorage) GetDiff(context.Context, *storage.GetDiffRequest) (storage.WriteLogIterator, error) {
 	return nil, storage.ErrUnsupported
 }
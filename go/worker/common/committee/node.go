@@ -31,6 +31,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
 	tpConfig "github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p/bundlesync"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p/txsync"
 )
 
@@ -919,7 +920,7 @@ func NewNode(
 	handler := runtimeRegistry.NewRuntimeHostHandler(&nodeEnvironment{n}, n.Runtime, consensus)
 
 	// Prepare the runtime host node helpers.
-	rhn, err := runtimeRegistry.NewRuntimeHostNode(runtime, provisioner, handler)
+	rhn, err := runtimeRegistry.NewRuntimeHostNode(runtime, provisioner, handler, rtRegistry.GetVolumeManager())
 	if err != nil {
 		return nil, err
 	}
@@ -929,7 +930,7 @@ func NewNode(
 	n.notifier = runtimeRegistry.NewRuntimeHostNotifier(runtime, rhn.GetHostedRuntime(), consensus)
 
 	// Prepare transaction pool.
-	n.TxPool = txpool.New(runtime.ID(), txPoolCfg, rhn.GetHostedRuntime(), runtime.History(), n)
+	n.TxPool = txpool.New(runtime.ID(), txPoolCfg, rhn.GetHostedRuntime(), runtime.History(), n, runtime.DataDir())
 
 	// Register transaction message handler as that is something that all workers must handle.
 	p2pHost.RegisterHandler(txTopic, &txMsgHandler{n})
@@ -937,5 +938,10 @@ func NewNode(
 	// Register transaction sync service.
 	p2pHost.RegisterProtocolServer(txsync.NewServer(chainContext, runtime.ID(), n.TxPool))
 
+	// Register bundle sync service and allow the bundle manager to fetch bundles from peers.
+	bundleManager := rtRegistry.GetBundleManager()
+	p2pHost.RegisterProtocolServer(bundlesync.NewServer(chainContext, runtime.ID(), bundleManager))
+	bundleManager.RegisterPeerFetcher(runtime.ID(), bundlesync.NewClient(p2pHost, chainContext, runtime.ID()))
+
 	return n, nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/config"
+	p2pAPI "github.com/oasisprotocol/oasis-core/go/p2p/api"
 	p2pError "github.com/oasisprotocol/oasis-core/go/p2p/error"
 	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
 )
@@ -29,7 +30,7 @@ func (h *txMsgHandler) AuthorizeMessage(context.Context, signature.PublicKey, an
 	return nil
 }
 
-func (h *txMsgHandler) HandleMessage(ctx context.Context, _ signature.PublicKey, msg any, isOwn bool) error {
+func (h *txMsgHandler) HandleMessage(ctx context.Context, from signature.PublicKey, msg any, isOwn bool) error {
 	// Ignore own messages as those are handled separately.
 	if isOwn {
 		return nil
@@ -41,6 +42,17 @@ func (h *txMsgHandler) HandleMessage(ctx context.Context, _ signature.PublicKey,
 	case config.ModeStatelessClient:
 		// Ignore transactions on stateless clients.
 	default:
+		// Enforce the per-peer rate limit. As the message may have been relayed, the transaction
+		// is only dropped without retrying and the message is still relayed so that the relaying
+		// peers are not penalized.
+		peerID, err := p2pAPI.PublicKeyToPeerID(from)
+		if err != nil {
+			return p2pError.Permanent(err)
+		}
+		if !h.n.TxPool.AllowPeerTxs(peerID.String(), 1) {
+			return p2pError.Relayable(p2pError.Permanent(txpool.ErrPeerRateLimited))
+		}
+
 		// Queue in local transaction pool if we are not running a stateless client.
 		result, err := h.n.TxPool.SubmitTx(ctx, tx, &txpool.TransactionMeta{Local: false})
 		switch {
@@ -0,0 +1,223 @@
+package bundlesync
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/p2p/api"
+	"github.com/oasisprotocol/oasis-core/go/p2p/protocol"
+	"github.com/oasisprotocol/oasis-core/go/p2p/rpc"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+const testChainContext = "bundlesync test"
+
+type testBundleProvider struct {
+	mu      sync.Mutex
+	bundles map[hash.Hash]*bundle.Bundle
+}
+
+func (p *testBundleProvider) GetExplodedBundle(_ common.Namespace, manifestHash hash.Hash) (*bundle.Bundle, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	bnd, ok := p.bundles[manifestHash]
+	if !ok {
+		return nil, fmt.Errorf("bundle not found: %s", manifestHash.Hex())
+	}
+	return bnd, nil
+}
+
+func (p *testBundleProvider) add(manifestHash hash.Hash, bnd *bundle.Bundle) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.bundles[manifestHash] = bnd
+}
+
+type testPeerManager struct {
+	rpc.PeerManager
+
+	peers []core.PeerID
+
+	mu       sync.Mutex
+	badPeers int
+}
+
+func (m *testPeerManager) RecordSuccess(core.PeerID, time.Duration) {
+}
+
+func (m *testPeerManager) RecordFailure(core.PeerID, time.Duration) {
+}
+
+func (m *testPeerManager) RecordBadPeer(core.PeerID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.badPeers++
+}
+
+func (m *testPeerManager) GetBestPeers(...rpc.BestPeersOption) []core.PeerID {
+	return m.peers
+}
+
+func (m *testPeerManager) numBadPeers() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.badPeers
+}
+
+func newTestHost(t *testing.T) host.Host {
+	signer, err := memory.NewFactory().Generate(signature.SignerP2P, rand.Reader)
+	require.NoError(t, err, "Generate")
+
+	listenAddr, err := multiaddr.NewMultiaddr("/ip4/127.0.0.1/tcp/0")
+	require.NoError(t, err, "NewMultiaddr")
+
+	h, err := libp2p.New(
+		libp2p.ListenAddrs(listenAddr),
+		libp2p.Identity(api.SignerToPrivKey(signer)),
+	)
+	require.NoError(t, err, "libp2p.New")
+	t.Cleanup(func() { h.Close() })
+
+	return h
+}
+
+func newTestBundle(t *testing.T, runtimeID common.Namespace, name string, files map[string]int) *bundle.Bundle {
+	bnd := &bundle.Bundle{
+		Manifest: &bundle.Manifest{
+			Name: name,
+			ID:   runtimeID,
+			Components: []*bundle.Component{
+				{
+					Kind: component.ROFL,
+					Name: "my-rofl-comp",
+					ELF: &bundle.ELFMetadata{
+						Executable: "rofl.bin",
+					},
+				},
+			},
+		},
+	}
+	for fn, size := range files {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i*7 + len(fn))
+		}
+		err := bnd.Add(fn, bundle.NewBytesData(data))
+		require.NoError(t, err, "bundle.Add")
+	}
+	return bnd
+}
+
+func TestBundleSync(t *testing.T) {
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("bundlesync test ns"), 0)
+	pid := protocol.NewRuntimeProtocolID(testChainContext, runtimeID, BundleSyncProtocolID, BundleSyncProtocolVersion)
+
+	provider := &testBundleProvider{bundles: make(map[hash.Hash]*bundle.Bundle)}
+	serverHost := newTestHost(t)
+	srv := NewServer(testChainContext, runtimeID, provider)
+	serverHost.SetStreamHandler(srv.Protocol(), srv.HandleStream)
+
+	clientHost := newTestHost(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := clientHost.Connect(ctx, peer.AddrInfo{
+		ID:    serverHost.ID(),
+		Addrs: serverHost.Addrs(),
+	})
+	require.NoError(t, err, "Connect")
+
+	newClient := func() (*client, *testPeerManager) {
+		mgr := &testPeerManager{peers: []core.PeerID{serverHost.ID()}}
+		rc := rpc.NewClient(clientHost, pid)
+		rc.RegisterListener(mgr)
+		return &client{rc: rc, mgr: mgr}, mgr
+	}
+	tmpDir := t.TempDir()
+
+	t.Run("HappyPath", func(t *testing.T) {
+		require := require.New(t)
+
+		// Files that are empty or an exact multiple of the chunk size should also be fetched.
+		bnd := newTestBundle(t, runtimeID, "happy", map[string]int{
+			"rofl.bin":  2 * MaxFileChunkSize,
+			"extra.bin": MaxFileChunkSize + 123,
+			"empty.bin": 0,
+		})
+		manifestHash := bnd.Manifest.Hash()
+		provider.add(manifestHash, bnd)
+
+		c, mgr := newClient()
+		fn := filepath.Join(tmpDir, "happy.orc")
+		err := c.FetchBundle(ctx, manifestHash, fn, 4*MaxFileChunkSize)
+		require.NoError(err, "FetchBundle")
+		require.Zero(mgr.numBadPeers())
+
+		fetched, err := bundle.Open(fn, bundle.WithManifestHash(manifestHash))
+		require.NoError(err, "Open")
+		defer fetched.Close()
+		for name, data := range bnd.Data {
+			expected, err := bundle.ReadAllData(data)
+			require.NoError(err, "ReadAllData")
+			actual, err := bundle.ReadAllData(fetched.Data[name])
+			require.NoError(err, "ReadAllData")
+			require.Equal(expected, actual, "file '%s' should be fetched", name)
+		}
+
+		// Bundles exceeding the size limit should be rejected without penalizing the peer.
+		err = c.FetchBundle(ctx, manifestHash, filepath.Join(tmpDir, "too-large.orc"), 2*MaxFileChunkSize)
+		require.ErrorContains(err, "bundle exceeds size limit")
+		require.Zero(mgr.numBadPeers())
+	})
+
+	t.Run("ManifestHashMismatch", func(t *testing.T) {
+		require := require.New(t)
+
+		bnd := newTestBundle(t, runtimeID, "mismatch", map[string]int{"rofl.bin": 1024})
+		other := newTestBundle(t, runtimeID, "other", map[string]int{"rofl.bin": 1024})
+		manifestHash := bnd.Manifest.Hash()
+		provider.add(manifestHash, other)
+
+		c, mgr := newClient()
+		err := c.FetchBundle(ctx, manifestHash, filepath.Join(tmpDir, "mismatch.orc"), 4*MaxFileChunkSize)
+		require.ErrorContains(err, "invalid manifest")
+		require.Equal(1, mgr.numBadPeers())
+	})
+
+	t.Run("CorruptedChunk", func(t *testing.T) {
+		require := require.New(t)
+
+		bnd := newTestBundle(t, runtimeID, "corrupted", map[string]int{"rofl.bin": MaxFileChunkSize + 1})
+		corrupted, err := bundle.ReadAllData(bnd.Data["rofl.bin"])
+		require.NoError(err, "ReadAllData")
+		corrupted[MaxFileChunkSize] ^= 0xff
+		bnd.Data["rofl.bin"] = bundle.NewBytesData(corrupted)
+		manifestHash := bnd.Manifest.Hash()
+		provider.add(manifestHash, bnd)
+
+		c, mgr := newClient()
+		err = c.FetchBundle(ctx, manifestHash, filepath.Join(tmpDir, "corrupted.orc"), 4*MaxFileChunkSize)
+		require.ErrorContains(err, "invalid digest")
+		require.Equal(1, mgr.numBadPeers())
+	})
+}
@@ -0,0 +1,203 @@
+package bundlesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/libp2p/go-libp2p/core"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/p2p/protocol"
+	"github.com/oasisprotocol/oasis-core/go/p2p/rpc"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+)
+
+const (
+	// minProtocolPeers is the minimum number of peers from the registry we want to have connected
+	// for BundleSync protocol.
+	minProtocolPeers = 5
+
+	// totalProtocolPeers is the number of peers we want to have connected for BundleSync protocol.
+	totalProtocolPeers = 10
+)
+
+// Client is a bundle sync protocol client.
+type Client interface {
+	// FetchBundle fetches the bundle with the given manifest hash from peers and writes it to
+	// the given file. The total size of the bundle contents must not exceed maxSize bytes.
+	//
+	// The fetched manifest is verified against the manifest hash and all files against
+	// the manifest digests.
+	FetchBundle(ctx context.Context, manifestHash hash.Hash, fn string, maxSize int64) error
+}
+
+type client struct {
+	rc  rpc.Client
+	mgr rpc.PeerManager
+}
+
+func (c *client) FetchBundle(ctx context.Context, manifestHash hash.Hash, fn string, maxSize int64) error {
+	peers := c.mgr.GetBestPeers()
+	if len(peers) == 0 {
+		return fmt.Errorf("no peers available")
+	}
+
+	// Fetch the whole bundle from a single peer so that misbehaving peers can be identified.
+	var errs error
+	for _, peer := range peers {
+		err := c.fetchBundleFromPeer(ctx, peer, manifestHash, fn, maxSize)
+		if err == nil {
+			return nil
+		}
+		errs = errors.Join(errs, fmt.Errorf("peer %s: %w", peer, err))
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return errs
+}
+
+func (c *client) fetchBundleFromPeer(ctx context.Context, peer core.PeerID, manifestHash hash.Hash, fn string, maxSize int64) error {
+	var rsp GetManifestResponse
+	pf, err := c.rc.Call(ctx, peer, MethodGetManifest, &GetManifestRequest{ManifestHash: manifestHash}, &rsp,
+		rpc.WithMaxPeerResponseTime(MaxGetManifestResponseTime),
+	)
+	if err != nil {
+		return err
+	}
+	if rsp.Manifest == nil {
+		pf.RecordBadPeer()
+		return fmt.Errorf("missing manifest")
+	}
+	if h := rsp.Manifest.Hash(); !h.Equal(&manifestHash) {
+		pf.RecordBadPeer()
+		return fmt.Errorf("invalid manifest (got: %s, expected: %s)", h.Hex(), manifestHash.Hex())
+	}
+
+	dir, err := os.MkdirTemp(filepath.Dir(fn), "oasis-bundle-p2p-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	bnd := &bundle.Bundle{
+		Manifest:   rsp.Manifest,
+		Data:       make(map[string]bundle.Data),
+		Signatures: rsp.Signatures,
+	}
+	var size int64
+	for name, digest := range rsp.Manifest.Digests {
+		// Only files in the bundle root are part of the bundle data.
+		if filepath.Dir(name) != "." {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		var n int64
+		n, err = c.fetchFile(ctx, peer, manifestHash, name, digest, path, maxSize-size)
+		if err != nil {
+			return err
+		}
+		size += n
+
+		bnd.Data[name] = bundle.NewFileData(path)
+	}
+
+	// Writing the bundle also validates it.
+	if err = bnd.Write(fn); err != nil {
+		pf.RecordBadPeer()
+		return err
+	}
+
+	pf.RecordSuccess()
+
+	return nil
+}
+
+func (c *client) fetchFile(
+	ctx context.Context,
+	peer core.PeerID,
+	manifestHash hash.Hash,
+	name string,
+	digest hash.Hash,
+	path string,
+	maxSize int64,
+) (int64, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create '%s': %w", name, err)
+	}
+	defer f.Close()
+
+	hb := hash.NewBuilder()
+	var offset, size uint64
+	for {
+		var rsp GetFileChunkResponse
+		pf, err := c.rc.Call(ctx, peer, MethodGetFileChunk, &GetFileChunkRequest{
+			ManifestHash: manifestHash,
+			Name:         name,
+			Offset:       offset,
+		}, &rsp,
+			rpc.WithMaxPeerResponseTime(MaxGetFileChunkResponseTime),
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		// The file size reported by the peer is verified together with the digest, but it must
+		// not change during the transfer so that the peer cannot stream more data than declared.
+		switch {
+		case offset == 0:
+			if rsp.Size > uint64(maxSize) {
+				return 0, fmt.Errorf("bundle exceeds size limit")
+			}
+			size = rsp.Size
+		case rsp.Size != size:
+			pf.RecordBadPeer()
+			return 0, fmt.Errorf("size of '%s' changed during transfer", name)
+		}
+
+		chunkSize := min(size-offset, MaxFileChunkSize)
+		if uint64(len(rsp.Chunk)) != chunkSize {
+			pf.RecordBadPeer()
+			return 0, fmt.Errorf("invalid chunk of '%s' (got: %d bytes, expected: %d bytes)", name, len(rsp.Chunk), chunkSize)
+		}
+		offset += chunkSize
+
+		_, _ = hb.Write(rsp.Chunk)
+		if _, err = f.Write(rsp.Chunk); err != nil {
+			return 0, fmt.Errorf("failed to write '%s': %w", name, err)
+		}
+
+		if offset == size {
+			break
+		}
+	}
+
+	if h := hb.Build(); !h.Equal(&digest) {
+		c.mgr.RecordBadPeer(peer)
+		return 0, fmt.Errorf("invalid digest: '%s'", name)
+	}
+
+	return int64(size), nil
+}
+
+// NewClient creates a new bundle sync protocol client.
+func NewClient(p2p rpc.P2P, chainContext string, runtimeID common.Namespace) Client {
+	pid := protocol.NewRuntimeProtocolID(chainContext, runtimeID, BundleSyncProtocolID, BundleSyncProtocolVersion)
+	mgr := rpc.NewPeerManager(p2p, pid)
+	rc := rpc.NewClient(p2p.Host(), pid)
+	rc.RegisterListener(mgr)
+
+	p2p.RegisterProtocol(pid, minProtocolPeers, totalProtocolPeers)
+
+	return &client{
+		rc:  rc,
+		mgr: mgr,
+	}
+}
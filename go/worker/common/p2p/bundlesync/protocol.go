@@ -0,0 +1,78 @@
+package bundlesync
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p/core"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/p2p/peermgmt"
+	"github.com/oasisprotocol/oasis-core/go/p2p/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+)
+
+// BundleSyncProtocolID is a unique protocol identifier for the bundle sync protocol.
+const BundleSyncProtocolID = "bundlesync"
+
+// BundleSyncProtocolVersion is the supported version of the bundle sync protocol.
+var BundleSyncProtocolVersion = version.Version{Major: 1, Minor: 0, Patch: 0}
+
+// Constants related to the GetManifest method.
+const (
+	MethodGetManifest          = "GetManifest"
+	MaxGetManifestResponseTime = 15 * time.Second
+)
+
+// GetManifestRequest is a GetManifest request.
+type GetManifestRequest struct {
+	ManifestHash hash.Hash `json:"manifest_hash"`
+}
+
+// GetManifestResponse is a response to a GetManifest request.
+type GetManifestResponse struct {
+	Manifest   *bundle.Manifest      `json:"manifest"`
+	Signatures []signature.Signature `json:"signatures,omitempty"`
+}
+
+// Constants related to the GetFileChunk method.
+const (
+	MethodGetFileChunk          = "GetFileChunk"
+	MaxGetFileChunkResponseTime = 60 * time.Second
+	MaxFileChunkSize            = 1024 * 1024
+)
+
+// GetFileChunkRequest is a GetFileChunk request.
+type GetFileChunkRequest struct {
+	ManifestHash hash.Hash `json:"manifest_hash"`
+	Name         string    `json:"name"`
+	Offset       uint64    `json:"offset"`
+}
+
+// GetFileChunkResponse is a response to a GetFileChunk request.
+//
+// Each chunk is MaxFileChunkSize bytes long, except for the last chunk of the file.
+type GetFileChunkResponse struct {
+	// Size is the total size of the file.
+	Size  uint64 `json:"size"`
+	Chunk []byte `json:"chunk,omitempty"`
+}
+
+func init() {
+	peermgmt.RegisterNodeHandler(&peermgmt.NodeHandlerBundle{
+		ProtocolsFn: func(n *node.Node, chainContext string) []core.ProtocolID {
+			if !n.HasRoles(node.RoleComputeWorker | node.RoleObserver) {
+				return []core.ProtocolID{}
+			}
+
+			protocols := make([]core.ProtocolID, len(n.Runtimes))
+			for i, rt := range n.Runtimes {
+				protocols[i] = protocol.NewRuntimeProtocolID(chainContext, rt.ID, BundleSyncProtocolID, BundleSyncProtocolVersion)
+			}
+
+			return protocols
+		},
+	})
+}
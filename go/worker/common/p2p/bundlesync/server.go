@@ -0,0 +1,190 @@
+package bundlesync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cache/lru"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/p2p/protocol"
+	"github.com/oasisprotocol/oasis-core/go/p2p/rpc"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+)
+
+// BundleProvider is an interface for looking up locally available bundles.
+type BundleProvider interface {
+	// GetExplodedBundle returns the registered bundle of the given runtime with the given
+	// manifest hash.
+	GetExplodedBundle(runtimeID common.Namespace, manifestHash hash.Hash) (*bundle.Bundle, error)
+}
+
+const (
+	// bundleCacheSize is the maximum number of served bundles kept in the cache.
+	bundleCacheSize = 8
+	// bundleCacheTTL is the time after which a cached bundle is looked up again, so that bundles
+	// which are no longer available locally stop being served.
+	bundleCacheTTL = time.Minute
+)
+
+// servedBundle is a bundle served to peers.
+type servedBundle struct {
+	bnd *bundle.Bundle
+	// sizes are the sizes of all bundle files.
+	sizes map[string]uint64
+
+	loadedAt time.Time
+}
+
+type service struct {
+	runtimeID common.Namespace
+	bundles   BundleProvider
+
+	// cache caches served bundles by manifest hash, as bundles are fetched in many requests.
+	cache *lru.Cache
+}
+
+func (s *service) HandleRequest(ctx context.Context, method string, body cbor.RawMessage) (any, error) {
+	switch method {
+	case MethodGetManifest:
+		var rq GetManifestRequest
+		if err := cbor.Unmarshal(body, &rq); err != nil {
+			return nil, rpc.ErrBadRequest
+		}
+
+		return s.handleGetManifest(ctx, &rq)
+	case MethodGetFileChunk:
+		var rq GetFileChunkRequest
+		if err := cbor.Unmarshal(body, &rq); err != nil {
+			return nil, rpc.ErrBadRequest
+		}
+
+		return s.handleGetFileChunk(ctx, &rq)
+	default:
+		return nil, rpc.ErrMethodNotSupported
+	}
+}
+
+// getBundle returns the bundle with the given manifest hash.
+func (s *service) getBundle(manifestHash hash.Hash) (*servedBundle, error) {
+	if cached, ok := s.cache.Get(manifestHash); ok {
+		sb := cached.(*servedBundle)
+		if time.Since(sb.loadedAt) < bundleCacheTTL {
+			return sb, nil
+		}
+	}
+
+	bnd, err := s.bundles.GetExplodedBundle(s.runtimeID, manifestHash)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]uint64, len(bnd.Data))
+	for name, data := range bnd.Data {
+		if sizes[name], err = dataSize(data); err != nil {
+			return nil, fmt.Errorf("failed to determine size of '%s': %w", name, err)
+		}
+	}
+
+	sb := &servedBundle{
+		bnd:      bnd,
+		sizes:    sizes,
+		loadedAt: time.Now(),
+	}
+	_ = s.cache.Put(manifestHash, sb)
+
+	return sb, nil
+}
+
+func (s *service) handleGetManifest(_ context.Context, request *GetManifestRequest) (*GetManifestResponse, error) {
+	sb, err := s.getBundle(request.ManifestHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetManifestResponse{
+		Manifest:   sb.bnd.Manifest,
+		Signatures: sb.bnd.Signatures,
+	}, nil
+}
+
+func (s *service) handleGetFileChunk(_ context.Context, request *GetFileChunkRequest) (*GetFileChunkResponse, error) {
+	sb, err := s.getBundle(request.ManifestHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only serve files covered by the manifest digests.
+	if _, ok := sb.bnd.Manifest.Digests[request.Name]; !ok || filepath.Dir(request.Name) != "." {
+		return nil, rpc.ErrBadRequest
+	}
+	data, ok := sb.bnd.Data[request.Name]
+	if !ok {
+		return nil, rpc.ErrBadRequest
+	}
+	size := sb.sizes[request.Name]
+	if request.Offset > size {
+		return nil, rpc.ErrBadRequest
+	}
+
+	f, err := data.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open '%s': %w", request.Name, err)
+	}
+	defer f.Close()
+
+	switch sf := f.(type) {
+	case io.Seeker:
+		if _, err = sf.Seek(int64(request.Offset), io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to seek '%s': %w", request.Name, err)
+		}
+	default:
+		if _, err = io.CopyN(io.Discard, f, int64(request.Offset)); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read '%s': %w", request.Name, err)
+		}
+	}
+
+	chunk := make([]byte, min(size-request.Offset, MaxFileChunkSize))
+	if _, err = io.ReadFull(f, chunk); err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", request.Name, err)
+	}
+
+	return &GetFileChunkResponse{
+		Size:  size,
+		Chunk: chunk,
+	}, nil
+}
+
+// dataSize returns the size of the given bundle data.
+func dataSize(data bundle.Data) (uint64, error) {
+	f, err := data.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if sf, ok := f.(interface{ Stat() (os.FileInfo, error) }); ok {
+		fi, err := sf.Stat()
+		if err != nil {
+			return 0, err
+		}
+		return uint64(fi.Size()), nil
+	}
+
+	n, err := io.Copy(io.Discard, f)
+	return uint64(n), err
+}
+
+// NewServer creates a new bundle sync protocol server.
+func NewServer(chainContext string, runtimeID common.Namespace, bundles BundleProvider) rpc.Server {
+	return rpc.NewServer(protocol.NewRuntimeProtocolID(chainContext, runtimeID, BundleSyncProtocolID, BundleSyncProtocolVersion), &service{
+		runtimeID: runtimeID,
+		bundles:   bundles,
+		cache:     lru.New(lru.Capacity(bundleCacheSize, false)),
+	})
+}
@@ -14,7 +14,7 @@ type service struct {
 	txPool txpool.TransactionPool
 }
 
-func (s *service) HandleRequest(_ context.Context, method string, body cbor.RawMessage) (any, error) {
+func (s *service) HandleRequest(ctx context.Context, method string, body cbor.RawMessage) (any, error) {
 	switch method {
 	case MethodGetTxs:
 		var rq GetTxsRequest
@@ -22,13 +22,13 @@ func (s *service) HandleRequest(_ context.Context, method string, body cbor.RawM
 			return nil, rpc.ErrBadRequest
 		}
 
-		return s.handleGetTxs(&rq)
+		return s.handleGetTxs(ctx, &rq)
 	default:
 		return nil, rpc.ErrMethodNotSupported
 	}
 }
 
-func (s *service) handleGetTxs(request *GetTxsRequest) (*GetTxsResponse, error) {
+func (s *service) handleGetTxs(ctx context.Context, request *GetTxsRequest) (*GetTxsResponse, error) {
 	var rsp GetTxsResponse
 	switch {
 	case len(request.Txs) == 0:
@@ -39,6 +39,11 @@ func (s *service) handleGetTxs(request *GetTxsRequest) (*GetTxsResponse, error)
 	default:
 	}
 
+	// Enforce the per-peer rate limit on requested transactions.
+	if peerID, ok := rpc.PeerIDFromContext(ctx); ok && !s.txPool.AllowPeerTxs(peerID.String(), len(request.Txs)) {
+		return nil, txpool.ErrPeerRateLimited
+	}
+
 	txs, _ := s.txPool.GetKnownBatch(request.Txs)
 	rsp.Txs = make([][]byte, 0, len(txs))
 	for _, tx := range txs {
@@ -101,10 +101,11 @@ func (n *Node) checkpointChunkFetcher(
 
 		// Fetch chunk from peers.
 		rsp, pf, err := n.storageSync.GetCheckpointChunk(chunkCtx, &storageSync.GetCheckpointChunkRequest{
-			Version: chunk.Version,
-			Root:    chunk.Root,
-			Index:   chunk.Index,
-			Digest:  chunk.Digest,
+			Version:  chunk.Version,
+			Root:     chunk.Root,
+			Index:    chunk.Index,
+			Digest:   chunk.Digest,
+			BaseRoot: chunk.BaseRoot,
 		}, chunk.checkpoint)
 		if err != nil {
 			n.logger.Error("failed to fetch chunk from peers",
@@ -218,10 +219,11 @@ func (n *Node) handleCheckpoint(check *storageSync.Checkpoint, maxParallelReques
 	for i, c := range check.Chunks {
 		heap.Push(chunks, &chunk{
 			ChunkMetadata: &checkpoint.ChunkMetadata{
-				Version: check.Version,
-				Index:   uint64(i),
-				Digest:  c,
-				Root:    check.Root,
+				Version:  check.Version,
+				Index:    uint64(i),
+				Digest:   c,
+				Root:     check.Root,
+				BaseRoot: check.BaseRoot,
 			},
 			checkpoint: check,
 		})
@@ -279,20 +281,36 @@ func (n *Node) getCheckpointList() ([]*storageSync.Checkpoint, error) {
 	ctx, cancel := context.WithTimeout(n.ctx, cpListsTimeout)
 	defer cancel()
 
-	list, err := n.storageSync.GetCheckpoints(ctx, &storageSync.GetCheckpointsRequest{
-		Version: 1,
-	})
-	if err != nil {
+	// Request checkpoints of all supported versions as peers may only have some of them.
+	var (
+		list    []*storageSync.Checkpoint
+		lastErr error
+	)
+	for _, version := range checkpoint.SupportedVersions {
+		cps, err := n.storageSync.GetCheckpoints(ctx, &storageSync.GetCheckpointsRequest{
+			Version: version,
+		})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		list = append(list, cps...)
+	}
+	if len(list) == 0 && lastErr != nil {
 		n.logger.Error("failed to retrieve any checkpoints",
-			"err", err,
+			"err", lastErr,
 		)
-		return nil, err
+		return nil, lastErr
 	}
 
 	// Sort checkpoints by version, descending.
 	sort.Slice(list, func(i, j int) bool {
 		// Descending!
 		if list[j].Root.Version == list[i].Root.Version {
+			if list[j].Root.Hash.Equal(&list[i].Root.Hash) {
+				// Prefer checkpoints with higher (more efficient) versions.
+				return list[j].Version < list[i].Version
+			}
 			return bytes.Compare(list[j].Root.Hash[:], list[i].Root.Hash[:]) < 0
 		}
 		return list[j].Root.Version < list[i].Root.Version
@@ -300,6 +318,225 @@ func (n *Node) getCheckpointList() ([]*storageSync.Checkpoint, error) {
 	return list, nil
 }
 
+// getDeltaCheckpoints returns the delta checkpoints advertised by peers, indexed by their target
+// root and sorted by the number of chunks.
+func (n *Node) getDeltaCheckpoints() map[storageApi.Root][]*storageSync.Checkpoint {
+	ctx, cancel := context.WithTimeout(n.ctx, cpListsTimeout)
+	defer cancel()
+
+	var list []*storageSync.Checkpoint
+	for _, version := range checkpoint.SupportedVersions {
+		cps, err := n.storageSync.GetCheckpoints(ctx, &storageSync.GetCheckpointsRequest{
+			Version:       version,
+			IncludeDeltas: true,
+		})
+		if err != nil {
+			// Delta checkpoints are optional, so just use full checkpoints.
+			n.logger.Debug("failed to retrieve delta checkpoints",
+				"version", version,
+				"err", err,
+			)
+			continue
+		}
+		list = append(list, cps...)
+	}
+
+	deltas := make(map[storageApi.Root][]*storageSync.Checkpoint)
+	for _, cp := range list {
+		// Only deltas against earlier roots of the same type can form a chain.
+		if !cp.IsDelta() || cp.BaseRoot.Type != cp.Root.Type || cp.BaseRoot.Version >= cp.Root.Version {
+			continue
+		}
+		deltas[cp.Root] = append(deltas[cp.Root], cp)
+	}
+	sortCheckpointsByChunks(deltas)
+	return deltas
+}
+
+// sortCheckpointsByChunks sorts the indexed checkpoints by the number of chunks, ascending.
+func sortCheckpointsByChunks(cps map[storageApi.Root][]*storageSync.Checkpoint) {
+	for _, list := range cps {
+		sort.SliceStable(list, func(i, j int) bool {
+			return len(list[i].Chunks) < len(list[j].Chunks)
+		})
+	}
+}
+
+// checkpointChain is a chain of checkpoints that restores a root. It starts with either a full
+// checkpoint or a delta checkpoint whose base root is available locally, and each following delta
+// checkpoint is applied on top of the root restored by the previous one.
+type checkpointChain []*storageSync.Checkpoint
+
+// chunks returns the total number of chunks in the chain.
+func (c checkpointChain) chunks() int {
+	var total int
+	for _, cp := range c {
+		total += len(cp.Chunks)
+	}
+	return total
+}
+
+// getCheckpointChain returns the cheapest chain of checkpoints, ranked by the total number of
+// chunks, that ends with a delta checkpoint for the root of the given full checkpoint. It returns
+// nil in case no such chain is cheaper than the full checkpoint itself.
+func (n *Node) getCheckpointChain(
+	check *storageSync.Checkpoint,
+	fulls map[storageApi.Root][]*storageSync.Checkpoint,
+	deltas map[storageApi.Root][]*storageSync.Checkpoint,
+) checkpointChain {
+	ndb := n.localStorage.NodeDB()
+	latestVersion, hasLatest := ndb.GetLatestVersion()
+
+	type result struct {
+		chain checkpointChain
+		ok    bool
+	}
+	cache := make(map[storageApi.Root]result)
+
+	// Versions of base roots are always lower than the versions of the roots that are restored
+	// on top of them, so the recursion always terminates.
+	var cheapest func(root storageApi.Root) (checkpointChain, bool)
+	cheapest = func(root storageApi.Root) (checkpointChain, bool) {
+		if r, ok := cache[root]; ok {
+			return r.chain, r.ok
+		}
+
+		var best result
+		switch {
+		case ndb.HasRoot(root):
+			best.ok = true
+		case hasLatest && root.Version <= latestVersion:
+			// Versions that have already been finalized can no longer be restored.
+		case n.isCommittedRoot(root):
+			// Intermediate roots are finalized locally so they must be committed.
+			if cps := fulls[root]; len(cps) > 0 {
+				best = result{checkpointChain{cps[0]}, true}
+			}
+			for _, delta := range deltas[root] {
+				base, ok := cheapest(*delta.BaseRoot)
+				if !ok {
+					continue
+				}
+				chain := append(append(checkpointChain{}, base...), delta)
+				if !best.ok || chain.chunks() < best.chain.chunks() {
+					best = result{chain, true}
+				}
+			}
+		}
+		cache[root] = best
+		return best.chain, best.ok
+	}
+
+	var best checkpointChain
+	for _, delta := range deltas[check.Root] {
+		base, ok := cheapest(*delta.BaseRoot)
+		if !ok {
+			continue
+		}
+		chain := append(append(checkpointChain{}, base...), delta)
+		if chain.chunks() >= len(check.Chunks) {
+			continue
+		}
+		if best == nil || chain.chunks() < best.chunks() {
+			best = chain
+		}
+	}
+	return best
+}
+
+// restoreIntermediateCheckpoints restores the given checkpoints in order, finalizing the version
+// of each restored root so that it can serve as the base root for the next checkpoint. It returns
+// the number of checkpoints that have been restored and finalized, even in case of errors.
+func (n *Node) restoreIntermediateCheckpoints(chain checkpointChain) (int, error) {
+	ndb := n.localStorage.NodeDB()
+	for i, cp := range chain {
+		if err := ndb.StartMultipartInsert(cp.Root.Version); err != nil {
+			return i, fmt.Errorf("error starting multipart insert for round %d: %w", cp.Root.Version, err)
+		}
+
+		status, err := n.handleCheckpoint(cp, n.checkpointSyncCfg.ChunkFetcherCount)
+		if status == checkpointStatusDone {
+			err = ndb.Finalize([]storageApi.Root{cp.Root})
+		} else if err == nil {
+			err = fmt.Errorf("checkpoint restore not completed")
+		}
+		if err != nil {
+			if abortErr := ndb.AbortMultipartInsert(); abortErr != nil {
+				n.logger.Error("error aborting multipart restore of intermediate root",
+					"err", abortErr,
+				)
+			}
+			return i, fmt.Errorf("can't restore intermediate root %s: %w", cp.Root, err)
+		}
+
+		n.logger.Info("restored intermediate root from checkpoint",
+			"root", cp.Root,
+			"base_root", cp.BaseRoot,
+		)
+	}
+	return len(chain), nil
+}
+
+// prepareDeltaCheckpoints determines the cheapest checkpoint chains for the roots of the given
+// round and restores their intermediate roots. It returns the last delta checkpoint of each chain,
+// which can be used to restore the corresponding root of the round, and the minimum version that
+// can still be restored, as versions up to the last finalized intermediate root can no longer be
+// finalized.
+func (n *Node) prepareDeltaCheckpoints(
+	round []*storageSync.Checkpoint,
+	fulls map[storageApi.Root][]*storageSync.Checkpoint,
+	deltas map[storageApi.Root][]*storageSync.Checkpoint,
+	genesisRound uint64,
+	minVersion uint64,
+) (map[storageApi.Root]*storageSync.Checkpoint, uint64) {
+	if len(deltas) == 0 {
+		return nil, minVersion
+	}
+
+	prepared := make(map[storageApi.Root]*storageSync.Checkpoint)
+	for _, check := range round {
+		if _, ok := prepared[check.Root]; ok {
+			continue
+		}
+		if !n.checkCheckpointUsable(check, outstandingMaskFull, genesisRound) {
+			continue
+		}
+
+		chain := n.getCheckpointChain(check, fulls, deltas)
+		if chain == nil {
+			continue
+		}
+		restored, err := n.restoreIntermediateCheckpoints(chain[:len(chain)-1])
+		if restored > 0 {
+			minVersion = max(minVersion, chain[restored-1].Root.Version+1)
+		}
+		if err != nil {
+			n.logger.Info("error trying to restore checkpoint chain, using full checkpoint",
+				"root", check.Root,
+				"err", err,
+			)
+			continue
+		}
+		prepared[check.Root] = chain[len(chain)-1]
+	}
+	return prepared, minVersion
+}
+
+// isCommittedRoot checks whether the given root has been committed in the corresponding runtime
+// block.
+func (n *Node) isCommittedRoot(root storageApi.Root) bool {
+	blk, err := n.commonNode.Runtime.History().GetCommittedBlock(n.ctx, root.Version)
+	if err != nil || !root.Namespace.Equal(&blk.Header.Namespace) {
+		return false
+	}
+	for _, r := range blk.Header.StorageRoots() {
+		if r.Type == root.Type && r.Hash.Equal(&root.Hash) {
+			return true
+		}
+	}
+	return false
+}
+
 func (n *Node) checkCheckpointUsable(cp *storageSync.Checkpoint, remainingMask outstandingMask, genesisRound uint64) bool {
 	namespace := n.commonNode.Runtime.ID()
 	if !namespace.Equal(&cp.Root.Namespace) {
@@ -359,11 +596,25 @@ func (n *Node) syncCheckpoints(genesisRound uint64, wantOnlyGenesis bool) (*bloc
 		cps = filteredCps
 	}
 
+	// Chains of delta checkpoints, starting either from a full checkpoint or from a root that is
+	// available locally, can be used instead of full checkpoints in case they are cheaper.
+	var deltas map[storageApi.Root][]*storageSync.Checkpoint
+	fulls := make(map[storageApi.Root][]*storageSync.Checkpoint)
+	if !wantOnlyGenesis {
+		deltas = n.getDeltaCheckpoints()
+		for _, cp := range cps {
+			fulls[cp.Root] = append(fulls[cp.Root], cp)
+		}
+		sortCheckpointsByChunks(fulls)
+	}
+
 	// Try all the checkpoints now, from most recent backwards.
 	var (
 		prevVersion      = ^uint64(0)
+		minVersion       = genesisRound
 		multipartRunning bool
 		mask             outstandingMask
+		roundDeltas      map[storageApi.Root]*storageSync.Checkpoint
 	)
 	remainingRoots := outstandingMaskFull
 
@@ -378,9 +629,9 @@ func (n *Node) syncCheckpoints(genesisRound uint64, wantOnlyGenesis bool) (*bloc
 		}
 	}()
 
-	for _, check := range cps {
+	for i, check := range cps {
 
-		if check.Root.Version < genesisRound || !n.checkCheckpointUsable(check, remainingRoots, genesisRound) {
+		if check.Root.Version < minVersion || !n.checkCheckpointUsable(check, remainingRoots, genesisRound) {
 			continue
 		}
 
@@ -392,6 +643,18 @@ func (n *Node) syncCheckpoints(genesisRound uint64, wantOnlyGenesis bool) (*bloc
 			if err := n.localStorage.NodeDB().AbortMultipartInsert(); err != nil {
 				return nil, fmt.Errorf("error aborting previous multipart restore: %w", err)
 			}
+
+			// Restore the intermediate roots of any delta checkpoint chains before starting the
+			// multipart insert for this round, as each of them needs to be finalized separately.
+			round := cps[i:]
+			for j, cp := range round {
+				if cp.Root.Version != check.Root.Version {
+					round = round[:j]
+					break
+				}
+			}
+			roundDeltas, minVersion = n.prepareDeltaCheckpoints(round, fulls, deltas, genesisRound, minVersion)
+
 			if err := n.localStorage.NodeDB().StartMultipartInsert(check.Root.Version); err != nil {
 				return nil, fmt.Errorf("error starting multipart insert for round %d: %w", check.Root.Version, err)
 			}
@@ -417,7 +680,25 @@ func (n *Node) syncCheckpoints(genesisRound uint64, wantOnlyGenesis bool) (*bloc
 			}
 		}
 
-		status, err := n.handleCheckpoint(check, n.checkpointSyncCfg.ChunkFetcherCount)
+		// Try the last delta checkpoint of the cheapest chain first, falling back to the full
+		// checkpoint.
+		status := checkpointStatusNext
+		var err error
+		if delta, ok := roundDeltas[check.Root]; ok {
+			delete(roundDeltas, check.Root)
+
+			status, err = n.handleCheckpoint(delta, n.checkpointSyncCfg.ChunkFetcherCount)
+			if status == checkpointStatusNext {
+				n.logger.Info("error trying to restore from delta checkpoint, using full checkpoint",
+					"root", delta.Root,
+					"base_root", delta.BaseRoot,
+					"err", err,
+				)
+			}
+		}
+		if status == checkpointStatusNext {
+			status, err = n.handleCheckpoint(check, n.checkpointSyncCfg.ChunkFetcherCount)
+		}
 		switch status {
 		case checkpointStatusDone:
 			n.logger.Info("successfully restored from checkpoint", "root", check.Root, "mask", mask)
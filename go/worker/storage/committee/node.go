@@ -207,11 +207,16 @@ func NewNode(
 	if config.GlobalConfig.Storage.Checkpointer.Enabled {
 		checkInterval = config.GlobalConfig.Storage.Checkpointer.CheckInterval
 	}
+	checkpointVersion, err := config.GlobalConfig.Storage.Checkpointer.CheckpointVersion()
+	if err != nil {
+		return nil, err
+	}
 	checkpointerCfg := checkpoint.CheckpointerConfig{
 		Name:            "runtime",
 		Namespace:       commonNode.Runtime.ID(),
 		CheckInterval:   checkInterval,
 		RootsPerVersion: 2, // State root and I/O root.
+		Version:         checkpointVersion,
 		GetParameters: func(ctx context.Context) (*checkpoint.CreationParameters, error) {
 			rt, rerr := commonNode.Runtime.ActiveDescriptor(ctx)
 			if rerr != nil {
@@ -241,8 +246,8 @@ func NewNode(
 
 			return blk.Header.StorageRoots(), nil
 		},
+		CreateDeltas: config.GlobalConfig.Storage.Checkpointer.DeltaCheckpoints,
 	}
-	var err error
 	n.checkpointer, err = checkpoint.NewCheckpointer(
 		n.ctx,
 		localStorage.NodeDB(),
@@ -1353,3 +1358,12 @@ func (p *pruneHandler) Prune(rounds []uint64) error {
 
 	return nil
 }
+
+// DiskUsage implements history.DiskUsageReporter.
+func (p *pruneHandler) DiskUsage() (uint64, error) {
+	size, err := p.node.localStorage.NodeDB().Size()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(size), nil
+}
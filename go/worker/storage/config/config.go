@@ -2,8 +2,11 @@
 package config
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db"
 )
 
@@ -31,10 +34,33 @@ type CheckpointerConfig struct {
 	Enabled bool `yaml:"enabled"`
 	// Storage checkpointer check interval.
 	CheckInterval time.Duration `yaml:"check_interval"`
+	// Create delta checkpoints against the previous checkpoint in addition to full checkpoints.
+	//
+	// Nodes restoring state pick the cheapest chain of checkpoints, which may consist of a full
+	// checkpoint or a locally available root followed by any number of delta checkpoints.
+	DeltaCheckpoints bool `yaml:"delta_checkpoints"`
+	// Compression algorithm used for checkpoint chunks (snappy or zstd). Using zstd produces
+	// smaller, content-addressed chunks which can only be fetched by peers that support them.
+	Compression string `yaml:"compression"`
+}
+
+// CheckpointVersion returns the checkpoint version corresponding to the configured compression.
+func (c *CheckpointerConfig) CheckpointVersion() (uint16, error) {
+	switch strings.ToLower(c.Compression) {
+	case "", "snappy":
+		return checkpoint.VersionSnappy, nil
+	case "zstd":
+		return checkpoint.VersionZstd, nil
+	default:
+		return 0, fmt.Errorf("unsupported checkpoint compression: %s", c.Compression)
+	}
 }
 
 // Validate validates the configuration settings.
 func (c *Config) Validate() error {
+	if _, err := c.Checkpointer.CheckpointVersion(); err != nil {
+		return err
+	}
 	if c.Backend != "auto" {
 		_, err := db.GetBackendByName(c.Backend)
 		return err
@@ -51,8 +77,10 @@ func DefaultConfig() Config {
 		PublicRPCEnabled:       false,
 		CheckpointSyncDisabled: false,
 		Checkpointer: CheckpointerConfig{
-			Enabled:       false,
-			CheckInterval: 1 * time.Minute,
+			Enabled:          false,
+			CheckInterval:    1 * time.Minute,
+			DeltaCheckpoints: true,
+			Compression:      "snappy",
 		},
 	}
 }
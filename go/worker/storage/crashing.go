@@ -48,6 +48,20 @@ func (w *crashingWrapper) SyncIterate(ctx context.Context, request *api.IterateR
 	return res, err
 }
 
+func (w *crashingWrapper) SyncGetMulti(ctx context.Context, request *api.GetMultiRequest) (*api.ProofResponse, error) {
+	crash.Here(crashPointReadBefore)
+	res, err := w.LocalBackend.SyncGetMulti(ctx, request)
+	crash.Here(crashPointReadAfter)
+	return res, err
+}
+
+func (w *crashingWrapper) SyncGetRange(ctx context.Context, request *api.GetRangeRequest) (*api.ProofResponse, error) {
+	crash.Here(crashPointReadBefore)
+	res, err := w.LocalBackend.SyncGetRange(ctx, request)
+	crash.Here(crashPointReadAfter)
+	return res, err
+}
+
 func (w *crashingWrapper) Apply(ctx context.Context, request *api.ApplyRequest) error {
 	crash.Here(crashPointWriteBefore)
 	err := w.LocalBackend.Apply(ctx, request)
@@ -28,6 +28,13 @@ type Client interface {
 	// Iterate seeks to a given key and then fetches the specified number of following items based
 	// on key iteration order.
 	Iterate(ctx context.Context, request *IterateRequest) (*ProofResponse, rpc.PeerFeedback, error)
+
+	// GetMulti fetches multiple keys and returns a single proof for all of them.
+	GetMulti(ctx context.Context, request *GetMultiRequest) (*ProofResponse, rpc.PeerFeedback, error)
+
+	// GetRange fetches all keys in the given range, up to the given limit, and returns a proof
+	// of range completeness.
+	GetRange(ctx context.Context, request *GetRangeRequest) (*ProofResponse, rpc.PeerFeedback, error)
 }
 
 type client struct {
@@ -62,6 +69,24 @@ func (c *client) Iterate(ctx context.Context, request *IterateRequest) (*ProofRe
 	return &rsp, pf, nil
 }
 
+func (c *client) GetMulti(ctx context.Context, request *GetMultiRequest) (*ProofResponse, rpc.PeerFeedback, error) {
+	var rsp ProofResponse
+	pf, err := c.rc.CallOne(ctx, c.mgr.GetBestPeers(), MethodGetMulti, request, &rsp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &rsp, pf, nil
+}
+
+func (c *client) GetRange(ctx context.Context, request *GetRangeRequest) (*ProofResponse, rpc.PeerFeedback, error) {
+	var rsp ProofResponse
+	pf, err := c.rc.CallOne(ctx, c.mgr.GetBestPeers(), MethodGetRange, request, &rsp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &rsp, pf, nil
+}
+
 // NewClient creates a new storage pub protocol client.
 func NewClient(p2p rpc.P2P, chainContext string, runtimeID common.Namespace) Client {
 	pid := protocol.NewRuntimeProtocolID(chainContext, runtimeID, StoragePubProtocolID, StoragePubProtocolVersion)
@@ -24,7 +24,7 @@ const (
 // GetRequest is a Get request.
 type GetRequest = syncer.GetRequest
 
-// ProofResponse is a response to Get/GetPrefixes/Iterate containing a proof.
+// ProofResponse is a response to Get/GetPrefixes/Iterate/GetMulti/GetRange containing a proof.
 type ProofResponse = syncer.ProofResponse
 
 // Constants related to the GetPrefixes method.
@@ -43,6 +43,22 @@ const (
 // IterateRequest is an Iterate request.
 type IterateRequest = syncer.IterateRequest
 
+// Constants related to the GetMulti method.
+const (
+	MethodGetMulti = "GetMulti"
+)
+
+// GetMultiRequest is a GetMulti request.
+type GetMultiRequest = syncer.GetMultiRequest
+
+// Constants related to the GetRange method.
+const (
+	MethodGetRange = "GetRange"
+)
+
+// GetRangeRequest is a GetRange request.
+type GetRangeRequest = syncer.GetRangeRequest
+
 func init() {
 	peermgmt.RegisterNodeHandler(&peermgmt.NodeHandlerBundle{
 		ProtocolsFn: func(n *node.Node, chainContext string) []core.ProtocolID {
@@ -37,6 +37,23 @@ func (s *service) HandleRequest(ctx context.Context, method string, body cbor.Ra
 		}
 
 		return s.backend.SyncIterate(ctx, &rq)
+	case MethodGetMulti:
+		var rq GetMultiRequest
+		if err := cbor.Unmarshal(body, &rq); err != nil {
+			return nil, rpc.ErrBadRequest
+		}
+		if err := rq.Validate(); err != nil {
+			return nil, rpc.ErrBadRequest
+		}
+
+		return s.backend.SyncGetMulti(ctx, &rq)
+	case MethodGetRange:
+		var rq GetRangeRequest
+		if err := cbor.Unmarshal(body, &rq); err != nil {
+			return nil, rpc.ErrBadRequest
+		}
+
+		return s.backend.SyncGetRange(ctx, &rq)
 	default:
 		return nil, rpc.ErrMethodNotSupported
 	}
@@ -45,6 +45,9 @@ const (
 // GetCheckpointsRequest is a GetCheckpoints request.
 type GetCheckpointsRequest struct {
 	Version uint16 `json:"version"`
+
+	// IncludeDeltas specifies whether delta checkpoints should be included in the response.
+	IncludeDeltas bool `json:"include_deltas,omitempty"`
 }
 
 // GetCheckpointsResponse is a response to a GetCheckpoints request.
@@ -64,6 +67,9 @@ type GetCheckpointChunkRequest struct {
 	Root    storage.Root `json:"root"`
 	Index   uint64       `json:"index"`
 	Digest  hash.Hash    `json:"digest"`
+
+	// BaseRoot is the base root in case the chunk belongs to a delta checkpoint.
+	BaseRoot *storage.Root `json:"base_root,omitempty"`
 }
 
 // GetCheckpointChunkResponse is a respose to a GetCheckpointChunk request.
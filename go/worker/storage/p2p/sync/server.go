@@ -74,7 +74,8 @@ func (s *service) handleGetDiff(ctx context.Context, request *GetDiffRequest) (*
 
 func (s *service) handleGetCheckpoints(ctx context.Context, request *GetCheckpointsRequest) (*GetCheckpointsResponse, error) {
 	cps, err := s.backend.GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{
-		Version: request.Version,
+		Version:       request.Version,
+		IncludeDeltas: request.IncludeDeltas,
 	})
 	if err != nil {
 		return nil, err
@@ -89,10 +90,11 @@ func (s *service) handleGetCheckpointChunk(ctx context.Context, request *GetChec
 	// TODO: Use stream resource manager to track buffer use.
 	var buf bytes.Buffer
 	err := s.backend.GetCheckpointChunk(ctx, &checkpoint.ChunkMetadata{
-		Version: request.Version,
-		Root:    request.Root,
-		Index:   request.Index,
-		Digest:  request.Digest,
+		Version:  request.Version,
+		Root:     request.Root,
+		Index:    request.Index,
+		Digest:   request.Digest,
+		BaseRoot: request.BaseRoot,
 	}, &buf)
 	if err != nil {
 		return nil, err
@@ -7,7 +7,7 @@
 use std::collections::BTreeMap;
 
 use crate::{
-    common::{quantity::Quantity, version::ProtocolVersions},
+    common::{crypto::hash::Hash, quantity::Quantity, version::ProtocolVersions},
     consensus::beacon::EpochTime,
 };
 
@@ -60,6 +60,15 @@ pub struct ChangeParametersProposal {
     pub changes: Option<cbor::Value>,
 }
 
+/// Text proposal content.
+#[derive(Clone, Debug, Default, PartialEq, Eq, Hash, cbor::Encode, cbor::Decode)]
+pub struct TextProposal {
+    pub title: String,
+    pub content_hash: Hash,
+    #[cbor(optional)]
+    pub uri: String,
+}
+
 /// Consensus layer governance proposal content.
 #[derive(Clone, Debug, Default, PartialEq, Eq, cbor::Encode, cbor::Decode)]
 pub struct ProposalContent {
@@ -69,6 +78,8 @@ pub struct ProposalContent {
     pub cancel_upgrade: Option<CancelUpgradeProposal>,
     #[cbor(optional)]
     pub change_parameters: Option<ChangeParametersProposal>,
+    #[cbor(optional)]
+    pub text: Option<TextProposal>,
 }
 
 // Allowed governance consensus parameter changes.
@@ -88,6 +99,8 @@ pub struct ConsensusParameterChanges {
     pub upgrade_cancel_min_epoch_diff: Option<EpochTime>,
     #[cbor(optional)]
     pub enable_change_parameters_proposal: Option<bool>,
+    #[cbor(optional)]
+    pub enable_text_proposal: Option<bool>,
 }
 
 #[cfg(test)]
@@ -175,6 +188,17 @@ mod tests {
                     ..Default::default()
                 }
             ),
+            (
+                "oWR0ZXh0o2N1cml4GGh0dHBzOi8vZXhhbXBsZS5jb20vdGVzdGV0aXRsZWp0ZXN0LXRpdGxlbGNvbnRlbnRfaGFzaFgggJ38dP9Kga8AHrJJ7GBEiDqFoNwHSTKAkaI3YXr8k0U=",
+                ProposalContent {
+                    text: Some(TextProposal {
+                        title: "test-title".into(),
+                        content_hash: Hash::digest_bytes(b"test-content"),
+                        uri: "https://example.com/test".into(),
+                    }),
+                    ..Default::default()
+                },
+            ),
         ];
         for (encoded_base64, content) in tcs {
             let dec: ProposalContent =
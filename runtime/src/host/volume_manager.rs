@@ -15,6 +15,14 @@ pub const METHOD_VOLUME_ADD: &str = "VolumeAdd";
 pub const METHOD_VOLUME_REMOVE: &str = "VolumeRemove";
 /// Name of the VolumeList method.
 pub const METHOD_VOLUME_LIST: &str = "VolumeList";
+/// Name of the VolumeSnapshot method.
+pub const METHOD_VOLUME_SNAPSHOT: &str = "VolumeSnapshot";
+/// Name of the VolumeRestore method.
+pub const METHOD_VOLUME_RESTORE: &str = "VolumeRestore";
+/// Name of the VolumeSnapshotRemove method.
+pub const METHOD_VOLUME_SNAPSHOT_REMOVE: &str = "VolumeSnapshotRemove";
+/// Name of the VolumeClone method.
+pub const METHOD_VOLUME_CLONE: &str = "VolumeClone";
 
 /// Volume manager interface.
 #[async_trait]
@@ -34,6 +42,32 @@ pub trait VolumeManager: Send + Sync {
     ///
     /// The `PermissionVolumeAdd` permission is required to call this method.
     async fn volume_list(&self, args: VolumeListRequest) -> Result<VolumeListResponse, Error>;
+
+    /// Request to host to snapshot a volume.
+    ///
+    /// Snapshots count towards the volume quota. Volumes attached to running components cannot be
+    /// snapshotted, restored or cloned.
+    ///
+    /// The `PermissionVolumeAdd` permission is required to call this method.
+    async fn volume_snapshot(&self, args: VolumeSnapshotRequest)
+        -> Result<VolumeSnapshotResponse, Error>;
+
+    /// Request to host to restore a volume from a snapshot.
+    ///
+    /// The `PermissionVolumeAdd` permission is required to call this method.
+    async fn volume_restore(&self, args: VolumeRestoreRequest)
+        -> Result<VolumeRestoreResponse, Error>;
+
+    /// Request to host to remove a volume snapshot.
+    ///
+    /// The `PermissionVolumeRemove` permission is required to call this method.
+    async fn volume_snapshot_remove(&self, args: VolumeSnapshotRemoveRequest)
+        -> Result<VolumeSnapshotRemoveResponse, Error>;
+
+    /// Request to host to clone a volume.
+    ///
+    /// The `PermissionVolumeAdd` permission is required to call this method.
+    async fn volume_clone(&self, args: VolumeCloneRequest) -> Result<VolumeCloneResponse, Error>;
 }
 
 #[async_trait]
@@ -70,6 +104,55 @@ impl VolumeManager for Protocol {
         )
         .await
     }
+
+    async fn volume_snapshot(
+        &self,
+        args: VolumeSnapshotRequest,
+    ) -> Result<VolumeSnapshotResponse, Error> {
+        host_rpc_call(
+            self,
+            LOCAL_RPC_ENDPOINT_VOLUME_MANAGER,
+            METHOD_VOLUME_SNAPSHOT,
+            args,
+        )
+        .await
+    }
+
+    async fn volume_restore(
+        &self,
+        args: VolumeRestoreRequest,
+    ) -> Result<VolumeRestoreResponse, Error> {
+        host_rpc_call(
+            self,
+            LOCAL_RPC_ENDPOINT_VOLUME_MANAGER,
+            METHOD_VOLUME_RESTORE,
+            args,
+        )
+        .await
+    }
+
+    async fn volume_snapshot_remove(
+        &self,
+        args: VolumeSnapshotRemoveRequest,
+    ) -> Result<VolumeSnapshotRemoveResponse, Error> {
+        host_rpc_call(
+            self,
+            LOCAL_RPC_ENDPOINT_VOLUME_MANAGER,
+            METHOD_VOLUME_SNAPSHOT_REMOVE,
+            args,
+        )
+        .await
+    }
+
+    async fn volume_clone(&self, args: VolumeCloneRequest) -> Result<VolumeCloneResponse, Error> {
+        host_rpc_call(
+            self,
+            LOCAL_RPC_ENDPOINT_VOLUME_MANAGER,
+            METHOD_VOLUME_CLONE,
+            args,
+        )
+        .await
+    }
 }
 
 /// Request to add a volume.
@@ -79,6 +162,9 @@ impl VolumeManager for Protocol {
 pub struct VolumeAddRequest {
     /// Labels to tag the volume with so it can later be found.
     pub labels: BTreeMap<String, String>,
+    /// Maximum size of the volume in bytes. Zero means no limit.
+    #[cbor(optional)]
+    pub quota: u64,
 }
 
 /// Response from the VolumeAdd method.
@@ -124,4 +210,86 @@ pub struct VolumeInfo {
     pub id: String,
     /// Labels assigned to this volume.
     pub labels: BTreeMap<String, String>,
+    /// Maximum size of the volume in bytes. Zero means no limit.
+    #[cbor(optional)]
+    pub quota: u64,
+    /// Amount of disk space used by the volume and its snapshots in bytes.
+    #[cbor(optional)]
+    pub usage: u64,
+    /// Snapshots of the volume in creation order.
+    #[cbor(optional)]
+    pub snapshots: Vec<VolumeSnapshotInfo>,
+}
+
+/// Volume snapshot information.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeSnapshotInfo {
+    /// Snapshot identifier, unique within the volume.
+    pub id: String,
+    /// UNIX timestamp of when the snapshot was created.
+    pub created: u64,
+}
+
+/// Request to snapshot a volume.
+///
+/// The `PermissionVolumeAdd` permission is required to call this method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeSnapshotRequest {
+    /// Unique volume identifier.
+    pub id: String,
+}
+
+/// Response from the VolumeSnapshot method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeSnapshotResponse {
+    /// Identifier of the created snapshot.
+    pub snapshot_id: String,
+}
+
+/// Request to restore a volume from a snapshot.
+///
+/// The `PermissionVolumeAdd` permission is required to call this method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeRestoreRequest {
+    /// Unique volume identifier.
+    pub id: String,
+    /// Identifier of the snapshot to restore.
+    pub snapshot_id: String,
+}
+
+/// Response from the VolumeRestore method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeRestoreResponse {}
+
+/// Request to remove a volume snapshot.
+///
+/// The `PermissionVolumeRemove` permission is required to call this method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeSnapshotRemoveRequest {
+    /// Unique volume identifier.
+    pub id: String,
+    /// Identifier of the snapshot to remove.
+    pub snapshot_id: String,
+}
+
+/// Response from the VolumeSnapshotRemove method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeSnapshotRemoveResponse {}
+
+/// Request to clone a volume.
+///
+/// The `PermissionVolumeAdd` permission is required to call this method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeCloneRequest {
+    /// Unique identifier of the volume to clone.
+    pub id: String,
+    /// Labels to tag the new volume with so it can later be found.
+    pub labels: BTreeMap<String, String>,
+}
+
+/// Response from the VolumeClone method.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct VolumeCloneResponse {
+    /// Unique identifier of the new volume.
+    pub id: String,
 }